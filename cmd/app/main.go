@@ -2,19 +2,35 @@ package main
 
 import (
 	"context"
+	"crypto/rsa"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"go-auth/internal/audit"
 	"go-auth/internal/bootstrap"
+	"go-auth/internal/clientip"
 	"go-auth/internal/config"
+	"go-auth/internal/domain"
+	"go-auth/internal/event"
+	"go-auth/internal/mailer"
+	"go-auth/internal/middleware"
+	"go-auth/internal/rbac"
 	"go-auth/internal/repository"
 	"go-auth/internal/security"
 	"go-auth/internal/service"
+	"go-auth/internal/webhook"
 	"go-auth/pkg/logger"
 	_ "go-auth/pkg/logger/adapter/zap"
 	_ "go-auth/pkg/logger/adapter/zerolog"
 )
 
+const defaultIDTokenTTL = time.Hour
+
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -35,7 +51,8 @@ func run() error {
 
 	defer func() { _ = log.Sync() }()
 
-	ctx := context.Background()
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
 
 	pool, err := bootstrap.NewDBPool(ctx, cfg)
 	if err != nil {
@@ -43,31 +60,289 @@ func run() error {
 	}
 	defer pool.Close()
 
-	userRepo, sessionRepo, _ := repository.NewRepositories(pool)
+	repos := repository.NewRepositories(pool)
+
+	permResolver, err := rbac.NewResolver(repos.Roles, log, cfg.Security.PermissionsRefresh)
+	if err != nil {
+		return fmt.Errorf("create permission resolver: %w", err)
+	}
+
+	if err = permResolver.Refresh(ctx); err != nil {
+		return fmt.Errorf("load role permissions: %w", err)
+	}
+
+	domain.SetPermissionResolver(permResolver)
+
+	go permResolver.Run(ctx)
+
+	auditWriter, err := audit.NewBufferedWriter(repos.Audit, log, audit.DefaultBufferSize)
+	if err != nil {
+		return fmt.Errorf("create audit writer: %w", err)
+	}
+
+	go auditWriter.Run(ctx)
+
+	defer func() {
+		cancel()
+		<-auditWriter.Done()
+	}()
+
+	events, err := event.NewBus(log, event.DefaultQueueSize)
+	if err != nil {
+		return fmt.Errorf("create event bus: %w", err)
+	}
+
+	events.SubscribeAsync(func(ctx context.Context, e domain.Event) error {
+		log.DebugCtx(ctx, "Domain event", "event", e.EventName(), "at", e.OccurredAt())
+
+		return nil
+	})
+
+	go events.Run(ctx)
+
+	defer func() {
+		cancel()
+		<-events.Done()
+	}()
+
+	mail, err := newMailer(cfg, log)
+	if err != nil {
+		return fmt.Errorf("create mailer: %w", err)
+	}
+
+	ipResolver, err := clientip.NewResolver(cfg.ClientIP.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("create client IP resolver: %w", err)
+	}
+
+	ipPolicy, err := clientip.NewPolicy(cfg.ClientIP.Allow, cfg.ClientIP.Deny)
+	if err != nil {
+		return fmt.Errorf("create client IP policy: %w", err)
+	}
+
 	passwordHasher := security.NewHasher(cfg.Security.HashCost)
-	opaqueTokenManager := security.NewOpaque(32)
+	opaqueTokenManager := security.NewOpaque(32, cfg.JWTKey())
 
 	accessTokenManager, err := security.NewJWT(cfg.Security.JWTSecret, cfg.App.Name, cfg.Security.AccessTTL)
 	if err != nil {
 		return fmt.Errorf("create access token manager: %w", err)
 	}
 
+	idTokenManager, err := newIDTokenManager(cfg, log)
+	if err != nil {
+		return fmt.Errorf("create id token manager: %w", err)
+	}
+
+	identityProviders, err := bootstrap.NewIdentityProviders(cfg)
+	if err != nil {
+		return fmt.Errorf("create identity providers: %w", err)
+	}
+
+	passkeyVerifier, err := bootstrap.NewPasskeyVerifier(cfg)
+	if err != nil {
+		return fmt.Errorf("create passkey verifier: %w", err)
+	}
+
 	svc, err := service.NewService(&service.Config{
-		UserRepo:           userRepo,
-		SessionRepo:        sessionRepo,
-		PasswordHasher:     passwordHasher,
-		OpaqueTokenManager: opaqueTokenManager,
-		AccessTokenManager: accessTokenManager,
-		AccessTokenTTL:     cfg.Security.AccessTTL,
-		RefreshTokenTTL:    cfg.Security.RefreshTTL,
+		UserRepo:             repos.Users,
+		SessionRepo:          repos.Sessions,
+		RoleRepo:             repos.Roles,
+		PermissionRepo:       repos.Permissions,
+		AuditRepo:            repos.Audit,
+		AuditLogger:          auditWriter,
+		DeviceRepo:           repos.Devices,
+		OAuthClientRepo:      repos.OAuthClients,
+		AuthCodeRepo:         repos.AuthCodes,
+		Mailer:               mail,
+		ClientIPPolicy:       ipPolicy,
+		PermRefresher:        permResolver,
+		PasswordHasher:       passwordHasher,
+		OpaqueTokenManager:   opaqueTokenManager,
+		AccessTokenManager:   accessTokenManager,
+		IDTokenManager:       idTokenManager,
+		AccessTokenTTL:       cfg.Security.AccessTTL,
+		RefreshTokenTTL:      cfg.Security.RefreshTTL,
+		PermissionClaims:     service.PermissionClaims(cfg.Security.PermissionClaims),
+		TokenAudience:        cfg.Security.Audience,
+		SessionBinding:       service.SessionBinding(cfg.Security.SessionBinding),
+		SessionMaxLifetime:   cfg.Security.SessionMaxLifetime,
+		SessionIdleTimeout:   cfg.Security.SessionIdleTimeout,
+		RefreshGracePeriod:   cfg.Security.RefreshGrace,
+		AuthorizationCodeTTL: cfg.OAuth.CodeTTL,
+		IdentityProviders:    identityProviders,
+		UserIdentityRepo:     repos.Identities,
+		LoginStateRepo:       repos.LoginStates,
+		FederatedStateTTL:    cfg.Federation.StateTTL,
+		MagicLinkURL:         cfg.MagicLink.URL,
+		TokenRepo:            repos.Tokens,
+		MagicLinkTTL:         cfg.MagicLink.TTL,
+		MagicLinkBinding:     cfg.MagicLink.BindBrowser,
+		PasskeyVerifier:      passkeyVerifier,
+		PasskeyRepo:          repos.Passkeys,
+		PasskeyChallengeRepo: repos.PasskeyChallenges,
+		PasskeyChallengeTTL:  cfg.WebAuthn.ChallengeTTL,
+		EmailOTP:             cfg.MFA.EmailOTP.Enabled,
+		EmailOTPTTL:          cfg.MFA.EmailOTP.TTL,
+		EmailOTPMaxAttempts:  cfg.MFA.EmailOTP.MaxAttempts,
+		StepUpWindow:         cfg.Security.StepUpWindow,
+		ImpersonationTTL:     cfg.Security.ImpersonationTTL,
+		ClientAdmin:          cfg.Security.ClientAdmin,
+		OrganizationRepo:     repos.Organizations,
+		UserScope:            service.UserScope(cfg.Organizations.UserScope),
+		InvitationURL:        cfg.Organizations.Invitations.URL,
+		InvitationRepo:       repos.Invitations,
+		InvitationTTL:        cfg.Organizations.Invitations.TTL,
+		APIKeys:              cfg.APIKeys.Enabled,
+		APIKeyRepo:           repos.APIKeys,
+		APIKeyMaxTTL:         cfg.APIKeys.MaxTTL,
+		Webhooks:             cfg.Webhooks.Enabled,
+		WebhookRepo:          repos.Webhooks,
+		Transactor:           repos.Transactor,
+		Events:               events,
 	})
 	if err != nil {
 		return fmt.Errorf("create service: %w", err)
 	}
 
-	_ = svc
+	if cfg.Webhooks.Enabled {
+		dispatcher, err := webhook.NewDispatcher(webhook.Config{
+			Repo:         repos.Webhooks,
+			Log:          log,
+			PollInterval: cfg.Webhooks.PollInterval,
+			Timeout:      cfg.Webhooks.Timeout,
+			MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		})
+		if err != nil {
+			return fmt.Errorf("create webhook dispatcher: %w", err)
+		}
 
-	log.Info("Service layer is ready")
+		go dispatcher.Run(ctx)
+
+		defer func() {
+			cancel()
+			<-dispatcher.Done()
+		}()
+	}
+
+	authHandler, err := bootstrap.NewAuthHandler(cfg, svc, ipResolver)
+	if err != nil {
+		return fmt.Errorf("create auth handler: %w", err)
+	}
+
+	oauthHandler, err := bootstrap.NewOAuthHandler(cfg, svc, ipResolver)
+	if err != nil {
+		return fmt.Errorf("create oauth handler: %w", err)
+	}
+
+	oidcHandler, err := bootstrap.NewOIDCHandler(cfg, svc, idTokenManager)
+	if err != nil {
+		return fmt.Errorf("create oidc handler: %w", err)
+	}
+
+	cors, err := bootstrap.NewCORS(cfg)
+	if err != nil {
+		return fmt.Errorf("create CORS middleware: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	authHandler.Register(mux)
+
+	if oauthHandler != nil {
+		oauthHandler.Register(mux)
+	}
+
+	if oidcHandler != nil {
+		oidcHandler.Register(mux)
+	}
+
+	var apiKeys middleware.APIKeyValidator
+	if cfg.APIKeys.Enabled {
+		apiKeys = svc
+	}
+
+	authenticate := middleware.Authenticate(accessTokenManager, apiKeys, cfg.Security.Audience)
+
+	return serve(ctx, cfg, log, bootstrap.NewHTTPServer(cfg, cors(authenticate(mux))))
+}
+
+func serve(ctx context.Context, cfg *config.Config, log logger.Logger, srv *http.Server) error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		log.Info("HTTP server listening", "addr", srv.Addr)
+
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serve HTTP: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.Server.ShutdownTO)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown HTTP server: %w", err)
+	}
+
+	log.Info("HTTP server stopped")
 
 	return nil
 }
+
+func newMailer(cfg *config.Config, log logger.Logger) (domain.Mailer, error) {
+	if cfg.IsDevelopment() {
+		return mailer.NewLogMailer(log)
+	}
+
+	return mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Addr:     cfg.SMTPAddr(),
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.SMTP.From,
+	})
+}
+
+// newIDTokenManager returns nil when no OIDC issuer is configured. Development
+// falls back to an ephemeral signing key, so ID tokens do not survive a restart.
+func newIDTokenManager(cfg *config.Config, log logger.Logger) (domain.IDTokenManager, error) {
+	if cfg.OIDC.Issuer == "" {
+		return nil, nil //nolint:nilnil // OIDC is optional.
+	}
+
+	var key *rsa.PrivateKey
+
+	switch {
+	case cfg.OIDC.SigningKey != "":
+		parsed, err := security.ParseRSAPrivateKey(cfg.OIDC.SigningKey)
+		if err != nil {
+			return nil, fmt.Errorf("parse OIDC signing key: %w", err)
+		}
+
+		key = parsed
+	case cfg.IsDevelopment():
+		generated, err := security.GenerateRSAKey()
+		if err != nil {
+			return nil, fmt.Errorf("generate OIDC signing key: %w", err)
+		}
+
+		log.Warn("OIDC_SIGNING_KEY is not set, using an ephemeral signing key")
+
+		key = generated
+	default:
+		return nil, errors.New("OIDC_SIGNING_KEY is required when oidc.issuer is set")
+	}
+
+	ttl := cfg.OIDC.IDTokenTTL
+	if ttl == 0 {
+		ttl = defaultIDTokenTTL
+	}
+
+	return security.NewIDTokenSigner(key, cfg.OIDC.Issuer, ttl)
+}
@@ -21,4 +21,105 @@ const (
 	ErrCodeSessionNotFound     Code = "SESSION_NOT_FOUND"
 	ErrCodeInvalidToken        Code = "INVALID_TOKEN"
 	ErrCodeTokenRequired       Code = "TOKEN_REQUIRED"
+	ErrCodeIPNotAllowed        Code = "IP_NOT_ALLOWED"
+	ErrCodeSessionBinding      Code = "SESSION_BINDING_MISMATCH"
+	ErrCodeSessionExpired      Code = "SESSION_EXPIRED"
+	ErrCodeCSRFInvalid         Code = "CSRF_TOKEN_INVALID"
+)
+
+// RBAC error codes.
+const (
+	ErrCodePermissionDenied        Code = "PERMISSION_DENIED"
+	ErrCodeRoleNotFound            Code = "ROLE_NOT_FOUND"
+	ErrCodeRoleAlreadyExists       Code = "ROLE_ALREADY_EXISTS"
+	ErrCodeRoleInUse               Code = "ROLE_IN_USE"
+	ErrCodeRoleBuiltIn             Code = "ROLE_BUILT_IN"
+	ErrCodePermissionNotFound      Code = "PERMISSION_NOT_FOUND"
+	ErrCodePermissionAlreadyExists Code = "PERMISSION_ALREADY_EXISTS"
+)
+
+// User administration error codes.
+const (
+	ErrCodeUserAlreadyBanned       Code = "USER_ALREADY_BANNED"
+	ErrCodeUserNotBanned           Code = "USER_NOT_BANNED"
+	ErrCodeTargetProtected         Code = "TARGET_PROTECTED"
+	ErrCodeImpersonationRestricted Code = "IMPERSONATION_RESTRICTED"
+)
+
+// OAuth error codes; the HTTP layer maps them to RFC 6749 error responses.
+const (
+	ErrCodeInvalidClient           Code = "INVALID_CLIENT"
+	ErrCodeInvalidGrant            Code = "INVALID_GRANT"
+	ErrCodeInvalidScope            Code = "INVALID_SCOPE"
+	ErrCodeInvalidRedirectURI      Code = "INVALID_REDIRECT_URI"
+	ErrCodeUnsupportedGrantType    Code = "UNSUPPORTED_GRANT_TYPE"
+	ErrCodeUnsupportedResponseType Code = "UNSUPPORTED_RESPONSE_TYPE"
+	ErrCodeUnsupportedTokenType    Code = "UNSUPPORTED_TOKEN_TYPE"
+	ErrCodeUnauthorizedClient      Code = "UNAUTHORIZED_CLIENT"
+	ErrCodeOAuthClientNotFound     Code = "OAUTH_CLIENT_NOT_FOUND"
+	ErrCodeOAuthClientDisabled     Code = "OAUTH_CLIENT_DISABLED"
+	ErrCodeInsufficientScope       Code = "INSUFFICIENT_SCOPE"
+)
+
+// Federated login error codes.
+const (
+	ErrCodeProviderNotFound      Code = "IDENTITY_PROVIDER_NOT_FOUND"
+	ErrCodeProviderUnavailable   Code = "IDENTITY_PROVIDER_UNAVAILABLE"
+	ErrCodeFederatedStateInvalid Code = "FEDERATED_STATE_INVALID"
+	ErrCodeFederatedLoginFailed  Code = "FEDERATED_LOGIN_FAILED"
+	ErrCodeAccountLinkRequired   Code = "ACCOUNT_LINK_REQUIRED"
+	ErrCodeIdentityAlreadyLinked Code = "IDENTITY_ALREADY_LINKED"
+)
+
+// Magic link error codes.
+const (
+	ErrCodeMagicLinkDisabled Code = "MAGIC_LINK_DISABLED"
+	ErrCodeMagicLinkBinding  Code = "MAGIC_LINK_BINDING_MISMATCH"
+)
+
+// Passkey error codes.
+const (
+	ErrCodePasskeysDisabled        Code = "PASSKEYS_DISABLED"
+	ErrCodePasskeyChallengeInvalid Code = "PASSKEY_CHALLENGE_INVALID"
+	ErrCodePasskeyInvalid          Code = "PASSKEY_INVALID"
+	ErrCodePasskeyNotFound         Code = "PASSKEY_NOT_FOUND"
+	ErrCodePasskeyAlreadyExists    Code = "PASSKEY_ALREADY_REGISTERED"
+)
+
+// MFA error codes.
+const (
+	ErrCodeMFAFactorDisabled   Code = "MFA_FACTOR_DISABLED"
+	ErrCodeMFAChallengeInvalid Code = "MFA_CHALLENGE_INVALID"
+	ErrCodeMFACodeInvalid      Code = "MFA_CODE_INVALID"
+)
+
+// Step-up error codes.
+const (
+	ErrCodeReauthenticationRequired Code = "REAUTHENTICATION_REQUIRED"
+)
+
+// Organisation error codes.
+const (
+	ErrCodeOrganizationsDisabled          Code = "ORGANIZATIONS_DISABLED"
+	ErrCodeOrganizationNotFound           Code = "ORGANIZATION_NOT_FOUND"
+	ErrCodeOrganizationAlreadyExists      Code = "ORGANIZATION_ALREADY_EXISTS"
+	ErrCodeOrganizationMembershipRequired Code = "ORGANIZATION_MEMBERSHIP_REQUIRED"
+	ErrCodeOrganizationMemberNotFound     Code = "ORGANIZATION_MEMBER_NOT_FOUND"
+	ErrCodeInvitationsDisabled            Code = "INVITATIONS_DISABLED"
+	ErrCodeInvitationNotFound             Code = "INVITATION_NOT_FOUND"
+	ErrCodeInvitationInvalid              Code = "INVITATION_INVALID"
+	ErrCodeInvitationNotPending           Code = "INVITATION_NOT_PENDING"
+	ErrCodeInvitationEmailMismatch        Code = "INVITATION_EMAIL_MISMATCH"
+)
+
+// API key error codes.
+const (
+	ErrCodeAPIKeysDisabled Code = "API_KEYS_DISABLED"
+	ErrCodeAPIKeyNotFound  Code = "API_KEY_NOT_FOUND"
+)
+
+// Webhook error codes.
+const (
+	ErrCodeWebhooksDisabled Code = "WEBHOOKS_DISABLED"
+	ErrCodeWebhookNotFound  Code = "WEBHOOK_NOT_FOUND"
 )
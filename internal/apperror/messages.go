@@ -1,31 +1,194 @@
 package apperror
 
 const (
-	MsgInvalidCredentials      = "Invalid credentials" //nolint:gosec
-	MsgLoginRequestRequired    = "Login request is required"
-	MsgRegisterRequestRequired = "Register request is required"
-	MsgUsernameAlreadyInUse    = "Username already in use"
-	MsgEmailAlreadyInUse       = "Email already in use"
-	MsgRefreshRequestRequired  = "Refresh request is required"
-	MsgRefreshTokenRequired    = "Refresh token is required"
-	MsgSessionNotFound         = "Session not found"
-	MsgSessionNotActive        = "Session is not active"
-	MsgUserNotFound            = "User not found"
-	MsgSessionExpiredOrRevoked = "Session expired or revoked"
-	MsgAccountAccessRevoked    = "Account access has been revoked"
-	MsgOperationFailed         = "Operation failed"
+	MsgInvalidCredentials       = "Invalid credentials" //nolint:gosec
+	MsgLoginRequestRequired     = "Login request is required"
+	MsgRegisterRequestRequired  = "Register request is required"
+	MsgUsernameAlreadyInUse     = "Username already in use"
+	MsgEmailAlreadyInUse        = "Email already in use"
+	MsgRefreshRequestRequired   = "Refresh request is required"
+	MsgRefreshTokenRequired     = "Refresh token is required"
+	MsgSessionNotFound          = "Session not found"
+	MsgSessionNotActive         = "Session is not active"
+	MsgUserNotFound             = "User not found"
+	MsgSessionExpiredOrRevoked  = "Session expired or revoked"
+	MsgAccountAccessRevoked     = "Account access has been revoked"
+	MsgOperationFailed          = "Operation failed"
+	MsgPermissionDenied         = "Permission denied"
+	MsgScopeNotGrantable        = "Scopes cannot grant permissions you do not hold"
+	MsgRoleRequestRequired      = "Role request is required"
+	MsgRoleNotFound             = "Role not found"
+	MsgRoleAlreadyExists        = "Role already exists"
+	MsgRoleInUse                = "Role is assigned to users, organization members or invitations"
+	MsgRoleBuiltIn              = "Built-in roles cannot be modified"
+	MsgPermissionRequired       = "Permission request is required"
+	MsgPermissionNotFound       = "Permission not found"
+	MsgPermissionAlreadyExists  = "Permission already exists"
+	MsgUserActionRequired       = "User action request is required"
+	MsgUserAlreadyBanned        = "User is already banned"
+	MsgUserNotBanned            = "User is not banned"
+	MsgCannotManageSelf         = "This action cannot be performed on your own account"
+	MsgTargetProtected          = "Target account is protected"
+	MsgAuditPageInvalid         = "Audit page limit or offset is invalid"
+	MsgAuthenticationRequired   = "Authentication required"
+	MsgHistoryLimitInvalid      = "History limit is invalid"
+	MsgClientIPInvalid          = "Client IP is invalid"
+	MsgIPNotAllowed             = "Access from this network is not allowed"
+	MsgSessionBindingMismatch   = "Session cannot be used from this device or network"
+	MsgSessionLifetimeExceeded  = "Session has reached its maximum lifetime, please log in again"
+	MsgSessionIdle              = "Session expired due to inactivity, please log in again"
+	MsgInvalidJSON              = "Request body is not valid JSON"
+	MsgCSRFTokenInvalid         = "CSRF token is missing or invalid"
+	MsgAuthorizeRequired        = "Authorization request is required"
+	MsgTokenRequestRequired     = "Token request is required"
+	MsgOAuthClientRequired      = "OAuth client request is required"
+	MsgInvalidClient            = "Client authentication failed"
+	MsgInvalidRedirectURI       = "Redirect URI is not registered for this client"
+	MsgInvalidScope             = "Requested scope is not allowed for this client"
+	MsgInvalidGrant             = "Authorization grant is invalid, expired, or revoked"
+	MsgUnsupportedGrantType     = "Grant type is not supported"
+	MsgUnsupportedResponseType  = "Only the code response type is supported"
+	MsgPKCERequired             = "PKCE code challenge with method S256 is required"
+	MsgCodeVerifierRequired     = "Code verifier is required"
+	MsgAuthCodeRequired         = "Authorization code is required"
+	MsgClientCannotAuthorize    = "OAuth client tokens and API keys cannot authorize other clients"
+	MsgAccessTokenInvalid       = "Access token is missing, malformed, or invalid"
+	MsgUnauthorizedClient       = "Client is not allowed to use this grant type"
+	MsgOAuthClientNotFound      = "OAuth client not found"
+	MsgOAuthClientDisabled      = "OAuth client is already disabled"
+	MsgOAuthClientPublic        = "Public OAuth clients have no secret"
+	MsgServiceClientPublic      = "Service account clients must be confidential"
+	MsgNonceInvalid             = "Nonce must be at most 255 characters"
+	MsgInsufficientScope        = "Access token lacks the required scope"
+	MsgIntrospectRequired       = "Introspection request is required"
+	MsgRevokeRequired           = "Revocation request is required"
+	MsgTokenParamRequired       = "Token parameter is required"
+	MsgPublicClientIntrospect   = "Public clients cannot introspect tokens"
+	MsgUnsupportedTokenType     = "Access tokens cannot be revoked, revoke the refresh token instead"
+	MsgFederatedRequestRequired = "Federated login request is required"
+	MsgProviderNotFound         = "Identity provider not found"
+	MsgProviderUnavailable      = "Identity provider is unavailable"
+	MsgFederatedCallbackInvalid = "Authorization code and state are required"
+	MsgFederatedStateInvalid    = "Sign-in request is invalid or expired, please start again"
+	MsgFederatedLoginFailed     = "Sign-in with the identity provider failed"
+	MsgFederatedProfile         = "Identity provider did not share a verified email address and full name"
+	MsgAccountLinkRequired      = "Email belongs to an existing account, sign in to link this provider"
+	MsgIdentityAlreadyLinked    = "This identity is already linked to another account"
+	MsgMagicLinkRequestRequired = "Magic link request is required"
+	MsgMagicLinkDisabled        = "Sign-in links are not enabled"
+	MsgMagicLinkInvalid         = "Sign-in link is invalid, expired, or already used"
+	MsgMagicLinkBinding         = "Sign-in link must be opened in the browser that requested it"
+	MsgPasskeyRequestRequired   = "Passkey request is required"
+	MsgPasskeysDisabled         = "Passkeys are not enabled"
+	MsgPasskeyChallengeInvalid  = "Passkey challenge is invalid or expired, please start again"
+	MsgPasskeyInvalid           = "Passkey could not be verified"
+	MsgPasskeyNotFound          = "Passkey not found"
+	MsgPasskeyAlreadyExists     = "This passkey is already registered"
+	MsgMFARequestRequired       = "MFA request is required"
+	MsgMFACodeRequired          = "MFA token and code are required"
+	MsgMFAFactorDisabled        = "This second factor is not enabled"
+	MsgMFAChallengeInvalid      = "Verification is invalid, expired, or out of attempts, please sign in again"
+	MsgMFACodeInvalid           = "Verification code is incorrect"
+	MsgReauthRequestRequired    = "Re-authentication request is required"
+	MsgReauthProofRequired      = "Password, or MFA token and code, are required"
+	MsgReauthNotAllowed         = "Tokens issued to applications and API keys cannot be elevated"
+	MsgReauthenticationRequired = "Please confirm your identity again to continue"
+	MsgImpersonateRequired      = "Impersonation request is required"
+	MsgImpersonationRestricted  = "This action is not available while impersonating a user"
+	MsgOrganizationsDisabled    = "Organizations are not enabled"
+	MsgOrganizationRequired     = "Organization request is required"
+	MsgOrganizationNotFound     = "Organization not found"
+	MsgOrganizationExists       = "An organization with this slug already exists"
+	MsgOrganizationMemberOnly   = "You are not a member of this organization"
+	MsgOrganizationMemberAbsent = "The user is not a member of this organization"
+	MsgInvitationsDisabled      = "Invitations are not enabled"
+	MsgInvitationRequired       = "Invitation request is required"
+	MsgInvitationNotFound       = "Invitation not found"
+	MsgInvitationInvalid        = "The invitation is invalid or has expired"
+	MsgInvitationNotPending     = "The invitation has already been accepted or revoked"
+	MsgInvitationEmailMismatch  = "The invitation was sent to a different email address"
+	MsgAPIKeysDisabled          = "API keys are not enabled"
+	MsgAPIKeyRequired           = "API key request is required"
+	MsgAPIKeyNotFound           = "API key not found"
+	MsgAPIKeyInvalid            = "API key is invalid, expired or revoked"
+	MsgAPIKeyExpiryTooLong      = "API key expiry exceeds the maximum lifetime"
+	MsgWebhooksDisabled         = "Webhooks are not enabled"
+	MsgWebhookRequired          = "Webhook request is required"
+	MsgWebhookNotFound          = "Webhook not found"
 )
 
 const (
-	MsgGetUserByUsername    = "get user by username"
-	MsgGetUserByEmail       = "get user by email"
-	MsgGetSession           = "get session"
-	MsgGetUser              = "get user"
-	MsgGenerateRefreshToken = "generate refresh token"
-	MsgHashRefreshToken     = "hash refresh token"
-	MsgRotateSession        = "rotate session"
-	MsgUpdateSession        = "update session"
-	MsgSaveNewSession       = "save new session"
-	MsgRevokeSession        = "revoke session"
-	MsgGenerateAccessToken  = "generate access token"
+	MsgGetUserByUsername     = "get user by username"
+	MsgGetUserByEmail        = "get user by email"
+	MsgGetSession            = "get session"
+	MsgGetUser               = "get user"
+	MsgGenerateRefreshToken  = "generate refresh token"
+	MsgHashRefreshToken      = "hash refresh token"
+	MsgRotateSession         = "rotate session"
+	MsgUpdateSession         = "update session"
+	MsgSaveNewSession        = "save new session"
+	MsgRevokeSession         = "revoke session"
+	MsgGenerateAccessToken   = "generate access token"
+	MsgGetRole               = "get role"
+	MsgListRoles             = "list roles"
+	MsgSaveRole              = "save role"
+	MsgUpdateRole            = "update role"
+	MsgDeleteRole            = "delete role"
+	MsgGetPermission         = "get permission"
+	MsgListPermissions       = "list permissions"
+	MsgSavePermission        = "save permission"
+	MsgUpdateUser            = "update user"
+	MsgListAuditEvents       = "list audit events"
+	MsgListLoginHistory      = "list login history"
+	MsgListKnownDevices      = "list known devices"
+	MsgGetOAuthClient        = "get oauth client"
+	MsgSaveOAuthClient       = "save oauth client"
+	MsgUpdateOAuthClient     = "update oauth client"
+	MsgGenerateClientSecret  = "generate client secret"
+	MsgGenerateAuthCode      = "generate authorization code"
+	MsgGetAuthCode           = "get authorization code"
+	MsgSaveAuthCode          = "save authorization code"
+	MsgUseAuthCode           = "use authorization code"
+	MsgGenerateIDToken       = "generate id token"
+	MsgGenerateLoginState    = "generate federated login state"
+	MsgSaveLoginState        = "save federated login state"
+	MsgConsumeLoginState     = "consume federated login state"
+	MsgGetUserIdentity       = "get user identity"
+	MsgSaveUserIdentity      = "save user identity"
+	MsgGenerateMagicLink     = "generate magic link"
+	MsgSaveToken             = "save token"
+	MsgGetToken              = "get token"
+	MsgUseToken              = "use token"
+	MsgGeneratePasskey       = "generate passkey challenge"
+	MsgSavePasskeyChallenge  = "save passkey challenge"
+	MsgConsumePasskey        = "consume passkey challenge"
+	MsgGetPasskey            = "get passkey"
+	MsgListPasskeys          = "list passkeys"
+	MsgSavePasskey           = "save passkey"
+	MsgUsePasskey            = "use passkey"
+	MsgDeletePasskey         = "delete passkey"
+	MsgGenerateMFACode       = "generate MFA code"
+	MsgGetOrganization       = "get organization"
+	MsgSaveOrganization      = "save organization"
+	MsgListOrganizations     = "list organizations"
+	MsgGetMember             = "get organization member"
+	MsgSaveMember            = "save organization member"
+	MsgRemoveMember          = "remove organization member"
+	MsgGenerateInvitation    = "generate invitation"
+	MsgSaveInvitation        = "save invitation"
+	MsgGetInvitation         = "get invitation"
+	MsgListInvitations       = "list invitations"
+	MsgAcceptInvitation      = "accept invitation"
+	MsgRevokeInvitation      = "revoke invitation"
+	MsgGenerateAPIKey        = "generate API key"
+	MsgSaveAPIKey            = "save API key"
+	MsgGetAPIKey             = "get API key"
+	MsgListAPIKeys           = "list API keys"
+	MsgUseAPIKey             = "use API key"
+	MsgRevokeAPIKey          = "revoke API key"
+	MsgGenerateWebhookSecret = "generate webhook secret"
+	MsgSaveWebhook           = "save webhook"
+	MsgListWebhooks          = "list webhooks"
+	MsgDisableWebhook        = "disable webhook"
+	MsgEnqueueWebhook        = "enqueue webhook event"
 )
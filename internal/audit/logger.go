@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"go-auth/internal/domain"
+	"go-auth/pkg/logger"
+)
+
+var _ domain.AuditLogger = (*Logger)(nil)
+
+type Logger struct {
+	log logger.Logger
+}
+
+func NewLogger(log logger.Logger) (*Logger, error) {
+	if log == nil {
+		return nil, errors.New("logger is required")
+	}
+
+	return &Logger{log: log}, nil
+}
+
+func (l *Logger) Log(ctx context.Context, event *domain.AuditEvent) error {
+	l.log.InfoCtx(ctx, "Audit event",
+		"id", event.ID,
+		"action", event.Action,
+		"outcome", event.Outcome,
+		"actor_id", event.ActorID,
+		"target_id", event.TargetID,
+		"client_ip", event.ClientIP,
+		"user_agent", event.UserAgent,
+		"metadata", event.Metadata,
+	)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-auth/internal/domain"
+	"go-auth/pkg/logger"
+)
+
+const (
+	DefaultBufferSize   = 1024
+	DefaultWriteTimeout = 5 * time.Second
+)
+
+var ErrBufferFull = errors.New("audit buffer is full")
+
+var _ domain.AuditLogger = (*BufferedWriter)(nil)
+
+// BufferedWriter queues events in memory and hands them to the next logger
+// from a single background goroutine started with Run. Log never blocks:
+// when the buffer is full the event is dropped and a warning is logged.
+type BufferedWriter struct {
+	next         domain.AuditLogger
+	log          logger.Logger
+	events       chan *domain.AuditEvent
+	writeTimeout time.Duration
+	done         chan struct{}
+}
+
+func NewBufferedWriter(next domain.AuditLogger, log logger.Logger, size int) (*BufferedWriter, error) {
+	if next == nil {
+		return nil, errors.New("audit logger is required")
+	}
+
+	if log == nil {
+		return nil, errors.New("logger is required")
+	}
+
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+
+	return &BufferedWriter{
+		next:         next,
+		log:          log,
+		events:       make(chan *domain.AuditEvent, size),
+		writeTimeout: DefaultWriteTimeout,
+		done:         make(chan struct{}),
+	}, nil
+}
+
+func (w *BufferedWriter) Log(ctx context.Context, event *domain.AuditEvent) error {
+	if event == nil {
+		return nil
+	}
+
+	select {
+	case w.events <- event:
+		return nil
+	default:
+		w.log.WarnCtx(ctx, "Audit buffer full, dropping event", "id", event.ID, "action", event.Action)
+
+		return ErrBufferFull
+	}
+}
+
+func (w *BufferedWriter) Run(ctx context.Context) {
+	defer close(w.done)
+
+	// Writes get their own timeout so a shutdown does not abort one half-way.
+	writeCtx := context.WithoutCancel(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.flush(writeCtx)
+
+			return
+		case event := <-w.events:
+			w.write(writeCtx, event)
+		}
+	}
+}
+
+func (w *BufferedWriter) Done() <-chan struct{} {
+	return w.done
+}
+
+func (w *BufferedWriter) flush(ctx context.Context) {
+	for {
+		select {
+		case event := <-w.events:
+			w.write(ctx, event)
+		default:
+			return
+		}
+	}
+}
+
+func (w *BufferedWriter) write(ctx context.Context, event *domain.AuditEvent) {
+	writeCtx, cancel := context.WithTimeout(ctx, w.writeTimeout)
+	defer cancel()
+
+	if err := w.next.Log(writeCtx, event); err != nil {
+		w.log.ErrorCtx(ctx, "Write audit event failed", "id", event.ID, "action", event.Action, "error", err)
+	}
+}
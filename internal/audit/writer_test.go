@@ -0,0 +1,122 @@
+package audit_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/audit"
+	"go-auth/internal/domain"
+	"go-auth/pkg/logger"
+	_ "go-auth/pkg/logger/adapter/nop"
+)
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	events  []*domain.AuditEvent
+	release chan struct{}
+}
+
+func (r *recordingLogger) Log(ctx context.Context, event *domain.AuditEvent) error {
+	if r.release != nil {
+		<-r.release
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+
+	return nil
+}
+
+func (r *recordingLogger) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.events)
+}
+
+func newNopLogger(t *testing.T) logger.Logger {
+	t.Helper()
+
+	log, err := logger.New(logger.WithDriver(logger.DriverNop))
+	require.NoError(t, err)
+
+	return log
+}
+
+func TestNewBufferedWriter(t *testing.T) {
+	_, err := audit.NewBufferedWriter(nil, newNopLogger(t), 1)
+	require.Error(t, err)
+
+	_, err = audit.NewBufferedWriter(&recordingLogger{}, nil, 1)
+	require.Error(t, err)
+}
+
+func TestBufferedWriterDelivers(t *testing.T) {
+	next := &recordingLogger{}
+	w, err := audit.NewBufferedWriter(next, newNopLogger(t), 4)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go w.Run(ctx)
+
+	require.NoError(t, w.Log(ctx, domain.NewAuditEvent(domain.AuditActionLogin, domain.AuditOutcomeSuccess)))
+	require.NoError(t, w.Log(ctx, domain.NewAuditEvent(domain.AuditActionLogout, domain.AuditOutcomeSuccess)))
+
+	assert.Eventually(t, func() bool { return next.count() == 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-w.Done()
+}
+
+func TestBufferedWriterDropsWhenFull(t *testing.T) {
+	next := &recordingLogger{}
+	w, err := audit.NewBufferedWriter(next, newNopLogger(t), 1)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	require.NoError(t, w.Log(ctx, domain.NewAuditEvent(domain.AuditActionLogin, domain.AuditOutcomeSuccess)))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Log(ctx, domain.NewAuditEvent(domain.AuditActionLogin, domain.AuditOutcomeSuccess))
+	}()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, audit.ErrBufferFull)
+	case <-time.After(time.Second):
+		t.Fatal("Log blocked on a full buffer")
+	}
+}
+
+func TestBufferedWriterFlushesOnShutdown(t *testing.T) {
+	next := &recordingLogger{release: make(chan struct{})}
+	w, err := audit.NewBufferedWriter(next, newNopLogger(t), 8)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for range 3 {
+		require.NoError(t, w.Log(ctx, domain.NewAuditEvent(domain.AuditActionRefresh, domain.AuditOutcomeSuccess)))
+	}
+
+	go w.Run(ctx)
+	cancel()
+	close(next.release)
+
+	select {
+	case <-w.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+
+	assert.Equal(t, 3, next.count())
+}
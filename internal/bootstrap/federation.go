@@ -0,0 +1,40 @@
+package bootstrap
+
+import (
+	"slices"
+
+	"go-auth/internal/config"
+	"go-auth/internal/domain"
+	"go-auth/internal/federation"
+)
+
+func NewIdentityProviders(cfg *config.Config) ([]domain.IdentityProvider, error) {
+	names := make([]string, 0, len(cfg.Federation.Providers))
+	for name := range cfg.Federation.Providers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	providers := make([]domain.IdentityProvider, 0, len(names))
+
+	for _, name := range names {
+		p := cfg.Federation.Providers[name]
+
+		provider, err := federation.NewOIDCProvider(federation.OIDCConfig{
+			Name:         name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,128 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go-auth/internal/config"
+	"go-auth/internal/csrf"
+	"go-auth/internal/domain"
+	"go-auth/internal/handler"
+	"go-auth/internal/middleware"
+	"go-auth/internal/service"
+)
+
+func NewHTTPServer(cfg *config.Config, h http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.ServerAddr(),
+		Handler:           h,
+		ReadTimeout:       cfg.Server.ReadTO,
+		ReadHeaderTimeout: cfg.Server.ReadTO,
+		WriteTimeout:      cfg.Server.WriteTO,
+		IdleTimeout:       cfg.Server.IdleTO,
+	}
+}
+
+func NewCORS(cfg *config.Config) (func(http.Handler) http.Handler, error) {
+	return middleware.CORS(middleware.CORSConfig{
+		Origins:          cfg.CORS.Origins,
+		Methods:          cfg.CORS.Methods,
+		Headers:          cfg.CORS.Headers,
+		MaxAge:           cfg.CORS.MaxAge,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+	})
+}
+
+func NewAuthHandler(
+	cfg *config.Config,
+	svc service.Service,
+	resolver handler.ClientIPResolver,
+) (*handler.AuthHandler, error) {
+	transport := &cfg.Transport
+
+	authCfg := &handler.AuthConfig{
+		Service:    svc,
+		IPResolver: resolver,
+		Transport:  handler.Transport(transport.Mode),
+	}
+
+	if authCfg.Transport != handler.TransportCookie {
+		return handler.NewAuthHandler(authCfg)
+	}
+
+	if cfg.IsProduction() && !transport.Cookie.Secure {
+		return nil, errors.New("refresh cookie must be secure in production")
+	}
+
+	sameSite := parseSameSite(transport.Cookie.SameSite)
+
+	protector, err := csrf.New(csrf.Config{
+		Mode:       csrf.Mode(transport.CSRF.Mode),
+		CookieName: transport.CSRF.Cookie,
+		HeaderName: transport.CSRF.Header,
+		Domain:     transport.Cookie.Domain,
+		Secure:     transport.Cookie.Secure,
+		SameSite:   sameSite,
+		Key:        cfg.JWTKey(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create CSRF protector: %w", err)
+	}
+
+	authCfg.CSRF = protector
+	authCfg.Cookie = handler.RefreshCookie{
+		Name:     transport.Cookie.Name,
+		Domain:   transport.Cookie.Domain,
+		Path:     transport.Cookie.Path,
+		Secure:   transport.Cookie.Secure,
+		SameSite: sameSite,
+	}
+
+	return handler.NewAuthHandler(authCfg)
+}
+
+func NewOAuthHandler(
+	cfg *config.Config,
+	svc service.Service,
+	resolver handler.ClientIPResolver,
+) (*handler.OAuthHandler, error) {
+	if cfg.OAuth.LoginURL == "" {
+		return nil, nil //nolint:nilnil // OAuth is optional.
+	}
+
+	return handler.NewOAuthHandler(&handler.OAuthConfig{
+		Service:    svc,
+		IPResolver: resolver,
+		LoginURL:   cfg.OAuth.LoginURL,
+	})
+}
+
+func NewOIDCHandler(
+	cfg *config.Config,
+	svc service.Service,
+	idTokens domain.IDTokenManager,
+) (*handler.OIDCHandler, error) {
+	if idTokens == nil {
+		return nil, nil //nolint:nilnil // OIDC is optional.
+	}
+
+	return handler.NewOIDCHandler(&handler.OIDCConfig{
+		Service:  svc,
+		IDTokens: idTokens,
+		Issuer:   cfg.OIDC.Issuer,
+	})
+}
+
+func parseSameSite(value string) http.SameSite {
+	switch value {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	case "strict":
+		return http.SameSiteStrictMode
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,19 @@
+package bootstrap
+
+import (
+	"go-auth/internal/config"
+	"go-auth/internal/domain"
+	"go-auth/internal/webauthn"
+)
+
+func NewPasskeyVerifier(cfg *config.Config) (domain.PasskeyVerifier, error) {
+	if cfg.WebAuthn.RPID == "" {
+		return nil, nil //nolint:nilnil // Passkeys are optional.
+	}
+
+	return webauthn.New(webauthn.Config{
+		RPID:    cfg.WebAuthn.RPID,
+		RPName:  cfg.WebAuthn.RPName,
+		Origins: cfg.WebAuthn.Origins,
+	})
+}
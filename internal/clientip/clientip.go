@@ -0,0 +1,229 @@
+package clientip
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+var ErrInvalid = errors.New("client IP is invalid")
+
+// Parse validates raw as an IP address, strips any zone and unmaps
+// IPv4-mapped IPv6 addresses so ::ffff:192.0.2.1 and 192.0.2.1 compare equal.
+func Parse(raw string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(raw))
+	if err != nil {
+		return netip.Addr{}, ErrInvalid
+	}
+
+	return addr.Unmap().WithZone(""), nil
+}
+
+func Normalize(raw string) (string, error) {
+	addr, err := Parse(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return addr.String(), nil
+}
+
+func ParsePrefixes(raw []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(raw))
+
+	for _, r := range raw {
+		r = strings.TrimSpace(r)
+
+		if strings.Contains(r, "/") {
+			prefix, err := netip.ParsePrefix(r)
+			if err != nil {
+				return nil, fmt.Errorf("parse prefix %q: %w", r, err)
+			}
+
+			if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+				prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+			}
+
+			out = append(out, prefix.Masked())
+
+			continue
+		}
+
+		addr, err := Parse(r)
+		if err != nil {
+			return nil, fmt.Errorf("parse address %q: %w", r, err)
+		}
+
+		out = append(out, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return out, nil
+}
+
+func contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Resolver struct {
+	trusted []netip.Prefix
+}
+
+func NewResolver(trustedProxies []string) (*Resolver, error) {
+	trusted, err := ParsePrefixes(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resolver{trusted: trusted}, nil
+}
+
+// Resolve returns the client address of r. When the peer is trusted, the
+// Forwarded header (or X-Forwarded-For if Forwarded is absent) is walked from
+// right to left and the first untrusted hop is returned.
+func (r *Resolver) Resolve(req *http.Request) (netip.Addr, error) {
+	peer, err := parseRemoteAddr(req.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	if !contains(r.trusted, peer) {
+		return peer, nil
+	}
+
+	hops, ok := forwardedFor(req.Header)
+	if !ok {
+		hops, ok = xForwardedFor(req.Header)
+	}
+
+	if !ok {
+		return peer, nil
+	}
+
+	client := peer
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := Parse(hops[i])
+		if err != nil {
+			// A malformed hop means everything to its left is untrustworthy.
+			return client, nil
+		}
+
+		client = addr
+
+		if !contains(r.trusted, addr) {
+			return addr, nil
+		}
+	}
+
+	return client, nil
+}
+
+func parseRemoteAddr(remoteAddr string) (netip.Addr, error) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	return Parse(host)
+}
+
+func xForwardedFor(h http.Header) ([]string, bool) {
+	values := h.Values("X-Forwarded-For")
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	var hops []string
+
+	for _, v := range values {
+		for hop := range strings.SplitSeq(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+
+	return hops, true
+}
+
+func forwardedFor(h http.Header) ([]string, bool) {
+	values := h.Values("Forwarded")
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	var hops []string
+
+	for _, v := range values {
+		for element := range strings.SplitSeq(v, ",") {
+			hop := ""
+
+			for pair := range strings.SplitSeq(element, ";") {
+				key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if found && strings.EqualFold(key, "for") {
+					hop = forwardedNode(value)
+				}
+			}
+
+			hops = append(hops, hop)
+		}
+	}
+
+	return hops, true
+}
+
+func forwardedNode(value string) string {
+	value = strings.Trim(strings.TrimSpace(value), `"`)
+
+	if strings.HasPrefix(value, "[") {
+		if end := strings.Index(value, "]"); end > 0 {
+			return value[1:end]
+		}
+
+		return value
+	}
+
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return host
+	}
+
+	return value
+}
+
+// Policy restricts which client addresses may authenticate. Deny wins over
+// allow; an empty allow list admits every address that is not denied.
+type Policy struct {
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+func NewPolicy(allow, deny []string) (*Policy, error) {
+	allowed, err := ParsePrefixes(allow)
+	if err != nil {
+		return nil, fmt.Errorf("allow list: %w", err)
+	}
+
+	denied, err := ParsePrefixes(deny)
+	if err != nil {
+		return nil, fmt.Errorf("deny list: %w", err)
+	}
+
+	return &Policy{allow: allowed, deny: denied}, nil
+}
+
+func (p *Policy) Allowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if contains(p.deny, addr) {
+		return false
+	}
+
+	return len(p.allow) == 0 || contains(p.allow, addr)
+}
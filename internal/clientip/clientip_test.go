@@ -0,0 +1,195 @@
+package clientip_test
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/clientip"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{name: "ipv4", raw: "192.0.2.1", want: "192.0.2.1"},
+		{name: "ipv4 mapped", raw: "::ffff:192.0.2.1", want: "192.0.2.1"},
+		{name: "ipv6", raw: "2001:DB8::1", want: "2001:db8::1"},
+		{name: "zone stripped", raw: "fe80::1%eth0", want: "fe80::1"},
+		{name: "whitespace", raw: " 192.0.2.1 ", want: "192.0.2.1"},
+		{name: "empty", raw: "", wantErr: true},
+		{name: "hostname", raw: "example.com", wantErr: true},
+		{name: "with port", raw: "192.0.2.1:80", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := clientip.Normalize(tt.raw)
+			if tt.wantErr {
+				require.ErrorIs(t, err, clientip.ErrInvalid)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePrefixes(t *testing.T) {
+	got, err := clientip.ParsePrefixes([]string{"10.0.0.0/8", "192.0.2.7", "::ffff:172.16.0.0/108", "2001:db8::/32"})
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.0.2.7/32"),
+		netip.MustParsePrefix("172.16.0.0/12"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}, got)
+
+	_, err = clientip.ParsePrefixes([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+
+	_, err = clientip.ParsePrefixes([]string{"nope"})
+	require.Error(t, err)
+}
+
+func TestResolverResolve(t *testing.T) {
+	r, err := clientip.NewResolver([]string{"10.0.0.0/8", "2001:db8:ffff::/48"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string][]string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "untrusted peer ignores headers",
+			remoteAddr: "203.0.113.9:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			want:       "203.0.113.9",
+		},
+		{
+			name:       "trusted peer without headers",
+			remoteAddr: "10.0.0.1:5000",
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "x-forwarded-for rightmost untrusted",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.1.1.1, 198.51.100.1, 10.0.0.2"}},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "x-forwarded-for across multiple headers",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.1.1.1", "198.51.100.1"}},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "x-forwarded-for ipv4 mapped",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"::ffff:198.51.100.1"}},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "x-forwarded-for all trusted returns leftmost",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"10.1.1.1, 10.2.2.2"}},
+			want:       "10.1.1.1",
+		},
+		{
+			name:       "x-forwarded-for malformed hop stops the walk",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.1, garbage, 10.0.0.2"}},
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "forwarded preferred over x-forwarded-for",
+			remoteAddr: "10.0.0.1:5000",
+			headers: map[string][]string{
+				"Forwarded":       {`for=198.51.100.7;proto=https, for="[2001:db8:ffff::1]:443"`},
+				"X-Forwarded-For": {"1.1.1.1"},
+			},
+			want: "198.51.100.7",
+		},
+		{
+			name:       "forwarded ipv6",
+			remoteAddr: "[2001:db8:ffff::2]:443",
+			headers:    map[string][]string{"Forwarded": {`For="[2001:db8:1::9]:1234"`}},
+			want:       "2001:db8:1::9",
+		},
+		{
+			name:       "remote addr without port",
+			remoteAddr: "203.0.113.9",
+			want:       "203.0.113.9",
+		},
+		{
+			name:       "invalid remote addr",
+			remoteAddr: "somewhere",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := &http.Request{RemoteAddr: tt.remoteAddr, Header: http.Header{}}
+			for k, vs := range tt.headers {
+				for _, v := range vs {
+					req.Header.Add(k, v)
+				}
+			}
+
+			got, err := r.Resolve(req)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestPolicyAllowed(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow []string
+		deny  []string
+		addr  string
+		want  bool
+	}{
+		{name: "no rules", addr: "198.51.100.1", want: true},
+		{name: "denied", deny: []string{"198.51.100.0/24"}, addr: "198.51.100.1", want: false},
+		{name: "not denied", deny: []string{"198.51.100.0/24"}, addr: "198.51.101.1", want: true},
+		{name: "allowed", allow: []string{"10.0.0.0/8"}, addr: "10.1.2.3", want: true},
+		{name: "outside allow list", allow: []string{"10.0.0.0/8"}, addr: "11.1.2.3", want: false},
+		{name: "deny wins", allow: []string{"10.0.0.0/8"}, deny: []string{"10.6.6.6"}, addr: "10.6.6.6", want: false},
+		{name: "mapped address", deny: []string{"198.51.100.0/24"}, addr: "::ffff:198.51.100.1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := clientip.NewPolicy(tt.allow, tt.deny)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, p.Allowed(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
@@ -9,14 +9,25 @@ import (
 )
 
 type Config struct {
-	App       App       `mapstructure:"app"`
-	Server    Server    `mapstructure:"server"`
-	CORS      CORS      `mapstructure:"cors"`
-	RateLimit RateLimit `mapstructure:"rate_limit"`
-	Database  Database  `mapstructure:"database"`
-	Security  Security  `mapstructure:"security"`
-	SMTP      SMTP      `mapstructure:"smtp"`
-	Logger    Logger    `mapstructure:"logger"`
+	App           App           `mapstructure:"app"`
+	Server        Server        `mapstructure:"server"`
+	CORS          CORS          `mapstructure:"cors"`
+	RateLimit     RateLimit     `mapstructure:"rate_limit"`
+	Database      Database      `mapstructure:"database"`
+	Security      Security      `mapstructure:"security"`
+	ClientIP      ClientIP      `mapstructure:"client_ip"`
+	Transport     Transport     `mapstructure:"transport"`
+	OAuth         OAuth         `mapstructure:"oauth"`
+	OIDC          OIDC          `mapstructure:"oidc"`
+	Federation    Federation    `mapstructure:"federation"`
+	MagicLink     MagicLink     `mapstructure:"magic_link"`
+	WebAuthn      WebAuthn      `mapstructure:"webauthn"`
+	MFA           MFA           `mapstructure:"mfa"`
+	Organizations Organizations `mapstructure:"organizations"`
+	APIKeys       APIKeys       `mapstructure:"api_keys"`
+	Webhooks      Webhooks      `mapstructure:"webhooks"`
+	SMTP          SMTP          `mapstructure:"smtp"`
+	Logger        Logger        `mapstructure:"logger"`
 }
 
 type App struct {
@@ -34,10 +45,11 @@ type Server struct {
 }
 
 type CORS struct {
-	Origins []string `mapstructure:"origins" validate:"required,min=1,dive,http_url|https_url"`
-	Methods []string `mapstructure:"methods" validate:"required,min=1,dive,oneof=GET POST PUT DELETE OPTIONS"`
-	Headers []string `mapstructure:"headers" validate:"required,min=1,dive,oneof=Content-Type Authorization"`
-	MaxAge  int      `mapstructure:"max_age" validate:"required,min=0,max=86400"`
+	Origins          []string `mapstructure:"origins"           validate:"required,min=1,dive,http_url|https_url"`
+	Methods          []string `mapstructure:"methods"           validate:"required,min=1,dive,oneof=GET POST PUT DELETE OPTIONS"`
+	Headers          []string `mapstructure:"headers"           validate:"required,min=1,dive,oneof=Content-Type Authorization X-CSRF-Token"`
+	MaxAge           int      `mapstructure:"max_age"           validate:"required,min=0,max=86400"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
 }
 
 type RateLimit struct {
@@ -58,10 +70,117 @@ type Database struct {
 }
 
 type Security struct {
-	JWTSecret  string        `mapstructure:"jwt_secret"  validate:"required,min=32,max=512"`
-	AccessTTL  time.Duration `mapstructure:"access_ttl"  validate:"required,min=5m,max=1h"`
-	RefreshTTL time.Duration `mapstructure:"refresh_ttl" validate:"required,min=1h,max=168h,gtfield=AccessTTL"`
-	HashCost   int           `mapstructure:"hash_cost"   validate:"required,min=10,max=15"`
+	JWTSecret          string        `mapstructure:"jwt_secret"           validate:"required,min=32,max=512"`
+	AccessTTL          time.Duration `mapstructure:"access_ttl"           validate:"required,min=5m,max=1h"`
+	RefreshTTL         time.Duration `mapstructure:"refresh_ttl"          validate:"required,min=1h,max=168h,gtfield=AccessTTL"`
+	HashCost           int           `mapstructure:"hash_cost"            validate:"required,min=10,max=15"`
+	PermissionsRefresh time.Duration `mapstructure:"permissions_refresh"  validate:"omitempty,min=5s,max=1h"`
+	PermissionClaims   string        `mapstructure:"permission_claims"    validate:"omitempty,oneof=none permissions scope"`
+	Audience           []string      `mapstructure:"audience"             validate:"omitempty,dive,required"`
+	SessionBinding     string        `mapstructure:"session_binding"      validate:"omitempty,oneof=strict warn off"`
+	SessionMaxLifetime time.Duration `mapstructure:"session_max_lifetime" validate:"omitempty,min=1h,max=8760h"`
+	SessionIdleTimeout time.Duration `mapstructure:"session_idle_timeout" validate:"omitempty,min=5m,max=720h"`
+	RefreshGrace       time.Duration `mapstructure:"refresh_grace"        validate:"omitempty,min=1s,max=1m"`
+	StepUpWindow       time.Duration `mapstructure:"step_up_window"       validate:"omitempty,min=1m,max=1h"`
+	ImpersonationTTL   time.Duration `mapstructure:"impersonation_ttl"    validate:"omitempty,min=1m,max=1h"`
+	ClientAdmin        bool          `mapstructure:"client_admin"`
+}
+
+type ClientIP struct {
+	TrustedProxies []string `mapstructure:"trusted_proxies" validate:"omitempty,dive,cidr|ip"`
+	Allow          []string `mapstructure:"allow"           validate:"omitempty,dive,cidr|ip"`
+	Deny           []string `mapstructure:"deny"            validate:"omitempty,dive,cidr|ip"`
+}
+
+type Transport struct {
+	Mode   string          `mapstructure:"mode"   validate:"omitempty,oneof=body cookie"`
+	Cookie TransportCookie `mapstructure:"cookie"`
+	CSRF   CSRF            `mapstructure:"csrf"`
+}
+
+type TransportCookie struct {
+	Name     string `mapstructure:"name"      validate:"omitempty,max=64,printascii,excludesall=;= "`
+	Domain   string `mapstructure:"domain"    validate:"omitempty,fqdn"`
+	Path     string `mapstructure:"path"      validate:"omitempty,startswith=/"`
+	Secure   bool   `mapstructure:"secure"`
+	SameSite string `mapstructure:"same_site" validate:"omitempty,oneof=strict lax none"`
+}
+
+type CSRF struct {
+	Mode   string `mapstructure:"mode"   validate:"omitempty,oneof=double_submit signed"`
+	Cookie string `mapstructure:"cookie" validate:"omitempty,max=64,printascii,excludesall=;= "`
+	Header string `mapstructure:"header" validate:"omitempty,max=64,printascii"`
+}
+
+type OAuth struct {
+	LoginURL string        `mapstructure:"login_url" validate:"omitempty,http_url|https_url"`
+	CodeTTL  time.Duration `mapstructure:"code_ttl"  validate:"omitempty,min=10s,max=10m"`
+}
+
+type OIDC struct {
+	Issuer     string        `mapstructure:"issuer"       validate:"omitempty,http_url|https_url"`
+	SigningKey string        `mapstructure:"signing_key"`
+	IDTokenTTL time.Duration `mapstructure:"id_token_ttl" validate:"omitempty,min=1m,max=24h"`
+}
+
+type Federation struct {
+	StateTTL  time.Duration                 `mapstructure:"state_ttl" validate:"omitempty,min=1m,max=1h"`
+	Providers map[string]FederationProvider `mapstructure:"providers" validate:"dive"`
+}
+
+type FederationProvider struct {
+	Issuer       string   `mapstructure:"issuer"        validate:"required,http_url|https_url"`
+	ClientID     string   `mapstructure:"client_id"     validate:"required"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	Scopes       []string `mapstructure:"scopes"`
+	RedirectURL  string   `mapstructure:"redirect_url"  validate:"required,http_url|https_url"`
+}
+
+type MagicLink struct {
+	URL         string        `mapstructure:"url"          validate:"omitempty,http_url|https_url"`
+	TTL         time.Duration `mapstructure:"ttl"          validate:"omitempty,min=1m,max=1h"`
+	BindBrowser bool          `mapstructure:"bind_browser"`
+}
+
+// WebAuthn enables passkey sign-in when RPID is set. Origins are the web origins the
+// browser reports in ceremonies; each must be the RP ID or one of its subdomains.
+type WebAuthn struct {
+	RPID         string        `mapstructure:"rp_id"         validate:"omitempty,hostname"`
+	RPName       string        `mapstructure:"rp_name"       validate:"max=100"`
+	Origins      []string      `mapstructure:"origins"       validate:"required_with=RPID,dive,http_url|https_url"`
+	ChallengeTTL time.Duration `mapstructure:"challenge_ttl" validate:"omitempty,min=30s,max=10m"`
+}
+
+type MFA struct {
+	EmailOTP EmailOTP `mapstructure:"email_otp"`
+}
+
+type EmailOTP struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	TTL         time.Duration `mapstructure:"ttl"          validate:"omitempty,min=1m,max=30m"`
+	MaxAttempts int           `mapstructure:"max_attempts" validate:"omitempty,min=1,max=10"`
+}
+
+type Organizations struct {
+	UserScope   string      `mapstructure:"user_scope"  validate:"omitempty,oneof=global organization"`
+	Invitations Invitations `mapstructure:"invitations"`
+}
+
+type Invitations struct {
+	URL string        `mapstructure:"url" validate:"omitempty,http_url|https_url"`
+	TTL time.Duration `mapstructure:"ttl" validate:"omitempty,min=1h,max=720h"`
+}
+
+type APIKeys struct {
+	Enabled bool          `mapstructure:"enabled"`
+	MaxTTL  time.Duration `mapstructure:"max_ttl" validate:"omitempty,min=24h,max=8760h"`
+}
+
+type Webhooks struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	PollInterval time.Duration `mapstructure:"poll_interval" validate:"omitempty,min=1s,max=1m"`
+	Timeout      time.Duration `mapstructure:"timeout"       validate:"omitempty,min=1s,max=1m"`
+	MaxAttempts  int           `mapstructure:"max_attempts"  validate:"omitempty,min=1,max=25"`
 }
 
 type SMTP struct {
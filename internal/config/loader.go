@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -39,6 +40,7 @@ var forbiddenFileKeys = []string{
 	"database.password",
 	"database.sslmode",
 	"security.jwt_secret",
+	"oidc.signing_key",
 	"smtp.host",
 	"smtp.port",
 	"smtp.username",
@@ -101,7 +103,7 @@ func (l *Loader) LoadFromReader(r io.Reader) (*Config, error) {
 }
 
 func (l *Loader) ensureNoSensitiveKeysInFile() error {
-	for _, key := range forbiddenFileKeys {
+	for _, key := range slices.Concat(forbiddenFileKeys, l.providerSecretKeys()) {
 		if l.vip.InConfig(key) {
 			return fmt.Errorf("%w: key '%s' is not allowed in %s.%s", ErrSensitiveConfig, key, fileName, fileType)
 		}
@@ -110,7 +112,27 @@ func (l *Loader) ensureNoSensitiveKeysInFile() error {
 	return nil
 }
 
+// providerSecretKeys lists the client secret of every federation provider in the file.
+// Provider names are only known once the file is read, so they cannot be in forbiddenFileKeys.
+func (l *Loader) providerSecretKeys() []string {
+	providers := l.vip.GetStringMap("federation.providers")
+
+	keys := make([]string, 0, len(providers))
+	for name := range providers {
+		keys = append(keys, "federation.providers."+name+".client_secret")
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
 func (l *Loader) process() (*Config, error) {
+	envName := strings.NewReplacer(".", "_", "-", "_")
+	for _, key := range l.providerSecretKeys() {
+		_ = l.vip.BindEnv(key, strings.ToUpper(envName.Replace(key)))
+	}
+
 	var cfg Config
 	if err := l.vip.Unmarshal(&cfg); err != nil {
 		return nil, wrapError(ErrConfigUnmarshal, err)
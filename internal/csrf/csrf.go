@@ -0,0 +1,162 @@
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type Mode string
+
+const (
+	ModeDoubleSubmit Mode = "double_submit"
+	// ModeSigned derives the token from the session it protects, so a cookie planted
+	// by a sibling subdomain cannot be paired with a forged header.
+	ModeSigned Mode = "signed"
+)
+
+const (
+	DefaultCookieName = "csrf_token"
+	DefaultHeaderName = "X-CSRF-Token"
+
+	tokenLength = 32
+	signLabel   = "csrf:"
+)
+
+var (
+	ErrMissing     = errors.New("csrf: token missing")
+	ErrMismatch    = errors.New("csrf: token mismatch")
+	ErrKeyRequired = errors.New("csrf: key is required for signed mode")
+)
+
+type Config struct {
+	Mode       Mode
+	CookieName string
+	HeaderName string
+	Domain     string
+	Secure     bool
+	SameSite   http.SameSite
+	Key        []byte
+}
+
+type Protector struct {
+	cfg Config
+}
+
+func New(cfg Config) (*Protector, error) {
+	if cfg.Mode == "" {
+		cfg.Mode = ModeDoubleSubmit
+	}
+
+	switch cfg.Mode {
+	case ModeDoubleSubmit:
+	case ModeSigned:
+		if len(cfg.Key) == 0 {
+			return nil, ErrKeyRequired
+		}
+	default:
+		return nil, fmt.Errorf("csrf: unknown mode %q", cfg.Mode)
+	}
+
+	if cfg.CookieName == "" {
+		cfg.CookieName = DefaultCookieName
+	}
+
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = DefaultHeaderName
+	}
+
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+
+	return &Protector{cfg: cfg}, nil
+}
+
+func (p *Protector) HeaderName() string {
+	return p.cfg.HeaderName
+}
+
+func (p *Protector) Issue(w http.ResponseWriter, binding string, expires time.Time) (string, error) {
+	token, err := p.token(binding)
+	if err != nil {
+		return "", err
+	}
+
+	http.SetCookie(w, p.cookie(token, expires))
+
+	return token, nil
+}
+
+func (p *Protector) Verify(r *http.Request, binding string) error {
+	got := r.Header.Get(p.cfg.HeaderName)
+	if got == "" {
+		return ErrMissing
+	}
+
+	var want string
+
+	switch p.cfg.Mode {
+	case ModeSigned:
+		want = p.sign(binding)
+	default:
+		cookie, err := r.Cookie(p.cfg.CookieName)
+		if err != nil || cookie.Value == "" {
+			return ErrMissing
+		}
+
+		want = cookie.Value
+	}
+
+	if subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+		return ErrMismatch
+	}
+
+	return nil
+}
+
+func (p *Protector) Clear(w http.ResponseWriter) {
+	cookie := p.cookie("", time.Unix(0, 0))
+	cookie.MaxAge = -1
+
+	http.SetCookie(w, cookie)
+}
+
+func (p *Protector) token(binding string) (string, error) {
+	if p.cfg.Mode == ModeSigned {
+		return p.sign(binding), nil
+	}
+
+	b := make([]byte, tokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate csrf token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (p *Protector) sign(binding string) string {
+	mac := hmac.New(sha256.New, p.cfg.Key)
+	mac.Write([]byte(signLabel + binding))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (p *Protector) cookie(value string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     p.cfg.CookieName,
+		Value:    value,
+		Path:     "/",
+		Domain:   p.cfg.Domain,
+		Expires:  expires,
+		Secure:   p.cfg.Secure,
+		HttpOnly: false,
+		SameSite: p.cfg.SameSite,
+	}
+}
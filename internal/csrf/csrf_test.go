@@ -0,0 +1,170 @@
+package csrf_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/csrf"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     csrf.Config
+		wantErr bool
+		errIs   error
+	}{
+		{name: "defaults", cfg: csrf.Config{}},
+		{name: "signed", cfg: csrf.Config{Mode: csrf.ModeSigned, Key: []byte("key")}},
+		{name: "signed without key", cfg: csrf.Config{Mode: csrf.ModeSigned}, wantErr: true, errIs: csrf.ErrKeyRequired},
+		{name: "unknown mode", cfg: csrf.Config{Mode: "token"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := csrf.New(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				if tt.errIs != nil {
+					assert.ErrorIs(t, err, tt.errIs)
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, csrf.DefaultHeaderName, p.HeaderName())
+		})
+	}
+}
+
+func TestProtectorVerify(t *testing.T) {
+	t.Parallel()
+
+	const binding = "refresh-token"
+
+	tests := []struct {
+		name    string
+		mode    csrf.Mode
+		header  func(issued string) string
+		cookie  bool
+		wantErr error
+	}{
+		{
+			name:   "double submit match",
+			mode:   csrf.ModeDoubleSubmit,
+			header: func(issued string) string { return issued },
+			cookie: true,
+		},
+		{
+			name:    "double submit header missing",
+			mode:    csrf.ModeDoubleSubmit,
+			header:  func(string) string { return "" },
+			cookie:  true,
+			wantErr: csrf.ErrMissing,
+		},
+		{
+			name:    "double submit cookie missing",
+			mode:    csrf.ModeDoubleSubmit,
+			header:  func(issued string) string { return issued },
+			wantErr: csrf.ErrMissing,
+		},
+		{
+			name:    "double submit mismatch",
+			mode:    csrf.ModeDoubleSubmit,
+			header:  func(string) string { return "forged" },
+			cookie:  true,
+			wantErr: csrf.ErrMismatch,
+		},
+		{
+			name:   "signed match without cookie",
+			mode:   csrf.ModeSigned,
+			header: func(issued string) string { return issued },
+		},
+		{
+			name:    "signed mismatch",
+			mode:    csrf.ModeSigned,
+			header:  func(string) string { return "forged" },
+			cookie:  true,
+			wantErr: csrf.ErrMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := csrf.New(csrf.Config{Mode: tt.mode, Key: []byte("key"), Secure: true})
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			issued, err := p.Issue(rec, binding, time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			require.NotEmpty(t, issued)
+
+			cookies := rec.Result().Cookies()
+			require.Len(t, cookies, 1)
+			assert.Equal(t, csrf.DefaultCookieName, cookies[0].Name)
+			assert.Equal(t, issued, cookies[0].Value)
+			assert.False(t, cookies[0].HttpOnly)
+			assert.True(t, cookies[0].Secure)
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if h := tt.header(issued); h != "" {
+				req.Header.Set(csrf.DefaultHeaderName, h)
+			}
+
+			if tt.cookie {
+				req.AddCookie(cookies[0])
+			}
+
+			err = p.Verify(req, binding)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProtectorSignedBinding(t *testing.T) {
+	t.Parallel()
+
+	p, err := csrf.New(csrf.Config{Mode: csrf.ModeSigned, Key: []byte("key")})
+	require.NoError(t, err)
+
+	issued, err := p.Issue(httptest.NewRecorder(), "session-a", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(csrf.DefaultHeaderName, issued)
+
+	assert.NoError(t, p.Verify(req, "session-a"))
+	assert.ErrorIs(t, p.Verify(req, "session-b"), csrf.ErrMismatch)
+}
+
+func TestProtectorClear(t *testing.T) {
+	t.Parallel()
+
+	p, err := csrf.New(csrf.Config{})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	p.Clear(rec)
+
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+	assert.Empty(t, cookies[0].Value)
+}
@@ -0,0 +1,119 @@
+package device
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+	"strings"
+)
+
+const (
+	ipv4PrefixBits = 24
+	ipv6PrefixBits = 64
+
+	unknown = "Unknown"
+)
+
+type Info struct {
+	Family      string
+	Prefix      string
+	Fingerprint string
+}
+
+func Identify(userAgent, clientIP string) Info {
+	family := Family(userAgent)
+	prefix := Prefix(clientIP)
+
+	return Info{
+		Family:      family,
+		Prefix:      prefix,
+		Fingerprint: Fingerprint(family, prefix),
+	}
+}
+
+func Fingerprint(family, prefix string) string {
+	sum := sha256.Sum256([]byte(family + "|" + prefix))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func Prefix(clientIP string) string {
+	addr, err := netip.ParseAddr(strings.TrimSpace(clientIP))
+	if err != nil {
+		return ""
+	}
+
+	addr = addr.Unmap()
+
+	bits := ipv6PrefixBits
+	if addr.Is4() {
+		bits = ipv4PrefixBits
+	}
+
+	prefix, err := addr.WithZone("").Prefix(bits)
+	if err != nil {
+		return ""
+	}
+
+	return prefix.String()
+}
+
+func Family(userAgent string) string {
+	ua := strings.TrimSpace(userAgent)
+	if ua == "" {
+		return unknown
+	}
+
+	browser := match(ua, browserRules)
+	os := match(ua, osRules)
+
+	switch {
+	case browser == unknown && os == unknown:
+		return unknown
+	case os == unknown:
+		return browser
+	default:
+		return browser + " on " + os
+	}
+}
+
+type rule struct {
+	token string
+	name  string
+}
+
+var browserRules = []rule{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"Firefox/", "Firefox"},
+	{"FxiOS/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+	{"PostmanRuntime/", "Postman"},
+	{"okhttp/", "OkHttp"},
+	{"Go-http-client/", "Go HTTP client"},
+}
+
+var osRules = []rule{
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"Macintosh", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+func match(ua string, rules []rule) string {
+	for _, r := range rules {
+		if strings.Contains(ua, r.token) {
+			return r.name
+		}
+	}
+
+	return unknown
+}
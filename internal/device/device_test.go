@@ -0,0 +1,97 @@
+package device_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-auth/internal/device"
+)
+
+func TestFamily(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want string
+	}{
+		{"empty", "", "Unknown"},
+		{
+			"chrome on windows",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			"Chrome on Windows",
+		},
+		{
+			"edge on windows",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0",
+			"Edge on Windows",
+		},
+		{
+			"safari on ios",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+			"Safari on iOS",
+		},
+		{
+			"firefox on linux",
+			"Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0",
+			"Firefox on Linux",
+		},
+		{
+			"chrome on android",
+			"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36",
+			"Chrome on Android",
+		},
+		{"curl", "curl/8.6.0", "curl"},
+		{"unrecognised", "something-else", "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, device.Family(tt.ua))
+		})
+	}
+}
+
+func TestFamilyIgnoresVersion(t *testing.T) {
+	a := device.Family("Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0")
+	b := device.Family("Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0")
+	assert.Equal(t, a, b)
+}
+
+func TestPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{"ipv4", "203.0.113.57", "203.0.113.0/24"},
+		{"ipv4 mapped", "::ffff:203.0.113.57", "203.0.113.0/24"},
+		{"ipv6", "2001:db8:abcd:12:1:2:3:4", "2001:db8:abcd:12::/64"},
+		{"ipv6 zone", "fe80::1%eth0", "fe80::/64"},
+		{"invalid", "not-an-ip", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, device.Prefix(tt.ip))
+		})
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	ua := "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0"
+
+	a := device.Identify(ua, "198.51.100.10")
+	b := device.Identify(ua, "198.51.100.200")
+	c := device.Identify(ua, "198.51.101.10")
+
+	assert.Equal(t, "Firefox on Linux", a.Family)
+	assert.Equal(t, "198.51.100.0/24", a.Prefix)
+	assert.Len(t, a.Fingerprint, 64)
+	assert.Equal(t, a.Fingerprint, b.Fingerprint)
+	assert.NotEqual(t, a.Fingerprint, c.Fingerprint)
+}
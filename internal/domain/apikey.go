@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to recognise and scan for.
+const APIKeyPrefix = "ga_live_"
+
+const (
+	maxAPIKeyNameLength = 100
+	// apiKeyIDLength is the length of a key ID in hex; the secret that follows may contain
+	// underscores, so the ID must have a fixed length.
+	apiKeyIDLength = 32
+)
+
+type APIKey struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	SecretHash string
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func NewAPIKey(userID uuid.UUID, name, secretHash string, scopes []string, expiresAt time.Time) (*APIKey, error) {
+	if userID == uuid.Nil {
+		return nil, ErrUserIDRequired
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		return nil, ErrAPIKeyNameInvalid
+	}
+
+	if secretHash == "" {
+		return nil, ErrTokenRequired
+	}
+
+	normalized := make([]string, len(scopes))
+	for i, scope := range scopes {
+		perm, err := NewPermission(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		normalized[i] = perm.String()
+	}
+
+	now := time.Now().UTC()
+	if !expiresAt.After(now) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	return &APIKey{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       name,
+		SecretHash: secretHash,
+		Scopes:     slices.Compact(slices.Sorted(slices.Values(normalized))),
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+	}, nil
+}
+
+func (k *APIKey) IsExpired() bool {
+	return !k.ExpiresAt.After(time.Now().UTC())
+}
+
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+func (k *APIKey) IsActive() bool {
+	return !k.IsRevoked() && !k.IsExpired()
+}
+
+func (k *APIKey) Use() {
+	now := time.Now().UTC()
+	k.LastUsedAt = &now
+}
+
+func FormatAPIKey(id uuid.UUID, secret string) string {
+	return APIKeyPrefix + strings.ReplaceAll(id.String(), "-", "") + "_" + secret
+}
+
+func IsAPIKey(raw string) bool {
+	return strings.HasPrefix(raw, APIKeyPrefix)
+}
+
+func ParseAPIKey(raw string) (uuid.UUID, string, error) {
+	rest, ok := strings.CutPrefix(raw, APIKeyPrefix)
+	if !ok || len(rest) < apiKeyIDLength+2 || rest[apiKeyIDLength] != '_' {
+		return uuid.Nil, "", ErrAPIKeyInvalid
+	}
+
+	id, err := uuid.Parse(rest[:apiKeyIDLength])
+	if err != nil {
+		return uuid.Nil, "", ErrAPIKeyInvalid
+	}
+
+	return id, rest[apiKeyIDLength+1:], nil
+}
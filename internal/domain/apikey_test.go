@@ -0,0 +1,128 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	t.Parallel()
+
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		userID     uuid.UUID
+		keyName    string
+		secretHash string
+		scopes     []string
+		expiresAt  time.Time
+		wantScopes []string
+		wantErr    error
+	}{
+		{
+			name:       "valid",
+			userID:     uuid.New(),
+			keyName:    " deploy ",
+			secretHash: "hash",
+			scopes:     []string{"User:Write", "user:read", "user:write"},
+			expiresAt:  future,
+			wantScopes: []string{"user:read", "user:write"},
+		},
+		{
+			name:       "missing user",
+			keyName:    "deploy",
+			secretHash: "hash",
+			expiresAt:  future,
+			wantErr:    domain.ErrUserIDRequired,
+		},
+		{
+			name:       "blank name",
+			userID:     uuid.New(),
+			keyName:    "  ",
+			secretHash: "hash",
+			expiresAt:  future,
+			wantErr:    domain.ErrAPIKeyNameInvalid,
+		},
+		{
+			name:      "missing secret",
+			userID:    uuid.New(),
+			keyName:   "deploy",
+			expiresAt: future,
+			wantErr:   domain.ErrTokenRequired,
+		},
+		{
+			name:       "invalid scope",
+			userID:     uuid.New(),
+			keyName:    "deploy",
+			secretHash: "hash",
+			scopes:     []string{"everything"},
+			expiresAt:  future,
+			wantErr:    domain.ErrPermissionInvalid,
+		},
+		{
+			name:       "already expired",
+			userID:     uuid.New(),
+			keyName:    "deploy",
+			secretHash: "hash",
+			expiresAt:  time.Now().Add(-time.Minute),
+			wantErr:    domain.ErrAPIKeyExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key, err := domain.NewAPIKey(tt.userID, tt.keyName, tt.secretHash, tt.scopes, tt.expiresAt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "deploy", key.Name)
+			assert.Equal(t, tt.wantScopes, key.Scopes)
+			assert.True(t, key.IsActive())
+		})
+	}
+}
+
+func TestParseAPIKey(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.New()
+
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+
+		raw := domain.FormatAPIKey(id, "se_cr_et")
+		assert.True(t, domain.IsAPIKey(raw))
+
+		gotID, secret, err := domain.ParseAPIKey(raw)
+		require.NoError(t, err)
+		assert.Equal(t, id, gotID)
+		assert.Equal(t, "se_cr_et", secret)
+	})
+
+	for name, raw := range map[string]string{
+		"missing prefix": "eyJhbGciOiJSUzI1NiJ9.payload.signature",
+		"too short":      domain.APIKeyPrefix + "abc",
+		"missing secret": domain.FormatAPIKey(id, ""),
+		"invalid id":     domain.APIKeyPrefix + "zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz_secret",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := domain.ParseAPIKey(raw)
+			assert.ErrorIs(t, err, domain.ErrAPIKeyInvalid)
+		})
+	}
+}
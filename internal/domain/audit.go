@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditActionLogin          AuditAction = "auth.login"
+	AuditActionLoginFailed    AuditAction = "auth.login_failed"
+	AuditActionRefresh        AuditAction = "auth.refresh"
+	AuditActionLogout         AuditAction = "auth.logout"
+	AuditActionSessionBinding AuditAction = "auth.session_binding_mismatch"
+	AuditActionUserBanned     AuditAction = "user.banned"
+	AuditActionUserUnban      AuditAction = "user.unbanned"
+	AuditActionRoleChanged    AuditAction = "user.role_changed"
+	AuditActionOAuthClient    AuditAction = "oauth.client_created"
+	AuditActionOAuthRotate    AuditAction = "oauth.client_secret_rotated"
+	AuditActionOAuthDisable   AuditAction = "oauth.client_disabled"
+	AuditActionOAuthAuthorize AuditAction = "oauth.authorize"
+	AuditActionOAuthToken     AuditAction = "oauth.token"
+	AuditActionOAuthRevoke    AuditAction = "oauth.token_revoked"
+	AuditActionIdentityLinked AuditAction = "auth.identity_linked"
+	AuditActionMagicLink      AuditAction = "auth.magic_link_requested"
+	AuditActionPasskeyAdded   AuditAction = "auth.passkey_registered"
+	AuditActionPasskeyRemoved AuditAction = "auth.passkey_removed"
+	AuditActionMFAChallenge   AuditAction = "auth.mfa_challenged"
+	AuditActionMFAChanged     AuditAction = "user.mfa_changed"
+	AuditActionReauthenticate AuditAction = "auth.reauthenticated"
+	AuditActionImpersonate    AuditAction = "user.impersonated"
+	AuditActionOrgCreated     AuditAction = "organization.created"
+	AuditActionMemberSet      AuditAction = "organization.member_set"
+	AuditActionMemberRemoved  AuditAction = "organization.member_removed"
+	AuditActionOrgSwitched    AuditAction = "auth.organization_switched"
+	AuditActionInviteCreated  AuditAction = "organization.invitation_created"
+	AuditActionInviteRevoked  AuditAction = "organization.invitation_revoked"
+	AuditActionInviteAccepted AuditAction = "organization.invitation_accepted"
+	AuditActionAPIKeyCreated  AuditAction = "auth.api_key_created"
+	AuditActionAPIKeyRevoked  AuditAction = "auth.api_key_revoked"
+	AuditActionWebhookCreated AuditAction = "webhook.created"
+	AuditActionWebhookDisable AuditAction = "webhook.disabled"
+)
+
+func (a AuditAction) String() string {
+	return string(a)
+}
+
+type AuditOutcome string
+
+const (
+	AuditOutcomeSuccess AuditOutcome = "success"
+	AuditOutcomeFailure AuditOutcome = "failure"
+	AuditOutcomeDenied  AuditOutcome = "denied"
+)
+
+func (o AuditOutcome) String() string {
+	return string(o)
+}
+
+type AuditEvent struct {
+	ID        uuid.UUID
+	ActorID   *uuid.UUID
+	TargetID  *uuid.UUID
+	Action    AuditAction
+	Outcome   AuditOutcome
+	ClientIP  string
+	UserAgent string
+	Metadata  map[string]any
+	CreatedAt time.Time
+}
+
+func NewAuditEvent(action AuditAction, outcome AuditOutcome) *AuditEvent {
+	return &AuditEvent{
+		ID:        uuid.New(),
+		Action:    action,
+		Outcome:   outcome,
+		Metadata:  map[string]any{},
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (e *AuditEvent) WithActor(id uuid.UUID) *AuditEvent {
+	e.ActorID = &id
+
+	return e
+}
+
+func (e *AuditEvent) WithActorClaims(claims *AccessClaims) *AuditEvent {
+	switch {
+	case claims == nil:
+		return e
+	case claims.IsClient():
+		return e.WithMetadata(map[string]any{"actor_client_id": claims.ClientID})
+	case claims.IsImpersonated():
+		return e.WithActor(claims.UserID).
+			WithMetadata(map[string]any{"impersonator_id": claims.ImpersonatorID.String()})
+	case claims.APIKeyID != uuid.Nil:
+		return e.WithActor(claims.UserID).
+			WithMetadata(map[string]any{"api_key_id": claims.APIKeyID.String()})
+	default:
+		return e.WithActor(claims.UserID)
+	}
+}
+
+func (e *AuditEvent) WithTarget(id uuid.UUID) *AuditEvent {
+	e.TargetID = &id
+
+	return e
+}
+
+func (e *AuditEvent) WithClient(userAgent, clientIP string) *AuditEvent {
+	e.UserAgent = userAgent
+	e.ClientIP = clientIP
+
+	return e
+}
+
+func (e *AuditEvent) WithMetadata(metadata map[string]any) *AuditEvent {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]any, len(metadata))
+	}
+
+	maps.Copy(e.Metadata, metadata)
+
+	return e
+}
+
+// AuditLogger records audit events. Implementations used on request paths
+// must not block the caller for longer than it takes to enqueue the event.
+type AuditLogger interface {
+	Log(ctx context.Context, event *AuditEvent) error
+}
+
+type AuditFilter struct {
+	ActorID  *uuid.UUID
+	TargetID *uuid.UUID
+	Actions  []AuditAction
+	Since    *time.Time
+	Until    *time.Time
+	Limit    int
+	Offset   int
+}
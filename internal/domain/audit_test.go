@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+func TestNewAuditEvent(t *testing.T) {
+	actor := uuid.New()
+	target := uuid.New()
+
+	event := domain.NewAuditEvent(domain.AuditActionUserBanned, domain.AuditOutcomeSuccess).
+		WithActor(actor).
+		WithTarget(target).
+		WithClient("ua", "1.2.3.4").
+		WithMetadata(map[string]any{"reason": "spam"})
+
+	assert.NotEqual(t, uuid.Nil, event.ID)
+	assert.False(t, event.CreatedAt.IsZero())
+	require.NotNil(t, event.ActorID)
+	require.NotNil(t, event.TargetID)
+	assert.Equal(t, actor, *event.ActorID)
+	assert.Equal(t, target, *event.TargetID)
+	assert.Equal(t, "ua", event.UserAgent)
+	assert.Equal(t, "1.2.3.4", event.ClientIP)
+	assert.Equal(t, map[string]any{"reason": "spam"}, event.Metadata)
+}
+
+func TestAuditEventWithActorClaims(t *testing.T) {
+	userID := uuid.New()
+
+	event := domain.NewAuditEvent(domain.AuditActionUserBanned, domain.AuditOutcomeSuccess).
+		WithActorClaims(&domain.AccessClaims{UserID: userID})
+	require.NotNil(t, event.ActorID)
+	assert.Equal(t, userID, *event.ActorID)
+
+	event = domain.NewAuditEvent(domain.AuditActionUserBanned, domain.AuditOutcomeSuccess).
+		WithActorClaims(&domain.AccessClaims{SubjectType: domain.SubjectTypeClient, ClientID: "svc"})
+	assert.Nil(t, event.ActorID)
+	assert.Equal(t, "svc", event.Metadata["actor_client_id"])
+
+	adminID := uuid.New()
+
+	event = domain.NewAuditEvent(domain.AuditActionUserBanned, domain.AuditOutcomeSuccess).
+		WithActorClaims(&domain.AccessClaims{UserID: userID, ImpersonatorID: adminID})
+	require.NotNil(t, event.ActorID)
+	assert.Equal(t, userID, *event.ActorID)
+	assert.Equal(t, adminID.String(), event.Metadata["impersonator_id"])
+
+	event = domain.NewAuditEvent(domain.AuditActionUserBanned, domain.AuditOutcomeDenied).WithActorClaims(nil)
+	assert.Nil(t, event.ActorID)
+	assert.Empty(t, event.Metadata)
+}
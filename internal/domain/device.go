@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type KnownDevice struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Fingerprint string
+	UAFamily    string
+	IPPrefix    string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+func NewKnownDevice(userID uuid.UUID, fingerprint, uaFamily, ipPrefix string) (*KnownDevice, error) {
+	if userID == uuid.Nil {
+		return nil, ErrUserIDRequired
+	}
+
+	if fingerprint == "" {
+		return nil, ErrDeviceFingerprintRequired
+	}
+
+	now := time.Now().UTC()
+
+	return &KnownDevice{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Fingerprint: fingerprint,
+		UAFamily:    uaFamily,
+		IPPrefix:    ipPrefix,
+		FirstSeenAt: now,
+		LastSeenAt:  now,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+func TestNewKnownDevice(t *testing.T) {
+	userID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	tests := []struct {
+		name        string
+		userID      uuid.UUID
+		fingerprint string
+		wantErr     error
+	}{
+		{name: "valid", userID: userID, fingerprint: "fp", wantErr: nil},
+		{name: "nil user id", userID: uuid.Nil, fingerprint: "fp", wantErr: domain.ErrUserIDRequired},
+		{name: "empty fingerprint", userID: userID, fingerprint: "", wantErr: domain.ErrDeviceFingerprintRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := domain.NewKnownDevice(tt.userID, tt.fingerprint, "Firefox on Linux", "198.51.100.0/24")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, d)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, d.ID)
+			assert.Equal(t, tt.userID, d.UserID)
+			assert.Equal(t, d.FirstSeenAt, d.LastSeenAt)
+		})
+	}
+}
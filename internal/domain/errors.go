@@ -17,6 +17,12 @@ var (
 	ErrRoleRequired = errors.New("role is required")
 	ErrRoleInvalid  = errors.New("role is invalid")
 	ErrRoleScan     = errors.New("unsupported type for role")
+	ErrRoleBuiltIn  = errors.New("role is built-in")
+)
+
+var (
+	ErrPermissionRequired = errors.New("permission is required")
+	ErrPermissionInvalid  = errors.New("permission is invalid")
 )
 
 var (
@@ -34,18 +40,88 @@ var (
 	ErrUserNotBanned     = errors.New("user is not banned")
 	ErrUserNotActivated  = errors.New("user is not activated")
 	ErrUserVerified      = errors.New("user is verified")
+	ErrMFAFactorInvalid  = errors.New("MFA factor is invalid")
 )
 
 var (
 	ErrSessionExpired          = errors.New("session is expired")
 	ErrSessionRevoked          = errors.New("session is revoked")
+	ErrSessionIdle             = errors.New("session is idle")
+	ErrSessionLifetimeExceeded = errors.New("session lifetime exceeded")
 	ErrUserIDRequired          = errors.New("user ID is required")
 	ErrTokenRequired           = errors.New("token is required")
 	ErrTokenInvalid            = errors.New("token is invalid")
 	ErrTokenExpired            = errors.New("token is expired")
 	ErrTokenUsed               = errors.New("token is used")
 	ErrTokenTypeInvalid        = errors.New("token type is invalid")
+	ErrTokenAudienceInvalid    = errors.New("token audience is invalid")
 	ErrTokenSecretRequired     = errors.New("token secret is required")
 	ErrTokenAccessTTLRequired  = errors.New("access TTL must be positive")
 	ErrTokenRefreshTTLRequired = errors.New("refresh TTL must be positive")
 )
+
+var ErrDeviceFingerprintRequired = errors.New("device fingerprint is required")
+
+var (
+	ErrOAuthClientIDRequired          = errors.New("oauth client ID is required")
+	ErrOAuthClientNameRequired        = errors.New("oauth client name is required")
+	ErrOAuthClientSecretRequired      = errors.New("oauth client secret is required")
+	ErrOAuthClientPublic              = errors.New("oauth client is public and has no secret")
+	ErrOAuthClientDisabled            = errors.New("oauth client is disabled")
+	ErrRedirectURIRequired            = errors.New("redirect URI is required")
+	ErrRedirectURIInvalid             = errors.New("redirect URI is invalid")
+	ErrScopeInvalid                   = errors.New("scope is invalid")
+	ErrCodeChallengeRequired          = errors.New("code challenge is required")
+	ErrCodeChallengeInvalid           = errors.New("code challenge is invalid")
+	ErrCodeChallengeMethodUnsupported = errors.New("code challenge method is unsupported")
+	ErrCodeVerifierInvalid            = errors.New("code verifier is invalid")
+	ErrCodeVerifierMismatch           = errors.New("code verifier does not match challenge")
+	ErrNonceInvalid                   = errors.New("nonce is invalid")
+	ErrSigningKeyRequired             = errors.New("signing key is required")
+	ErrIssuerRequired                 = errors.New("issuer is required")
+	ErrIDTokenTTLRequired             = errors.New("ID token TTL must be positive")
+)
+
+var (
+	ErrProviderRequired        = errors.New("identity provider is required")
+	ErrProviderInvalid         = errors.New("identity provider name is invalid")
+	ErrIdentitySubjectRequired = errors.New("identity subject is required")
+	ErrIdentityAlreadyLinked   = errors.New("identity is already linked to a user")
+	ErrIDTokenInvalid          = errors.New("ID token is invalid")
+)
+
+var (
+	ErrPasskeyCredentialInvalid   = errors.New("passkey credential is invalid")
+	ErrPasskeyNameInvalid         = errors.New("passkey name must be 1 to 100 characters")
+	ErrPasskeyInvalid             = errors.New("passkey response is invalid")
+	ErrPasskeySignCount           = errors.New("passkey signature counter did not increase")
+	ErrPasskeyAlreadyRegistered   = errors.New("passkey is already registered")
+	ErrPasskeyAlgorithmNotAllowed = errors.New("passkey algorithm is not allowed")
+)
+
+var (
+	ErrOrganizationRequired     = errors.New("organization is required")
+	ErrOrganizationSlugRequired = errors.New("organization slug is required")
+	ErrOrganizationSlugInvalid  = errors.New("organization slug is invalid")
+	ErrOrganizationSlugTaken    = errors.New("organization slug is taken")
+	ErrOrganizationNameInvalid  = errors.New("organization name must be 1 to 100 characters")
+)
+
+var (
+	ErrInvitationExpired    = errors.New("invitation has expired")
+	ErrInvitationAccepted   = errors.New("invitation has already been accepted")
+	ErrInvitationRevoked    = errors.New("invitation has been revoked")
+	ErrInvitationNotPending = errors.New("invitation is no longer pending")
+)
+
+var (
+	ErrAPIKeyInvalid     = errors.New("API key is malformed")
+	ErrAPIKeyNameInvalid = errors.New("API key name must be 1 to 100 characters")
+	ErrAPIKeyExpired     = errors.New("API key has expired")
+)
+
+var (
+	ErrWebhookURLInvalid     = errors.New("webhook URL must be an absolute https URL")
+	ErrWebhookEventsRequired = errors.New("webhook must subscribe to at least one event")
+	ErrWebhookEventInvalid   = errors.New("webhook event type is unknown")
+)
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventName string
+
+const (
+	EventUserRegistered   EventName = "user.registered"
+	EventUserLoggedIn     EventName = "user.logged_in"
+	EventLoginFailed      EventName = "user.login_failed"
+	EventSessionRefreshed EventName = "session.refreshed"
+	EventSessionRevoked   EventName = "session.revoked"
+	EventUserBanned       EventName = "user.banned"
+	EventUserUnbanned     EventName = "user.unbanned"
+)
+
+func (n EventName) String() string {
+	return string(n)
+}
+
+type Event interface {
+	EventName() EventName
+	OccurredAt() time.Time
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+type UserRegistered struct {
+	UserID         uuid.UUID
+	OrganizationID *uuid.UUID
+	Verified       bool
+	Provider       string
+	At             time.Time
+}
+
+func (e UserRegistered) EventName() EventName  { return EventUserRegistered }
+func (e UserRegistered) OccurredAt() time.Time { return e.At }
+
+type UserLoggedIn struct {
+	UserID    uuid.UUID
+	Method    string
+	ClientIP  string
+	UserAgent string
+	At        time.Time
+}
+
+func (e UserLoggedIn) EventName() EventName  { return EventUserLoggedIn }
+func (e UserLoggedIn) OccurredAt() time.Time { return e.At }
+
+type LoginFailed struct {
+	UserID    *uuid.UUID
+	Login     string
+	Method    string
+	Reason    string
+	ClientIP  string
+	UserAgent string
+	At        time.Time
+}
+
+func (e LoginFailed) EventName() EventName  { return EventLoginFailed }
+func (e LoginFailed) OccurredAt() time.Time { return e.At }
+
+type SessionRefreshed struct {
+	UserID            uuid.UUID
+	SessionID         uuid.UUID
+	PreviousSessionID uuid.UUID
+	ClientID          string
+	At                time.Time
+}
+
+func (e SessionRefreshed) EventName() EventName  { return EventSessionRefreshed }
+func (e SessionRefreshed) OccurredAt() time.Time { return e.At }
+
+type SessionRevokeReason string
+
+const (
+	SessionRevokedLogout          SessionRevokeReason = "logout"
+	SessionRevokedByClient        SessionRevokeReason = "client_revoked"
+	SessionRevokedBindingMismatch SessionRevokeReason = "binding_mismatch"
+)
+
+type SessionRevoked struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	ClientID  string
+	Reason    SessionRevokeReason
+	At        time.Time
+}
+
+func (e SessionRevoked) EventName() EventName  { return EventSessionRevoked }
+func (e SessionRevoked) OccurredAt() time.Time { return e.At }
+
+type UserBanned struct {
+	UserID  uuid.UUID
+	ActorID uuid.UUID
+	At      time.Time
+}
+
+func (e UserBanned) EventName() EventName  { return EventUserBanned }
+func (e UserBanned) OccurredAt() time.Time { return e.At }
+
+type UserUnbanned struct {
+	UserID  uuid.UUID
+	ActorID uuid.UUID
+	At      time.Time
+}
+
+func (e UserUnbanned) EventName() EventName  { return EventUserUnbanned }
+func (e UserUnbanned) OccurredAt() time.Time { return e.At }
@@ -0,0 +1,38 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-auth/internal/domain"
+)
+
+func TestEventName(t *testing.T) {
+	t.Parallel()
+
+	at := time.Now()
+
+	tests := []struct {
+		event domain.Event
+		want  domain.EventName
+	}{
+		{domain.UserRegistered{At: at}, domain.EventUserRegistered},
+		{domain.UserLoggedIn{At: at}, domain.EventUserLoggedIn},
+		{domain.LoginFailed{At: at}, domain.EventLoginFailed},
+		{domain.SessionRefreshed{At: at}, domain.EventSessionRefreshed},
+		{domain.SessionRevoked{At: at}, domain.EventSessionRevoked},
+		{domain.UserBanned{At: at}, domain.EventUserBanned},
+		{domain.UserUnbanned{At: at}, domain.EventUserUnbanned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.event.EventName())
+			assert.Equal(t, at, tt.event.OccurredAt())
+		})
+	}
+}
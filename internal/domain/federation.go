@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var providerNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,49}$`)
+
+type UserIdentity struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+func NewUserIdentity(userID uuid.UUID, provider, subject, email string) (*UserIdentity, error) {
+	if userID == uuid.Nil {
+		return nil, ErrUserIDRequired
+	}
+
+	if err := ValidateProviderName(provider); err != nil {
+		return nil, err
+	}
+
+	if subject == "" {
+		return nil, ErrIdentitySubjectRequired
+	}
+
+	return &UserIdentity{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Provider:  provider,
+		Subject:   subject,
+		Email:     strings.TrimSpace(email),
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func ValidateProviderName(name string) error {
+	if name == "" {
+		return ErrProviderRequired
+	}
+
+	if !providerNamePattern.MatchString(name) {
+		return ErrProviderInvalid
+	}
+
+	return nil
+}
+
+type ExternalIdentity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	GivenName         string
+	FamilyName        string
+	PreferredUsername string
+}
+
+func (e *ExternalIdentity) Names() (string, string) {
+	given, family := strings.TrimSpace(e.GivenName), strings.TrimSpace(e.FamilyName)
+	if given != "" && family != "" {
+		return given, family
+	}
+
+	if first, rest, ok := strings.Cut(strings.TrimSpace(e.Name), " "); ok {
+		return strings.TrimSpace(first), strings.TrimSpace(rest)
+	}
+
+	return given, family
+}
+
+// FederatedLoginState is the server side of an authorization request sent to an identity
+// provider. It is looked up by the hash of the state parameter and used once.
+type FederatedLoginState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	UserID       *uuid.UUID
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+func NewFederatedLoginState(
+	stateHash, provider, nonce, codeVerifier string,
+	userID *uuid.UUID,
+	ttl time.Duration,
+) (*FederatedLoginState, error) {
+	if stateHash == "" {
+		return nil, ErrTokenRequired
+	}
+
+	if err := ValidateProviderName(provider); err != nil {
+		return nil, err
+	}
+
+	if nonce == "" {
+		return nil, ErrNonceInvalid
+	}
+
+	if _, err := PKCEChallengeFor(codeVerifier); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return &FederatedLoginState{
+		StateHash:    stateHash,
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		UserID:       userID,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}, nil
+}
+
+func (s *FederatedLoginState) IsExpired() bool {
+	return !s.ExpiresAt.After(time.Now().UTC())
+}
+
+func (s *FederatedLoginState) IsLink() bool {
+	return s.UserID != nil
+}
+
+type IdentityProvider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce string, pkce PKCEChallenge) (string, error)
+	// Exchange redeems the code and returns the identity from the verified ID token,
+	// whose nonce must equal nonce.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*ExternalIdentity, error)
+}
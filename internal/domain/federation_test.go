@@ -0,0 +1,105 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+func TestNewUserIdentity(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		provider string
+		subject  string
+		wantErr  error
+	}{
+		{name: "valid", userID: userID, provider: "google", subject: "1234"},
+		{name: "missing user", provider: "google", subject: "1234", wantErr: domain.ErrUserIDRequired},
+		{name: "missing provider", userID: userID, subject: "1234", wantErr: domain.ErrProviderRequired},
+		{name: "invalid provider", userID: userID, provider: "Go/ogle", subject: "1", wantErr: domain.ErrProviderInvalid},
+		{name: "missing subject", userID: userID, provider: "google", wantErr: domain.ErrIdentitySubjectRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewUserIdentity(tt.userID, tt.provider, tt.subject, " alice@example.com ")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, got.ID)
+			assert.Equal(t, "alice@example.com", got.Email)
+		})
+	}
+}
+
+func TestExternalIdentityNames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		identity   domain.ExternalIdentity
+		wantGiven  string
+		wantFamily string
+	}{
+		{
+			name:       "given and family",
+			identity:   domain.ExternalIdentity{GivenName: "Jane", FamilyName: "Doe", Name: "J D"},
+			wantGiven:  "Jane",
+			wantFamily: "Doe",
+		},
+		{
+			name:       "split name",
+			identity:   domain.ExternalIdentity{Name: "Jane van Doe"},
+			wantGiven:  "Jane",
+			wantFamily: "van Doe",
+		},
+		{name: "single name", identity: domain.ExternalIdentity{Name: "Jane"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			given, family := tt.identity.Names()
+			assert.Equal(t, tt.wantGiven, given)
+			assert.Equal(t, tt.wantFamily, family)
+		})
+	}
+}
+
+func TestNewFederatedLoginState(t *testing.T) {
+	t.Parallel()
+
+	state, err := domain.NewFederatedLoginState("hash", "google", "nonce", codeVerifier, nil, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, state.IsExpired())
+	assert.False(t, state.IsLink())
+
+	userID := uuid.New()
+	state, err = domain.NewFederatedLoginState("hash", "google", "nonce", codeVerifier, &userID, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, state.IsLink())
+
+	_, err = domain.NewFederatedLoginState("hash", "google", "", codeVerifier, nil, time.Minute)
+	require.ErrorIs(t, err, domain.ErrNonceInvalid)
+
+	_, err = domain.NewFederatedLoginState("hash", "google", "nonce", strings.Repeat("v", 10), nil, time.Minute)
+	require.ErrorIs(t, err, domain.ErrCodeVerifierInvalid)
+}
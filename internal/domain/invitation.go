@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+	InvitationStatusExpired  InvitationStatus = "expired"
+)
+
+func (s InvitationStatus) String() string {
+	return string(s)
+}
+
+// Invitation asks the owner of Email to join an organisation with Role. It is redeemed with
+// a single-use secret sent to Email, of which only the hash is stored in Token.
+type Invitation struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	InviterID      uuid.UUID
+	Email          Email
+	Role           Role
+	Token          string
+	ExpiresAt      time.Time
+	AcceptedAt     *time.Time
+	AcceptedBy     *uuid.UUID
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+}
+
+func NewInvitation(
+	organizationID, inviterID uuid.UUID,
+	email Email,
+	role Role,
+	tokenHash string,
+	expiresAt time.Time,
+) (*Invitation, error) {
+	if organizationID == uuid.Nil {
+		return nil, ErrOrganizationRequired
+	}
+
+	if inviterID == uuid.Nil {
+		return nil, ErrUserIDRequired
+	}
+
+	if email.IsZero() {
+		return nil, ErrEmailRequired
+	}
+
+	if role.IsZero() {
+		return nil, ErrRoleRequired
+	}
+
+	if tokenHash == "" {
+		return nil, ErrTokenRequired
+	}
+
+	now := time.Now().UTC()
+	if !expiresAt.After(now) {
+		return nil, ErrInvitationExpired
+	}
+
+	return &Invitation{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		InviterID:      inviterID,
+		Email:          email,
+		Role:           role,
+		Token:          tokenHash,
+		ExpiresAt:      expiresAt,
+		CreatedAt:      now,
+	}, nil
+}
+
+func (i *Invitation) IsExpired() bool {
+	return !i.ExpiresAt.After(time.Now().UTC())
+}
+
+func (i *Invitation) Status() InvitationStatus {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationStatusAccepted
+	case i.RevokedAt != nil:
+		return InvitationStatusRevoked
+	case i.IsExpired():
+		return InvitationStatusExpired
+	default:
+		return InvitationStatusPending
+	}
+}
+
+func (i *Invitation) Accept(userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return ErrUserIDRequired
+	}
+
+	if err := i.checkPending(); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	i.AcceptedAt = &now
+	i.AcceptedBy = &userID
+
+	return nil
+}
+
+func (i *Invitation) Revoke() error {
+	if err := i.checkPending(); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	i.RevokedAt = &now
+
+	return nil
+}
+
+func (i *Invitation) checkPending() error {
+	switch i.Status() {
+	case InvitationStatusAccepted:
+		return ErrInvitationAccepted
+	case InvitationStatusRevoked:
+		return ErrInvitationRevoked
+	case InvitationStatusExpired:
+		return ErrInvitationExpired
+	case InvitationStatusPending:
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+func mustInvitation(t *testing.T) *domain.Invitation {
+	t.Helper()
+
+	email, err := domain.NewEmail("bob@example.com")
+	require.NoError(t, err)
+
+	role, err := domain.NewRole(domain.RoleUser)
+	require.NoError(t, err)
+
+	invitation, err := domain.NewInvitation(uuid.New(), uuid.New(), email, role, "hash", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	return invitation
+}
+
+func TestNewInvitation(t *testing.T) {
+	t.Parallel()
+
+	email, err := domain.NewEmail("bob@example.com")
+	require.NoError(t, err)
+
+	role, err := domain.NewRole(domain.RoleUser)
+	require.NoError(t, err)
+
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		organizationID uuid.UUID
+		inviterID      uuid.UUID
+		email          domain.Email
+		role           domain.Role
+		tokenHash      string
+		expiresAt      time.Time
+		wantErr        error
+	}{
+		{
+			name:           "valid",
+			organizationID: uuid.New(),
+			inviterID:      uuid.New(),
+			email:          email,
+			role:           role,
+			tokenHash:      "hash",
+			expiresAt:      future,
+		},
+		{
+			name:      "missing organization",
+			inviterID: uuid.New(),
+			email:     email,
+			role:      role,
+			tokenHash: "hash",
+			expiresAt: future,
+			wantErr:   domain.ErrOrganizationRequired,
+		},
+		{
+			name:           "missing email",
+			organizationID: uuid.New(),
+			inviterID:      uuid.New(),
+			role:           role,
+			tokenHash:      "hash",
+			expiresAt:      future,
+			wantErr:        domain.ErrEmailRequired,
+		},
+		{
+			name:           "missing token",
+			organizationID: uuid.New(),
+			inviterID:      uuid.New(),
+			email:          email,
+			role:           role,
+			expiresAt:      future,
+			wantErr:        domain.ErrTokenRequired,
+		},
+		{
+			name:           "already expired",
+			organizationID: uuid.New(),
+			inviterID:      uuid.New(),
+			email:          email,
+			role:           role,
+			tokenHash:      "hash",
+			expiresAt:      time.Now().Add(-time.Minute),
+			wantErr:        domain.ErrInvitationExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			invitation, err := domain.NewInvitation(
+				tt.organizationID, tt.inviterID, tt.email, tt.role, tt.tokenHash, tt.expiresAt,
+			)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, domain.InvitationStatusPending, invitation.Status())
+		})
+	}
+}
+
+func TestInvitationLifecycle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accept", func(t *testing.T) {
+		t.Parallel()
+
+		invitation := mustInvitation(t)
+		userID := uuid.New()
+
+		require.NoError(t, invitation.Accept(userID))
+		assert.Equal(t, domain.InvitationStatusAccepted, invitation.Status())
+		assert.Equal(t, &userID, invitation.AcceptedBy)
+		assert.ErrorIs(t, invitation.Accept(uuid.New()), domain.ErrInvitationAccepted)
+		assert.ErrorIs(t, invitation.Revoke(), domain.ErrInvitationAccepted)
+	})
+	t.Run("revoke", func(t *testing.T) {
+		t.Parallel()
+
+		invitation := mustInvitation(t)
+
+		require.NoError(t, invitation.Revoke())
+		assert.Equal(t, domain.InvitationStatusRevoked, invitation.Status())
+		assert.ErrorIs(t, invitation.Accept(uuid.New()), domain.ErrInvitationRevoked)
+	})
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		invitation := mustInvitation(t)
+		invitation.ExpiresAt = time.Now().Add(-time.Second)
+
+		assert.Equal(t, domain.InvitationStatusExpired, invitation.Status())
+		assert.ErrorIs(t, invitation.Accept(uuid.New()), domain.ErrInvitationExpired)
+		assert.ErrorIs(t, invitation.Revoke(), domain.ErrInvitationExpired)
+	})
+}
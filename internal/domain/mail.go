@@ -0,0 +1,13 @@
+package domain
+
+import "context"
+
+type EmailMessage struct {
+	To      Email
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg *EmailMessage) error
+}
@@ -0,0 +1,25 @@
+package domain
+
+type MFAFactor string
+
+const (
+	MFAFactorNone     MFAFactor = ""
+	MFAFactorEmailOTP MFAFactor = "email_otp"
+)
+
+func (f MFAFactor) String() string {
+	return string(f)
+}
+
+func (f MFAFactor) IsValid() bool {
+	return f == MFAFactorNone || f == MFAFactorEmailOTP
+}
+
+func (f MFAFactor) ChallengeTokenType() TokenType {
+	switch f {
+	case MFAFactorEmailOTP:
+		return TokenTypeEmailOTP
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,352 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+
+	ResponseTypeCode = "code"
+
+	TokenTypeAccessToken  = "access_token"
+	TokenTypeRefreshToken = "refresh_token"
+
+	// PKCEMethodS256 is the only accepted code_challenge_method; "plain" is rejected.
+	PKCEMethodS256 = "S256"
+)
+
+var scopePattern = regexp.MustCompile(`^[\x21\x23-\x5B\x5D-\x7E]+$`)
+
+var codeVerifierPattern = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
+const s256ChallengeLength = 43
+
+type OAuthClient struct {
+	ID   string
+	Name string
+	// SecretHash is empty for public clients, which must rely on PKCE alone.
+	SecretHash   string
+	RedirectURIs []string
+	Scopes       []string
+	GrantTypes   []string
+	DisabledAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func NewOAuthClient(id, name, secretHash string, redirectURIs, scopes []string) (*OAuthClient, error) {
+	if id == "" {
+		return nil, ErrOAuthClientIDRequired
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrOAuthClientNameRequired
+	}
+
+	if len(redirectURIs) == 0 {
+		return nil, ErrRedirectURIRequired
+	}
+
+	for _, uri := range redirectURIs {
+		if err := validateRedirectURI(uri); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateScopes(scopes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return &OAuthClient{
+		ID:           id,
+		Name:         name,
+		SecretHash:   secretHash,
+		RedirectURIs: slices.Compact(slices.Sorted(slices.Values(redirectURIs))),
+		Scopes:       slices.Compact(slices.Sorted(slices.Values(scopes))),
+		GrantTypes:   []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+func NewServiceClient(id, name, secretHash string, scopes []string) (*OAuthClient, error) {
+	if id == "" {
+		return nil, ErrOAuthClientIDRequired
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrOAuthClientNameRequired
+	}
+
+	if secretHash == "" {
+		return nil, ErrOAuthClientSecretRequired
+	}
+
+	if err := validateScopes(scopes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	return &OAuthClient{
+		ID:           id,
+		Name:         name,
+		SecretHash:   secretHash,
+		RedirectURIs: []string{},
+		Scopes:       slices.Compact(slices.Sorted(slices.Values(scopes))),
+		GrantTypes:   []string{GrantTypeClientCredentials},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+func validateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !scopePattern.MatchString(scope) {
+			return ErrScopeInvalid
+		}
+	}
+
+	return nil
+}
+
+// validateRedirectURI accepts absolute https URIs, and http only on loopback
+// hosts for native apps (RFC 8252 section 7.3). Fragments are not allowed.
+func validateRedirectURI(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.Fragment != "" || u.User != nil {
+		return ErrRedirectURIInvalid
+	}
+
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := u.Hostname()
+		if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+			return nil
+		}
+	}
+
+	return ErrRedirectURIInvalid
+}
+
+func (c *OAuthClient) IsPublic() bool {
+	return c.SecretHash == ""
+}
+
+func (c *OAuthClient) IsDisabled() bool {
+	return c.DisabledAt != nil
+}
+
+func (c *OAuthClient) AllowsGrantType(grantType string) bool {
+	return slices.Contains(c.GrantTypes, grantType)
+}
+
+// RotateSecret replaces the secret; the previous one stops working immediately.
+func (c *OAuthClient) RotateSecret(secretHash string) error {
+	if c.IsPublic() {
+		return ErrOAuthClientPublic
+	}
+
+	if secretHash == "" {
+		return ErrOAuthClientSecretRequired
+	}
+
+	c.SecretHash = secretHash
+	c.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (c *OAuthClient) Disable() error {
+	if c.IsDisabled() {
+		return ErrOAuthClientDisabled
+	}
+
+	now := time.Now().UTC()
+	c.DisabledAt = &now
+	c.UpdatedAt = now
+
+	return nil
+}
+
+// AllowsRedirectURI requires an exact match against a registered URI.
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+func (c *OAuthClient) GrantScopes(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return slices.Clone(c.Scopes), nil
+	}
+
+	for _, scope := range requested {
+		if !slices.Contains(c.Scopes, scope) {
+			return nil, ErrScopeInvalid
+		}
+	}
+
+	return slices.Compact(slices.Sorted(slices.Values(requested))), nil
+}
+
+type PKCEChallenge struct {
+	Challenge string
+	Method    string
+}
+
+func NewPKCEChallenge(challenge, method string) (PKCEChallenge, error) {
+	if challenge == "" {
+		return PKCEChallenge{}, ErrCodeChallengeRequired
+	}
+
+	if method != PKCEMethodS256 {
+		return PKCEChallenge{}, ErrCodeChallengeMethodUnsupported
+	}
+
+	if len(challenge) != s256ChallengeLength {
+		return PKCEChallenge{}, ErrCodeChallengeInvalid
+	}
+
+	if _, err := base64.RawURLEncoding.DecodeString(challenge); err != nil {
+		return PKCEChallenge{}, ErrCodeChallengeInvalid
+	}
+
+	return PKCEChallenge{Challenge: challenge, Method: method}, nil
+}
+
+func PKCEChallengeFor(verifier string) (PKCEChallenge, error) {
+	if !codeVerifierPattern.MatchString(verifier) {
+		return PKCEChallenge{}, ErrCodeVerifierInvalid
+	}
+
+	return PKCEChallenge{Challenge: s256(verifier), Method: PKCEMethodS256}, nil
+}
+
+func NewCodeVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate code verifier: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (p PKCEChallenge) Verify(verifier string) error {
+	if !codeVerifierPattern.MatchString(verifier) {
+		return ErrCodeVerifierInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(s256(verifier)), []byte(p.Challenge)) != 1 {
+		return ErrCodeVerifierMismatch
+	}
+
+	return nil
+}
+
+func s256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+type AuthorizationCode struct {
+	CodeHash    string
+	ClientID    string
+	UserID      uuid.UUID
+	RedirectURI string
+	Scopes      []string
+	PKCE        PKCEChallenge
+	Nonce       string
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+	CreatedAt   time.Time
+}
+
+func NewAuthorizationCode(
+	codeHash, clientID string,
+	userID uuid.UUID,
+	redirectURI string,
+	scopes []string,
+	pkce PKCEChallenge,
+	expiresAt time.Time,
+) (*AuthorizationCode, error) {
+	if codeHash == "" {
+		return nil, ErrTokenRequired
+	}
+
+	if clientID == "" {
+		return nil, ErrOAuthClientIDRequired
+	}
+
+	if userID == uuid.Nil {
+		return nil, ErrUserIDRequired
+	}
+
+	if redirectURI == "" {
+		return nil, ErrRedirectURIRequired
+	}
+
+	if pkce.Challenge == "" {
+		return nil, ErrCodeChallengeRequired
+	}
+
+	now := time.Now().UTC()
+	if !expiresAt.After(now) {
+		return nil, ErrTokenExpired
+	}
+
+	return &AuthorizationCode{
+		CodeHash:    codeHash,
+		ClientID:    clientID,
+		UserID:      userID,
+		RedirectURI: redirectURI,
+		Scopes:      slices.Clone(scopes),
+		PKCE:        pkce,
+		ExpiresAt:   expiresAt,
+		UsedAt:      nil,
+		CreatedAt:   now,
+	}, nil
+}
+
+func (c *AuthorizationCode) IsExpired() bool {
+	return !c.ExpiresAt.After(time.Now().UTC())
+}
+
+func (c *AuthorizationCode) IsUsed() bool {
+	return c.UsedAt != nil
+}
+
+func (c *AuthorizationCode) Use() error {
+	if c.IsUsed() {
+		return ErrTokenUsed
+	}
+
+	if c.IsExpired() {
+		return ErrTokenExpired
+	}
+
+	now := time.Now().UTC()
+	c.UsedAt = &now
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package domain_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+const codeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+func s256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestNewOAuthClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		id       string
+		clientNm string
+		uris     []string
+		scopes   []string
+		wantErr  error
+	}{
+		{"valid", "c1", "App", []string{"https://app.example.com/cb"}, []string{"profile"}, nil},
+		{"loopback http", "c1", "CLI", []string{"http://127.0.0.1:8080/cb", "http://localhost/cb"}, nil, nil},
+		{"missing id", "", "App", []string{"https://app.example.com/cb"}, nil, domain.ErrOAuthClientIDRequired},
+		{"missing name", "c1", "  ", []string{"https://app.example.com/cb"}, nil, domain.ErrOAuthClientNameRequired},
+		{"no redirect uris", "c1", "App", nil, nil, domain.ErrRedirectURIRequired},
+		{"plain http", "c1", "App", []string{"http://app.example.com/cb"}, nil, domain.ErrRedirectURIInvalid},
+		{"relative uri", "c1", "App", []string{"/cb"}, nil, domain.ErrRedirectURIInvalid},
+		{"fragment", "c1", "App", []string{"https://app.example.com/cb#x"}, nil, domain.ErrRedirectURIInvalid},
+		{"custom scheme", "c1", "App", []string{"com.example.app:/cb"}, nil, domain.ErrRedirectURIInvalid},
+		{"scope with space", "c1", "App", []string{"https://app.example.com/cb"}, []string{"a b"}, domain.ErrScopeInvalid},
+		{"scope with quote", "c1", "App", []string{"https://app.example.com/cb"}, []string{`a"b`}, domain.ErrScopeInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := domain.NewOAuthClient(tt.id, tt.clientNm, "", tt.uris, tt.scopes)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, client.IsPublic())
+		})
+	}
+}
+
+func TestOAuthClientRedirectAndScopes(t *testing.T) {
+	t.Parallel()
+
+	client, err := domain.NewOAuthClient("c1", "App", "secret-hash",
+		[]string{"https://app.example.com/cb"}, []string{"user:read", "profile"})
+	require.NoError(t, err)
+
+	assert.False(t, client.IsPublic())
+	assert.True(t, client.AllowsRedirectURI("https://app.example.com/cb"))
+	assert.False(t, client.AllowsRedirectURI("https://app.example.com/cb/"))
+	assert.False(t, client.AllowsRedirectURI("https://app.example.com/cb?x=1"))
+
+	granted, err := client.GrantScopes(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"profile", "user:read"}, granted)
+
+	granted, err = client.GrantScopes([]string{"user:read", "user:read"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user:read"}, granted)
+
+	_, err = client.GrantScopes([]string{"profile", "user:delete"})
+	assert.ErrorIs(t, err, domain.ErrScopeInvalid)
+}
+
+func TestNewServiceClient(t *testing.T) {
+	t.Parallel()
+
+	_, err := domain.NewServiceClient("svc", "Billing", "", []string{"user:read"})
+	require.ErrorIs(t, err, domain.ErrOAuthClientSecretRequired)
+
+	_, err = domain.NewServiceClient("svc", "Billing", "secret-hash", []string{"a b"})
+	require.ErrorIs(t, err, domain.ErrScopeInvalid)
+
+	client, err := domain.NewServiceClient("svc", "Billing", "secret-hash", []string{"user:read"})
+	require.NoError(t, err)
+
+	assert.False(t, client.IsPublic())
+	assert.Empty(t, client.RedirectURIs)
+	assert.True(t, client.AllowsGrantType(domain.GrantTypeClientCredentials))
+	assert.False(t, client.AllowsGrantType(domain.GrantTypeAuthorizationCode))
+	assert.False(t, client.AllowsGrantType(domain.GrantTypeRefreshToken))
+}
+
+func TestOAuthClientSecretAndDisable(t *testing.T) {
+	t.Parallel()
+
+	public, err := domain.NewOAuthClient("c1", "SPA", "", []string{"https://app.example.com/cb"}, nil)
+	require.NoError(t, err)
+	assert.True(t, public.AllowsGrantType(domain.GrantTypeAuthorizationCode))
+	assert.False(t, public.AllowsGrantType(domain.GrantTypeClientCredentials))
+	assert.ErrorIs(t, public.RotateSecret("new-hash"), domain.ErrOAuthClientPublic)
+
+	client, err := domain.NewServiceClient("svc", "Billing", "old-hash", nil)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, client.RotateSecret(""), domain.ErrOAuthClientSecretRequired)
+	require.NoError(t, client.RotateSecret("new-hash"))
+	assert.Equal(t, "new-hash", client.SecretHash)
+
+	assert.False(t, client.IsDisabled())
+	require.NoError(t, client.Disable())
+	assert.True(t, client.IsDisabled())
+	assert.ErrorIs(t, client.Disable(), domain.ErrOAuthClientDisabled)
+}
+
+func TestPKCEChallenge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects plain and malformed challenges", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := domain.NewPKCEChallenge("", domain.PKCEMethodS256)
+		assert.ErrorIs(t, err, domain.ErrCodeChallengeRequired)
+
+		_, err = domain.NewPKCEChallenge(codeVerifier, "plain")
+		assert.ErrorIs(t, err, domain.ErrCodeChallengeMethodUnsupported)
+
+		_, err = domain.NewPKCEChallenge("short", domain.PKCEMethodS256)
+		assert.ErrorIs(t, err, domain.ErrCodeChallengeInvalid)
+
+		_, err = domain.NewPKCEChallenge(s256(codeVerifier)[:42]+"=", domain.PKCEMethodS256)
+		assert.ErrorIs(t, err, domain.ErrCodeChallengeInvalid)
+	})
+
+	t.Run("verifies S256", func(t *testing.T) {
+		t.Parallel()
+
+		pkce, err := domain.NewPKCEChallenge(s256(codeVerifier), domain.PKCEMethodS256)
+		require.NoError(t, err)
+
+		require.NoError(t, pkce.Verify(codeVerifier))
+		assert.ErrorIs(t, pkce.Verify("x"+codeVerifier[1:]), domain.ErrCodeVerifierMismatch)
+		assert.ErrorIs(t, pkce.Verify("too-short"), domain.ErrCodeVerifierInvalid)
+		assert.ErrorIs(t, pkce.Verify(codeVerifier+"!"), domain.ErrCodeVerifierInvalid)
+	})
+
+	t.Run("derives S256 for a verifier", func(t *testing.T) {
+		t.Parallel()
+
+		pkce, err := domain.PKCEChallengeFor(codeVerifier)
+		require.NoError(t, err)
+		assert.Equal(t, domain.PKCEChallenge{Challenge: s256(codeVerifier), Method: domain.PKCEMethodS256}, pkce)
+
+		_, err = domain.PKCEChallengeFor("too-short")
+		assert.ErrorIs(t, err, domain.ErrCodeVerifierInvalid)
+	})
+
+	t.Run("generated verifiers are valid and distinct", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := domain.NewCodeVerifier()
+		require.NoError(t, err)
+
+		second, err := domain.NewCodeVerifier()
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+
+		pkce, err := domain.PKCEChallengeFor(first)
+		require.NoError(t, err)
+		assert.NoError(t, pkce.Verify(first))
+	})
+}
+
+func TestAuthorizationCodeUse(t *testing.T) {
+	t.Parallel()
+
+	pkce, err := domain.NewPKCEChallenge(s256(codeVerifier), domain.PKCEMethodS256)
+	require.NoError(t, err)
+
+	userID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	_, err = domain.NewAuthorizationCode("h", "c1", userID, "https://app.example.com/cb", nil, pkce,
+		time.Now().UTC().Add(-time.Second))
+	assert.ErrorIs(t, err, domain.ErrTokenExpired)
+
+	_, err = domain.NewAuthorizationCode("h", "c1", userID, "https://app.example.com/cb", nil, domain.PKCEChallenge{},
+		time.Now().UTC().Add(time.Minute))
+	assert.ErrorIs(t, err, domain.ErrCodeChallengeRequired)
+
+	code, err := domain.NewAuthorizationCode("h", "c1", userID, "https://app.example.com/cb", []string{"profile"}, pkce,
+		time.Now().UTC().Add(time.Minute))
+	require.NoError(t, err)
+
+	require.NoError(t, code.Use())
+	assert.True(t, code.IsUsed())
+	assert.ErrorIs(t, code.Use(), domain.ErrTokenUsed)
+
+	code.UsedAt = nil
+	code.ExpiresAt = time.Now().UTC().Add(-time.Second)
+	assert.ErrorIs(t, code.Use(), domain.ErrTokenExpired)
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+const maxNonceLength = 255
+
+type UserInfo struct {
+	Subject           string
+	Name              string
+	GivenName         string
+	FamilyName        string
+	PreferredUsername string
+	Email             string
+	EmailVerified     *bool
+}
+
+func NewUserInfo(user *User, scopes []string) UserInfo {
+	info := UserInfo{Subject: user.ID.String()}
+
+	if slices.Contains(scopes, ScopeProfile) {
+		info.Name = strings.TrimSpace(user.FullName())
+		info.GivenName = user.FirstName
+		info.FamilyName = user.LastName
+		info.PreferredUsername = user.Username.String()
+	}
+
+	if slices.Contains(scopes, ScopeEmail) {
+		verified := user.IsVerified()
+		info.Email = user.Email.String()
+		info.EmailVerified = &verified
+	}
+
+	return info
+}
+
+type IDClaims struct {
+	UserInfo
+
+	ClientID string
+	Nonce    string
+}
+
+func ValidateNonce(nonce string) error {
+	if len(nonce) > maxNonceLength {
+		return ErrNonceInvalid
+	}
+
+	return nil
+}
+
+type JSONWebKey struct {
+	KeyType   string
+	KeyID     string
+	Use       string
+	Algorithm string
+	N         string
+	E         string
+}
+
+type IDTokenManager interface {
+	Generate(claims IDClaims) (string, error)
+	PublicKeys() []JSONWebKey
+	Algorithm() string
+	TTL() time.Duration
+}
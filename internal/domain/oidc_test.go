@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+func TestNewUserInfo(t *testing.T) {
+	t.Parallel()
+
+	user := mustUser(t)
+
+	t.Run("openid only", func(t *testing.T) {
+		t.Parallel()
+
+		info := domain.NewUserInfo(user, []string{domain.ScopeOpenID})
+		assert.Equal(t, domain.UserInfo{Subject: user.ID.String()}, info)
+	})
+
+	t.Run("profile and email", func(t *testing.T) {
+		t.Parallel()
+
+		info := domain.NewUserInfo(user, []string{domain.ScopeOpenID, domain.ScopeProfile, domain.ScopeEmail})
+		assert.Equal(t, "Alice Doe", info.Name)
+		assert.Equal(t, userTestFirstName, info.GivenName)
+		assert.Equal(t, userTestLastName, info.FamilyName)
+		assert.Equal(t, userTestUsername, info.PreferredUsername)
+		assert.Equal(t, userTestEmail, info.Email)
+		require.NotNil(t, info.EmailVerified)
+		assert.False(t, *info.EmailVerified)
+	})
+}
+
+func TestValidateNonce(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, domain.ValidateNonce(""))
+	require.NoError(t, domain.ValidateNonce("n-0S6_WzA2Mj"))
+	assert.ErrorIs(t, domain.ValidateNonce(strings.Repeat("n", 256)), domain.ErrNonceInvalid)
+}
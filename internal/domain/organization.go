@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxOrganizationNameLength = 100
+
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}[a-z0-9]$`)
+
+type Organization struct {
+	ID        uuid.UUID
+	Slug      string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewOrganization(slug, name string) (*Organization, error) {
+	normalized, err := NormalizeOrganizationSlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxOrganizationNameLength {
+		return nil, ErrOrganizationNameInvalid
+	}
+
+	now := time.Now().UTC()
+
+	return &Organization{
+		ID:        uuid.New(),
+		Slug:      normalized,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func NormalizeOrganizationSlug(slug string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(slug))
+	if normalized == "" {
+		return "", ErrOrganizationSlugRequired
+	}
+
+	if !organizationSlugPattern.MatchString(normalized) {
+		return "", ErrOrganizationSlugInvalid
+	}
+
+	return normalized, nil
+}
+
+// OrganizationMember grants a user Role within an organisation. The role applies only to
+// access tokens scoped to the organisation; the user's own Role applies everywhere else.
+type OrganizationMember struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	Role           Role
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func NewOrganizationMember(organizationID, userID uuid.UUID, role Role) (*OrganizationMember, error) {
+	if organizationID == uuid.Nil {
+		return nil, ErrOrganizationRequired
+	}
+
+	if userID == uuid.Nil {
+		return nil, ErrUserIDRequired
+	}
+
+	if role.IsZero() {
+		return nil, ErrRoleRequired
+	}
+
+	now := time.Now().UTC()
+
+	return &OrganizationMember{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Role:           role,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+type OrganizationMembership struct {
+	Organization *Organization
+	Role         Role
+	JoinedAt     time.Time
+}
@@ -0,0 +1,77 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+func TestNewOrganization(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		slug     string
+		orgName  string
+		wantSlug string
+		wantErr  error
+	}{
+		{name: "valid", slug: " Acme-Corp ", orgName: " Acme Corp ", wantSlug: "acme-corp"},
+		{name: "missing slug", slug: " ", orgName: "Acme", wantErr: domain.ErrOrganizationSlugRequired},
+		{name: "slug too short", slug: "ab", orgName: "Acme", wantErr: domain.ErrOrganizationSlugInvalid},
+		{
+			name:    "slug too long",
+			slug:    strings.Repeat("a", 65),
+			orgName: "Acme",
+			wantErr: domain.ErrOrganizationSlugInvalid,
+		},
+		{name: "slug with trailing hyphen", slug: "acme-", orgName: "Acme", wantErr: domain.ErrOrganizationSlugInvalid},
+		{name: "slug with underscore", slug: "acme_corp", orgName: "Acme", wantErr: domain.ErrOrganizationSlugInvalid},
+		{name: "missing name", slug: "acme", orgName: " ", wantErr: domain.ErrOrganizationNameInvalid},
+		{name: "name too long", slug: "acme", orgName: strings.Repeat("a", 101), wantErr: domain.ErrOrganizationNameInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			organization, err := domain.NewOrganization(tt.slug, tt.orgName)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, organization)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, organization.ID)
+			assert.Equal(t, tt.wantSlug, organization.Slug)
+			assert.Equal(t, "Acme Corp", organization.Name)
+		})
+	}
+}
+
+func TestNewOrganizationMember(t *testing.T) {
+	t.Parallel()
+
+	role, err := domain.NewRole(domain.RoleAdmin)
+	require.NoError(t, err)
+
+	member, err := domain.NewOrganizationMember(uuid.New(), uuid.New(), role)
+	require.NoError(t, err)
+	assert.Equal(t, role, member.Role)
+
+	_, err = domain.NewOrganizationMember(uuid.Nil, uuid.New(), role)
+	assert.ErrorIs(t, err, domain.ErrOrganizationRequired)
+
+	_, err = domain.NewOrganizationMember(uuid.New(), uuid.Nil, role)
+	assert.ErrorIs(t, err, domain.ErrUserIDRequired)
+
+	_, err = domain.NewOrganizationMember(uuid.New(), uuid.New(), domain.Role{})
+	assert.ErrorIs(t, err, domain.ErrRoleRequired)
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxPasskeyNameLength   = 100
+	maxPasskeyCredentialID = 1023
+)
+
+type PasskeyCeremony string
+
+const (
+	PasskeyCeremonyRegistration PasskeyCeremony = "registration"
+	PasskeyCeremonyLogin        PasskeyCeremony = "login"
+)
+
+func (c PasskeyCeremony) String() string {
+	return string(c)
+}
+
+type PasskeyCredential struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	CredentialID []byte
+	PublicKey    []byte
+	SignCount    uint32
+	AAGUID       []byte
+	Transports   []string
+	Name         string
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
+
+func NewPasskeyCredential(userID uuid.UUID, verified *VerifiedPasskey, name string) (*PasskeyCredential, error) {
+	if userID == uuid.Nil {
+		return nil, ErrUserIDRequired
+	}
+
+	if len(verified.CredentialID) == 0 || len(verified.CredentialID) > maxPasskeyCredentialID {
+		return nil, ErrPasskeyCredentialInvalid
+	}
+
+	if len(verified.PublicKey) == 0 {
+		return nil, ErrPasskeyCredentialInvalid
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxPasskeyNameLength {
+		return nil, ErrPasskeyNameInvalid
+	}
+
+	return &PasskeyCredential{
+		ID:           uuid.New(),
+		UserID:       userID,
+		CredentialID: verified.CredentialID,
+		PublicKey:    verified.PublicKey,
+		SignCount:    verified.SignCount,
+		AAGUID:       verified.AAGUID,
+		Transports:   verified.Transports,
+		Name:         name,
+		CreatedAt:    time.Now().UTC(),
+	}, nil
+}
+
+// Use records an assertion with the authenticator's signature counter. A counter that did
+// not increase means the credential may have been cloned, unless the authenticator does
+// not implement one and always reports zero.
+func (c *PasskeyCredential) Use(signCount uint32) error {
+	if (signCount != 0 || c.SignCount != 0) && signCount <= c.SignCount {
+		return ErrPasskeySignCount
+	}
+
+	now := time.Now().UTC()
+	c.SignCount = signCount
+	c.LastUsedAt = &now
+
+	return nil
+}
+
+// PasskeyChallenge is the server side of a WebAuthn ceremony. It is looked up by the hash of
+// the challenge the client echoes in its client data and used once.
+type PasskeyChallenge struct {
+	ChallengeHash string
+	Ceremony      PasskeyCeremony
+	UserID        *uuid.UUID
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
+
+func NewPasskeyChallenge(
+	challengeHash string,
+	ceremony PasskeyCeremony,
+	userID *uuid.UUID,
+	ttl time.Duration,
+) (*PasskeyChallenge, error) {
+	if challengeHash == "" {
+		return nil, ErrTokenRequired
+	}
+
+	if ceremony == PasskeyCeremonyRegistration && userID == nil {
+		return nil, ErrUserIDRequired
+	}
+
+	now := time.Now().UTC()
+
+	return &PasskeyChallenge{
+		ChallengeHash: challengeHash,
+		Ceremony:      ceremony,
+		UserID:        userID,
+		ExpiresAt:     now.Add(ttl),
+		CreatedAt:     now,
+	}, nil
+}
+
+func (c *PasskeyChallenge) IsExpired() bool {
+	return !c.ExpiresAt.After(time.Now().UTC())
+}
+
+type PasskeyAttestation struct {
+	ClientDataJSON    []byte
+	AttestationObject []byte
+	Transports        []string
+}
+
+type PasskeyAssertion struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+	UserHandle        []byte
+}
+
+type VerifiedPasskey struct {
+	CredentialID []byte
+	PublicKey    []byte
+	SignCount    uint32
+	AAGUID       []byte
+	Transports   []string
+}
+
+type PasskeyVerifier interface {
+	RelyingParty() (id, name string)
+	Algorithms() []int
+	// ClientChallenge returns the challenge in a response's client data, without verifying anything.
+	ClientChallenge(clientDataJSON []byte) (string, error)
+	VerifyRegistration(challenge string, attestation *PasskeyAttestation) (*VerifiedPasskey, error)
+	VerifyAssertion(challenge string, publicKey []byte, assertion *PasskeyAssertion) (uint32, error)
+}
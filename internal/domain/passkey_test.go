@@ -0,0 +1,133 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+func TestNewPasskeyCredential(t *testing.T) {
+	t.Parallel()
+
+	verified := func(id []byte) *domain.VerifiedPasskey {
+		return &domain.VerifiedPasskey{CredentialID: id, PublicKey: []byte{0xa5}, SignCount: 3}
+	}
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		verified *domain.VerifiedPasskey
+		passkey  string
+		wantErr  error
+	}{
+		{name: "valid", userID: uuid.New(), verified: verified([]byte{1}), passkey: " Laptop "},
+		{name: "missing user", verified: verified([]byte{1}), passkey: "Laptop", wantErr: domain.ErrUserIDRequired},
+		{
+			name:     "missing credential ID",
+			userID:   uuid.New(),
+			verified: verified(nil),
+			passkey:  "Laptop",
+			wantErr:  domain.ErrPasskeyCredentialInvalid,
+		},
+		{
+			name:     "credential ID too long",
+			userID:   uuid.New(),
+			verified: verified(make([]byte, 1024)),
+			passkey:  "Laptop",
+			wantErr:  domain.ErrPasskeyCredentialInvalid,
+		},
+		{name: "blank name", userID: uuid.New(), verified: verified([]byte{1}), wantErr: domain.ErrPasskeyNameInvalid},
+		{
+			name:     "name too long",
+			userID:   uuid.New(),
+			verified: verified([]byte{1}),
+			passkey:  strings.Repeat("a", 101),
+			wantErr:  domain.ErrPasskeyNameInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewPasskeyCredential(tt.userID, tt.verified, tt.passkey)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEqual(t, uuid.Nil, got.ID)
+			assert.Equal(t, "Laptop", got.Name)
+			assert.Equal(t, uint32(3), got.SignCount)
+			assert.Nil(t, got.LastUsedAt)
+		})
+	}
+}
+
+func TestPasskeyCredentialUse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		stored  uint32
+		next    uint32
+		wantErr bool
+	}{
+		{name: "counter advanced", stored: 5, next: 6},
+		{name: "counter jumped", stored: 5, next: 500},
+		{name: "no counter", stored: 0, next: 0},
+		{name: "counter started", stored: 0, next: 1},
+		{name: "counter repeated", stored: 5, next: 5, wantErr: true},
+		{name: "counter went back", stored: 5, next: 4, wantErr: true},
+		{name: "counter stopped", stored: 5, next: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := &domain.PasskeyCredential{SignCount: tt.stored}
+
+			err := c.Use(tt.next)
+			if tt.wantErr {
+				require.ErrorIs(t, err, domain.ErrPasskeySignCount)
+				assert.Equal(t, tt.stored, c.SignCount)
+				assert.Nil(t, c.LastUsedAt)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.next, c.SignCount)
+			assert.NotNil(t, c.LastUsedAt)
+		})
+	}
+}
+
+func TestNewPasskeyChallenge(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	c, err := domain.NewPasskeyChallenge("hash", domain.PasskeyCeremonyRegistration, &userID, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, c.IsExpired())
+
+	_, err = domain.NewPasskeyChallenge("hash", domain.PasskeyCeremonyRegistration, nil, time.Minute)
+	require.ErrorIs(t, err, domain.ErrUserIDRequired)
+
+	_, err = domain.NewPasskeyChallenge("", domain.PasskeyCeremonyLogin, nil, time.Minute)
+	require.ErrorIs(t, err, domain.ErrTokenRequired)
+
+	c, err = domain.NewPasskeyChallenge("hash", domain.PasskeyCeremonyLogin, nil, -time.Second)
+	require.NoError(t, err)
+	assert.True(t, c.IsExpired())
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type RolePermissionResolver interface {
+	RoleExists(role string) bool
+	Permissions(role string) []Permission
+	HasPermission(role string, perm Permission) bool
+}
+
+type resolverHolder struct {
+	resolver RolePermissionResolver
+}
+
+var (
+	defaultResolver    = NewStaticPermissionResolver(builtinRolePermissions)
+	permissionResolver atomic.Pointer[resolverHolder]
+)
+
+func SetPermissionResolver(resolver RolePermissionResolver) {
+	if resolver == nil {
+		resolver = defaultResolver
+	}
+
+	permissionResolver.Store(&resolverHolder{resolver: resolver})
+}
+
+func CurrentPermissionResolver() RolePermissionResolver {
+	if holder := permissionResolver.Load(); holder != nil {
+		return holder.resolver
+	}
+
+	return defaultResolver
+}
+
+type staticResolver struct {
+	roles map[string]map[Permission]struct{}
+}
+
+func NewStaticPermissionResolver(roles map[string][]Permission) RolePermissionResolver {
+	out := make(map[string]map[Permission]struct{}, len(roles))
+
+	for role, perms := range roles {
+		set := make(map[Permission]struct{}, len(perms))
+		for _, perm := range perms {
+			set[perm] = struct{}{}
+		}
+
+		out[role] = set
+	}
+
+	return &staticResolver{roles: out}
+}
+
+func (r *staticResolver) RoleExists(role string) bool {
+	_, ok := r.roles[role]
+
+	return ok
+}
+
+func (r *staticResolver) Permissions(role string) []Permission {
+	perms := make([]Permission, 0, len(r.roles[role]))
+	for perm := range r.roles[role] {
+		perms = append(perms, perm)
+	}
+
+	slices.Sort(perms)
+
+	return perms
+}
+
+func (r *staticResolver) HasPermission(role string, perm Permission) bool {
+	_, ok := r.roles[role][perm]
+
+	return ok
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,19}$`)
+
+type RoleDefinition struct {
+	Name        string
+	Description string
+	Permissions []Permission
+	BuiltIn     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func NewRoleDefinition(name, description string, perms []Permission) (*RoleDefinition, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	if normalized == "" {
+		return nil, ErrRoleRequired
+	}
+
+	if !roleNamePattern.MatchString(normalized) {
+		return nil, ErrRoleInvalid
+	}
+
+	if IsBuiltinRole(normalized) {
+		return nil, ErrRoleBuiltIn
+	}
+
+	now := time.Now().UTC()
+
+	return &RoleDefinition{
+		Name:        normalized,
+		Description: strings.TrimSpace(description),
+		Permissions: normalizePermissions(perms),
+		BuiltIn:     false,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+func (r *RoleDefinition) Update(description string, perms []Permission) error {
+	if r.BuiltIn {
+		return ErrRoleBuiltIn
+	}
+
+	r.Description = strings.TrimSpace(description)
+	r.Permissions = normalizePermissions(perms)
+	r.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (r *RoleDefinition) HasPermission(perm Permission) bool {
+	return slices.Contains(r.Permissions, perm)
+}
+
+type PermissionDefinition struct {
+	Name        Permission
+	Description string
+	CreatedAt   time.Time
+}
+
+func NewPermissionDefinition(name, description string) (*PermissionDefinition, error) {
+	perm, err := NewPermission(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PermissionDefinition{
+		Name:        perm,
+		Description: strings.TrimSpace(description),
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+func normalizePermissions(perms []Permission) []Permission {
+	out := slices.Clone(perms)
+	slices.Sort(out)
+
+	return slices.Compact(out)
+}
@@ -0,0 +1,114 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+func TestNewPermission(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    domain.Permission
+		wantErr error
+	}{
+		{"valid", "user:read", domain.PermUserRead, nil},
+		{"normalized", "  Billing:Read ", "billing:read", nil},
+		{"underscore", "audit_log:export", "audit_log:export", nil},
+		{"empty", "", "", domain.ErrPermissionRequired},
+		{"missing action", "billing", "", domain.ErrPermissionInvalid},
+		{"extra segment", "billing:read:all", "", domain.ErrPermissionInvalid},
+		{"invalid characters", "bill-ing:read", "", domain.ErrPermissionInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewPermission(tt.input)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewRoleDefinition(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		perms   []domain.Permission
+		want    string
+		wantErr error
+	}{
+		{"valid", "support", []domain.Permission{domain.PermUserRead}, "support", nil},
+		{"normalized", "  Support ", nil, "support", nil},
+		{"empty", "", nil, "", domain.ErrRoleRequired},
+		{"too short", "s", nil, "", domain.ErrRoleInvalid},
+		{"invalid characters", "support-team", nil, "", domain.ErrRoleInvalid},
+		{"built-in", "admin", nil, "", domain.ErrRoleBuiltIn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.NewRoleDefinition(tt.input, "desc", tt.perms)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Name)
+			assert.False(t, got.BuiltIn)
+		})
+	}
+}
+
+func TestRoleDefinitionUpdate(t *testing.T) {
+	t.Run("deduplicates and sorts permissions", func(t *testing.T) {
+		role, err := domain.NewRoleDefinition("support", "", nil)
+		require.NoError(t, err)
+
+		err = role.Update(" Support staff ", []domain.Permission{
+			domain.PermUserWrite,
+			domain.PermUserRead,
+			domain.PermUserWrite,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Support staff", role.Description)
+		assert.Equal(t, []domain.Permission{domain.PermUserRead, domain.PermUserWrite}, role.Permissions)
+		assert.True(t, role.HasPermission(domain.PermUserRead))
+		assert.False(t, role.HasPermission(domain.PermUserBan))
+	})
+
+	t.Run("built-in role", func(t *testing.T) {
+		role := &domain.RoleDefinition{Name: domain.RoleAdmin, BuiltIn: true}
+		assert.ErrorIs(t, role.Update("", nil), domain.ErrRoleBuiltIn)
+	})
+}
+
+func TestSetPermissionResolver(t *testing.T) {
+	perms := domain.BuiltinRolePermissions()
+	perms["support"] = []domain.Permission{domain.PermUserRead, domain.PermUserWrite}
+
+	domain.SetPermissionResolver(domain.NewStaticPermissionResolver(perms))
+	t.Cleanup(func() { domain.SetPermissionResolver(nil) })
+
+	role, err := domain.NewRole("support")
+	require.NoError(t, err)
+	assert.True(t, role.HasPermission(domain.PermUserWrite))
+	assert.False(t, role.HasPermission(domain.PermUserBan))
+	assert.Equal(t, []domain.Permission{domain.PermUserRead, domain.PermUserWrite}, role.Permissions())
+
+	domain.SetPermissionResolver(nil)
+
+	_, err = domain.NewRole("support")
+	assert.ErrorIs(t, err, domain.ErrRoleInvalid)
+}
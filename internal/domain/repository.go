@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -15,6 +16,12 @@ type UserRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	ExistsByUsername(ctx context.Context, username Username) (bool, error)
 	ExistsByEmail(ctx context.Context, email Email) (bool, error)
+	// The InOrganization lookups only match accounts registered in organizationID; a nil
+	// organizationID matches global accounts.
+	GetByUsernameInOrganization(ctx context.Context, organizationID *uuid.UUID, username Username) (*User, error)
+	GetByEmailInOrganization(ctx context.Context, organizationID *uuid.UUID, email Email) (*User, error)
+	ExistsByUsernameInOrganization(ctx context.Context, organizationID *uuid.UUID, username Username) (bool, error)
+	ExistsByEmailInOrganization(ctx context.Context, organizationID *uuid.UUID, email Email) (bool, error)
 }
 
 type SessionRepository interface {
@@ -23,6 +30,9 @@ type SessionRepository interface {
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*Session, error)
 	GetByToken(ctx context.Context, token string) (*Session, error)
 	Update(ctx context.Context, session *Session) error
+	// Rotate atomically revokes session and saves its successor. It returns
+	// ErrSessionRevoked when session was already revoked, e.g. by a concurrent Rotate.
+	Rotate(ctx context.Context, session, successor *Session) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
@@ -30,8 +40,132 @@ type SessionRepository interface {
 type TokenRepository interface {
 	Save(ctx context.Context, token *Token) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Token, error)
+	GetByToken(ctx context.Context, tokenHash string) (*Token, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*Token, error)
 	Update(ctx context.Context, token *Token) error
+	// Use marks the token as used. It returns ErrTokenUsed when the token was
+	// already redeemed, e.g. by a concurrent request.
+	Use(ctx context.Context, token *Token) error
+	// Attempt counts a code checked against the token. It returns ErrTokenUsed when the
+	// token was redeemed or already had maxAttempts codes checked against it.
+	Attempt(ctx context.Context, token *Token, maxAttempts int) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
 }
+
+type RoleRepository interface {
+	Save(ctx context.Context, role *RoleDefinition) error
+	GetByName(ctx context.Context, name string) (*RoleDefinition, error)
+	List(ctx context.Context) ([]*RoleDefinition, error)
+	Update(ctx context.Context, role *RoleDefinition) error
+	Delete(ctx context.Context, name string) error
+	IsAssigned(ctx context.Context, name string) (bool, error)
+}
+
+type PermissionRepository interface {
+	Save(ctx context.Context, perm *PermissionDefinition) error
+	GetByName(ctx context.Context, name Permission) (*PermissionDefinition, error)
+	List(ctx context.Context) ([]*PermissionDefinition, error)
+	Delete(ctx context.Context, name Permission) error
+}
+
+type AuditRepository interface {
+	Save(ctx context.Context, event *AuditEvent) error
+	List(ctx context.Context, filter AuditFilter) ([]*AuditEvent, error)
+}
+
+type KnownDeviceRepository interface {
+	Upsert(ctx context.Context, device *KnownDevice) (bool, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*KnownDevice, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+type OAuthClientRepository interface {
+	Save(ctx context.Context, client *OAuthClient) error
+	GetByID(ctx context.Context, id string) (*OAuthClient, error)
+	Update(ctx context.Context, client *OAuthClient) error
+}
+
+type AuthorizationCodeRepository interface {
+	Save(ctx context.Context, code *AuthorizationCode) error
+	GetByHash(ctx context.Context, codeHash string) (*AuthorizationCode, error)
+	// Use marks the code as used. It returns ErrTokenUsed when the code was
+	// already redeemed, e.g. by a concurrent token request.
+	Use(ctx context.Context, code *AuthorizationCode) error
+}
+
+type UserIdentityRepository interface {
+	Save(ctx context.Context, identity *UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*UserIdentity, error)
+}
+
+type FederatedLoginStateRepository interface {
+	Save(ctx context.Context, state *FederatedLoginState) error
+	// Consume deletes and returns the state, or nil when there is none, so each state is used once.
+	Consume(ctx context.Context, stateHash string) (*FederatedLoginState, error)
+}
+
+type PasskeyCredentialRepository interface {
+	Save(ctx context.Context, credential *PasskeyCredential) error
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*PasskeyCredential, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*PasskeyCredential, error)
+	// Use stores the credential's new signature counter and last use. It returns
+	// ErrPasskeySignCount when a concurrent assertion already advanced the counter.
+	Use(ctx context.Context, credential *PasskeyCredential) error
+	Delete(ctx context.Context, userID, id uuid.UUID) (bool, error)
+}
+
+type PasskeyChallengeRepository interface {
+	Save(ctx context.Context, challenge *PasskeyChallenge) error
+	// Consume deletes and returns the challenge, or nil when there is none, so each challenge is used once.
+	Consume(ctx context.Context, challengeHash string) (*PasskeyChallenge, error)
+}
+
+type OrganizationRepository interface {
+	Save(ctx context.Context, organization *Organization) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Organization, error)
+	GetBySlug(ctx context.Context, slug string) (*Organization, error)
+	SaveMember(ctx context.Context, member *OrganizationMember) error
+	GetMember(ctx context.Context, organizationID, userID uuid.UUID) (*OrganizationMember, error)
+	ListMemberships(ctx context.Context, userID uuid.UUID) ([]*OrganizationMembership, error)
+	RemoveMember(ctx context.Context, organizationID, userID uuid.UUID) (bool, error)
+}
+
+type InvitationRepository interface {
+	Save(ctx context.Context, invitation *Invitation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Invitation, error)
+	GetByToken(ctx context.Context, tokenHash string) (*Invitation, error)
+	ListByOrganization(ctx context.Context, organizationID uuid.UUID) ([]*Invitation, error)
+	// Accept stores the invitation's acceptance. It returns ErrInvitationNotPending when the
+	// invitation was accepted or revoked meanwhile, e.g. by a concurrent request.
+	Accept(ctx context.Context, invitation *Invitation) error
+	Revoke(ctx context.Context, invitation *Invitation) error
+}
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, key *APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*APIKey, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*APIKey, error)
+	Use(ctx context.Context, key *APIKey) error
+	Revoke(ctx context.Context, userID, id uuid.UUID, revokedAt time.Time) (bool, error)
+}
+
+type WebhookRepository interface {
+	SaveSubscription(ctx context.Context, subscription *WebhookSubscription) error
+	ListSubscriptions(ctx context.Context) ([]*WebhookSubscription, error)
+	DisableSubscription(ctx context.Context, id uuid.UUID, disabledAt time.Time) (bool, error)
+	// Enqueue stores the event in the outbox with a pending delivery for every enabled
+	// subscription to its type. It must run in the transaction of the change the event reports.
+	Enqueue(ctx context.Context, event *WebhookEvent) error
+	// ClaimDeliveries returns up to limit pending deliveries due at now to enabled subscriptions
+	// and hides them from other claims until leaseUntil.
+	ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+}
+
+// Transactor runs fn in a transaction, committed when fn returns nil and rolled back otherwise.
+// Repository calls made with the context passed to fn take part in it; nested calls join the
+// transaction already in the context.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
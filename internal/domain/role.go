@@ -2,18 +2,67 @@ package domain
 
 import (
 	"database/sql/driver"
+	"regexp"
+	"slices"
 	"strings"
 )
 
 type Permission string
 
 const (
-	PermUserRead   Permission = "user:read"
-	PermUserWrite  Permission = "user:write"
-	PermUserBan    Permission = "user:ban"
-	PermUserDelete Permission = "user:delete"
+	PermUserRead           Permission = "user:read"
+	PermUserWrite          Permission = "user:write"
+	PermUserBan            Permission = "user:ban"
+	PermUserDelete         Permission = "user:delete"
+	PermUserImpersonate    Permission = "user:impersonate"
+	PermRoleManage         Permission = "role:manage"
+	PermAuditRead          Permission = "audit:read"
+	PermOAuthClientManage  Permission = "oauth_client:manage"
+	PermOrganizationManage Permission = "organization:manage"
+	PermWebhookManage      Permission = "webhook:manage"
 )
 
+// deploymentPermissions administer the whole deployment rather than one organisation, so a
+// role held within an organisation never grants them.
+var deploymentPermissions = []Permission{
+	PermUserWrite,
+	PermUserBan,
+	PermUserDelete,
+	PermUserImpersonate,
+	PermRoleManage,
+	PermAuditRead,
+	PermOAuthClientManage,
+	PermOrganizationManage,
+	PermWebhookManage,
+}
+
+var permissionPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*:[a-z][a-z0-9_]*$`)
+
+const maxPermissionLength = 64
+
+func NewPermission(raw string) (Permission, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(raw))
+	if trimmed == "" {
+		return "", ErrPermissionRequired
+	}
+
+	if len(trimmed) > maxPermissionLength || !permissionPattern.MatchString(trimmed) {
+		return "", ErrPermissionInvalid
+	}
+
+	return Permission(trimmed), nil
+}
+
+func (p Permission) String() string {
+	return string(p)
+}
+
+// IsDeploymentWide reports whether p administers the whole deployment; such permissions are
+// left out of access tokens scoped to an organisation.
+func (p Permission) IsDeploymentWide() bool {
+	return slices.Contains(deploymentPermissions, p)
+}
+
 type Role struct {
 	value string
 }
@@ -24,23 +73,46 @@ const (
 	RoleSuperAdmin = "superadmin"
 )
 
-var rolePermissions = map[string]map[Permission]struct{}{
+var builtinRolePermissions = map[string][]Permission{
 	RoleUser: {
-		PermUserRead: {},
+		PermUserRead,
 	},
 	RoleAdmin: {
-		PermUserRead:  {},
-		PermUserWrite: {},
-		PermUserBan:   {},
+		PermUserRead,
+		PermUserWrite,
+		PermUserBan,
+		PermAuditRead,
+		PermOAuthClientManage,
 	},
 	RoleSuperAdmin: {
-		PermUserRead:   {},
-		PermUserWrite:  {},
-		PermUserBan:    {},
-		PermUserDelete: {},
+		PermUserRead,
+		PermUserWrite,
+		PermUserBan,
+		PermUserDelete,
+		PermUserImpersonate,
+		PermRoleManage,
+		PermAuditRead,
+		PermOAuthClientManage,
+		PermOrganizationManage,
+		PermWebhookManage,
 	},
 }
 
+func BuiltinRolePermissions() map[string][]Permission {
+	out := make(map[string][]Permission, len(builtinRolePermissions))
+	for role, perms := range builtinRolePermissions {
+		out[role] = slices.Clone(perms)
+	}
+
+	return out
+}
+
+func IsBuiltinRole(name string) bool {
+	_, ok := builtinRolePermissions[name]
+
+	return ok
+}
+
 func NewRole(raw string) (Role, error) {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
@@ -48,7 +120,7 @@ func NewRole(raw string) (Role, error) {
 	}
 
 	normalized := strings.ToLower(trimmed)
-	if _, ok := rolePermissions[normalized]; !ok {
+	if !CurrentPermissionResolver().RoleExists(normalized) {
 		return Role{}, ErrRoleInvalid
 	}
 
@@ -64,9 +136,11 @@ func (r Role) IsZero() bool {
 }
 
 func (r Role) HasPermission(perm Permission) bool {
-	_, ok := rolePermissions[r.value][perm]
+	return CurrentPermissionResolver().HasPermission(r.value, perm)
+}
 
-	return ok
+func (r Role) Permissions() []Permission {
+	return CurrentPermissionResolver().Permissions(r.value)
 }
 
 func (r Role) Value() (driver.Value, error) {
@@ -86,16 +86,31 @@ func TestRoleHasPermission(t *testing.T) {
 		{"user lacks user:write", user, domain.PermUserWrite, false},
 		{"user lacks user:ban", user, domain.PermUserBan, false},
 		{"user lacks user:delete", user, domain.PermUserDelete, false},
+		{"user lacks role:manage", user, domain.PermRoleManage, false},
+		{"user lacks audit:read", user, domain.PermAuditRead, false},
+		{"user lacks oauth_client:manage", user, domain.PermOAuthClientManage, false},
+		{"user lacks user:impersonate", user, domain.PermUserImpersonate, false},
+		{"user lacks organization:manage", user, domain.PermOrganizationManage, false},
 
 		{"admin has user:read", admin, domain.PermUserRead, true},
 		{"admin has user:write", admin, domain.PermUserWrite, true},
 		{"admin has user:ban", admin, domain.PermUserBan, true},
 		{"admin lacks user:delete", admin, domain.PermUserDelete, false},
+		{"admin lacks role:manage", admin, domain.PermRoleManage, false},
+		{"admin has audit:read", admin, domain.PermAuditRead, true},
+		{"admin has oauth_client:manage", admin, domain.PermOAuthClientManage, true},
+		{"admin lacks user:impersonate", admin, domain.PermUserImpersonate, false},
+		{"admin lacks organization:manage", admin, domain.PermOrganizationManage, false},
 
 		{"superadmin has user:read", superadmin, domain.PermUserRead, true},
 		{"superadmin has user:write", superadmin, domain.PermUserWrite, true},
 		{"superadmin has user:ban", superadmin, domain.PermUserBan, true},
 		{"superadmin has user:delete", superadmin, domain.PermUserDelete, true},
+		{"superadmin has role:manage", superadmin, domain.PermRoleManage, true},
+		{"superadmin has audit:read", superadmin, domain.PermAuditRead, true},
+		{"superadmin has oauth_client:manage", superadmin, domain.PermOAuthClientManage, true},
+		{"superadmin has user:impersonate", superadmin, domain.PermUserImpersonate, true},
+		{"superadmin has organization:manage", superadmin, domain.PermOrganizationManage, true},
 	}
 
 	for _, tt := range tests {
@@ -108,6 +123,17 @@ func TestRoleHasPermission(t *testing.T) {
 	}
 }
 
+func TestPermissionIsDeploymentWide(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, domain.PermUserRead.IsDeploymentWide())
+	assert.False(t, domain.Permission("billing:read").IsDeploymentWide())
+	assert.True(t, domain.PermUserWrite.IsDeploymentWide())
+	assert.True(t, domain.PermRoleManage.IsDeploymentWide())
+	assert.True(t, domain.PermOrganizationManage.IsDeploymentWide())
+	assert.True(t, domain.PermWebhookManage.IsDeploymentWide())
+}
+
 func TestRoleValue(t *testing.T) {
 	var zero domain.Role
 
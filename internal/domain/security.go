@@ -1,6 +1,10 @@
 package domain
 
 import (
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -12,14 +16,102 @@ type PasswordHasher interface {
 type OpaqueTokenManager interface {
 	Generate() (string, error)
 	Hash(token string) (string, error)
+	// Derive returns a token deterministically derived from token, so a refresh
+	// token's successor can be reissued without storing it in plain text.
+	Derive(token string) (string, error)
 }
 
+type SubjectType string
+
+const (
+	SubjectTypeUser   SubjectType = "user"
+	SubjectTypeClient SubjectType = "client"
+)
+
 type AccessClaims struct {
-	UserID uuid.UUID
-	Role   Role
+	// SubjectType is SubjectTypeClient for client_credentials tokens, which have
+	// no UserID or Role; the zero value is treated as SubjectTypeUser.
+	SubjectType SubjectType
+	UserID      uuid.UUID
+	Role        Role
+	Permissions []Permission
+	Scopes      []string
+	Audience    []string
+	// ClientID is set when the token was issued to an OAuth client, either acting
+	// for the user or for itself; such tokens are limited to their granted Scopes.
+	ClientID string
+	AuthTime time.Time
+	// ImpersonatorID is the admin acting as the user when the token was issued by an
+	// impersonation; downstream services should refuse destructive actions on such tokens.
+	ImpersonatorID uuid.UUID
+	// APIKeyID is set when the request authenticated with a personal API key rather than a
+	// token; such requests are limited to the key's Scopes.
+	APIKeyID uuid.UUID
+	// OrganizationID is the organisation the token is scoped to; Role and Permissions are then
+	// the user's role in it. It is uuid.Nil for tokens that act outside any organisation.
+	OrganizationID uuid.UUID
+	// IssuedAt and ExpiresAt are set by AccessTokenManager.Validate. Generate ignores IssuedAt
+	// and honours ExpiresAt only when it is sooner than the manager's access TTL.
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+func (c *AccessClaims) IsClient() bool {
+	return c.SubjectType == SubjectTypeClient
+}
+
+func (c *AccessClaims) Subject() string {
+	if c.IsClient() {
+		return c.ClientID
+	}
+
+	return c.UserID.String()
+}
+
+func (c *AccessClaims) IsImpersonated() bool {
+	return c.ImpersonatorID != uuid.Nil
+}
+
+func (c *AccessClaims) IsDelegated() bool {
+	return c.ClientID != "" || c.APIKeyID != uuid.Nil
+}
+
+func (c *AccessClaims) InOrganization() bool {
+	return c.OrganizationID != uuid.Nil
+}
+
+func (c *AccessClaims) AuthenticatedWithin(window time.Duration, now time.Time) bool {
+	return !c.AuthTime.IsZero() && now.Sub(c.AuthTime) <= window
+}
+
+func (c *AccessClaims) HasScopes(required ...string) bool {
+	for _, scope := range required {
+		if !slices.Contains(c.Scopes, scope) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasPermission checks the permissions embedded in the token, not the role's current permissions.
+func (c *AccessClaims) HasPermission(perm Permission) bool {
+	return slices.Contains(c.Permissions, perm)
+}
+
+func (c *AccessClaims) Scope() string {
+	return JoinScope(c.Scopes)
+}
+
+func ParseScope(scope string) []string {
+	return strings.Fields(scope)
+}
+
+func JoinScope(scopes []string) string {
+	return strings.Join(scopes, " ")
 }
 
 type AccessTokenManager interface {
 	Generate(claims AccessClaims) (string, error)
-	Validate(token string) (*AccessClaims, error)
+	Validate(token string, audience ...string) (*AccessClaims, error)
 }
@@ -0,0 +1,41 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"go-auth/internal/domain"
+)
+
+func TestAccessClaimsHasScopes(t *testing.T) {
+	claims := &domain.AccessClaims{Scopes: domain.ParseScope("user:read  user:write")}
+
+	assert.Equal(t, "user:read user:write", claims.Scope())
+	assert.True(t, claims.HasScopes())
+	assert.True(t, claims.HasScopes("user:read"))
+	assert.True(t, claims.HasScopes("user:write", "user:read"))
+	assert.False(t, claims.HasScopes("user:read", "user:ban"))
+}
+
+func TestAccessClaimsSubject(t *testing.T) {
+	userID := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	user := &domain.AccessClaims{UserID: userID, ClientID: "c1"}
+	assert.False(t, user.IsClient())
+	assert.Equal(t, userID.String(), user.Subject())
+
+	client := &domain.AccessClaims{SubjectType: domain.SubjectTypeClient, ClientID: "c1"}
+	assert.True(t, client.IsClient())
+	assert.Equal(t, "c1", client.Subject())
+}
+
+func TestAccessClaimsAuthenticatedWithin(t *testing.T) {
+	now := time.Now().UTC()
+
+	assert.True(t, (&domain.AccessClaims{AuthTime: now.Add(-time.Minute)}).AuthenticatedWithin(5*time.Minute, now))
+	assert.False(t, (&domain.AccessClaims{AuthTime: now.Add(-time.Hour)}).AuthenticatedWithin(5*time.Minute, now))
+	assert.False(t, (&domain.AccessClaims{}).AuthenticatedWithin(5*time.Minute, now))
+}
@@ -7,15 +7,44 @@ import (
 )
 
 type Session struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Token     string
-	UserAgent string
-	ClientIP  string
-	ExpiresAt time.Time
-	RevokedAt *time.Time
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Token           string
+	UserAgent       string
+	ClientIP        string
+	ExpiresAt       time.Time
+	RevokedAt       *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	FlaggedAt       *time.Time
+	AuthenticatedAt time.Time
+	ReplacedBy      *uuid.UUID
+	ClientID        string
+	Scopes          []string
+	OrganizationID  *uuid.UUID
+}
+
+type SessionLimits struct {
+	// MaxLifetime is measured from AuthenticatedAt, regardless of rotations.
+	MaxLifetime time.Duration
+	// IdleTimeout is measured from the last refresh, i.e. the current session's CreatedAt.
+	IdleTimeout time.Duration
+}
+
+func (l SessionLimits) Deadline(authenticatedAt time.Time) (time.Time, bool) {
+	if l.MaxLifetime <= 0 {
+		return time.Time{}, false
+	}
+
+	return authenticatedAt.Add(l.MaxLifetime), true
+}
+
+func (l SessionLimits) CapExpiry(authenticatedAt, expiresAt time.Time) time.Time {
+	if deadline, ok := l.Deadline(authenticatedAt); ok && deadline.Before(expiresAt) {
+		return deadline
+	}
+
+	return expiresAt
 }
 
 func NewSession(userID uuid.UUID, tokenHash, userAgent, clientIP string, expiresAt time.Time) (*Session, error) {
@@ -33,15 +62,16 @@ func NewSession(userID uuid.UUID, tokenHash, userAgent, clientIP string, expires
 	}
 
 	return &Session{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Token:     tokenHash,
-		UserAgent: userAgent,
-		ClientIP:  clientIP,
-		ExpiresAt: expiresAt,
-		RevokedAt: nil,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:              uuid.New(),
+		UserID:          userID,
+		Token:           tokenHash,
+		UserAgent:       userAgent,
+		ClientIP:        clientIP,
+		ExpiresAt:       expiresAt,
+		RevokedAt:       nil,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		AuthenticatedAt: now,
 	}, nil
 }
 
@@ -57,6 +87,44 @@ func (s *Session) IsActive() bool {
 	return !s.IsExpired() && !s.IsRevoked()
 }
 
+func (s *Session) CheckLimits(limits SessionLimits) error {
+	now := time.Now().UTC()
+
+	if deadline, ok := limits.Deadline(s.AuthenticatedAt); ok && !deadline.After(now) {
+		return ErrSessionLifetimeExceeded
+	}
+
+	if limits.IdleTimeout > 0 && !s.CreatedAt.Add(limits.IdleTimeout).After(now) {
+		return ErrSessionIdle
+	}
+
+	return nil
+}
+
+// RotatedWithin reports whether s was replaced by Rotate no longer than grace ago.
+// Sessions revoked any other way, e.g. on logout, are never within a grace period.
+func (s *Session) RotatedWithin(grace time.Duration) bool {
+	if grace <= 0 || s.ReplacedBy == nil || s.RevokedAt == nil {
+		return false
+	}
+
+	return time.Since(*s.RevokedAt) <= grace
+}
+
+func (s *Session) IsFlagged() bool {
+	return s.FlaggedAt != nil
+}
+
+func (s *Session) Flag() {
+	if s.IsFlagged() {
+		return
+	}
+
+	now := time.Now().UTC()
+	s.FlaggedAt = &now
+	s.touch()
+}
+
 func (s *Session) Revoke() error {
 	if s.IsRevoked() {
 		return ErrSessionRevoked
@@ -69,7 +137,12 @@ func (s *Session) Revoke() error {
 	return nil
 }
 
-func (s *Session) Rotate(newToken string, newExpiresAt time.Time, newUserAgent, newClientIP string) (*Session, error) {
+func (s *Session) Rotate(
+	newToken string,
+	newExpiresAt time.Time,
+	newUserAgent, newClientIP string,
+	limits SessionLimits,
+) (*Session, error) {
 	if s.IsRevoked() {
 		return nil, ErrSessionRevoked
 	}
@@ -78,11 +151,16 @@ func (s *Session) Rotate(newToken string, newExpiresAt time.Time, newUserAgent,
 		return nil, ErrSessionExpired
 	}
 
+	if err := s.CheckLimits(limits); err != nil {
+		return nil, err
+	}
+
 	if newToken == "" {
 		return nil, ErrTokenRequired
 	}
 
 	now := time.Now().UTC()
+	newExpiresAt = limits.CapExpiry(s.AuthenticatedAt, newExpiresAt)
 	if !newExpiresAt.After(now) {
 		return nil, ErrSessionExpired
 	}
@@ -101,17 +179,24 @@ func (s *Session) Rotate(newToken string, newExpiresAt time.Time, newUserAgent,
 	}
 
 	newSession := &Session{
-		ID:        uuid.New(),
-		UserID:    s.UserID,
-		Token:     newToken,
-		UserAgent: userAgent,
-		ClientIP:  clientIP,
-		ExpiresAt: newExpiresAt,
-		RevokedAt: nil,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:              uuid.New(),
+		UserID:          s.UserID,
+		Token:           newToken,
+		UserAgent:       userAgent,
+		ClientIP:        clientIP,
+		ExpiresAt:       newExpiresAt,
+		RevokedAt:       nil,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		FlaggedAt:       s.FlaggedAt,
+		AuthenticatedAt: s.AuthenticatedAt,
+		ClientID:        s.ClientID,
+		Scopes:          s.Scopes,
+		OrganizationID:  s.OrganizationID,
 	}
 
+	s.ReplacedBy = &newSession.ID
+
 	return newSession, nil
 }
 
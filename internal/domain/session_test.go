@@ -194,6 +194,19 @@ func TestSessionRevoke(t *testing.T) {
 	})
 }
 
+func TestSessionFlag(t *testing.T) {
+	s := mustSession(t)
+	assert.False(t, s.IsFlagged())
+
+	s.Flag()
+	assert.True(t, s.IsFlagged())
+
+	first := *s.FlaggedAt
+
+	s.Flag()
+	assert.Equal(t, first, *s.FlaggedAt)
+}
+
 func TestSessionRotate(t *testing.T) {
 	newToken := "new-jwt-token"
 	newExpiresAt := time.Now().UTC().Add(48 * time.Hour)
@@ -202,7 +215,7 @@ func TestSessionRotate(t *testing.T) {
 		s := mustSession(t)
 		oldID := s.ID
 
-		newS, err := s.Rotate(newToken, newExpiresAt, "", "")
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", domain.SessionLimits{})
 		assert.NoError(t, err)
 		assert.NotNil(t, newS)
 
@@ -221,7 +234,7 @@ func TestSessionRotate(t *testing.T) {
 		now := time.Now().UTC()
 		s.RevokedAt = &now
 
-		newS, err := s.Rotate(newToken, newExpiresAt, "", "")
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", domain.SessionLimits{})
 		assert.ErrorIs(t, err, domain.ErrSessionRevoked)
 		assert.Nil(t, newS)
 	})
@@ -230,7 +243,7 @@ func TestSessionRotate(t *testing.T) {
 		s := mustSession(t)
 		s.ExpiresAt = time.Now().UTC().Add(-time.Minute)
 
-		newS, err := s.Rotate(newToken, newExpiresAt, "", "")
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", domain.SessionLimits{})
 		assert.ErrorIs(t, err, domain.ErrSessionExpired)
 		assert.Nil(t, newS)
 	})
@@ -238,7 +251,7 @@ func TestSessionRotate(t *testing.T) {
 	t.Run("empty new token", func(t *testing.T) {
 		s := mustSession(t)
 
-		newS, err := s.Rotate("", newExpiresAt, "", "")
+		newS, err := s.Rotate("", newExpiresAt, "", "", domain.SessionLimits{})
 		assert.ErrorIs(t, err, domain.ErrTokenRequired)
 		assert.Nil(t, newS)
 	})
@@ -246,7 +259,7 @@ func TestSessionRotate(t *testing.T) {
 	t.Run("new expires at in past", func(t *testing.T) {
 		s := mustSession(t)
 
-		newS, err := s.Rotate(newToken, time.Now().UTC().Add(-time.Hour), "", "")
+		newS, err := s.Rotate(newToken, time.Now().UTC().Add(-time.Hour), "", "", domain.SessionLimits{})
 		assert.ErrorIs(t, err, domain.ErrSessionExpired)
 		assert.Nil(t, newS)
 	})
@@ -256,10 +269,126 @@ func TestSessionRotate(t *testing.T) {
 		newUA := "Mozilla/5.0 (refresh)"
 		newIP := "192.168.1.2"
 
-		newS, err := s.Rotate(newToken, newExpiresAt, newUA, newIP)
+		newS, err := s.Rotate(newToken, newExpiresAt, newUA, newIP, domain.SessionLimits{})
 		assert.NoError(t, err)
 		assert.NotNil(t, newS)
 		assert.Equal(t, newUA, newS.UserAgent)
 		assert.Equal(t, newIP, newS.ClientIP)
 	})
+
+	t.Run("flag carried to new session", func(t *testing.T) {
+		s := mustSession(t)
+		s.Flag()
+
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", domain.SessionLimits{})
+		assert.NoError(t, err)
+		assert.True(t, newS.IsFlagged())
+		assert.Equal(t, s.FlaggedAt, newS.FlaggedAt)
+	})
+
+	t.Run("oauth client and scopes carried to new session", func(t *testing.T) {
+		s := mustSession(t)
+		s.ClientID = "client-1"
+		s.Scopes = []string{"profile"}
+
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", domain.SessionLimits{})
+		assert.NoError(t, err)
+		assert.Equal(t, "client-1", newS.ClientID)
+		assert.Equal(t, []string{"profile"}, newS.Scopes)
+	})
+
+	t.Run("organization carried to new session", func(t *testing.T) {
+		s := mustSession(t)
+		organizationID := uuid.New()
+		s.OrganizationID = &organizationID
+
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", domain.SessionLimits{})
+		assert.NoError(t, err)
+		assert.Equal(t, &organizationID, newS.OrganizationID)
+	})
+}
+
+func TestSessionRotateLimits(t *testing.T) {
+	newToken := "new-jwt-token"
+	newExpiresAt := time.Now().UTC().Add(48 * time.Hour)
+
+	t.Run("authenticated at carried to new session", func(t *testing.T) {
+		s := mustSession(t)
+		s.AuthenticatedAt = time.Now().UTC().Add(-time.Hour)
+
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", domain.SessionLimits{})
+		assert.NoError(t, err)
+		assert.Equal(t, s.AuthenticatedAt, newS.AuthenticatedAt)
+		assert.Equal(t, newExpiresAt, newS.ExpiresAt)
+	})
+
+	t.Run("expiry capped at max lifetime", func(t *testing.T) {
+		s := mustSession(t)
+		s.AuthenticatedAt = time.Now().UTC().Add(-time.Hour)
+		limits := domain.SessionLimits{MaxLifetime: 3 * time.Hour}
+
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", limits)
+		assert.NoError(t, err)
+		assert.Equal(t, s.AuthenticatedAt.Add(3*time.Hour), newS.ExpiresAt)
+	})
+
+	t.Run("max lifetime exceeded", func(t *testing.T) {
+		s := mustSession(t)
+		s.AuthenticatedAt = time.Now().UTC().Add(-3 * time.Hour)
+
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", domain.SessionLimits{MaxLifetime: 2 * time.Hour})
+		assert.ErrorIs(t, err, domain.ErrSessionLifetimeExceeded)
+		assert.Nil(t, newS)
+		assert.False(t, s.IsRevoked())
+	})
+
+	t.Run("idle timeout exceeded", func(t *testing.T) {
+		s := mustSession(t)
+		s.CreatedAt = time.Now().UTC().Add(-2 * time.Hour)
+
+		newS, err := s.Rotate(newToken, newExpiresAt, "", "", domain.SessionLimits{IdleTimeout: time.Hour})
+		assert.ErrorIs(t, err, domain.ErrSessionIdle)
+		assert.Nil(t, newS)
+	})
+
+	t.Run("within limits", func(t *testing.T) {
+		s := mustSession(t)
+		limits := domain.SessionLimits{MaxLifetime: 72 * time.Hour, IdleTimeout: time.Hour}
+
+		assert.NoError(t, s.CheckLimits(limits))
+	})
+}
+
+func TestSessionRotatedWithin(t *testing.T) {
+	newExpiresAt := time.Now().UTC().Add(48 * time.Hour)
+
+	t.Run("rotated session", func(t *testing.T) {
+		s := mustSession(t)
+
+		newS, err := s.Rotate("new-jwt-token", newExpiresAt, "", "", domain.SessionLimits{})
+		assert.NoError(t, err)
+		assert.Equal(t, &newS.ID, s.ReplacedBy)
+		assert.Nil(t, newS.ReplacedBy)
+		assert.True(t, s.RotatedWithin(time.Minute))
+		assert.False(t, s.RotatedWithin(0))
+	})
+
+	t.Run("grace elapsed", func(t *testing.T) {
+		s := mustSession(t)
+
+		_, err := s.Rotate("new-jwt-token", newExpiresAt, "", "", domain.SessionLimits{})
+		assert.NoError(t, err)
+
+		rotatedAt := time.Now().UTC().Add(-time.Minute)
+		s.RevokedAt = &rotatedAt
+
+		assert.False(t, s.RotatedWithin(10*time.Second))
+	})
+
+	t.Run("revoked without rotation", func(t *testing.T) {
+		s := mustSession(t)
+		assert.NoError(t, s.Revoke())
+
+		assert.False(t, s.RotatedWithin(time.Minute))
+	})
 }
@@ -11,6 +11,8 @@ type TokenType string
 const (
 	TokenTypeVerifyEmail   TokenType = "verify_email"
 	TokenTypePasswordReset TokenType = "password_reset"
+	TokenTypeMagicLink     TokenType = "magic_link"
+	TokenTypeEmailOTP      TokenType = "email_otp"
 )
 
 func (t TokenType) String() string {
@@ -18,17 +20,27 @@ func (t TokenType) String() string {
 }
 
 func (t TokenType) IsValid() bool {
-	return t == TokenTypeVerifyEmail || t == TokenTypePasswordReset
+	return t == TokenTypeVerifyEmail || t == TokenTypePasswordReset || t == TokenTypeMagicLink ||
+		t == TokenTypeEmailOTP
 }
 
+// Token is a single-use, expiring secret sent to a user. Only its hash is stored.
+// BindingHash, when set, ties the token to the client that requested it: redeeming
+// it requires presenting the matching binding secret.
+//
+// CodeHash is set on tokens that are confirmed with a short code, such as a one-time code
+// sent by email; Attempts counts the codes checked against it.
 type Token struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Type      TokenType
-	Token     string
-	ExpiresAt time.Time
-	UsedAt    *time.Time
-	CreatedAt time.Time
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Type        TokenType
+	Token       string
+	BindingHash string
+	CodeHash    string
+	Attempts    int
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+	CreatedAt   time.Time
 }
 
 func NewToken(userID uuid.UUID, tokenType TokenType, tokenHash string, expiresAt time.Time) (*Token, error) {
@@ -56,6 +56,22 @@ func TestNewToken(t *testing.T) {
 			expiresAt: expiresAt,
 			wantErr:   nil,
 		},
+		{
+			name:      "valid magic_link",
+			userID:    userID,
+			tokenType: domain.TokenTypeMagicLink,
+			token:     tokenHash,
+			expiresAt: expiresAt,
+			wantErr:   nil,
+		},
+		{
+			name:      "valid email_otp",
+			userID:    userID,
+			tokenType: domain.TokenTypeEmailOTP,
+			token:     tokenHash,
+			expiresAt: expiresAt,
+			wantErr:   nil,
+		},
 		{
 			name:      "nil user id",
 			userID:    uuid.Nil,
@@ -8,17 +8,19 @@ import (
 )
 
 type User struct {
-	ID         uuid.UUID
-	Username   Username
-	Email      Email
-	Password   Password
-	FirstName  string
-	LastName   string
-	Role       Role
-	Status     Status
-	VerifiedAt *time.Time
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID             uuid.UUID
+	Username       Username
+	Email          Email
+	Password       Password
+	FirstName      string
+	LastName       string
+	Role           Role
+	Status         Status
+	VerifiedAt     *time.Time
+	MFAFactor      MFAFactor
+	OrganizationID *uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 func NewUser(username Username, email Email, password Password, firstName, lastName string) (*User, error) {
@@ -169,6 +171,25 @@ func (u *User) UpdateRole(role Role) error {
 	return nil
 }
 
+func (u *User) RequiresMFA() bool {
+	return u.MFAFactor != MFAFactorNone
+}
+
+func (u *User) SetMFAFactor(factor MFAFactor) error {
+	if !u.IsActivated() {
+		return ErrUserNotActivated
+	}
+
+	if !factor.IsValid() {
+		return ErrMFAFactorInvalid
+	}
+
+	u.MFAFactor = factor
+	u.touch()
+
+	return nil
+}
+
 func (u *User) touch() {
 	u.UpdatedAt = time.Now().UTC()
 }
@@ -276,3 +276,28 @@ func TestUserUpdateRole(t *testing.T) {
 		assert.ErrorIs(t, u.UpdateRole(admin), domain.ErrUserNotActivated)
 	})
 }
+
+func TestUserSetMFAFactor(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		u := mustUser(t)
+		assert.False(t, u.RequiresMFA())
+
+		assert.NoError(t, u.SetMFAFactor(domain.MFAFactorEmailOTP))
+		assert.True(t, u.RequiresMFA())
+
+		assert.NoError(t, u.SetMFAFactor(domain.MFAFactorNone))
+		assert.False(t, u.RequiresMFA())
+	})
+
+	t.Run("invalid factor", func(t *testing.T) {
+		u := mustUser(t)
+		assert.ErrorIs(t, u.SetMFAFactor(domain.MFAFactor("sms")), domain.ErrMFAFactorInvalid)
+		assert.False(t, u.RequiresMFA())
+	})
+
+	t.Run("banned", func(t *testing.T) {
+		u := mustUser(t)
+		u.Status = domain.StatusBanned
+		assert.ErrorIs(t, u.SetMFAFactor(domain.MFAFactorEmailOTP), domain.ErrUserNotActivated)
+	})
+}
@@ -49,6 +49,35 @@ func NewUsername(raw string) (Username, error) {
 	return Username{value: normalized}, nil
 }
 
+func UsernameFrom(hint, suffix string) (Username, error) {
+	base := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r == '.' || r == '-' || r == '+':
+			return '_'
+		default:
+			return -1
+		}
+	}, strings.ToLower(strings.TrimSpace(hint)))
+
+	base = strings.TrimLeft(base, "0123456789_")
+
+	if suffix == "" {
+		return NewUsername(base)
+	}
+
+	if base == "" {
+		base = "user"
+	}
+
+	if limit := maxUsernameLength - len(suffix) - 1; len(base) > limit {
+		base = base[:max(limit, 0)]
+	}
+
+	return NewUsername(base + "_" + suffix)
+}
+
 func (u Username) String() string {
 	return u.value
 }
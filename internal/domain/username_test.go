@@ -191,3 +191,39 @@ func TestUsernameUnmarshalText(t *testing.T) {
 		})
 	}
 }
+
+func TestUsernameFrom(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		hint    string
+		suffix  string
+		want    string
+		wantErr bool
+	}{
+		{name: "email local part", hint: "Jane.Doe+work", want: "jane_doe_work"},
+		{name: "leading digits dropped", hint: "42_alice", want: "alice"},
+		{name: "reserved", hint: "admin", wantErr: true},
+		{name: "reserved with suffix", hint: "admin", suffix: "1234", want: "admin_1234"},
+		{name: "nothing usable with suffix", hint: "李", suffix: "1234", want: "user_1234"},
+		{name: "truncated to fit suffix", hint: "averyveryverylongname", suffix: "1234", want: "averyveryverylo_1234"},
+		{name: "too short", hint: "jo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := domain.UsernameFrom(tt.hint, tt.suffix)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEventType string
+
+// No operation deletes users yet, so there is no user.deleted event; it belongs with that flow.
+const (
+	WebhookEventUserRegistered WebhookEventType = "user.registered"
+	WebhookEventUserVerified   WebhookEventType = "user.verified"
+	WebhookEventUserBanned     WebhookEventType = "user.banned"
+	WebhookEventUserUnbanned   WebhookEventType = "user.unbanned"
+)
+
+var webhookEventTypes = []WebhookEventType{
+	WebhookEventUserRegistered,
+	WebhookEventUserVerified,
+	WebhookEventUserBanned,
+	WebhookEventUserUnbanned,
+}
+
+func NewWebhookEventType(raw string) (WebhookEventType, error) {
+	t := WebhookEventType(strings.ToLower(strings.TrimSpace(raw)))
+	if !slices.Contains(webhookEventTypes, t) {
+		return "", ErrWebhookEventInvalid
+	}
+
+	return t, nil
+}
+
+func (t WebhookEventType) String() string {
+	return string(t)
+}
+
+// WebhookSubscription sends the events it subscribes to to URL. Payloads are signed with
+// Secret, which is stored as is because signing needs it.
+type WebhookSubscription struct {
+	ID         uuid.UUID
+	URL        string
+	Secret     string
+	Events     []WebhookEventType
+	DisabledAt *time.Time
+	CreatedAt  time.Time
+}
+
+func NewWebhookSubscription(rawURL, secret string, events []string) (*WebhookSubscription, error) {
+	if err := validateWebhookURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	if secret == "" {
+		return nil, ErrTokenRequired
+	}
+
+	if len(events) == 0 {
+		return nil, ErrWebhookEventsRequired
+	}
+
+	types := make([]WebhookEventType, len(events))
+	for i, raw := range events {
+		t, err := NewWebhookEventType(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		types[i] = t
+	}
+
+	return &WebhookSubscription{
+		ID:        uuid.New(),
+		URL:       rawURL,
+		Secret:    secret,
+		Events:    slices.Compact(slices.Sorted(slices.Values(types))),
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// validateWebhookURL allows https URLs, and http ones on the loopback interface for development.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.Fragment != "" || u.User != nil {
+		return ErrWebhookURLInvalid
+	}
+
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := u.Hostname()
+		if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+			return nil
+		}
+	}
+
+	return ErrWebhookURLInvalid
+}
+
+func (s *WebhookSubscription) IsDisabled() bool {
+	return s.DisabledAt != nil
+}
+
+// WebhookEvent is an entry in the webhook outbox. It is stored in the transaction of the change
+// it reports, together with a delivery for every subscription to its type.
+type WebhookEvent struct {
+	ID        uuid.UUID
+	Type      WebhookEventType
+	Data      map[string]any
+	CreatedAt time.Time
+}
+
+func NewWebhookEvent(t WebhookEventType, data map[string]any) *WebhookEvent {
+	return &WebhookEvent{
+		ID:        uuid.New(),
+		Type:      t,
+		Data:      data,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"
+)
+
+func (s WebhookDeliveryStatus) String() string {
+	return string(s)
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	URL            string
+	Secret         string
+	Event          *WebhookEvent
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
+
+func (d *WebhookDelivery) Succeed(at time.Time) {
+	d.Attempts++
+	d.Status = WebhookDeliveryDelivered
+	d.LastError = ""
+	d.DeliveredAt = &at
+}
+
+func (d *WebhookDelivery) Fail(reason string, retryAt time.Time, maxAttempts int) {
+	d.Attempts++
+	d.LastError = reason
+
+	if d.Attempts >= maxAttempts {
+		d.Status = WebhookDeliveryDead
+
+		return
+	}
+
+	d.NextAttemptAt = retryAt
+}
@@ -0,0 +1,161 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-auth/internal/domain"
+	"go-auth/pkg/logger"
+)
+
+const (
+	DefaultQueueSize      = 1024
+	DefaultHandlerTimeout = 5 * time.Second
+)
+
+var _ domain.EventPublisher = (*Bus)(nil)
+
+type Handler func(ctx context.Context, event domain.Event) error
+
+func On[E domain.Event](fn func(ctx context.Context, event E) error) Handler {
+	return func(ctx context.Context, event domain.Event) error {
+		typed, ok := event.(E)
+		if !ok {
+			return nil
+		}
+
+		return fn(ctx, typed)
+	}
+}
+
+// Bus is an in-process EventPublisher. Synchronous handlers run inside Publish, in the order
+// they subscribed. Asynchronous handlers run one event at a time on a background goroutine
+// started with Run; when its queue is full the event is dropped for them and a warning is logged.
+type Bus struct {
+	log            logger.Logger
+	mu             sync.RWMutex
+	sync           []Handler
+	async          []Handler
+	queue          chan queued
+	handlerTimeout time.Duration
+	done           chan struct{}
+}
+
+type queued struct {
+	ctx   context.Context
+	event domain.Event
+}
+
+func NewBus(log logger.Logger, size int) (*Bus, error) {
+	if log == nil {
+		return nil, errors.New("logger is required")
+	}
+
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+
+	return &Bus{
+		log:            log,
+		queue:          make(chan queued, size),
+		handlerTimeout: DefaultHandlerTimeout,
+		done:           make(chan struct{}),
+	}, nil
+}
+
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sync = append(b.sync, handler)
+}
+
+func (b *Bus) SubscribeAsync(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.async = append(b.async, handler)
+}
+
+func (b *Bus) Publish(ctx context.Context, event domain.Event) {
+	if event == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers, hasAsync := b.sync, len(b.async) > 0
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		b.handle(ctx, handler, event)
+	}
+
+	if !hasAsync {
+		return
+	}
+
+	// The request may be over by the time the event is handled; keep its values, not its deadline.
+	select {
+	case b.queue <- queued{ctx: context.WithoutCancel(ctx), event: event}:
+	default:
+		b.log.WarnCtx(ctx, "Event queue full, dropping event", "event", event.EventName())
+	}
+}
+
+func (b *Bus) Run(ctx context.Context) {
+	defer close(b.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.flush()
+
+			return
+		case q := <-b.queue:
+			b.dispatch(q)
+		}
+	}
+}
+
+func (b *Bus) Done() <-chan struct{} {
+	return b.done
+}
+
+func (b *Bus) flush() {
+	for {
+		select {
+		case q := <-b.queue:
+			b.dispatch(q)
+		default:
+			return
+		}
+	}
+}
+
+func (b *Bus) dispatch(q queued) {
+	b.mu.RLock()
+	handlers := b.async
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handlerCtx, cancel := context.WithTimeout(q.ctx, b.handlerTimeout)
+		b.handle(handlerCtx, handler, q.event)
+		cancel()
+	}
+}
+
+// handle runs one handler, logging its error or panic so that one faulty subscriber cannot
+// affect the others or the publisher.
+func (b *Bus) handle(ctx context.Context, handler Handler, event domain.Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.ErrorCtx(ctx, "Event handler panicked", "event", event.EventName(), "panic", r)
+		}
+	}()
+
+	if err := handler(ctx, event); err != nil {
+		b.log.ErrorCtx(ctx, "Handle event failed", "event", event.EventName(), "error", err)
+	}
+}
@@ -0,0 +1,143 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/event"
+	"go-auth/pkg/logger"
+	_ "go-auth/pkg/logger/adapter/nop"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (r *recorder) handle(ctx context.Context, e domain.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, e)
+
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.events)
+}
+
+func newNopLogger(t *testing.T) logger.Logger {
+	t.Helper()
+
+	log, err := logger.New(logger.WithDriver(logger.DriverNop))
+	require.NoError(t, err)
+
+	return log
+}
+
+func newBus(t *testing.T, size int) *event.Bus {
+	t.Helper()
+
+	bus, err := event.NewBus(newNopLogger(t), size)
+	require.NoError(t, err)
+
+	return bus
+}
+
+func TestNewBus(t *testing.T) {
+	_, err := event.NewBus(nil, 1)
+	require.Error(t, err)
+}
+
+func TestBusSubscribe(t *testing.T) {
+	bus := newBus(t, 1)
+
+	var banned []domain.UserBanned
+
+	bus.Subscribe(func(context.Context, domain.Event) error { return errors.New("handler failed") })
+	bus.Subscribe(func(context.Context, domain.Event) error { panic("handler panicked") })
+	bus.Subscribe(event.On(func(ctx context.Context, e domain.UserBanned) error {
+		banned = append(banned, e)
+
+		return nil
+	}))
+
+	userID := uuid.New()
+
+	bus.Publish(context.Background(), domain.UserUnbanned{UserID: userID})
+	bus.Publish(context.Background(), domain.UserBanned{UserID: userID})
+
+	require.Len(t, banned, 1, "handlers run synchronously, despite earlier failing handlers")
+	assert.Equal(t, userID, banned[0].UserID)
+}
+
+func TestBusSubscribeAsync(t *testing.T) {
+	t.Run("handles queued events and flushes on shutdown", func(t *testing.T) {
+		bus := newBus(t, 10)
+		rec := &recorder{}
+		bus.SubscribeAsync(rec.handle)
+
+		bus.Publish(context.Background(), domain.UserLoggedIn{UserID: uuid.New()})
+		bus.Publish(context.Background(), domain.LoginFailed{Login: "alice"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		bus.Run(ctx)
+
+		assert.Equal(t, 2, rec.count())
+		assert.IsType(t, domain.UserLoggedIn{}, rec.events[0])
+		assert.IsType(t, domain.LoginFailed{}, rec.events[1])
+
+		select {
+		case <-bus.Done():
+		default:
+			t.Fatal("Done is not closed after Run returned")
+		}
+	})
+
+	t.Run("handles events while running", func(t *testing.T) {
+		bus := newBus(t, 10)
+		rec := &recorder{}
+		bus.SubscribeAsync(rec.handle)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go bus.Run(ctx)
+
+		bus.Publish(ctx, domain.SessionRevoked{SessionID: uuid.New(), Reason: domain.SessionRevokedLogout})
+
+		assert.Eventually(t, func() bool { return rec.count() == 1 }, time.Second, time.Millisecond)
+
+		cancel()
+		<-bus.Done()
+	})
+
+	t.Run("drops events when the queue is full", func(t *testing.T) {
+		bus := newBus(t, 1)
+		rec := &recorder{}
+		bus.SubscribeAsync(rec.handle)
+
+		bus.Publish(context.Background(), domain.UserBanned{})
+		bus.Publish(context.Background(), domain.UserUnbanned{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		bus.Run(ctx)
+
+		require.Equal(t, 1, rec.count())
+		assert.IsType(t, domain.UserBanned{}, rec.events[0])
+	})
+}
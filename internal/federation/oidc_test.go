@@ -0,0 +1,227 @@
+package federation_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/federation"
+)
+
+const (
+	testRedirectURL = "https://app.example.com/auth/callback/stub"
+	testVerifier    = "dBjftJeZ4CQP-mB0KbRcygFXjmkrTS9nkXfHKOZyCrW"
+	testNonce       = "n-0S6_WzA2Mj"
+)
+
+func newTestProvider(t *testing.T, idp *stubIdP) *federation.OIDCProvider {
+	t.Helper()
+
+	provider, err := federation.NewOIDCProvider(federation.OIDCConfig{
+		Name:         "stub",
+		Issuer:       idp.issuer(),
+		ClientID:     stubClientID,
+		ClientSecret: stubClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestNewOIDCProvider(t *testing.T) {
+	t.Parallel()
+
+	valid := federation.OIDCConfig{
+		Name:        "google",
+		Issuer:      "https://accounts.google.com",
+		ClientID:    "client",
+		RedirectURL: testRedirectURL,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*federation.OIDCConfig)
+	}{
+		{name: "invalid name", modify: func(c *federation.OIDCConfig) { c.Name = "Google" }},
+		{name: "relative issuer", modify: func(c *federation.OIDCConfig) { c.Issuer = "accounts.google.com" }},
+		{name: "missing client id", modify: func(c *federation.OIDCConfig) { c.ClientID = "" }},
+		{name: "missing redirect", modify: func(c *federation.OIDCConfig) { c.RedirectURL = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := valid
+			tt.modify(&cfg)
+
+			_, err := federation.NewOIDCProvider(cfg)
+			require.Error(t, err)
+		})
+	}
+
+	provider, err := federation.NewOIDCProvider(valid)
+	require.NoError(t, err)
+	assert.Equal(t, "google", provider.Name())
+}
+
+func TestOIDCProviderAuthCodeURL(t *testing.T) {
+	t.Parallel()
+
+	idp := newStubIdP(t)
+	provider := newTestProvider(t, idp)
+
+	pkce, err := domain.PKCEChallengeFor(testVerifier)
+	require.NoError(t, err)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", testNonce, pkce)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, idp.issuer()+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+
+	query := parsed.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, stubClientID, query.Get("client_id"))
+	assert.Equal(t, testRedirectURL, query.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, testNonce, query.Get("nonce"))
+	assert.Equal(t, pkce.Challenge, query.Get("code_challenge"))
+	assert.Equal(t, domain.PKCEMethodS256, query.Get("code_challenge_method"))
+}
+
+func TestOIDCProviderExchange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		claims     jwt.MapClaims
+		nonce      string
+		verifier   string
+		tokenError string
+		wantErr    error
+		want       *domain.ExternalIdentity
+	}{
+		{
+			name: "verified identity",
+			claims: jwt.MapClaims{
+				"sub":            "248289761001",
+				"email":          "jane@example.com",
+				"email_verified": true,
+				"given_name":     "Jane",
+				"family_name":    "Doe",
+			},
+			want: &domain.ExternalIdentity{
+				Provider:      "stub",
+				Subject:       "248289761001",
+				Email:         "jane@example.com",
+				EmailVerified: true,
+				GivenName:     "Jane",
+				FamilyName:    "Doe",
+			},
+		},
+		{
+			name:   "string email_verified",
+			claims: jwt.MapClaims{"email": "jane@example.com", "email_verified": "true"},
+			want: &domain.ExternalIdentity{
+				Provider:      "stub",
+				Subject:       "stub-subject",
+				Email:         "jane@example.com",
+				EmailVerified: true,
+			},
+		},
+		{name: "nonce mismatch", nonce: "other-nonce", wantErr: domain.ErrIDTokenInvalid},
+		{name: "wrong issuer", claims: jwt.MapClaims{"iss": "https://evil.example.com"}, wantErr: domain.ErrIDTokenInvalid},
+		{name: "wrong audience", claims: jwt.MapClaims{"aud": "someone-else"}, wantErr: domain.ErrIDTokenInvalid},
+		{
+			name:    "multiple audiences without azp",
+			claims:  jwt.MapClaims{"aud": []string{stubClientID, "other"}},
+			wantErr: domain.ErrIDTokenInvalid,
+		},
+		{
+			name:    "expired",
+			claims:  jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()},
+			wantErr: domain.ErrIDTokenInvalid,
+		},
+		{name: "missing subject", claims: jwt.MapClaims{"sub": ""}, wantErr: domain.ErrIDTokenInvalid},
+		{name: "wrong verifier", verifier: strings.Repeat("x", 43)},
+		{name: "provider error", tokenError: "access_denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			idp := newStubIdP(t)
+			idp.tokenError = tt.tokenError
+			provider := newTestProvider(t, idp)
+
+			pkce, err := domain.PKCEChallengeFor(testVerifier)
+			require.NoError(t, err)
+
+			authURL, err := provider.AuthCodeURL(ctx, "state", testNonce, pkce)
+			require.NoError(t, err)
+
+			code, state := idp.authorize(t, authURL, tt.claims)
+			assert.Equal(t, "state", state)
+
+			nonce := testNonce
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			verifier := testVerifier
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+
+			got, err := provider.Exchange(ctx, code, verifier, nonce)
+			if tt.want == nil {
+				require.Error(t, err)
+
+				if tt.wantErr != nil {
+					require.ErrorIs(t, err, tt.wantErr)
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOIDCProviderCachesKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	idp := newStubIdP(t)
+	provider := newTestProvider(t, idp)
+
+	pkce, err := domain.PKCEChallengeFor(testVerifier)
+	require.NoError(t, err)
+
+	for range 3 {
+		authURL, err := provider.AuthCodeURL(ctx, "state", testNonce, pkce)
+		require.NoError(t, err)
+
+		code, _ := idp.authorize(t, authURL, nil)
+
+		_, err = provider.Exchange(ctx, code, testVerifier, testNonce)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, idp.jwksHits)
+}
@@ -0,0 +1,181 @@
+package federation_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+)
+
+const (
+	stubClientID     = "go-auth"
+	stubClientSecret = "s3cr:et"
+	stubKeyID        = "stub-key"
+)
+
+// stubIdP is a minimal OpenID provider: it serves discovery, JWKS and a token endpoint that
+// redeems codes issued by authorize for the claims given there.
+type stubIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu         sync.Mutex
+	grants     map[string]stubGrant
+	jwksHits   int
+	tokenError string
+}
+
+type stubGrant struct {
+	redirectURI string
+	nonce       string
+	challenge   string
+	claims      jwt.MapClaims
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &stubIdP{key: key, grants: map[string]stubGrant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("GET /jwks", idp.jwks)
+	mux.HandleFunc("POST /token", idp.token)
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (s *stubIdP) issuer() string {
+	return s.server.URL
+}
+
+// authorize plays the user signing in at authURL and returns the code and state the provider
+// would redirect back with. claims override the defaults put in the ID token.
+func (s *stubIdP) authorize(t *testing.T, authURL string, claims jwt.MapClaims) (string, string) {
+	t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	query := parsed.Query()
+	require.Equal(t, stubClientID, query.Get("client_id"))
+	require.Equal(t, domain.PKCEMethodS256, query.Get("code_challenge_method"))
+
+	code := rand.Text()
+
+	s.mu.Lock()
+	s.grants[code] = stubGrant{
+		redirectURI: query.Get("redirect_uri"),
+		nonce:       query.Get("nonce"),
+		challenge:   query.Get("code_challenge"),
+		claims:      claims,
+	}
+	s.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func (s *stubIdP) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                 s.issuer(),
+		"authorization_endpoint": s.issuer() + "/authorize",
+		"token_endpoint":         s.issuer() + "/token",
+		"jwks_uri":               s.issuer() + "/jwks",
+	})
+}
+
+func (s *stubIdP) jwks(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	s.jwksHits++
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": stubKeyID,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+	}}})
+}
+
+func (s *stubIdP) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	clientID, _ := url.QueryUnescape(id)
+	clientSecret, _ := url.QueryUnescape(secret)
+
+	if !ok || clientID != stubClientID || clientSecret != stubClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+
+		return
+	}
+
+	s.mu.Lock()
+	grant, found := s.grants[r.PostFormValue("code")]
+	delete(s.grants, r.PostFormValue("code"))
+	tokenError := s.tokenError
+	s.mu.Unlock()
+
+	if tokenError != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": tokenError})
+
+		return
+	}
+
+	challenge := domain.PKCEChallenge{Challenge: grant.challenge, Method: domain.PKCEMethodS256}
+	if !found || r.PostFormValue("grant_type") != domain.GrantTypeAuthorizationCode ||
+		r.PostFormValue("redirect_uri") != grant.redirectURI || challenge.Verify(r.PostFormValue("code_verifier")) != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   s.issuer(),
+		"sub":   "stub-subject",
+		"aud":   stubClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": grant.nonce,
+	}
+
+	for k, v := range grant.claims {
+		claims[k] = v
+	}
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = stubKeyID
+
+	signed, err := tok.SignedString(s.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"access_token": "at", "token_type": "Bearer", "id_token": signed})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
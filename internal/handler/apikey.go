@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/middleware"
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+type createAPIKeyRequest struct {
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type apiKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Key        string     `json:"key,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func toAPIKeyResponse(k *service.APIKeyResponse) *apiKeyResponse {
+	scopes := k.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return &apiKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Key:        k.Key,
+		Scopes:     scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+func (h *AuthHandler) createAPIKey(w http.ResponseWriter, r *http.Request) {
+	var body createAPIKeyRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.CreateAPIKey(r.Context(), middleware.ClaimsFromContext(r.Context()),
+		&service.CreateAPIKeyRequest{
+			Name:      body.Name,
+			Scopes:    body.Scopes,
+			ExpiresAt: body.ExpiresAt,
+			UserAgent: r.UserAgent(),
+			ClientIP:  ip,
+		})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.Created(w, toAPIKeyResponse(res))
+}
+
+func (h *AuthHandler) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.ListAPIKeys(r.Context(), middleware.ClaimsFromContext(r.Context()))
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := make([]*apiKeyResponse, len(res))
+	for i, k := range res {
+		out[i] = toAPIKeyResponse(k)
+	}
+
+	response.OK(w, out)
+}
+
+func (h *AuthHandler) revokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.NotFound(apperror.ErrCodeAPIKeyNotFound, apperror.MsgAPIKeyNotFound, err))
+
+		return
+	}
+
+	if err = h.svc.RevokeAPIKey(r.Context(), middleware.ClaimsFromContext(r.Context()), id); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.NoContent(w)
+}
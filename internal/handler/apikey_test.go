@@ -0,0 +1,106 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/handler"
+	"go-auth/internal/service"
+)
+
+type stubAPIKeyService struct {
+	stubService
+
+	createReq *service.CreateAPIKeyRequest
+	revokedID uuid.UUID
+}
+
+func (s *stubAPIKeyService) CreateAPIKey(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *service.CreateAPIKeyRequest,
+) (*service.APIKeyResponse, error) {
+	s.createReq = req
+
+	return &service.APIKeyResponse{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Key:       domain.APIKeyPrefix + "key",
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}, nil
+}
+
+func (s *stubAPIKeyService) RevokeAPIKey(ctx context.Context, actor *domain.AccessClaims, id uuid.UUID) error {
+	s.revokedID = id
+
+	return nil
+}
+
+func TestAuthHandlerCreateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	svc := &stubAPIKeyService{}
+	mux := newFederatedMux(t, svc, handler.TransportBody, &domain.AccessClaims{UserID: uuid.New()})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/api-keys", strings.NewReader(
+		`{"name":"deploy","scopes":["user:read"],"expires_at":"2030-01-01T00:00:00Z"}`))
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var body struct {
+		Data struct {
+			Name   string   `json:"name"`
+			Key    string   `json:"key"`
+			Scopes []string `json:"scopes"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "deploy", body.Data.Name)
+	assert.Equal(t, domain.APIKeyPrefix+"key", body.Data.Key)
+	assert.Equal(t, []string{"user:read"}, body.Data.Scopes)
+
+	require.NotNil(t, svc.createReq)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), svc.createReq.ExpiresAt)
+	assert.Equal(t, "203.0.113.7", svc.createReq.ClientIP)
+}
+
+func TestAuthHandlerRevokeAPIKey(t *testing.T) {
+	t.Parallel()
+
+	svc := &stubAPIKeyService{}
+	mux := newFederatedMux(t, svc, handler.TransportBody, &domain.AccessClaims{UserID: uuid.New()})
+
+	t.Run("revokes", func(t *testing.T) {
+		id := uuid.New()
+
+		req := httptest.NewRequest(http.MethodDelete, "/auth/api-keys/"+id.String(), nil)
+		req.Header.Set("Authorization", "Bearer token")
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, id, svc.revokedID)
+	})
+	t.Run("malformed id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/auth/api-keys/not-a-uuid", nil)
+		req.Header.Set("Authorization", "Bearer token")
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,341 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/csrf"
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+type Transport string
+
+const (
+	TransportBody   Transport = "body"
+	TransportCookie Transport = "cookie"
+)
+
+const (
+	DefaultRefreshCookieName = "refresh_token"
+	DefaultRefreshCookiePath = "/auth/session"
+)
+
+type RefreshCookie struct {
+	Name     string
+	Domain   string
+	Path     string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+type AuthConfig struct {
+	Service    service.Service
+	IPResolver ClientIPResolver
+	Transport  Transport
+	Cookie     RefreshCookie
+	CSRF       *csrf.Protector
+}
+
+type AuthHandler struct {
+	svc        service.Service
+	ipResolver ClientIPResolver
+	transport  Transport
+	cookie     RefreshCookie
+	csrf       *csrf.Protector
+}
+
+func NewAuthHandler(cfg *AuthConfig) (*AuthHandler, error) {
+	if cfg == nil {
+		return nil, errors.New("auth handler config is required")
+	}
+
+	if cfg.Service == nil {
+		return nil, errors.New("service is required")
+	}
+
+	if cfg.IPResolver == nil {
+		return nil, errors.New("client IP resolver is required")
+	}
+
+	transport := cfg.Transport
+	if transport == "" {
+		transport = TransportBody
+	}
+
+	cookie := cfg.Cookie
+
+	switch transport {
+	case TransportBody:
+	case TransportCookie:
+		if cfg.CSRF == nil {
+			return nil, errors.New("CSRF protector is required for cookie transport")
+		}
+
+		if cookie.SameSite == http.SameSiteNoneMode && !cookie.Secure {
+			return nil, errors.New("SameSite=None refresh cookie must be Secure")
+		}
+	default:
+		return nil, fmt.Errorf("unknown token transport %q", transport)
+	}
+
+	if cookie.Name == "" {
+		cookie.Name = DefaultRefreshCookieName
+	}
+
+	if cookie.Path == "" {
+		cookie.Path = DefaultRefreshCookiePath
+	}
+
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteStrictMode
+	}
+
+	return &AuthHandler{
+		svc:        cfg.Service,
+		ipResolver: cfg.IPResolver,
+		transport:  transport,
+		cookie:     cookie,
+		csrf:       cfg.CSRF,
+	}, nil
+}
+
+func (h *AuthHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /auth/login", h.login)
+	mux.HandleFunc("POST /auth/session/refresh", h.refresh)
+	mux.HandleFunc("POST /auth/session/logout", h.logout)
+	mux.HandleFunc("POST /auth/magic-link", h.requestMagicLink)
+	mux.HandleFunc("POST /auth/magic-link/consume", h.consumeMagicLink)
+	mux.HandleFunc("POST /auth/federated/{provider}/start", h.startFederatedLogin)
+	mux.HandleFunc("POST /auth/federated/{provider}/callback", h.completeFederatedLogin)
+	// The link endpoints expect middleware.Authenticate in front of them.
+	mux.HandleFunc("POST /auth/federated/{provider}/link", h.startFederatedLink)
+	mux.HandleFunc("POST /auth/federated/{provider}/link/callback", h.completeFederatedLink)
+	mux.HandleFunc("POST /auth/passkeys/login/options", h.beginPasskeyLogin)
+	mux.HandleFunc("POST /auth/passkeys/login", h.finishPasskeyLogin)
+	// The passkey management endpoints expect middleware.Authenticate in front of them.
+	mux.HandleFunc("POST /auth/passkeys/register/options", h.beginPasskeyRegistration)
+	mux.HandleFunc("POST /auth/passkeys/register", h.finishPasskeyRegistration)
+	mux.HandleFunc("GET /auth/passkeys", h.listPasskeys)
+	mux.HandleFunc("DELETE /auth/passkeys/{id}", h.deletePasskey)
+	mux.HandleFunc("POST /auth/mfa/verify", h.verifyMFA)
+	// Changing the second factor and re-authenticating expect middleware.Authenticate in front of them.
+	mux.HandleFunc("PUT /auth/mfa", h.setMFAFactor)
+	mux.HandleFunc("POST /auth/reauthenticate", h.reauthenticate)
+	// The organisation endpoints expect middleware.Authenticate in front of them.
+	mux.HandleFunc("GET /auth/organizations", h.listOrganizations)
+	mux.HandleFunc("POST /auth/session/organization", h.switchOrganization)
+	mux.HandleFunc("POST /auth/organizations/{organization}/invitations", h.inviteToOrganization)
+	mux.HandleFunc("GET /auth/organizations/{organization}/invitations", h.listInvitations)
+	mux.HandleFunc("DELETE /auth/organizations/{organization}/invitations/{id}", h.revokeInvitation)
+	mux.HandleFunc("POST /auth/invitations/accept", h.acceptInvitation)
+	// The API key endpoints expect middleware.Authenticate in front of them.
+	mux.HandleFunc("POST /auth/api-keys", h.createAPIKey)
+	mux.HandleFunc("GET /auth/api-keys", h.listAPIKeys)
+	mux.HandleFunc("DELETE /auth/api-keys/{id}", h.revokeAPIKey)
+}
+
+type loginRequest struct {
+	Login        string `json:"login"`
+	Password     string `json:"password"`
+	Organization string `json:"organization"`
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type tokenResponse struct {
+	UserID           *uuid.UUID `json:"user_id,omitempty"`
+	TokenType        string     `json:"token_type"`
+	AccessToken      string     `json:"access_token"`
+	AccessExpiresAt  time.Time  `json:"access_expires_at"`
+	RefreshToken     string     `json:"refresh_token,omitempty"`
+	RefreshExpiresAt time.Time  `json:"refresh_expires_at"`
+	CSRFToken        string     `json:"csrf_token,omitempty"`
+}
+
+func (h *AuthHandler) login(w http.ResponseWriter, r *http.Request) {
+	var body loginRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.Login(r.Context(), &service.LoginRequest{
+		Login:        body.Login,
+		Password:     body.Password,
+		Organization: body.Organization,
+		UserAgent:    r.UserAgent(),
+		ClientIP:     ip,
+	})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	if res.MFA != nil {
+		response.Accepted(w, toMFAChallengeResponse(res.UserID, res.MFA))
+
+		return
+	}
+
+	out := &tokenResponse{
+		UserID:           &res.UserID,
+		TokenType:        "Bearer",
+		AccessToken:      res.AccessToken,
+		AccessExpiresAt:  res.AccessExpiresAt,
+		RefreshExpiresAt: res.RefreshExpiresAt,
+	}
+
+	if err = h.deliverRefreshToken(w, out, res.RefreshToken); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, out)
+}
+
+func (h *AuthHandler) refresh(w http.ResponseWriter, r *http.Request) {
+	refreshToken, err := h.readRefreshToken(w, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.Refresh(r.Context(), &service.RefreshRequest{
+		RefreshToken: refreshToken,
+		UserAgent:    r.UserAgent(),
+		ClientIP:     ip,
+	})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := &tokenResponse{
+		TokenType:        "Bearer",
+		AccessToken:      res.AccessToken,
+		AccessExpiresAt:  res.AccessExpiresAt,
+		RefreshExpiresAt: res.RefreshExpiresAt,
+	}
+
+	if err = h.deliverRefreshToken(w, out, res.RefreshToken); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, out)
+}
+
+func (h *AuthHandler) logout(w http.ResponseWriter, r *http.Request) {
+	refreshToken, err := h.readRefreshToken(w, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	if h.transport == TransportCookie {
+		h.clearCookies(w)
+	}
+
+	if err = h.svc.Logout(r.Context(), refreshToken); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *AuthHandler) deliverRefreshToken(w http.ResponseWriter, out *tokenResponse, refreshToken string) error {
+	if h.transport == TransportBody {
+		out.RefreshToken = refreshToken
+
+		return nil
+	}
+
+	csrfToken, err := h.csrf.Issue(w, refreshToken, out.RefreshExpiresAt)
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	http.SetCookie(w, h.refreshCookie(refreshToken, out.RefreshExpiresAt))
+
+	out.CSRFToken = csrfToken
+
+	return nil
+}
+
+func (h *AuthHandler) readRefreshToken(w http.ResponseWriter, r *http.Request) (string, error) {
+	if h.transport == TransportBody {
+		var body refreshTokenRequest
+		if err := decodeJSON(w, r, &body); err != nil {
+			return "", err
+		}
+
+		return body.RefreshToken, nil
+	}
+
+	return h.cookieRefreshToken(r)
+}
+
+func (h *AuthHandler) cookieRefreshToken(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(h.cookie.Name)
+	if err != nil || cookie.Value == "" {
+		return "", apperror.BadRequest(apperror.ErrCodeTokenRequired, apperror.MsgRefreshTokenRequired, err)
+	}
+
+	if err = h.csrf.Verify(r, cookie.Value); err != nil {
+		return "", apperror.Forbidden(apperror.ErrCodeCSRFInvalid, apperror.MsgCSRFTokenInvalid, err)
+	}
+
+	return cookie.Value, nil
+}
+
+func (h *AuthHandler) refreshCookie(value string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     h.cookie.Name,
+		Value:    value,
+		Path:     h.cookie.Path,
+		Domain:   h.cookie.Domain,
+		Expires:  expires,
+		Secure:   h.cookie.Secure,
+		HttpOnly: true,
+		SameSite: h.cookie.SameSite,
+	}
+}
+
+func (h *AuthHandler) clearCookies(w http.ResponseWriter) {
+	cookie := h.refreshCookie("", time.Unix(0, 0))
+	cookie.MaxAge = -1
+
+	http.SetCookie(w, cookie)
+	h.csrf.Clear(w)
+}
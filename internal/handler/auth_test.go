@@ -0,0 +1,357 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/csrf"
+	"go-auth/internal/handler"
+	"go-auth/internal/service"
+)
+
+type stubService struct {
+	service.Service
+
+	loginReq     *service.LoginRequest
+	refreshReq   *service.RefreshRequest
+	logoutToken  string
+	refreshToken string
+	err          error
+}
+
+func (s *stubService) Login(ctx context.Context, req *service.LoginRequest) (*service.LoginResponse, error) {
+	s.loginReq = req
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &service.LoginResponse{
+		UserID:           uuid.New(),
+		AccessToken:      "at",
+		RefreshToken:     s.refreshToken,
+		AccessExpiresAt:  time.Now().Add(15 * time.Minute),
+		RefreshExpiresAt: time.Now().Add(48 * time.Hour),
+	}, nil
+}
+
+func (s *stubService) Refresh(ctx context.Context, req *service.RefreshRequest) (*service.RefreshResponse, error) {
+	s.refreshReq = req
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &service.RefreshResponse{
+		AccessToken:      "at-2",
+		RefreshToken:     s.refreshToken,
+		AccessExpiresAt:  time.Now().Add(15 * time.Minute),
+		RefreshExpiresAt: time.Now().Add(48 * time.Hour),
+	}, nil
+}
+
+func (s *stubService) Logout(ctx context.Context, refreshToken string) error {
+	s.logoutToken = refreshToken
+
+	return s.err
+}
+
+type stubResolver struct{}
+
+func (stubResolver) Resolve(r *http.Request) (netip.Addr, error) {
+	return netip.MustParseAddr("203.0.113.7"), nil
+}
+
+type tokenBody struct {
+	Data struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		CSRFToken    string `json:"csrf_token"`
+	} `json:"data"`
+	Error struct {
+		Code apperror.Code `json:"code"`
+	} `json:"error"`
+}
+
+func newAuthMux(t *testing.T, svc service.Service, transport handler.Transport) *http.ServeMux {
+	t.Helper()
+
+	protector, err := csrf.New(csrf.Config{Secure: true})
+	require.NoError(t, err)
+
+	h, err := handler.NewAuthHandler(&handler.AuthConfig{
+		Service:    svc,
+		IPResolver: stubResolver{},
+		Transport:  transport,
+		Cookie:     handler.RefreshCookie{Secure: true},
+		CSRF:       protector,
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+
+	return mux
+}
+
+func serve(t *testing.T, mux http.Handler, req *http.Request) (*httptest.ResponseRecorder, tokenBody) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	var body tokenBody
+	if rec.Body.Len() > 0 {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	}
+
+	return rec, body
+}
+
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func TestNewAuthHandler(t *testing.T) {
+	t.Parallel()
+
+	protector, err := csrf.New(csrf.Config{})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		cfg     *handler.AuthConfig
+		wantErr bool
+	}{
+		{name: "nil config", cfg: nil, wantErr: true},
+		{name: "missing service", cfg: &handler.AuthConfig{IPResolver: stubResolver{}}, wantErr: true},
+		{name: "missing resolver", cfg: &handler.AuthConfig{Service: &stubService{}}, wantErr: true},
+		{name: "body default", cfg: &handler.AuthConfig{Service: &stubService{}, IPResolver: stubResolver{}}},
+		{
+			name: "cookie without csrf",
+			cfg: &handler.AuthConfig{
+				Service:    &stubService{},
+				IPResolver: stubResolver{},
+				Transport:  handler.TransportCookie,
+			},
+			wantErr: true,
+		},
+		{
+			name: "insecure samesite none",
+			cfg: &handler.AuthConfig{
+				Service:    &stubService{},
+				IPResolver: stubResolver{},
+				Transport:  handler.TransportCookie,
+				Cookie:     handler.RefreshCookie{SameSite: http.SameSiteNoneMode},
+				CSRF:       protector,
+			},
+			wantErr: true,
+		},
+		{
+			name:    "unknown transport",
+			cfg:     &handler.AuthConfig{Service: &stubService{}, IPResolver: stubResolver{}, Transport: "header"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := handler.NewAuthHandler(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthHandlerBodyTransport(t *testing.T) {
+	t.Parallel()
+
+	svc := &stubService{refreshToken: "rt"}
+	mux := newAuthMux(t, svc, handler.TransportBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"login":"alice","password":"pw"}`))
+	req.Header.Set("User-Agent", "test-agent")
+
+	rec, body := serve(t, mux, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "rt", body.Data.RefreshToken)
+	assert.Empty(t, body.Data.CSRFToken)
+	assert.Empty(t, rec.Result().Cookies())
+	assert.Equal(t, "alice", svc.loginReq.Login)
+	assert.Equal(t, "test-agent", svc.loginReq.UserAgent)
+	assert.Equal(t, "203.0.113.7", svc.loginReq.ClientIP)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/session/refresh", strings.NewReader(`{"refresh_token":"rt"}`))
+	rec, body = serve(t, mux, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "rt", svc.refreshReq.RefreshToken)
+	assert.Equal(t, "at-2", body.Data.AccessToken)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/session/logout", strings.NewReader(`{"refresh_token":"rt"}`))
+	rec, _ = serve(t, mux, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "rt", svc.logoutToken)
+}
+
+func TestAuthHandlerInvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	mux := newAuthMux(t, &stubService{}, handler.TransportBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"login":`))
+	rec, body := serve(t, mux, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, apperror.ErrCodeInvalidJSON, body.Error.Code)
+}
+
+func TestAuthHandlerCookieTransport(t *testing.T) {
+	t.Parallel()
+
+	svc := &stubService{refreshToken: "rt"}
+	mux := newAuthMux(t, svc, handler.TransportCookie)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"login":"alice","password":"pw"}`))
+	rec, body := serve(t, mux, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, body.Data.RefreshToken)
+	require.NotEmpty(t, body.Data.CSRFToken)
+
+	cookies := rec.Result().Cookies()
+	refreshCookie := findCookie(cookies, handler.DefaultRefreshCookieName)
+	require.NotNil(t, refreshCookie)
+	assert.Equal(t, "rt", refreshCookie.Value)
+	assert.True(t, refreshCookie.HttpOnly)
+	assert.True(t, refreshCookie.Secure)
+	assert.Equal(t, http.SameSiteStrictMode, refreshCookie.SameSite)
+	assert.Equal(t, handler.DefaultRefreshCookiePath, refreshCookie.Path)
+
+	csrfCookie := findCookie(cookies, csrf.DefaultCookieName)
+	require.NotNil(t, csrfCookie)
+	assert.Equal(t, body.Data.CSRFToken, csrfCookie.Value)
+
+	tests := []struct {
+		name       string
+		path       string
+		withCookie bool
+		csrfHeader string
+		wantStatus int
+		wantCode   apperror.Code
+	}{
+		{
+			name:       "refresh without cookie",
+			path:       "/auth/session/refresh",
+			csrfHeader: body.Data.CSRFToken,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   apperror.ErrCodeTokenRequired,
+		},
+		{
+			name:       "refresh without csrf header",
+			path:       "/auth/session/refresh",
+			withCookie: true,
+			wantStatus: http.StatusForbidden,
+			wantCode:   apperror.ErrCodeCSRFInvalid,
+		},
+		{
+			name:       "refresh with forged csrf header",
+			path:       "/auth/session/refresh",
+			withCookie: true,
+			csrfHeader: "forged",
+			wantStatus: http.StatusForbidden,
+			wantCode:   apperror.ErrCodeCSRFInvalid,
+		},
+		{
+			name:       "refresh",
+			path:       "/auth/session/refresh",
+			withCookie: true,
+			csrfHeader: body.Data.CSRFToken,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "logout without csrf header",
+			path:       "/auth/session/logout",
+			withCookie: true,
+			wantStatus: http.StatusForbidden,
+			wantCode:   apperror.ErrCodeCSRFInvalid,
+		},
+		{
+			name:       "logout",
+			path:       "/auth/session/logout",
+			withCookie: true,
+			csrfHeader: body.Data.CSRFToken,
+			wantStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			if tt.withCookie {
+				req.AddCookie(refreshCookie)
+				req.AddCookie(csrfCookie)
+			}
+
+			if tt.csrfHeader != "" {
+				req.Header.Set(csrf.DefaultHeaderName, tt.csrfHeader)
+			}
+
+			rec, got := serve(t, mux, req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantCode, got.Error.Code)
+
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "rt", svc.refreshReq.RefreshToken)
+				assert.NotNil(t, findCookie(rec.Result().Cookies(), handler.DefaultRefreshCookieName))
+			}
+
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Equal(t, "rt", svc.logoutToken)
+
+				cleared := findCookie(rec.Result().Cookies(), handler.DefaultRefreshCookieName)
+				require.NotNil(t, cleared)
+				assert.Equal(t, -1, cleared.MaxAge)
+			}
+		})
+	}
+}
+
+func TestAuthHandlerServiceError(t *testing.T) {
+	t.Parallel()
+
+	svc := &stubService{err: apperror.Unauthorized(apperror.ErrCodeInvalidCredentials, "Invalid credentials", nil)}
+	mux := newAuthMux(t, svc, handler.TransportBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"login":"alice","password":"pw"}`))
+	rec, body := serve(t, mux, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, apperror.ErrCodeInvalidCredentials, body.Error.Code)
+
+	svc.err = errors.New("boom")
+	req = httptest.NewRequest(http.MethodPost, "/auth/session/refresh", strings.NewReader(`{"refresh_token":"rt"}`))
+	rec, body = serve(t, mux, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, apperror.ErrCodeInternalServer, body.Error.Code)
+}
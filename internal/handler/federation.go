@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/middleware"
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+const (
+	federatedStateCookieName = "federated_state"
+	federatedStateCookiePath = "/auth/federated"
+)
+
+type federatedStartResponse struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type federatedCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+type identityResponse struct {
+	Provider string    `json:"provider"`
+	Subject  string    `json:"subject"`
+	Email    string    `json:"email,omitempty"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+func (h *AuthHandler) startFederatedLogin(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.StartFederatedLogin(r.Context(), r.PathValue("provider"))
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	h.writeFederatedStart(w, res)
+}
+
+func (h *AuthHandler) completeFederatedLogin(w http.ResponseWriter, r *http.Request) {
+	req, err := h.readFederatedCallback(w, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.CompleteFederatedLogin(r.Context(), req)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := &tokenResponse{
+		UserID:           &res.UserID,
+		TokenType:        "Bearer",
+		AccessToken:      res.AccessToken,
+		AccessExpiresAt:  res.AccessExpiresAt,
+		RefreshExpiresAt: res.RefreshExpiresAt,
+	}
+
+	if err = h.deliverRefreshToken(w, out, res.RefreshToken); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, out)
+}
+
+func (h *AuthHandler) startFederatedLink(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.StartFederatedLink(r.Context(), middleware.ClaimsFromContext(r.Context()), r.PathValue("provider"))
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	h.writeFederatedStart(w, res)
+}
+
+func (h *AuthHandler) completeFederatedLink(w http.ResponseWriter, r *http.Request) {
+	req, err := h.readFederatedCallback(w, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.CompleteFederatedLink(r.Context(), middleware.ClaimsFromContext(r.Context()), req)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, &identityResponse{
+		Provider: res.Provider,
+		Subject:  res.Subject,
+		Email:    res.Email,
+		LinkedAt: res.CreatedAt,
+	})
+}
+
+// writeFederatedStart returns the provider URL. With cookie transport the state is also bound
+// to the browser, so a callback cannot be forged into a victim's browser (login CSRF); body
+// transport clients must compare the state themselves, as any OAuth client would.
+func (h *AuthHandler) writeFederatedStart(w http.ResponseWriter, res *service.FederatedStartResponse) {
+	if h.transport == TransportCookie {
+		http.SetCookie(w, h.federatedStateCookie(res.State, res.ExpiresAt))
+	}
+
+	response.OK(w, &federatedStartResponse{
+		AuthorizationURL: res.AuthURL,
+		State:            res.State,
+		ExpiresAt:        res.ExpiresAt,
+	})
+}
+
+func (h *AuthHandler) readFederatedCallback(
+	w http.ResponseWriter,
+	r *http.Request,
+) (*service.FederatedCallbackRequest, error) {
+	var body federatedCallbackRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		return nil, err
+	}
+
+	if h.transport == TransportCookie {
+		cookie, err := r.Cookie(federatedStateCookieName)
+		if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(body.State)) != 1 {
+			return nil, apperror.BadRequest(apperror.ErrCodeFederatedStateInvalid, apperror.MsgFederatedStateInvalid, err)
+		}
+
+		cleared := h.federatedStateCookie("", time.Unix(0, 0))
+		cleared.MaxAge = -1
+		http.SetCookie(w, cleared)
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.FederatedCallbackRequest{
+		Provider:  r.PathValue("provider"),
+		Code:      body.Code,
+		State:     body.State,
+		UserAgent: r.UserAgent(),
+		ClientIP:  ip,
+	}, nil
+}
+
+func (h *AuthHandler) federatedStateCookie(value string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     federatedStateCookieName,
+		Value:    value,
+		Path:     federatedStateCookiePath,
+		Domain:   h.cookie.Domain,
+		Expires:  expires,
+		Secure:   h.cookie.Secure,
+		HttpOnly: true,
+		SameSite: h.cookie.SameSite,
+	}
+}
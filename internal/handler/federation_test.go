@@ -0,0 +1,237 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/csrf"
+	"go-auth/internal/domain"
+	"go-auth/internal/handler"
+	"go-auth/internal/middleware"
+	"go-auth/internal/service"
+)
+
+type stubFederatedService struct {
+	service.Service
+
+	startProvider string
+	startActor    *domain.AccessClaims
+	callbackReq   *service.FederatedCallbackRequest
+	linkActor     *domain.AccessClaims
+}
+
+func (s *stubFederatedService) StartFederatedLogin(
+	ctx context.Context,
+	provider string,
+) (*service.FederatedStartResponse, error) {
+	s.startProvider = provider
+
+	return &service.FederatedStartResponse{
+		AuthURL:   "https://idp.example.com/authorize?state=st",
+		State:     "st",
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}, nil
+}
+
+func (s *stubFederatedService) StartFederatedLink(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	provider string,
+) (*service.FederatedStartResponse, error) {
+	s.startActor = actor
+
+	return s.StartFederatedLogin(ctx, provider)
+}
+
+func (s *stubFederatedService) CompleteFederatedLogin(
+	ctx context.Context,
+	req *service.FederatedCallbackRequest,
+) (*service.LoginResponse, error) {
+	s.callbackReq = req
+
+	return &service.LoginResponse{
+		UserID:           uuid.New(),
+		AccessToken:      "at",
+		RefreshToken:     "rt",
+		AccessExpiresAt:  time.Now().Add(15 * time.Minute),
+		RefreshExpiresAt: time.Now().Add(48 * time.Hour),
+	}, nil
+}
+
+func (s *stubFederatedService) CompleteFederatedLink(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *service.FederatedCallbackRequest,
+) (*service.IdentityResponse, error) {
+	s.linkActor = actor
+	s.callbackReq = req
+
+	return &service.IdentityResponse{Provider: req.Provider, Subject: "sub", CreatedAt: time.Now()}, nil
+}
+
+func newFederatedMux(
+	t *testing.T,
+	svc service.Service,
+	transport handler.Transport,
+	claims *domain.AccessClaims,
+) http.Handler {
+	t.Helper()
+
+	protector, err := csrf.New(csrf.Config{Secure: true})
+	require.NoError(t, err)
+
+	h, err := handler.NewAuthHandler(&handler.AuthConfig{
+		Service:    svc,
+		IPResolver: stubResolver{},
+		Transport:  transport,
+		Cookie:     handler.RefreshCookie{Secure: true},
+		CSRF:       protector,
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+
+	return middleware.Authenticate(claimsValidator{claims: claims}, nil, nil)(mux)
+}
+
+func TestAuthHandlerFederatedLogin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("body transport", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubFederatedService{}
+		mux := newFederatedMux(t, svc, handler.TransportBody, nil)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/federated/google/start", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "google", svc.startProvider)
+		assert.Nil(t, findCookie(rec.Result().Cookies(), "federated_state"))
+
+		var start struct {
+			Data struct {
+				AuthorizationURL string `json:"authorization_url"`
+				State            string `json:"state"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &start))
+		assert.Equal(t, "https://idp.example.com/authorize?state=st", start.Data.AuthorizationURL)
+		assert.Equal(t, "st", start.Data.State)
+
+		req := httptest.NewRequest(
+			http.MethodPost,
+			"/auth/federated/google/callback",
+			strings.NewReader(`{"code":"c","state":"st"}`),
+		)
+		req.Header.Set("User-Agent", "Mozilla/5.0")
+
+		rec, body := serve(t, mux, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "rt", body.Data.RefreshToken)
+		assert.Equal(t, &service.FederatedCallbackRequest{
+			Provider:  "google",
+			Code:      "c",
+			State:     "st",
+			UserAgent: "Mozilla/5.0",
+			ClientIP:  "203.0.113.7",
+		}, svc.callbackReq)
+	})
+	t.Run("cookie transport binds state to the browser", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubFederatedService{}
+		mux := newFederatedMux(t, svc, handler.TransportCookie, nil)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/federated/google/start", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		stateCookie := findCookie(rec.Result().Cookies(), "federated_state")
+		require.NotNil(t, stateCookie)
+		assert.Equal(t, "st", stateCookie.Value)
+		assert.True(t, stateCookie.HttpOnly)
+		assert.Equal(t, "/auth/federated", stateCookie.Path)
+
+		callback := func(cookie *http.Cookie) *http.Request {
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/auth/federated/google/callback",
+				strings.NewReader(`{"code":"c","state":"st"}`),
+			)
+			if cookie != nil {
+				req.AddCookie(cookie)
+			}
+
+			return req
+		}
+
+		rec, body := serve(t, mux, callback(nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, apperror.ErrCodeFederatedStateInvalid, body.Error.Code)
+		assert.Nil(t, svc.callbackReq)
+
+		rec, body = serve(t, mux, callback(&http.Cookie{Name: "federated_state", Value: "other"}))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, apperror.ErrCodeFederatedStateInvalid, body.Error.Code)
+
+		rec, body = serve(t, mux, callback(stateCookie))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, body.Data.RefreshToken)
+		assert.NotEmpty(t, body.Data.CSRFToken)
+		assert.NotNil(t, findCookie(rec.Result().Cookies(), handler.DefaultRefreshCookieName))
+
+		cleared := findCookie(rec.Result().Cookies(), "federated_state")
+		require.NotNil(t, cleared)
+		assert.Negative(t, cleared.MaxAge)
+	})
+}
+
+func TestAuthHandlerFederatedLink(t *testing.T) {
+	t.Parallel()
+
+	actor := &domain.AccessClaims{UserID: uuid.New()}
+	svc := &stubFederatedService{}
+	mux := newFederatedMux(t, svc, handler.TransportBody, actor)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/federated/google/link", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, actor, svc.startActor)
+
+	req = httptest.NewRequest(
+		http.MethodPost,
+		"/auth/federated/google/link/callback",
+		strings.NewReader(`{"code":"c","state":"st"}`),
+	)
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, actor, svc.linkActor)
+
+	var body struct {
+		Data struct {
+			Provider string `json:"provider"`
+			Subject  string `json:"subject"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "google", body.Data.Provider)
+	assert.Equal(t, "sub", body.Data.Subject)
+}
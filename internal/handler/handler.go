@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/netip"
+
+	"go-auth/internal/apperror"
+)
+
+const maxBodyBytes = 1 << 20
+
+type ClientIPResolver interface {
+	Resolve(r *http.Request) (netip.Addr, error)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidJSON, apperror.MsgInvalidJSON, err)
+	}
+
+	return nil
+}
+
+func clientIP(resolver ClientIPResolver, r *http.Request) (string, error) {
+	addr, err := resolver.Resolve(r)
+	if err != nil {
+		return "", apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgClientIPInvalid, err)
+	}
+
+	return addr.String(), nil
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+const (
+	magicLinkCookieName = "magic_link_binding"
+	magicLinkCookiePath = "/auth/magic-link"
+)
+
+type magicLinkRequest struct {
+	Email        string `json:"email"`
+	Organization string `json:"organization"`
+}
+
+type magicLinkResponse struct {
+	Binding   string    `json:"binding,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type consumeMagicLinkRequest struct {
+	Token   string `json:"token"`
+	Binding string `json:"binding"`
+}
+
+func (h *AuthHandler) requestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var body magicLinkRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.RequestMagicLink(r.Context(), &service.MagicLinkRequest{
+		Email:        body.Email,
+		Organization: body.Organization,
+		UserAgent:    r.UserAgent(),
+		ClientIP:     ip,
+	})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := &magicLinkResponse{Binding: res.Binding, ExpiresAt: res.ExpiresAt}
+
+	if h.transport == TransportCookie && res.Binding != "" {
+		http.SetCookie(w, h.magicLinkCookie(res.Binding, res.ExpiresAt))
+		out.Binding = ""
+	}
+
+	response.Accepted(w, out)
+}
+
+func (h *AuthHandler) consumeMagicLink(w http.ResponseWriter, r *http.Request) {
+	var body consumeMagicLinkRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	if h.transport == TransportCookie {
+		body.Binding = ""
+		if cookie, err := r.Cookie(magicLinkCookieName); err == nil {
+			body.Binding = cookie.Value
+		}
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.ConsumeMagicLink(r.Context(), &service.ConsumeMagicLinkRequest{
+		Token:     body.Token,
+		Binding:   body.Binding,
+		UserAgent: r.UserAgent(),
+		ClientIP:  ip,
+	})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	if h.transport == TransportCookie {
+		cleared := h.magicLinkCookie("", time.Unix(0, 0))
+		cleared.MaxAge = -1
+		http.SetCookie(w, cleared)
+	}
+
+	out := &tokenResponse{
+		UserID:           &res.UserID,
+		TokenType:        "Bearer",
+		AccessToken:      res.AccessToken,
+		AccessExpiresAt:  res.AccessExpiresAt,
+		RefreshExpiresAt: res.RefreshExpiresAt,
+	}
+
+	if err = h.deliverRefreshToken(w, out, res.RefreshToken); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, out)
+}
+
+func (h *AuthHandler) magicLinkCookie(value string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     magicLinkCookieName,
+		Value:    value,
+		Path:     magicLinkCookiePath,
+		Domain:   h.cookie.Domain,
+		Expires:  expires,
+		Secure:   h.cookie.Secure,
+		HttpOnly: true,
+		SameSite: h.cookie.SameSite,
+	}
+}
@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/handler"
+	"go-auth/internal/service"
+)
+
+type stubMagicLinkService struct {
+	service.Service
+
+	requestReq *service.MagicLinkRequest
+	consumeReq *service.ConsumeMagicLinkRequest
+}
+
+func (s *stubMagicLinkService) RequestMagicLink(
+	ctx context.Context,
+	req *service.MagicLinkRequest,
+) (*service.MagicLinkResponse, error) {
+	s.requestReq = req
+
+	return &service.MagicLinkResponse{Binding: "bind", ExpiresAt: time.Now().Add(15 * time.Minute)}, nil
+}
+
+func (s *stubMagicLinkService) ConsumeMagicLink(
+	ctx context.Context,
+	req *service.ConsumeMagicLinkRequest,
+) (*service.LoginResponse, error) {
+	s.consumeReq = req
+
+	return &service.LoginResponse{
+		UserID:           uuid.New(),
+		AccessToken:      "at",
+		RefreshToken:     "rt",
+		AccessExpiresAt:  time.Now().Add(15 * time.Minute),
+		RefreshExpiresAt: time.Now().Add(48 * time.Hour),
+	}, nil
+}
+
+func TestAuthHandlerMagicLink(t *testing.T) {
+	t.Parallel()
+
+	request := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/auth/magic-link", strings.NewReader(`{"email":"a@example.com"}`))
+	}
+
+	consume := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/auth/magic-link/consume", strings.NewReader(body))
+	}
+
+	t.Run("body transport", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubMagicLinkService{}
+		mux := newAuthMux(t, svc, handler.TransportBody)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, request())
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "a@example.com", svc.requestReq.Email)
+		assert.Equal(t, "203.0.113.7", svc.requestReq.ClientIP)
+		assert.Nil(t, findCookie(rec.Result().Cookies(), "magic_link_binding"))
+
+		var res struct {
+			Data struct {
+				Binding string `json:"binding"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+		assert.Equal(t, "bind", res.Data.Binding)
+
+		rec, body := serve(t, mux, consume(`{"token":"tok","binding":"bind"}`))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "rt", body.Data.RefreshToken)
+		assert.Equal(t, "tok", svc.consumeReq.Token)
+		assert.Equal(t, "bind", svc.consumeReq.Binding)
+	})
+	t.Run("cookie transport keeps the binding in a cookie", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubMagicLinkService{}
+		mux := newAuthMux(t, svc, handler.TransportCookie)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, request())
+		require.Equal(t, http.StatusAccepted, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "bind")
+
+		binding := findCookie(rec.Result().Cookies(), "magic_link_binding")
+		require.NotNil(t, binding)
+		assert.Equal(t, "bind", binding.Value)
+		assert.True(t, binding.HttpOnly)
+		assert.Equal(t, "/auth/magic-link", binding.Path)
+
+		req := consume(`{"token":"tok","binding":"forged"}`)
+		req.AddCookie(binding)
+
+		rec, body := serve(t, mux, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, body.Data.RefreshToken)
+		assert.NotEmpty(t, body.Data.CSRFToken)
+		assert.Equal(t, "bind", svc.consumeReq.Binding)
+
+		cleared := findCookie(rec.Result().Cookies(), "magic_link_binding")
+		require.NotNil(t, cleared)
+		assert.Negative(t, cleared.MaxAge)
+	})
+}
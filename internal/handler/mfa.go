@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/middleware"
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+type mfaChallengeResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	MFAToken  string    `json:"mfa_token"`
+	Factor    string    `json:"factor"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type verifyMFARequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+type setMFAFactorRequest struct {
+	Factor string `json:"factor"`
+}
+
+func (h *AuthHandler) verifyMFA(w http.ResponseWriter, r *http.Request) {
+	var body verifyMFARequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.VerifyMFA(r.Context(), &service.VerifyMFARequest{
+		Token:     body.MFAToken,
+		Code:      body.Code,
+		UserAgent: r.UserAgent(),
+		ClientIP:  ip,
+	})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := &tokenResponse{
+		UserID:           &res.UserID,
+		TokenType:        "Bearer",
+		AccessToken:      res.AccessToken,
+		AccessExpiresAt:  res.AccessExpiresAt,
+		RefreshExpiresAt: res.RefreshExpiresAt,
+	}
+
+	if err = h.deliverRefreshToken(w, out, res.RefreshToken); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, out)
+}
+
+func (h *AuthHandler) setMFAFactor(w http.ResponseWriter, r *http.Request) {
+	var body setMFAFactorRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	err = h.svc.SetMFAFactor(r.Context(), middleware.ClaimsFromContext(r.Context()), &service.SetMFAFactorRequest{
+		Factor:    body.Factor,
+		UserAgent: r.UserAgent(),
+		ClientIP:  ip,
+	})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func toMFAChallengeResponse(userID uuid.UUID, challenge *service.MFAChallenge) *mfaChallengeResponse {
+	return &mfaChallengeResponse{
+		UserID:    userID,
+		MFAToken:  challenge.Token,
+		Factor:    challenge.Factor.String(),
+		ExpiresAt: challenge.ExpiresAt,
+	}
+}
@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/handler"
+	"go-auth/internal/service"
+)
+
+type stubMFAService struct {
+	service.Service
+
+	verifyReq *service.VerifyMFARequest
+	setActor  *domain.AccessClaims
+	setReq    *service.SetMFAFactorRequest
+}
+
+func (s *stubMFAService) Login(ctx context.Context, req *service.LoginRequest) (*service.LoginResponse, error) {
+	return &service.LoginResponse{
+		UserID: uuid.New(),
+		MFA: &service.MFAChallenge{
+			Token:     "mfa-token",
+			Factor:    domain.MFAFactorEmailOTP,
+			ExpiresAt: time.Now().Add(10 * time.Minute),
+		},
+	}, nil
+}
+
+func (s *stubMFAService) VerifyMFA(ctx context.Context, req *service.VerifyMFARequest) (*service.LoginResponse, error) {
+	s.verifyReq = req
+
+	return &service.LoginResponse{
+		UserID:           uuid.New(),
+		AccessToken:      "at",
+		RefreshToken:     "rt",
+		AccessExpiresAt:  time.Now().Add(15 * time.Minute),
+		RefreshExpiresAt: time.Now().Add(48 * time.Hour),
+	}, nil
+}
+
+func (s *stubMFAService) SetMFAFactor(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *service.SetMFAFactorRequest,
+) error {
+	s.setActor, s.setReq = actor, req
+
+	return nil
+}
+
+func TestAuthHandlerLoginWithMFA(t *testing.T) {
+	t.Parallel()
+
+	svc := &stubMFAService{}
+	mux := newAuthMux(t, svc, handler.TransportBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"login":"alice","password":"secret"}`))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var challenge struct {
+		Data struct {
+			MFAToken     string `json:"mfa_token"`
+			Factor       string `json:"factor"`
+			AccessToken  string `json:"access_token"`
+			RefreshToken string `json:"refresh_token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &challenge))
+	assert.Equal(t, "mfa-token", challenge.Data.MFAToken)
+	assert.Equal(t, "email_otp", challenge.Data.Factor)
+	assert.Empty(t, challenge.Data.AccessToken)
+	assert.Empty(t, challenge.Data.RefreshToken)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/mfa/verify",
+		strings.NewReader(`{"mfa_token":"mfa-token","code":"123456"}`))
+
+	rec, body := serve(t, mux, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "at", body.Data.AccessToken)
+	assert.Equal(t, "rt", body.Data.RefreshToken)
+
+	require.NotNil(t, svc.verifyReq)
+	assert.Equal(t, "mfa-token", svc.verifyReq.Token)
+	assert.Equal(t, "123456", svc.verifyReq.Code)
+	assert.Equal(t, "203.0.113.7", svc.verifyReq.ClientIP)
+}
+
+func TestAuthHandlerSetMFAFactor(t *testing.T) {
+	t.Parallel()
+
+	actor := &domain.AccessClaims{UserID: uuid.New()}
+	svc := &stubMFAService{}
+	mux := newFederatedMux(t, svc, handler.TransportBody, actor)
+
+	req := httptest.NewRequest(http.MethodPut, "/auth/mfa", strings.NewReader(`{"factor":"email_otp"}`))
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, actor, svc.setActor)
+	assert.Equal(t, "email_otp", svc.setReq.Factor)
+	assert.Equal(t, "203.0.113.7", svc.setReq.ClientIP)
+}
@@ -0,0 +1,421 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/middleware"
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+type OAuthConfig struct {
+	Service    service.Service
+	IPResolver ClientIPResolver
+	LoginURL   string
+}
+
+type OAuthHandler struct {
+	svc        service.Service
+	ipResolver ClientIPResolver
+	loginURL   *url.URL
+}
+
+func NewOAuthHandler(cfg *OAuthConfig) (*OAuthHandler, error) {
+	if cfg == nil {
+		return nil, errors.New("oauth handler config is required")
+	}
+
+	if cfg.Service == nil {
+		return nil, errors.New("service is required")
+	}
+
+	if cfg.IPResolver == nil {
+		return nil, errors.New("client IP resolver is required")
+	}
+
+	loginURL, err := url.Parse(cfg.LoginURL)
+	if err != nil || cfg.LoginURL == "" {
+		return nil, errors.New("oauth login URL is invalid")
+	}
+
+	return &OAuthHandler{
+		svc:        cfg.Service,
+		ipResolver: cfg.IPResolver,
+		loginURL:   loginURL,
+	}, nil
+}
+
+// Register mounts the endpoints; POST /oauth/authorize expects middleware.Authenticate in front of it.
+func (h *OAuthHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /oauth/authorize", h.startAuthorization)
+	mux.HandleFunc("POST /oauth/authorize", h.authorize)
+	mux.HandleFunc("POST /oauth/token", h.token)
+	mux.HandleFunc("POST /oauth/introspect", h.introspect)
+	mux.HandleFunc("POST /oauth/revoke", h.revoke)
+}
+
+type authorizeRequest struct {
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	Nonce               string `json:"nonce"`
+}
+
+type authorizeResponse struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+type oauthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+type introspectResponse struct {
+	Active    bool             `json:"active"`
+	TokenType string           `json:"token_type,omitempty"`
+	Scope     string           `json:"scope,omitempty"`
+	ClientID  string           `json:"client_id,omitempty"`
+	Subject   string           `json:"sub,omitempty"`
+	ExpiresAt int64            `json:"exp,omitempty"`
+	IssuedAt  int64            `json:"iat,omitempty"`
+	Actor     *introspectActor `json:"act,omitempty"`
+}
+
+type introspectActor struct {
+	Subject string `json:"sub"`
+}
+
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+func (h *OAuthHandler) startAuthorization(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &service.AuthorizeRequest{
+		ResponseType:        query.Get("response_type"),
+		ClientID:            query.Get("client_id"),
+		RedirectURI:         query.Get("redirect_uri"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+		Nonce:               query.Get("nonce"),
+	}
+
+	if err := h.svc.ValidateAuthorization(r.Context(), req); err != nil {
+		if redirectTo, ok := authorizationErrorRedirect(req, err); ok {
+			http.Redirect(w, r, redirectTo, http.StatusFound)
+
+			return
+		}
+
+		writeOAuthError(w, err)
+
+		return
+	}
+
+	login := *h.loginURL
+	login.RawQuery = r.URL.RawQuery
+
+	http.Redirect(w, r, login.String(), http.StatusFound)
+}
+
+func (h *OAuthHandler) authorize(w http.ResponseWriter, r *http.Request) {
+	var body authorizeRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	req := &service.AuthorizeRequest{
+		ResponseType:        body.ResponseType,
+		ClientID:            body.ClientID,
+		RedirectURI:         body.RedirectURI,
+		Scope:               body.Scope,
+		State:               body.State,
+		CodeChallenge:       body.CodeChallenge,
+		CodeChallengeMethod: body.CodeChallengeMethod,
+		Nonce:               body.Nonce,
+	}
+
+	res, err := h.svc.Authorize(r.Context(), middleware.ClaimsFromContext(r.Context()), req)
+	if err != nil {
+		if redirectTo, ok := authorizationErrorRedirect(req, err); ok {
+			response.OK(w, &authorizeResponse{RedirectTo: redirectTo})
+
+			return
+		}
+
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, &authorizeResponse{RedirectTo: res.RedirectTo})
+}
+
+func (h *OAuthHandler) token(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	clientID, clientSecret, err := parseClientForm(w, r, apperror.MsgTokenRequestRequired)
+	if err != nil {
+		writeOAuthError(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		writeOAuthError(w, err)
+
+		return
+	}
+
+	res, err := h.svc.Token(r.Context(), &service.TokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		Scope:        r.PostForm.Get("scope"),
+		UserAgent:    r.UserAgent(),
+		ClientIP:     ip,
+	})
+	if err != nil {
+		writeClientError(w, r, err)
+
+		return
+	}
+
+	response.JSON(w, http.StatusOK, &oauthTokenResponse{
+		AccessToken:  res.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(res.AccessExpiresAt).Round(time.Second).Seconds()),
+		RefreshToken: res.RefreshToken,
+		IDToken:      res.IDToken,
+		Scope:        domain.JoinScope(res.Scopes),
+	})
+}
+
+func (h *OAuthHandler) introspect(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	clientID, clientSecret, err := parseClientForm(w, r, apperror.MsgIntrospectRequired)
+	if err != nil {
+		writeOAuthError(w, err)
+
+		return
+	}
+
+	res, err := h.svc.Introspect(r.Context(), &service.IntrospectRequest{
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+	})
+	if err != nil {
+		writeClientError(w, r, err)
+
+		return
+	}
+
+	out := &introspectResponse{Active: res.Active}
+	if res.Active {
+		out.TokenType = res.TokenType
+		out.Scope = domain.JoinScope(res.Scopes)
+		out.ClientID = res.ClientID
+		out.Subject = res.Subject
+		out.ExpiresAt = unixOrZero(res.ExpiresAt)
+		out.IssuedAt = unixOrZero(res.IssuedAt)
+
+		if res.Actor != "" {
+			out.Actor = &introspectActor{Subject: res.Actor}
+		}
+	}
+
+	response.JSON(w, http.StatusOK, out)
+}
+
+// revoke answers 200 with an empty body whether or not the token was known (RFC 7009 section 2.2).
+func (h *OAuthHandler) revoke(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, err := parseClientForm(w, r, apperror.MsgRevokeRequired)
+	if err != nil {
+		writeOAuthError(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		writeOAuthError(w, err)
+
+		return
+	}
+
+	err = h.svc.Revoke(r.Context(), &service.RevokeRequest{
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+		UserAgent:     r.UserAgent(),
+		ClientIP:      ip,
+	})
+	if err != nil {
+		writeClientError(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func parseClientForm(w http.ResponseWriter, r *http.Request, invalidBody string) (string, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	if err := r.ParseForm(); err != nil {
+		return "", "", apperror.BadRequest(apperror.ErrCodeInvalidParam, invalidBody, err)
+	}
+
+	return clientCredentials(r)
+}
+
+func writeClientError(w http.ResponseWriter, r *http.Request, err error) {
+	if _, _, ok := r.BasicAuth(); ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+
+	writeOAuthError(w, err)
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+// clientCredentials reads client_secret_basic or client_secret_post credentials;
+// a client must not use both.
+func clientCredentials(r *http.Request) (string, string, error) {
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret"), nil
+	}
+
+	if r.PostForm.Has("client_secret") {
+		return "", "", apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgInvalidClient, nil)
+	}
+
+	// RFC 6749 section 2.3.1 form-encodes the credentials before Basic encoding.
+	clientID, err := url.QueryUnescape(user)
+	if err != nil {
+		return "", "", apperror.Unauthorized(apperror.ErrCodeInvalidClient, apperror.MsgInvalidClient, err)
+	}
+
+	clientSecret, err := url.QueryUnescape(pass)
+	if err != nil {
+		return "", "", apperror.Unauthorized(apperror.ErrCodeInvalidClient, apperror.MsgInvalidClient, err)
+	}
+
+	if formID := r.PostForm.Get("client_id"); formID != "" && formID != clientID {
+		return "", "", apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgInvalidClient, nil)
+	}
+
+	return clientID, clientSecret, nil
+}
+
+func authorizationErrorRedirect(req *service.AuthorizeRequest, err error) (string, bool) {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		return "", false
+	}
+
+	switch appErr.Code {
+	case apperror.ErrCodeInvalidClient, apperror.ErrCodeInvalidRedirectURI, apperror.ErrCodeUnauthorized:
+		return "", false
+	}
+
+	redirectTo, parseErr := url.Parse(req.RedirectURI)
+	if parseErr != nil {
+		return "", false
+	}
+
+	status, code := oauthErrorCode(appErr)
+	if status == http.StatusInternalServerError {
+		return "", false
+	}
+
+	query := redirectTo.Query()
+	query.Set("error", code)
+	query.Set("error_description", appErr.Message)
+
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+
+	redirectTo.RawQuery = query.Encode()
+
+	return redirectTo.String(), true
+}
+
+func writeOAuthError(w http.ResponseWriter, err error) {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		appErr = apperror.InternalServerError(apperror.ErrCodeInternalServer, "", err)
+	}
+
+	status, code := oauthErrorCode(appErr)
+
+	description := appErr.Message
+	if status == http.StatusInternalServerError {
+		description = ""
+	}
+
+	response.JSON(w, status, &oauthErrorResponse{Error: code, ErrorDescription: description})
+}
+
+func oauthErrorCode(err *apperror.Error) (int, string) {
+	switch err.Code {
+	case apperror.ErrCodeInvalidClient:
+		return http.StatusUnauthorized, "invalid_client"
+	case apperror.ErrCodeInvalidGrant:
+		return http.StatusBadRequest, "invalid_grant"
+	case apperror.ErrCodeInvalidScope:
+		return http.StatusBadRequest, "invalid_scope"
+	case apperror.ErrCodeUnsupportedGrantType:
+		return http.StatusBadRequest, "unsupported_grant_type"
+	case apperror.ErrCodeUnsupportedResponseType:
+		return http.StatusBadRequest, "unsupported_response_type"
+	case apperror.ErrCodeUnauthorizedClient:
+		return http.StatusBadRequest, "unauthorized_client"
+	case apperror.ErrCodeUnsupportedTokenType:
+		return http.StatusBadRequest, "unsupported_token_type"
+	}
+
+	switch {
+	case err.Status >= http.StatusInternalServerError:
+		return http.StatusInternalServerError, "server_error"
+	case err.Status == http.StatusForbidden:
+		return http.StatusForbidden, "access_denied"
+	default:
+		return http.StatusBadRequest, "invalid_request"
+	}
+}
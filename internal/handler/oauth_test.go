@@ -0,0 +1,503 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/handler"
+	"go-auth/internal/middleware"
+	"go-auth/internal/service"
+)
+
+const testLoginURL = "https://id.example.com/login"
+
+type stubOAuthService struct {
+	service.Service
+
+	validateErr    error
+	authorizeActor *domain.AccessClaims
+	authorizeErr   error
+	tokenReq       *service.TokenRequest
+	tokenErr       error
+	introspectReq  *service.IntrospectRequest
+	introspectRes  *service.IntrospectResponse
+	revokeReq      *service.RevokeRequest
+	revokeErr      error
+}
+
+func (s *stubOAuthService) ValidateAuthorization(ctx context.Context, req *service.AuthorizeRequest) error {
+	return s.validateErr
+}
+
+func (s *stubOAuthService) Authorize(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *service.AuthorizeRequest,
+) (*service.AuthorizeResponse, error) {
+	s.authorizeActor = actor
+	if s.authorizeErr != nil {
+		return nil, s.authorizeErr
+	}
+
+	return &service.AuthorizeResponse{RedirectTo: req.RedirectURI + "?code=c&state=" + req.State}, nil
+}
+
+func (s *stubOAuthService) Token(ctx context.Context, req *service.TokenRequest) (*service.TokenResponse, error) {
+	s.tokenReq = req
+	if s.tokenErr != nil {
+		return nil, s.tokenErr
+	}
+
+	return &service.TokenResponse{
+		AccessToken:      "at",
+		RefreshToken:     "rt",
+		AccessExpiresAt:  time.Now().Add(15 * time.Minute),
+		RefreshExpiresAt: time.Now().Add(48 * time.Hour),
+		Scopes:           []string{"profile", "user:read"},
+	}, nil
+}
+
+func (s *stubOAuthService) Introspect(
+	ctx context.Context,
+	req *service.IntrospectRequest,
+) (*service.IntrospectResponse, error) {
+	s.introspectReq = req
+	if s.introspectRes == nil {
+		return &service.IntrospectResponse{}, nil
+	}
+
+	return s.introspectRes, nil
+}
+
+func (s *stubOAuthService) Revoke(ctx context.Context, req *service.RevokeRequest) error {
+	s.revokeReq = req
+
+	return s.revokeErr
+}
+
+type claimsValidator struct{ claims *domain.AccessClaims }
+
+func (v claimsValidator) Validate(token string, audience ...string) (*domain.AccessClaims, error) {
+	return v.claims, nil
+}
+
+func newOAuthMux(t *testing.T, svc *stubOAuthService, claims *domain.AccessClaims) http.Handler {
+	t.Helper()
+
+	h, err := handler.NewOAuthHandler(&handler.OAuthConfig{
+		Service:    svc,
+		IPResolver: stubResolver{},
+		LoginURL:   testLoginURL,
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+
+	return middleware.Authenticate(claimsValidator{claims: claims}, nil, nil)(mux)
+}
+
+const authorizeQuery = "response_type=code&client_id=c1&redirect_uri=https%3A%2F%2Fapp.example.com%2Fcb" +
+	"&state=xyz&code_challenge=abc&code_challenge_method=S256"
+
+func TestOAuthHandlerStartAuthorization(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		validateErr  error
+		wantStatus   int
+		wantLocation string
+		wantError    string
+	}{
+		{
+			name:         "valid request goes to login",
+			wantStatus:   http.StatusFound,
+			wantLocation: testLoginURL + "?" + authorizeQuery,
+		},
+		{
+			name:         "invalid scope is reported to the client",
+			validateErr:  apperror.BadRequest(apperror.ErrCodeInvalidScope, apperror.MsgInvalidScope, nil),
+			wantStatus:   http.StatusFound,
+			wantLocation: "https://app.example.com/cb?error=invalid_scope",
+		},
+		{
+			name:        "unknown client is not redirected",
+			validateErr: apperror.BadRequest(apperror.ErrCodeInvalidClient, apperror.MsgInvalidClient, nil),
+			wantStatus:  http.StatusUnauthorized,
+			wantError:   "invalid_client",
+		},
+		{
+			name:        "unregistered redirect uri is not redirected",
+			validateErr: apperror.BadRequest(apperror.ErrCodeInvalidRedirectURI, apperror.MsgInvalidRedirectURI, nil),
+			wantStatus:  http.StatusBadRequest,
+			wantError:   "invalid_request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mux := newOAuthMux(t, &stubOAuthService{validateErr: tt.validateErr}, nil)
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+authorizeQuery, nil))
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantLocation != "" {
+				assert.True(t, strings.HasPrefix(rec.Header().Get("Location"), tt.wantLocation),
+					"location %q", rec.Header().Get("Location"))
+			}
+
+			if tt.wantError != "" {
+				var body map[string]string
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, tt.wantError, body["error"])
+			}
+		})
+	}
+}
+
+func TestOAuthHandlerAuthorize(t *testing.T) {
+	t.Parallel()
+
+	body := `{"response_type":"code","client_id":"c1","redirect_uri":"https://app.example.com/cb",` +
+		`"state":"xyz","code_challenge":"abc","code_challenge_method":"S256"}`
+
+	t.Run("passes the caller's claims", func(t *testing.T) {
+		t.Parallel()
+
+		claims := &domain.AccessClaims{UserID: uuid.New()}
+		svc := &stubOAuthService{}
+		mux := newOAuthMux(t, svc, claims)
+
+		req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer at")
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Same(t, claims, svc.authorizeActor)
+		assert.Equal(t, "https://app.example.com/cb?code=c&state=xyz", decodeRedirectTo(t, rec))
+	})
+
+	t.Run("redirectable error", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubOAuthService{
+			authorizeErr: apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgPKCERequired, nil),
+		}
+		mux := newOAuthMux(t, svc, &domain.AccessClaims{UserID: uuid.New()})
+
+		req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer at")
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		redirect, err := url.Parse(decodeRedirectTo(t, rec))
+		require.NoError(t, err)
+		assert.Equal(t, "invalid_request", redirect.Query().Get("error"))
+		assert.Equal(t, "xyz", redirect.Query().Get("state"))
+	})
+
+	t.Run("anonymous caller", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubOAuthService{
+			authorizeErr: apperror.Unauthorized(apperror.ErrCodeUnauthorized, apperror.MsgAuthenticationRequired, nil),
+		}
+		mux := newOAuthMux(t, svc, nil)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(body)))
+
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Nil(t, svc.authorizeActor)
+	})
+}
+
+func decodeRedirectTo(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+
+	var out struct {
+		Data struct {
+			RedirectTo string `json:"redirect_to"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+
+	return out.Data.RedirectTo
+}
+
+func TestOAuthHandlerToken(t *testing.T) {
+	t.Parallel()
+
+	form := url.Values{
+		"grant_type":    {domain.GrantTypeAuthorizationCode},
+		"code":          {"c"},
+		"redirect_uri":  {"https://app.example.com/cb"},
+		"code_verifier": {"v"},
+	}
+
+	newReq := func(values url.Values) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		return req
+	}
+
+	t.Run("client_secret_basic", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubOAuthService{}
+		req := newReq(form)
+		req.SetBasicAuth(url.QueryEscape("c1"), url.QueryEscape("s3cr:t"))
+
+		rec := httptest.NewRecorder()
+		newOAuthMux(t, svc, nil).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+		var out map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+		assert.Equal(t, "at", out["access_token"])
+		assert.Equal(t, "Bearer", out["token_type"])
+		assert.Equal(t, "rt", out["refresh_token"])
+		assert.Equal(t, "profile user:read", out["scope"])
+		assert.InDelta(t, 900, out["expires_in"], 2)
+
+		require.NotNil(t, svc.tokenReq)
+		assert.Equal(t, "c1", svc.tokenReq.ClientID)
+		assert.Equal(t, "s3cr:t", svc.tokenReq.ClientSecret)
+		assert.Equal(t, "v", svc.tokenReq.CodeVerifier)
+		assert.Equal(t, "203.0.113.7", svc.tokenReq.ClientIP)
+	})
+
+	t.Run("public client posts its id", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubOAuthService{}
+		values := url.Values{"client_id": {"spa"}}
+		for k, v := range form {
+			values[k] = v
+		}
+
+		rec := httptest.NewRecorder()
+		newOAuthMux(t, svc, nil).ServeHTTP(rec, newReq(values))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "spa", svc.tokenReq.ClientID)
+		assert.Empty(t, svc.tokenReq.ClientSecret)
+	})
+
+	t.Run("client credentials with scope", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubOAuthService{}
+		req := newReq(url.Values{"grant_type": {domain.GrantTypeClientCredentials}, "scope": {"user:read"}})
+		req.SetBasicAuth("svc", "s")
+
+		rec := httptest.NewRecorder()
+		newOAuthMux(t, svc, nil).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, domain.GrantTypeClientCredentials, svc.tokenReq.GrantType)
+		assert.Equal(t, "user:read", svc.tokenReq.Scope)
+	})
+
+	t.Run("both basic and post credentials", func(t *testing.T) {
+		t.Parallel()
+
+		values := url.Values{"client_secret": {"s"}}
+		for k, v := range form {
+			values[k] = v
+		}
+
+		req := newReq(values)
+		req.SetBasicAuth("c1", "s")
+
+		rec := httptest.NewRecorder()
+		newOAuthMux(t, &stubOAuthService{}, nil).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"error":"invalid_request"`)
+	})
+
+	errorTests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "invalid client",
+			err:        apperror.Unauthorized(apperror.ErrCodeInvalidClient, apperror.MsgInvalidClient, nil),
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "invalid_client",
+		},
+		{
+			name:       "invalid grant",
+			err:        apperror.BadRequest(apperror.ErrCodeInvalidGrant, apperror.MsgInvalidGrant, nil),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid_grant",
+		},
+		{
+			name:       "unsupported grant type",
+			err:        apperror.BadRequest(apperror.ErrCodeUnsupportedGrantType, apperror.MsgUnsupportedGrantType, nil),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "unsupported_grant_type",
+		},
+		{
+			name:       "grant type not allowed for client",
+			err:        apperror.BadRequest(apperror.ErrCodeUnauthorizedClient, apperror.MsgUnauthorizedClient, nil),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "unauthorized_client",
+		},
+		{
+			name:       "internal error",
+			err:        apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetAuthCode, nil),
+			wantStatus: http.StatusInternalServerError,
+			wantError:  "server_error",
+		},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := newReq(form)
+			req.SetBasicAuth("c1", "s")
+
+			rec := httptest.NewRecorder()
+			newOAuthMux(t, &stubOAuthService{tokenErr: tt.err}, nil).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			var out map[string]string
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+			assert.Equal(t, tt.wantError, out["error"])
+			assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func newFormRequest(path string, values url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return req
+}
+
+func TestOAuthHandlerIntrospect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("active token", func(t *testing.T) {
+		t.Parallel()
+
+		expiresAt := time.Now().Add(time.Minute).Truncate(time.Second)
+		svc := &stubOAuthService{introspectRes: &service.IntrospectResponse{
+			Active:    true,
+			TokenType: domain.TokenTypeRefreshToken,
+			Subject:   "user-1",
+			ClientID:  "app",
+			Scopes:    []string{"profile", "email"},
+			ExpiresAt: expiresAt,
+		}}
+
+		req := newFormRequest("/oauth/introspect", url.Values{
+			"token":           {"rt"},
+			"token_type_hint": {domain.TokenTypeRefreshToken},
+		})
+		req.SetBasicAuth("rs", "s")
+
+		rec := httptest.NewRecorder()
+		newOAuthMux(t, svc, nil).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+		var out map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+		assert.Equal(t, true, out["active"])
+		assert.Equal(t, "refresh_token", out["token_type"])
+		assert.Equal(t, "profile email", out["scope"])
+		assert.Equal(t, "app", out["client_id"])
+		assert.Equal(t, "user-1", out["sub"])
+		assert.InDelta(t, expiresAt.Unix(), out["exp"], 0)
+		assert.NotContains(t, out, "iat")
+
+		require.NotNil(t, svc.introspectReq)
+		assert.Equal(t, "rs", svc.introspectReq.ClientID)
+		assert.Equal(t, "s", svc.introspectReq.ClientSecret)
+		assert.Equal(t, "rt", svc.introspectReq.Token)
+		assert.Equal(t, domain.TokenTypeRefreshToken, svc.introspectReq.TokenTypeHint)
+	})
+
+	t.Run("inactive token", func(t *testing.T) {
+		t.Parallel()
+
+		req := newFormRequest("/oauth/introspect", url.Values{"token": {"x"}})
+		req.SetBasicAuth("rs", "s")
+
+		rec := httptest.NewRecorder()
+		newOAuthMux(t, &stubOAuthService{}, nil).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"active":false}`, rec.Body.String())
+	})
+}
+
+func TestOAuthHandlerRevoke(t *testing.T) {
+	t.Parallel()
+
+	t.Run("revoked", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubOAuthService{}
+		values := url.Values{"client_id": {"spa"}, "token": {"rt"}}
+
+		rec := httptest.NewRecorder()
+		newOAuthMux(t, svc, nil).ServeHTTP(rec, newFormRequest("/oauth/revoke", values))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Body.String())
+
+		require.NotNil(t, svc.revokeReq)
+		assert.Equal(t, "spa", svc.revokeReq.ClientID)
+		assert.Equal(t, "rt", svc.revokeReq.Token)
+		assert.Equal(t, "203.0.113.7", svc.revokeReq.ClientIP)
+	})
+
+	t.Run("access token", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubOAuthService{revokeErr: apperror.BadRequest(
+			apperror.ErrCodeUnsupportedTokenType, apperror.MsgUnsupportedTokenType, nil,
+		)}
+
+		rec := httptest.NewRecorder()
+		newOAuthMux(t, svc, nil).ServeHTTP(rec, newFormRequest("/oauth/revoke", url.Values{"token": {"jwt"}}))
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"error":"unsupported_token_type"`)
+	})
+}
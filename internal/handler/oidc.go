@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/middleware"
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+type OIDCConfig struct {
+	Service  service.Service
+	IDTokens domain.IDTokenManager
+	Issuer   string
+}
+
+type OIDCHandler struct {
+	svc       service.Service
+	discovery *discoveryResponse
+	jwks      *jwksResponse
+}
+
+type discoveryResponse struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+type jwksResponse struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+type userInfoResponse struct {
+	Subject           string `json:"sub"`
+	Name              string `json:"name,omitempty"`
+	GivenName         string `json:"given_name,omitempty"`
+	FamilyName        string `json:"family_name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
+}
+
+func NewOIDCHandler(cfg *OIDCConfig) (*OIDCHandler, error) {
+	if cfg == nil {
+		return nil, errors.New("oidc handler config is required")
+	}
+
+	if cfg.Service == nil {
+		return nil, errors.New("service is required")
+	}
+
+	if cfg.IDTokens == nil {
+		return nil, errors.New("id token manager is required")
+	}
+
+	issuer, err := url.Parse(cfg.Issuer)
+	if err != nil || issuer.Scheme == "" || issuer.Host == "" || issuer.RawQuery != "" || issuer.Fragment != "" {
+		return nil, errors.New("oidc issuer must be an absolute URL without query or fragment")
+	}
+
+	keys := cfg.IDTokens.PublicKeys()
+	jwks := &jwksResponse{Keys: make([]jsonWebKey, len(keys))}
+
+	for i, key := range keys {
+		jwks.Keys[i] = jsonWebKey{
+			KeyType:   key.KeyType,
+			KeyID:     key.KeyID,
+			Use:       key.Use,
+			Algorithm: key.Algorithm,
+			N:         key.N,
+			E:         key.E,
+		}
+	}
+
+	return &OIDCHandler{
+		svc:       cfg.Service,
+		discovery: newDiscovery(strings.TrimSuffix(cfg.Issuer, "/"), cfg.IDTokens.Algorithm()),
+		jwks:      jwks,
+	}, nil
+}
+
+func newDiscovery(issuer, algorithm string) *discoveryResponse {
+	return &discoveryResponse{
+		Issuer:                issuer,
+		AuthorizationEndpoint: issuer + "/oauth/authorize",
+		TokenEndpoint:         issuer + "/oauth/token",
+		UserinfoEndpoint:      issuer + "/userinfo",
+		JWKSURI:               issuer + "/.well-known/jwks.json",
+		IntrospectionEndpoint: issuer + "/oauth/introspect",
+		RevocationEndpoint:    issuer + "/oauth/revoke",
+		ScopesSupported:       []string{domain.ScopeOpenID, domain.ScopeProfile, domain.ScopeEmail},
+		ResponseTypesSupported: []string{
+			domain.ResponseTypeCode,
+		},
+		GrantTypesSupported: []string{
+			domain.GrantTypeAuthorizationCode,
+			domain.GrantTypeRefreshToken,
+			domain.GrantTypeClientCredentials,
+		},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{algorithm},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{domain.PKCEMethodS256},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "iat", "nonce",
+			"name", "given_name", "family_name", "preferred_username", "email", "email_verified",
+		},
+	}
+}
+
+// Register mounts the endpoints; /userinfo expects middleware.Authenticate in front of it.
+func (h *OIDCHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /.well-known/openid-configuration", h.openIDConfiguration)
+	mux.HandleFunc("GET /.well-known/jwks.json", h.jwksKeys)
+	mux.HandleFunc("GET /userinfo", h.userInfo)
+	mux.HandleFunc("POST /userinfo", h.userInfo)
+}
+
+func (h *OIDCHandler) openIDConfiguration(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	response.JSON(w, http.StatusOK, h.discovery)
+}
+
+func (h *OIDCHandler) jwksKeys(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	response.JSON(w, http.StatusOK, h.jwks)
+}
+
+func (h *OIDCHandler) userInfo(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.UserInfo(r.Context(), middleware.ClaimsFromContext(r.Context()))
+	if err != nil {
+		var appErr *apperror.Error
+		if errors.As(err, &appErr) {
+			switch appErr.Status {
+			case http.StatusUnauthorized:
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			case http.StatusForbidden:
+				w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+			}
+		}
+
+		response.Error(w, err)
+
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, http.StatusOK, &userInfoResponse{
+		Subject:           res.Subject,
+		Name:              res.Name,
+		GivenName:         res.GivenName,
+		FamilyName:        res.FamilyName,
+		PreferredUsername: res.PreferredUsername,
+		Email:             res.Email,
+		EmailVerified:     res.EmailVerified,
+	})
+}
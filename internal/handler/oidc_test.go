@@ -0,0 +1,184 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/handler"
+	"go-auth/internal/middleware"
+	"go-auth/internal/service"
+)
+
+const testIssuer = "https://id.example.com/"
+
+type stubOIDCService struct {
+	service.Service
+
+	userInfoActor *domain.AccessClaims
+	userInfoErr   error
+}
+
+func (s *stubOIDCService) UserInfo(ctx context.Context, actor *domain.AccessClaims) (*service.UserInfoResponse, error) {
+	s.userInfoActor = actor
+	if s.userInfoErr != nil {
+		return nil, s.userInfoErr
+	}
+
+	return &service.UserInfoResponse{Subject: "user-1", Email: "alice@example.com"}, nil
+}
+
+type stubIDTokens struct{}
+
+func (stubIDTokens) Generate(domain.IDClaims) (string, error) { return "id-token", nil }
+func (stubIDTokens) Algorithm() string                        { return "RS256" }
+func (stubIDTokens) TTL() time.Duration                       { return time.Hour }
+
+func (stubIDTokens) PublicKeys() []domain.JSONWebKey {
+	return []domain.JSONWebKey{{KeyType: "RSA", KeyID: "k1", Use: "sig", Algorithm: "RS256", N: "n", E: "AQAB"}}
+}
+
+func newOIDCMux(t *testing.T, svc *stubOIDCService, claims *domain.AccessClaims) http.Handler {
+	t.Helper()
+
+	h, err := handler.NewOIDCHandler(&handler.OIDCConfig{Service: svc, IDTokens: stubIDTokens{}, Issuer: testIssuer})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+
+	return middleware.Authenticate(claimsValidator{claims: claims}, nil, nil)(mux)
+}
+
+func TestNewOIDCHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *handler.OIDCConfig
+	}{
+		{name: "nil config"},
+		{name: "missing service", cfg: &handler.OIDCConfig{IDTokens: stubIDTokens{}, Issuer: testIssuer}},
+		{name: "missing id tokens", cfg: &handler.OIDCConfig{Service: &stubOIDCService{}, Issuer: testIssuer}},
+		{
+			name: "relative issuer",
+			cfg:  &handler.OIDCConfig{Service: &stubOIDCService{}, IDTokens: stubIDTokens{}, Issuer: "/id"},
+		},
+		{
+			name: "issuer with query",
+			cfg: &handler.OIDCConfig{
+				Service:  &stubOIDCService{},
+				IDTokens: stubIDTokens{},
+				Issuer:   "https://id.example.com?x=1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := handler.NewOIDCHandler(tt.cfg)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestOIDCHandlerDiscovery(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	newOIDCMux(t, &stubOIDCService{}, nil).ServeHTTP(rec,
+		httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+	assert.Equal(t, "https://id.example.com", out["issuer"])
+	assert.Equal(t, "https://id.example.com/oauth/authorize", out["authorization_endpoint"])
+	assert.Equal(t, "https://id.example.com/oauth/token", out["token_endpoint"])
+	assert.Equal(t, "https://id.example.com/userinfo", out["userinfo_endpoint"])
+	assert.Equal(t, "https://id.example.com/.well-known/jwks.json", out["jwks_uri"])
+	assert.Equal(t, "https://id.example.com/oauth/introspect", out["introspection_endpoint"])
+	assert.Equal(t, "https://id.example.com/oauth/revoke", out["revocation_endpoint"])
+	assert.Equal(t, []any{"RS256"}, out["id_token_signing_alg_values_supported"])
+	assert.Equal(t, []any{"openid", "profile", "email"}, out["scopes_supported"])
+	assert.Equal(t, []any{"S256"}, out["code_challenge_methods_supported"])
+}
+
+func TestOIDCHandlerJWKS(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	newOIDCMux(t, &stubOIDCService{}, nil).ServeHTTP(rec,
+		httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"keys":[{"kty":"RSA","kid":"k1","use":"sig","alg":"RS256","n":"n","e":"AQAB"}]}`,
+		rec.Body.String())
+}
+
+func TestOIDCHandlerUserInfo(t *testing.T) {
+	t.Parallel()
+
+	claims := &domain.AccessClaims{ClientID: "c1", Scopes: []string{domain.ScopeOpenID}}
+
+	tests := []struct {
+		name          string
+		method        string
+		err           error
+		wantStatus    int
+		wantChallenge string
+	}{
+		{name: "get", method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "post", method: http.MethodPost, wantStatus: http.StatusOK},
+		{
+			name:   "invalid token",
+			method: http.MethodGet,
+			err: apperror.Unauthorized(
+				apperror.ErrCodeUnauthorized, apperror.MsgAuthenticationRequired, nil,
+			),
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:   "insufficient scope",
+			method: http.MethodGet,
+			err: apperror.Forbidden(
+				apperror.ErrCodeInsufficientScope, apperror.MsgInsufficientScope, nil,
+			),
+			wantStatus:    http.StatusForbidden,
+			wantChallenge: `Bearer error="insufficient_scope", scope="openid"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &stubOIDCService{userInfoErr: tt.err}
+			req := httptest.NewRequest(tt.method, "/userinfo", nil)
+			req.Header.Set("Authorization", "Bearer at")
+
+			rec := httptest.NewRecorder()
+			newOIDCMux(t, svc, claims).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantChallenge, rec.Header().Get("WWW-Authenticate"))
+			assert.Same(t, claims, svc.userInfoActor)
+
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, `{"sub":"user-1","email":"alice@example.com"}`, rec.Body.String())
+			}
+		})
+	}
+}
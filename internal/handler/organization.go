@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/middleware"
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+type switchOrganizationRequest struct {
+	Organization string `json:"organization"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type organizationMembershipResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Slug     string    `json:"slug"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+	Active   bool      `json:"active"`
+}
+
+func (h *AuthHandler) listOrganizations(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.ListOrganizations(r.Context(), middleware.ClaimsFromContext(r.Context()))
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := make([]*organizationMembershipResponse, len(res))
+	for i, m := range res {
+		out[i] = &organizationMembershipResponse{
+			ID:       m.ID,
+			Slug:     m.Slug,
+			Name:     m.Name,
+			Role:     m.Role,
+			JoinedAt: m.JoinedAt,
+			Active:   m.Active,
+		}
+	}
+
+	response.OK(w, out)
+}
+
+func (h *AuthHandler) switchOrganization(w http.ResponseWriter, r *http.Request) {
+	var body switchOrganizationRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	refreshToken := body.RefreshToken
+
+	if h.transport == TransportCookie {
+		var err error
+		if refreshToken, err = h.cookieRefreshToken(r); err != nil {
+			response.Error(w, err)
+
+			return
+		}
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.SwitchOrganization(r.Context(), middleware.ClaimsFromContext(r.Context()),
+		&service.SwitchOrganizationRequest{
+			Organization: body.Organization,
+			RefreshToken: refreshToken,
+			UserAgent:    r.UserAgent(),
+			ClientIP:     ip,
+		})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := &tokenResponse{
+		TokenType:        "Bearer",
+		AccessToken:      res.AccessToken,
+		AccessExpiresAt:  res.AccessExpiresAt,
+		RefreshExpiresAt: res.RefreshExpiresAt,
+	}
+
+	if err = h.deliverRefreshToken(w, out, res.RefreshToken); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, out)
+}
+
+type inviteRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type acceptInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+type invitationResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	Status     string     `json:"status"`
+	InviterID  uuid.UUID  `json:"inviter_id"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func toInvitationResponse(i *service.InvitationResponse) *invitationResponse {
+	return &invitationResponse{
+		ID:         i.ID,
+		Email:      i.Email,
+		Role:       i.Role,
+		Status:     i.Status,
+		InviterID:  i.InviterID,
+		ExpiresAt:  i.ExpiresAt,
+		AcceptedAt: i.AcceptedAt,
+		CreatedAt:  i.CreatedAt,
+	}
+}
+
+func (h *AuthHandler) inviteToOrganization(w http.ResponseWriter, r *http.Request) {
+	var body inviteRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.InviteToOrganization(r.Context(), middleware.ClaimsFromContext(r.Context()),
+		&service.InviteRequest{
+			Organization: r.PathValue("organization"),
+			Email:        body.Email,
+			Role:         body.Role,
+			UserAgent:    r.UserAgent(),
+			ClientIP:     ip,
+		})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.Created(w, toInvitationResponse(res))
+}
+
+func (h *AuthHandler) listInvitations(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.ListInvitations(r.Context(), middleware.ClaimsFromContext(r.Context()),
+		r.PathValue("organization"))
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := make([]*invitationResponse, len(res))
+	for i, invitation := range res {
+		out[i] = toInvitationResponse(invitation)
+	}
+
+	response.OK(w, out)
+}
+
+func (h *AuthHandler) revokeInvitation(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.NotFound(apperror.ErrCodeInvitationNotFound, apperror.MsgInvitationNotFound, err))
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	err = h.svc.RevokeInvitation(r.Context(), middleware.ClaimsFromContext(r.Context()),
+		&service.InvitationActionRequest{
+			Organization: r.PathValue("organization"),
+			InvitationID: id,
+			UserAgent:    r.UserAgent(),
+			ClientIP:     ip,
+		})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *AuthHandler) acceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var body acceptInvitationRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.AcceptInvitation(r.Context(), middleware.ClaimsFromContext(r.Context()),
+		&service.AcceptInvitationRequest{
+			Token:     body.Token,
+			UserAgent: r.UserAgent(),
+			ClientIP:  ip,
+		})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, &organizationMembershipResponse{
+		ID:       res.ID,
+		Slug:     res.Slug,
+		Name:     res.Name,
+		Role:     res.Role,
+		JoinedAt: res.JoinedAt,
+		Active:   res.Active,
+	})
+}
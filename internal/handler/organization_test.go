@@ -0,0 +1,179 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/csrf"
+	"go-auth/internal/domain"
+	"go-auth/internal/handler"
+	"go-auth/internal/service"
+)
+
+type stubOrganizationService struct {
+	stubService
+
+	switchReq *service.SwitchOrganizationRequest
+}
+
+func (s *stubOrganizationService) ListOrganizations(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+) ([]*service.OrganizationMembershipResponse, error) {
+	return []*service.OrganizationMembershipResponse{
+		{ID: actor.OrganizationID, Slug: "acme", Name: "Acme", Role: domain.RoleAdmin, Active: true},
+	}, nil
+}
+
+func (s *stubOrganizationService) SwitchOrganization(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *service.SwitchOrganizationRequest,
+) (*service.RefreshResponse, error) {
+	s.switchReq = req
+
+	return &service.RefreshResponse{
+		AccessToken:      "scoped",
+		RefreshToken:     "rt-2",
+		AccessExpiresAt:  time.Now().Add(15 * time.Minute),
+		RefreshExpiresAt: time.Now().Add(48 * time.Hour),
+	}, nil
+}
+
+func TestAuthHandlerListOrganizations(t *testing.T) {
+	t.Parallel()
+
+	actor := &domain.AccessClaims{UserID: uuid.New(), OrganizationID: uuid.New()}
+	mux := newFederatedMux(t, &stubOrganizationService{}, handler.TransportBody, actor)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/organizations", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data []struct {
+			ID     uuid.UUID `json:"id"`
+			Slug   string    `json:"slug"`
+			Role   string    `json:"role"`
+			Active bool      `json:"active"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, actor.OrganizationID, body.Data[0].ID)
+	assert.Equal(t, "acme", body.Data[0].Slug)
+	assert.Equal(t, domain.RoleAdmin, body.Data[0].Role)
+	assert.True(t, body.Data[0].Active)
+}
+
+func TestAuthHandlerSwitchOrganization(t *testing.T) {
+	t.Parallel()
+
+	actor := &domain.AccessClaims{UserID: uuid.New()}
+
+	t.Run("body transport", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubOrganizationService{}
+		mux := newFederatedMux(t, svc, handler.TransportBody, actor)
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/session/organization",
+			strings.NewReader(`{"organization":"acme","refresh_token":"rt"}`))
+		req.Header.Set("Authorization", "Bearer token")
+
+		rec, body := serve(t, mux, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "scoped", body.Data.AccessToken)
+		assert.Equal(t, "rt-2", body.Data.RefreshToken)
+
+		require.NotNil(t, svc.switchReq)
+		assert.Equal(t, "acme", svc.switchReq.Organization)
+		assert.Equal(t, "rt", svc.switchReq.RefreshToken)
+		assert.Equal(t, "203.0.113.7", svc.switchReq.ClientIP)
+	})
+	t.Run("cookie transport", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubOrganizationService{stubService: stubService{refreshToken: "rt"}}
+		mux := newFederatedMux(t, svc, handler.TransportCookie, actor)
+
+		login := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"login":"alice","password":"pw"}`))
+		rec, session := serve(t, mux, login)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		cookies := rec.Result().Cookies()
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/session/organization",
+			strings.NewReader(`{"organization":"acme"}`))
+		req.Header.Set("Authorization", "Bearer token")
+		req.Header.Set(csrf.DefaultHeaderName, session.Data.CSRFToken)
+		req.AddCookie(findCookie(cookies, handler.DefaultRefreshCookieName))
+		req.AddCookie(findCookie(cookies, csrf.DefaultCookieName))
+
+		rec, body := serve(t, mux, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, body.Data.RefreshToken)
+		assert.NotEmpty(t, body.Data.CSRFToken)
+		assert.Equal(t, "rt", svc.switchReq.RefreshToken)
+
+		refreshCookie := findCookie(rec.Result().Cookies(), handler.DefaultRefreshCookieName)
+		require.NotNil(t, refreshCookie)
+		assert.Equal(t, "rt-2", refreshCookie.Value)
+	})
+}
+
+type stubInvitationService struct {
+	stubService
+
+	acceptReq *service.AcceptInvitationRequest
+}
+
+func (s *stubInvitationService) AcceptInvitation(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *service.AcceptInvitationRequest,
+) (*service.OrganizationMembershipResponse, error) {
+	s.acceptReq = req
+
+	return &service.OrganizationMembershipResponse{ID: uuid.New(), Slug: "acme", Role: domain.RoleAdmin}, nil
+}
+
+func TestAuthHandlerAcceptInvitation(t *testing.T) {
+	t.Parallel()
+
+	svc := &stubInvitationService{}
+	mux := newFederatedMux(t, svc, handler.TransportBody, &domain.AccessClaims{UserID: uuid.New()})
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/invitations/accept", strings.NewReader(`{"token":"secret"}`))
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data struct {
+			Slug string `json:"slug"`
+			Role string `json:"role"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "acme", body.Data.Slug)
+	assert.Equal(t, domain.RoleAdmin, body.Data.Role)
+
+	require.NotNil(t, svc.acceptReq)
+	assert.Equal(t, "secret", svc.acceptReq.Token)
+	assert.Equal(t, "203.0.113.7", svc.acceptReq.ClientIP)
+}
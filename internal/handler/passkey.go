@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/middleware"
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+const publicKeyCredentialType = "public-key"
+
+type base64URL []byte
+
+func (b base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *base64URL) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(string(bytes.TrimRight([]byte(s), "=")))
+	if err != nil {
+		return err
+	}
+
+	*b = decoded
+
+	return nil
+}
+
+type passkeyOptionsResponse struct {
+	PublicKey any       `json:"public_key"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type passkeyCreationOptions struct {
+	Challenge              string                      `json:"challenge"`
+	RP                     passkeyRelyingParty         `json:"rp"`
+	User                   passkeyUser                 `json:"user"`
+	PubKeyCredParams       []passkeyCredentialParam    `json:"pubKeyCredParams"`
+	Timeout                int64                       `json:"timeout"`
+	ExcludeCredentials     []passkeyCredentialDescribe `json:"excludeCredentials"`
+	AuthenticatorSelection passkeySelection            `json:"authenticatorSelection"`
+	Attestation            string                      `json:"attestation"`
+}
+
+type passkeyRequestOptions struct {
+	Challenge        string `json:"challenge"`
+	RPID             string `json:"rpId"`
+	Timeout          int64  `json:"timeout"`
+	UserVerification string `json:"userVerification"`
+}
+
+type passkeyRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type passkeyUser struct {
+	ID          base64URL `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+}
+
+type passkeyCredentialParam struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+type passkeyCredentialDescribe struct {
+	Type string    `json:"type"`
+	ID   base64URL `json:"id"`
+}
+
+type passkeySelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+type passkeyCredential struct {
+	ID                      string                    `json:"id"`
+	RawID                   base64URL                 `json:"rawId"`
+	Type                    string                    `json:"type"`
+	AuthenticatorAttachment string                    `json:"authenticatorAttachment"`
+	ClientExtensionResults  json.RawMessage           `json:"clientExtensionResults"`
+	Response                passkeyCredentialResponse `json:"response"`
+}
+
+type passkeyCredentialResponse struct {
+	ClientDataJSON     base64URL `json:"clientDataJSON"`
+	AttestationObject  base64URL `json:"attestationObject"`
+	Transports         []string  `json:"transports"`
+	AuthenticatorData  base64URL `json:"authenticatorData"`
+	PublicKey          base64URL `json:"publicKey"`
+	PublicKeyAlgorithm int       `json:"publicKeyAlgorithm"`
+	Signature          base64URL `json:"signature"`
+	UserHandle         base64URL `json:"userHandle"`
+}
+
+type finishPasskeyRegistrationRequest struct {
+	Name       string            `json:"name"`
+	Credential passkeyCredential `json:"credential"`
+}
+
+type finishPasskeyLoginRequest struct {
+	Credential passkeyCredential `json:"credential"`
+}
+
+type passkeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Transports []string   `json:"transports"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+func (h *AuthHandler) beginPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.BeginPasskeyRegistration(r.Context(), middleware.ClaimsFromContext(r.Context()))
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	params := make([]passkeyCredentialParam, len(res.Algorithms))
+	for i, alg := range res.Algorithms {
+		params[i] = passkeyCredentialParam{Type: publicKeyCredentialType, Alg: alg}
+	}
+
+	exclude := make([]passkeyCredentialDescribe, len(res.ExcludeCredentials))
+	for i, id := range res.ExcludeCredentials {
+		exclude[i] = passkeyCredentialDescribe{Type: publicKeyCredentialType, ID: id}
+	}
+
+	response.OK(w, &passkeyOptionsResponse{
+		PublicKey: &passkeyCreationOptions{
+			Challenge:          res.Challenge,
+			RP:                 passkeyRelyingParty{ID: res.RPID, Name: res.RPName},
+			User:               passkeyUser{ID: res.UserHandle, Name: res.UserName, DisplayName: res.DisplayName},
+			PubKeyCredParams:   params,
+			Timeout:            time.Until(res.ExpiresAt).Milliseconds(),
+			ExcludeCredentials: exclude,
+			AuthenticatorSelection: passkeySelection{
+				ResidentKey:      "required",
+				UserVerification: "required",
+			},
+			Attestation: "none",
+		},
+		ExpiresAt: res.ExpiresAt,
+	})
+}
+
+func (h *AuthHandler) finishPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	var body finishPasskeyRegistrationRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	if err := body.Credential.validate(); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.FinishPasskeyRegistration(r.Context(), middleware.ClaimsFromContext(r.Context()),
+		&service.FinishPasskeyRegistrationRequest{
+			Name: body.Name,
+			Attestation: &domain.PasskeyAttestation{
+				ClientDataJSON:    body.Credential.Response.ClientDataJSON,
+				AttestationObject: body.Credential.Response.AttestationObject,
+				Transports:        body.Credential.Response.Transports,
+			},
+			UserAgent: r.UserAgent(),
+			ClientIP:  ip,
+		})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.Created(w, toPasskeyResponse(res))
+}
+
+func (h *AuthHandler) listPasskeys(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.ListPasskeys(r.Context(), middleware.ClaimsFromContext(r.Context()))
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := make([]*passkeyResponse, len(res))
+	for i, p := range res {
+		out[i] = toPasskeyResponse(p)
+	}
+
+	response.OK(w, out)
+}
+
+func (h *AuthHandler) deletePasskey(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		response.Error(w, apperror.NotFound(apperror.ErrCodePasskeyNotFound, apperror.MsgPasskeyNotFound, err))
+
+		return
+	}
+
+	if err = h.svc.DeletePasskey(r.Context(), middleware.ClaimsFromContext(r.Context()), id); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.NoContent(w)
+}
+
+func (h *AuthHandler) beginPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	res, err := h.svc.BeginPasskeyLogin(r.Context())
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, &passkeyOptionsResponse{
+		PublicKey: &passkeyRequestOptions{
+			Challenge:        res.Challenge,
+			RPID:             res.RPID,
+			Timeout:          time.Until(res.ExpiresAt).Milliseconds(),
+			UserVerification: "required",
+		},
+		ExpiresAt: res.ExpiresAt,
+	})
+}
+
+func (h *AuthHandler) finishPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	var body finishPasskeyLoginRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	if err := body.Credential.validate(); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	res, err := h.svc.FinishPasskeyLogin(r.Context(), &service.PasskeyLoginRequest{
+		Assertion: &domain.PasskeyAssertion{
+			CredentialID:      body.Credential.RawID,
+			ClientDataJSON:    body.Credential.Response.ClientDataJSON,
+			AuthenticatorData: body.Credential.Response.AuthenticatorData,
+			Signature:         body.Credential.Response.Signature,
+			UserHandle:        body.Credential.Response.UserHandle,
+		},
+		UserAgent: r.UserAgent(),
+		ClientIP:  ip,
+	})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	out := &tokenResponse{
+		UserID:           &res.UserID,
+		TokenType:        "Bearer",
+		AccessToken:      res.AccessToken,
+		AccessExpiresAt:  res.AccessExpiresAt,
+		RefreshExpiresAt: res.RefreshExpiresAt,
+	}
+
+	if err = h.deliverRefreshToken(w, out, res.RefreshToken); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	response.OK(w, out)
+}
+
+func (c *passkeyCredential) validate() error {
+	if c.Type != publicKeyCredentialType || len(c.RawID) == 0 || len(c.Response.ClientDataJSON) == 0 {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgPasskeyRequestRequired, nil)
+	}
+
+	return nil
+}
+
+func toPasskeyResponse(p *service.PasskeyResponse) *passkeyResponse {
+	return &passkeyResponse{
+		ID:         p.ID,
+		Name:       p.Name,
+		Transports: p.Transports,
+		CreatedAt:  p.CreatedAt,
+		LastUsedAt: p.LastUsedAt,
+	}
+}
@@ -0,0 +1,221 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/handler"
+	"go-auth/internal/service"
+)
+
+type stubPasskeyService struct {
+	service.Service
+
+	registerActor *domain.AccessClaims
+	registerReq   *service.FinishPasskeyRegistrationRequest
+	loginReq      *service.PasskeyLoginRequest
+}
+
+func (s *stubPasskeyService) BeginPasskeyRegistration(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+) (*service.PasskeyRegistrationOptions, error) {
+	return &service.PasskeyRegistrationOptions{
+		Challenge:          "Y2hhbGxlbmdl",
+		RPID:               "example.com",
+		RPName:             "Example",
+		UserHandle:         actor.UserID[:],
+		UserName:           "alice@example.com",
+		DisplayName:        "Alice Smith",
+		Algorithms:         []int{-7, -257},
+		ExcludeCredentials: [][]byte{{1, 2, 3}},
+		ExpiresAt:          time.Now().Add(5 * time.Minute),
+	}, nil
+}
+
+func (s *stubPasskeyService) FinishPasskeyRegistration(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *service.FinishPasskeyRegistrationRequest,
+) (*service.PasskeyResponse, error) {
+	s.registerActor, s.registerReq = actor, req
+
+	return &service.PasskeyResponse{ID: uuid.New(), Name: req.Name, CreatedAt: time.Now()}, nil
+}
+
+func (s *stubPasskeyService) BeginPasskeyLogin(ctx context.Context) (*service.PasskeyLoginOptions, error) {
+	return &service.PasskeyLoginOptions{
+		Challenge: "Y2hhbGxlbmdl",
+		RPID:      "example.com",
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	}, nil
+}
+
+func (s *stubPasskeyService) FinishPasskeyLogin(
+	ctx context.Context,
+	req *service.PasskeyLoginRequest,
+) (*service.LoginResponse, error) {
+	s.loginReq = req
+
+	return &service.LoginResponse{
+		UserID:           uuid.New(),
+		AccessToken:      "at",
+		RefreshToken:     "rt",
+		AccessExpiresAt:  time.Now().Add(15 * time.Minute),
+		RefreshExpiresAt: time.Now().Add(48 * time.Hour),
+	}, nil
+}
+
+func b64(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func TestAuthHandlerPasskeyLogin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+
+		mux := newAuthMux(t, &stubPasskeyService{}, handler.TransportBody)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/passkeys/login/options", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var body struct {
+			Data struct {
+				PublicKey struct {
+					Challenge        string `json:"challenge"`
+					RPID             string `json:"rpId"`
+					Timeout          int64  `json:"timeout"`
+					UserVerification string `json:"userVerification"`
+				} `json:"public_key"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "Y2hhbGxlbmdl", body.Data.PublicKey.Challenge)
+		assert.Equal(t, "example.com", body.Data.PublicKey.RPID)
+		assert.Equal(t, "required", body.Data.PublicKey.UserVerification)
+		assert.Positive(t, body.Data.PublicKey.Timeout)
+	})
+	t.Run("assertion", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubPasskeyService{}
+		mux := newAuthMux(t, svc, handler.TransportBody)
+
+		// A PublicKeyCredential as serialised by toJSON(), with a padded field and a null user handle.
+		credential := `{"credential":{"id":"` + b64("cred") + `","rawId":"` + b64("cred") + `",` +
+			`"type":"public-key","authenticatorAttachment":"platform","clientExtensionResults":{},` +
+			`"response":{"clientDataJSON":"` + b64("client") + `","authenticatorData":"` + b64("auth") + `",` +
+			`"signature":"` + base64.URLEncoding.EncodeToString([]byte("sig")) + `","userHandle":null}}}`
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/passkeys/login", strings.NewReader(credential))
+
+		rec, body := serve(t, mux, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "at", body.Data.AccessToken)
+		assert.Equal(t, "rt", body.Data.RefreshToken)
+
+		require.NotNil(t, svc.loginReq)
+		assert.Equal(t, "203.0.113.7", svc.loginReq.ClientIP)
+		assert.Equal(t, []byte("cred"), svc.loginReq.Assertion.CredentialID)
+		assert.Equal(t, []byte("client"), svc.loginReq.Assertion.ClientDataJSON)
+		assert.Equal(t, []byte("auth"), svc.loginReq.Assertion.AuthenticatorData)
+		assert.Equal(t, []byte("sig"), svc.loginReq.Assertion.Signature)
+		assert.Empty(t, svc.loginReq.Assertion.UserHandle)
+	})
+	t.Run("wrong credential type", func(t *testing.T) {
+		t.Parallel()
+
+		svc := &stubPasskeyService{}
+		mux := newAuthMux(t, svc, handler.TransportBody)
+
+		credential := `{"credential":{"id":"x","rawId":"` + b64("cred") + `","type":"password",` +
+			`"response":{"clientDataJSON":"` + b64("client") + `"}}}`
+
+		req := httptest.NewRequest(http.MethodPost, "/auth/passkeys/login", strings.NewReader(credential))
+
+		rec, body := serve(t, mux, req)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, apperror.ErrCodeInvalidParam, body.Error.Code)
+		assert.Nil(t, svc.loginReq)
+	})
+}
+
+func TestAuthHandlerPasskeyRegistration(t *testing.T) {
+	t.Parallel()
+
+	actor := &domain.AccessClaims{UserID: uuid.New()}
+	svc := &stubPasskeyService{}
+	mux := newFederatedMux(t, svc, handler.TransportBody, actor)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/passkeys/register/options", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var options struct {
+		Data struct {
+			PublicKey struct {
+				User struct {
+					ID string `json:"id"`
+				} `json:"user"`
+				PubKeyCredParams []struct {
+					Type string `json:"type"`
+					Alg  int    `json:"alg"`
+				} `json:"pubKeyCredParams"`
+				ExcludeCredentials []struct {
+					ID string `json:"id"`
+				} `json:"excludeCredentials"`
+				Attestation string `json:"attestation"`
+			} `json:"public_key"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &options))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(actor.UserID[:]), options.Data.PublicKey.User.ID)
+	require.Len(t, options.Data.PublicKey.PubKeyCredParams, 2)
+	assert.Equal(t, "public-key", options.Data.PublicKey.PubKeyCredParams[0].Type)
+	assert.Equal(t, -7, options.Data.PublicKey.PubKeyCredParams[0].Alg)
+	require.Len(t, options.Data.PublicKey.ExcludeCredentials, 1)
+	assert.Equal(t, "AQID", options.Data.PublicKey.ExcludeCredentials[0].ID)
+	assert.Equal(t, "none", options.Data.PublicKey.Attestation)
+
+	credential := `{"name":"Laptop","credential":{"id":"` + b64("cred") + `","rawId":"` + b64("cred") + `",` +
+		`"type":"public-key","clientExtensionResults":{},"response":{"clientDataJSON":"` + b64("client") + `",` +
+		`"attestationObject":"` + b64("att") + `","authenticatorData":"` + b64("auth") + `",` +
+		`"publicKeyAlgorithm":-7,"transports":["internal","hybrid"]}}}`
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/passkeys/register", strings.NewReader(credential))
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, actor, svc.registerActor)
+	assert.Equal(t, "Laptop", svc.registerReq.Name)
+	assert.Equal(t, []byte("client"), svc.registerReq.Attestation.ClientDataJSON)
+	assert.Equal(t, []byte("att"), svc.registerReq.Attestation.AttestationObject)
+	assert.Equal(t, []string{"internal", "hybrid"}, svc.registerReq.Attestation.Transports)
+
+	req = httptest.NewRequest(http.MethodDelete, "/auth/passkeys/not-a-uuid", nil)
+	req.Header.Set("Authorization", "Bearer token")
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
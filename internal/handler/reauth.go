@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"go-auth/internal/middleware"
+	"go-auth/internal/response"
+	"go-auth/internal/service"
+)
+
+type reauthenticateRequest struct {
+	Password string `json:"password"`
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+type elevatedTokenResponse struct {
+	TokenType       string    `json:"token_type"`
+	AccessToken     string    `json:"access_token"`
+	AccessExpiresAt time.Time `json:"access_expires_at"`
+}
+
+func (h *AuthHandler) reauthenticate(w http.ResponseWriter, r *http.Request) {
+	var body reauthenticateRequest
+	if err := decodeJSON(w, r, &body); err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	ip, err := clientIP(h.ipResolver, r)
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	actor := middleware.ClaimsFromContext(r.Context())
+
+	res, err := h.svc.Reauthenticate(r.Context(), actor, &service.ReauthenticateRequest{
+		Password:  body.Password,
+		MFAToken:  body.MFAToken,
+		Code:      body.Code,
+		UserAgent: r.UserAgent(),
+		ClientIP:  ip,
+	})
+	if err != nil {
+		response.Error(w, err)
+
+		return
+	}
+
+	if res.MFA != nil {
+		response.Accepted(w, toMFAChallengeResponse(actor.UserID, res.MFA))
+
+		return
+	}
+
+	response.OK(w, &elevatedTokenResponse{
+		TokenType:       "Bearer",
+		AccessToken:     res.AccessToken,
+		AccessExpiresAt: res.ExpiresAt,
+	})
+}
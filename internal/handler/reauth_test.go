@@ -0,0 +1,97 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/handler"
+	"go-auth/internal/service"
+)
+
+type stubReauthService struct {
+	service.Service
+
+	reqs []*service.ReauthenticateRequest
+}
+
+func (s *stubReauthService) Reauthenticate(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *service.ReauthenticateRequest,
+) (*service.ReauthenticateResponse, error) {
+	s.reqs = append(s.reqs, req)
+
+	if req.MFAToken == "" {
+		return &service.ReauthenticateResponse{
+			MFA: &service.MFAChallenge{
+				Token:     "mfa-token",
+				Factor:    domain.MFAFactorEmailOTP,
+				ExpiresAt: time.Now().Add(10 * time.Minute),
+			},
+		}, nil
+	}
+
+	return &service.ReauthenticateResponse{AccessToken: "elevated", ExpiresAt: time.Now().Add(5 * time.Minute)}, nil
+}
+
+func TestAuthHandlerReauthenticate(t *testing.T) {
+	t.Parallel()
+
+	actor := &domain.AccessClaims{UserID: uuid.New()}
+	svc := &stubReauthService{}
+	mux := newFederatedMux(t, svc, handler.TransportBody, actor)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/reauthenticate", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer token")
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := post(`{"password":"secret"}`)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var challenge struct {
+		Data struct {
+			UserID   uuid.UUID `json:"user_id"`
+			MFAToken string    `json:"mfa_token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &challenge))
+	assert.Equal(t, actor.UserID, challenge.Data.UserID)
+	assert.Equal(t, "mfa-token", challenge.Data.MFAToken)
+
+	rec = post(`{"mfa_token":"mfa-token","code":"123456"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var elevated struct {
+		Data struct {
+			TokenType    string `json:"token_type"`
+			AccessToken  string `json:"access_token"`
+			RefreshToken string `json:"refresh_token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &elevated))
+	assert.Equal(t, "Bearer", elevated.Data.TokenType)
+	assert.Equal(t, "elevated", elevated.Data.AccessToken)
+	assert.Empty(t, elevated.Data.RefreshToken)
+
+	require.Len(t, svc.reqs, 2)
+	assert.Equal(t, "secret", svc.reqs[0].Password)
+	assert.Equal(t, "203.0.113.7", svc.reqs[0].ClientIP)
+	assert.Equal(t, "mfa-token", svc.reqs[1].MFAToken)
+	assert.Equal(t, "123456", svc.reqs[1].Code)
+}
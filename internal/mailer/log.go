@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+
+	"go-auth/internal/domain"
+	"go-auth/pkg/logger"
+)
+
+var _ domain.Mailer = (*LogMailer)(nil)
+
+type LogMailer struct {
+	log logger.Logger
+}
+
+func NewLogMailer(log logger.Logger) (*LogMailer, error) {
+	if log == nil {
+		return nil, errors.New("logger is required")
+	}
+
+	return &LogMailer{log: log}, nil
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	if msg == nil {
+		return errors.New("email message is required")
+	}
+
+	m.log.InfoCtx(ctx, "Email sent", "to", msg.To.String(), "subject", msg.Subject)
+	m.log.DebugCtx(ctx, "Email body", "to", msg.To.String(), "body", msg.Body)
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package mailer_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/mailer"
+)
+
+// fakeSMTP accepts a single message over an unauthenticated, plain-text session.
+type fakeSMTP struct {
+	ln   net.Listener
+	rcpt chan string
+	data chan string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeSMTP{ln: ln, rcpt: make(chan string, 1), data: make(chan string, 1)}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go s.serve()
+
+	return s
+}
+
+func (s *fakeSMTP) serve() {
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake ESMTP")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			s.rcpt <- strings.TrimSpace(line)
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+
+			var body strings.Builder
+
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if l == ".\r\n" {
+					break
+				}
+
+				body.WriteString(l)
+			}
+
+			s.data <- body.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestNewSMTPMailer(t *testing.T) {
+	_, err := mailer.NewSMTPMailer(mailer.SMTPConfig{Addr: "no-port", From: "noreply@example.com"})
+	require.Error(t, err)
+
+	_, err = mailer.NewSMTPMailer(mailer.SMTPConfig{Addr: "localhost:25", From: "not an address"})
+	require.Error(t, err)
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	srv := newFakeSMTP(t)
+
+	m, err := mailer.NewSMTPMailer(mailer.SMTPConfig{Addr: srv.ln.Addr().String(), From: "noreply@example.com"})
+	require.NoError(t, err)
+
+	to, err := domain.NewEmail("alice@example.com")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = m.Send(ctx, &domain.EmailMessage{To: to, Subject: "Hello", Body: "line one\nline two"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "RCPT TO:<alice@example.com>", <-srv.rcpt)
+
+	data := <-srv.data
+	assert.Contains(t, data, "From: <noreply@example.com>\r\n")
+	assert.Contains(t, data, "To: alice@example.com\r\n")
+	assert.Contains(t, data, "Subject: Hello\r\n")
+	assert.Contains(t, data, "\r\n\r\nline one\r\nline two")
+}
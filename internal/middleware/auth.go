@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/response"
+)
+
+type TokenValidator interface {
+	Validate(token string, audience ...string) (*domain.AccessClaims, error)
+}
+
+type APIKeyValidator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.AccessClaims, error)
+}
+
+type claimsKey struct{}
+
+func WithClaims(ctx context.Context, claims *domain.AccessClaims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+func ClaimsFromContext(ctx context.Context) *domain.AccessClaims {
+	claims, _ := ctx.Value(claimsKey{}).(*domain.AccessClaims)
+
+	return claims
+}
+
+// Authenticate validates a Bearer access token and stores its claims in the request context.
+// Requests without a Bearer token pass through anonymously, leaving it to the service to
+// reject them; other schemes, such as Basic client credentials at the OAuth token endpoint,
+// are left to the handler. An empty or invalid Bearer token is rejected with 401.
+//
+// When keys is not nil, a Bearer credential carrying domain.APIKeyPrefix is checked as an
+// API key instead of an access token.
+func Authenticate(validator TokenValidator, keys APIKeyValidator, audience []string) func(http.Handler) http.Handler {
+	audience = slices.Clone(audience)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !strings.EqualFold(scheme, "Bearer") {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if token == "" {
+				unauthorized(w, nil)
+
+				return
+			}
+
+			var (
+				claims *domain.AccessClaims
+				err    error
+			)
+
+			if keys != nil && domain.IsAPIKey(token) {
+				claims, err = keys.AuthenticateAPIKey(r.Context(), token)
+			} else {
+				claims, err = validator.Validate(token, audience...)
+			}
+
+			if err != nil {
+				rejectCredential(w, err)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, cause error) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	response.Error(w, apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgAccessTokenInvalid, cause))
+}
+
+func rejectCredential(w http.ResponseWriter, err error) {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) && appErr.Status != http.StatusUnauthorized {
+		response.Error(w, err)
+
+		return
+	}
+
+	unauthorized(w, err)
+}
@@ -0,0 +1,170 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/middleware"
+)
+
+type stubValidator struct {
+	claims   *domain.AccessClaims
+	token    string
+	audience []string
+}
+
+func (v *stubValidator) Validate(token string, audience ...string) (*domain.AccessClaims, error) {
+	v.audience = audience
+	if token != v.token {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	return v.claims, nil
+}
+
+type stubAPIKeyValidator struct {
+	claims *domain.AccessClaims
+	err    error
+}
+
+func (v *stubAPIKeyValidator) AuthenticateAPIKey(ctx context.Context, key string) (*domain.AccessClaims, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+
+	if key != domain.APIKeyPrefix+"good" {
+		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgAPIKeyInvalid, nil)
+	}
+
+	return v.claims, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	claims := &domain.AccessClaims{UserID: uuid.New()}
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantClaims *domain.AccessClaims
+	}{
+		{name: "anonymous", header: "", wantStatus: http.StatusOK, wantClaims: nil},
+		{name: "valid bearer", header: "Bearer good", wantStatus: http.StatusOK, wantClaims: claims},
+		{name: "lowercase scheme", header: "bearer good", wantStatus: http.StatusOK, wantClaims: claims},
+		{name: "invalid token", header: "Bearer bad", wantStatus: http.StatusUnauthorized},
+		{name: "basic scheme left to handler", header: "Basic Zm9vOmJhcg==", wantStatus: http.StatusOK},
+		{name: "empty token", header: "Bearer ", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			validator := &stubValidator{claims: claims, token: "good"}
+
+			var got *domain.AccessClaims
+
+			h := middleware.Authenticate(validator, nil, []string{"api"})(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					got = middleware.ClaimsFromContext(r.Context())
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantClaims, got)
+
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "invalid_token")
+			}
+
+			if tt.wantClaims != nil {
+				assert.Equal(t, []string{"api"}, validator.audience)
+			}
+		})
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	keyClaims := &domain.AccessClaims{UserID: uuid.New(), APIKeyID: uuid.New()}
+
+	tests := []struct {
+		name       string
+		header     string
+		keys       *stubAPIKeyValidator
+		wantStatus int
+		wantClaims *domain.AccessClaims
+	}{
+		{
+			name:       "valid key",
+			header:     "Bearer " + domain.APIKeyPrefix + "good",
+			keys:       &stubAPIKeyValidator{claims: keyClaims},
+			wantStatus: http.StatusOK,
+			wantClaims: keyClaims,
+		},
+		{
+			name:       "invalid key",
+			header:     "Bearer " + domain.APIKeyPrefix + "bad",
+			keys:       &stubAPIKeyValidator{claims: keyClaims},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "key store unavailable",
+			header: "Bearer " + domain.APIKeyPrefix + "good",
+			keys: &stubAPIKeyValidator{
+				err: apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetAPIKey, nil),
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "access token still accepted",
+			header:     "Bearer good",
+			keys:       &stubAPIKeyValidator{claims: keyClaims},
+			wantStatus: http.StatusOK,
+			wantClaims: &domain.AccessClaims{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got *domain.AccessClaims
+
+			validator := &stubValidator{claims: &domain.AccessClaims{}, token: "good"}
+			h := middleware.Authenticate(validator, tt.keys, nil)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					got = middleware.ClaimsFromContext(r.Context())
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tt.header)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantClaims, got)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type CORSConfig struct {
+	// Origins lists allowed origins such as https://app.example.com. A leading
+	// "*." in the host, as in https://*.example.com, allows any subdomain but
+	// not the parent domain itself.
+	Origins          []string
+	Methods          []string
+	Headers          []string
+	MaxAge           int
+	AllowCredentials bool
+}
+
+type originPattern struct {
+	scheme   string
+	host     string
+	port     string
+	wildcard bool
+}
+
+type cors struct {
+	origins          []originPattern
+	methods          []string
+	headers          []string
+	allowMethods     string
+	allowHeaders     string
+	maxAge           string
+	allowCredentials bool
+}
+
+func CORS(cfg CORSConfig) (func(http.Handler) http.Handler, error) {
+	if len(cfg.Origins) == 0 {
+		return nil, errors.New("cors: at least one origin is required")
+	}
+
+	if cfg.MaxAge < 0 {
+		return nil, errors.New("cors: max age must not be negative")
+	}
+
+	c := &cors{
+		origins:          make([]originPattern, 0, len(cfg.Origins)),
+		allowCredentials: cfg.AllowCredentials,
+	}
+
+	for _, origin := range cfg.Origins {
+		pattern, err := parseOriginPattern(origin)
+		if err != nil {
+			return nil, err
+		}
+
+		c.origins = append(c.origins, pattern)
+	}
+
+	for _, method := range cfg.Methods {
+		c.methods = append(c.methods, strings.ToUpper(strings.TrimSpace(method)))
+	}
+
+	for _, header := range cfg.Headers {
+		c.headers = append(c.headers, http.CanonicalHeaderKey(strings.TrimSpace(header)))
+	}
+
+	c.allowMethods = strings.Join(c.methods, ", ")
+	c.allowHeaders = strings.Join(c.headers, ", ")
+
+	if cfg.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(cfg.MaxAge)
+	}
+
+	return c.handler, nil
+}
+
+func (c *cors) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			c.preflight(w, r, origin)
+
+			return
+		}
+
+		if origin != "" && c.originAllowed(origin) {
+			c.allowOrigin(header, origin)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *cors) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	header := w.Header()
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+
+	if origin == "" || !c.originAllowed(origin) ||
+		!c.methodAllowed(r.Header.Get("Access-Control-Request-Method")) ||
+		!c.headersAllowed(r.Header.Values("Access-Control-Request-Headers")) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	c.allowOrigin(header, origin)
+	header.Set("Access-Control-Allow-Methods", c.allowMethods)
+
+	if c.allowHeaders != "" {
+		header.Set("Access-Control-Allow-Headers", c.allowHeaders)
+	}
+
+	if c.maxAge != "" {
+		header.Set("Access-Control-Max-Age", c.maxAge)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowOrigin echoes the request origin rather than "*", which browsers refuse with credentials.
+func (c *cors) allowOrigin(header http.Header, origin string) {
+	header.Set("Access-Control-Allow-Origin", origin)
+
+	if c.allowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (c *cors) originAllowed(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.User != nil {
+		return false
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := normalizePort(scheme, u.Port())
+
+	for _, p := range c.origins {
+		if p.matches(scheme, host, port) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *cors) methodAllowed(method string) bool {
+	return slices.Contains(c.methods, strings.ToUpper(method))
+}
+
+func (c *cors) headersAllowed(values []string) bool {
+	for _, value := range values {
+		for name := range strings.SplitSeq(value, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" && !slices.Contains(c.headers, http.CanonicalHeaderKey(name)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func parseOriginPattern(origin string) (originPattern, error) {
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return originPattern{}, fmt.Errorf("cors: invalid origin %q", origin)
+	}
+
+	if u.Path != "" && u.Path != "/" {
+		return originPattern{}, fmt.Errorf("cors: origin %q must not have a path", origin)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	pattern := originPattern{scheme: u.Scheme, host: host, port: normalizePort(u.Scheme, u.Port())}
+
+	if rest, ok := strings.CutPrefix(host, "*."); ok {
+		if rest == "" || strings.Contains(rest, "*") {
+			return originPattern{}, fmt.Errorf("cors: invalid wildcard origin %q", origin)
+		}
+
+		pattern.host = rest
+		pattern.wildcard = true
+	} else if strings.Contains(host, "*") {
+		return originPattern{}, fmt.Errorf("cors: wildcard must be the leftmost label in %q", origin)
+	}
+
+	return pattern, nil
+}
+
+func (p originPattern) matches(scheme, host, port string) bool {
+	if p.scheme != scheme || p.port != port {
+		return false
+	}
+
+	if !p.wildcard {
+		return p.host == host
+	}
+
+	sub, ok := strings.CutSuffix(host, "."+p.host)
+
+	return ok && sub != ""
+}
+
+func normalizePort(scheme, port string) string {
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		return ""
+	}
+
+	return port
+}
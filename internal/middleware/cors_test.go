@@ -0,0 +1,257 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/middleware"
+)
+
+var testCORSConfig = middleware.CORSConfig{
+	Origins:          []string{"https://app.example.com", "https://*.example.org", "http://localhost:3000"},
+	Methods:          []string{"GET", "POST"},
+	Headers:          []string{"Content-Type", "X-CSRF-Token"},
+	MaxAge:           600,
+	AllowCredentials: true,
+}
+
+func TestCORSConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		origins []string
+		maxAge  int
+		wantErr bool
+	}{
+		{name: "valid", origins: []string{"https://app.example.com", "https://*.example.com:8443"}},
+		{name: "no origins", wantErr: true},
+		{name: "negative max age", origins: []string{"https://app.example.com"}, maxAge: -1, wantErr: true},
+		{name: "unsupported scheme", origins: []string{"ftp://example.com"}, wantErr: true},
+		{name: "missing host", origins: []string{"https://"}, wantErr: true},
+		{name: "with path", origins: []string{"https://example.com/app"}, wantErr: true},
+		{name: "inner wildcard", origins: []string{"https://app.*.example.com"}, wantErr: true},
+		{name: "bare wildcard", origins: []string{"https://*."}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := middleware.CORS(middleware.CORSConfig{Origins: tt.origins, MaxAge: tt.maxAge})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCORSRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		origin     string
+		wantOrigin string
+	}{
+		{name: "no origin"},
+		{name: "exact origin", origin: "https://app.example.com", wantOrigin: "https://app.example.com"},
+		{name: "exact origin case insensitive", origin: "https://APP.example.com", wantOrigin: "https://APP.example.com"},
+		{name: "explicit default port", origin: "https://app.example.com:443", wantOrigin: "https://app.example.com:443"},
+		{name: "wildcard subdomain", origin: "https://a.example.org", wantOrigin: "https://a.example.org"},
+		{name: "wildcard nested subdomain", origin: "https://a.b.example.org", wantOrigin: "https://a.b.example.org"},
+		{name: "wildcard excludes apex", origin: "https://example.org"},
+		{name: "wildcard suffix trick", origin: "https://evilexample.org"},
+		{name: "wrong scheme", origin: "http://app.example.com"},
+		{name: "wrong port", origin: "http://localhost:3001"},
+		{name: "lookalike host", origin: "https://app.example.com.evil.test"},
+		{name: "null origin", origin: "null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mw, err := middleware.CORS(testCORSConfig)
+			require.NoError(t, err)
+
+			called := false
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.True(t, called)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+			assert.Equal(t, tt.wantOrigin, rec.Header().Get("Access-Control-Allow-Origin"))
+
+			if tt.wantOrigin != "" {
+				assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+			} else {
+				assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+			}
+		})
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		cfg         middleware.CORSConfig
+		origin      string
+		method      string
+		headers     []string
+		wantStatus  int
+		wantOrigin  string
+		wantCreds   string
+		wantMaxAge  string
+		wantMethods string
+		wantHeaders string
+	}{
+		{
+			name:        "allowed",
+			cfg:         testCORSConfig,
+			origin:      "https://app.example.com",
+			method:      "POST",
+			headers:     []string{"content-type, x-csrf-token"},
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://app.example.com",
+			wantCreds:   "true",
+			wantMaxAge:  "600",
+			wantMethods: "GET, POST",
+			wantHeaders: "Content-Type, X-Csrf-Token",
+		},
+		{
+			name:        "allowed wildcard without request headers",
+			cfg:         testCORSConfig,
+			origin:      "https://tenant.example.org",
+			method:      "GET",
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://tenant.example.org",
+			wantCreds:   "true",
+			wantMaxAge:  "600",
+			wantMethods: "GET, POST",
+			wantHeaders: "Content-Type, X-Csrf-Token",
+		},
+		{
+			name:        "without credentials or max age",
+			cfg:         middleware.CORSConfig{Origins: []string{"https://app.example.com"}, Methods: []string{"post"}},
+			origin:      "https://app.example.com",
+			method:      "POST",
+			wantStatus:  http.StatusNoContent,
+			wantOrigin:  "https://app.example.com",
+			wantMethods: "POST",
+		},
+		{
+			name:       "disallowed header in repeated field",
+			cfg:        testCORSConfig,
+			origin:     "https://app.example.com",
+			method:     "POST",
+			headers:    []string{"Content-Type", "Authorization"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "disallowed origin",
+			cfg:        testCORSConfig,
+			origin:     "https://evil.test",
+			method:     "POST",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "missing origin",
+			cfg:        testCORSConfig,
+			method:     "POST",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "disallowed method",
+			cfg:        testCORSConfig,
+			origin:     "https://app.example.com",
+			method:     "DELETE",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "disallowed header",
+			cfg:        testCORSConfig,
+			origin:     "https://app.example.com",
+			method:     "POST",
+			headers:    []string{"Content-Type, X-Admin"},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mw, err := middleware.CORS(tt.cfg)
+			require.NoError(t, err)
+
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("preflight reached the next handler")
+			}))
+
+			req := httptest.NewRequest(http.MethodOptions, "/auth/session/refresh", nil)
+			req.Header.Set("Access-Control-Request-Method", tt.method)
+
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			for _, value := range tt.headers {
+				req.Header.Add("Access-Control-Request-Headers", value)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			got := rec.Header()
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantOrigin, got.Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.wantCreds, got.Get("Access-Control-Allow-Credentials"))
+			assert.Equal(t, tt.wantMaxAge, got.Get("Access-Control-Max-Age"))
+			assert.Equal(t, tt.wantMethods, got.Get("Access-Control-Allow-Methods"))
+			assert.Equal(t, tt.wantHeaders, got.Get("Access-Control-Allow-Headers"))
+			assert.Subset(t, got.Values("Vary"), []string{"Origin", "Access-Control-Request-Method"})
+		})
+	}
+}
+
+func TestCORSPlainOptions(t *testing.T) {
+	t.Parallel()
+
+	mw, err := middleware.CORS(testCORSConfig)
+	require.NoError(t, err)
+
+	called := false
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.True(t, called)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+}
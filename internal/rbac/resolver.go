@@ -0,0 +1,133 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-auth/internal/domain"
+	"go-auth/pkg/logger"
+)
+
+const DefaultRefreshInterval = time.Minute
+
+var _ domain.RolePermissionResolver = (*Resolver)(nil)
+
+type Resolver struct {
+	repo     domain.RoleRepository
+	log      logger.Logger
+	interval time.Duration
+	mu       sync.Mutex
+	snapshot atomic.Pointer[snapshot]
+}
+
+type snapshot struct {
+	domain.RolePermissionResolver
+	roles map[string][]domain.Permission
+}
+
+func newSnapshot(roles map[string][]domain.Permission) *snapshot {
+	return &snapshot{domain.NewStaticPermissionResolver(roles), roles}
+}
+
+func NewResolver(repo domain.RoleRepository, log logger.Logger, interval time.Duration) (*Resolver, error) {
+	if repo == nil {
+		return nil, errors.New("role repository is required")
+	}
+
+	if log == nil {
+		return nil, errors.New("logger is required")
+	}
+
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+
+	r := &Resolver{
+		repo:     repo,
+		log:      log,
+		interval: interval,
+	}
+	r.snapshot.Store(newSnapshot(domain.BuiltinRolePermissions()))
+
+	return r, nil
+}
+
+func (r *Resolver) Refresh(ctx context.Context) error {
+	// Held across the query so that a refresh listing roles before a PutRole cannot undo it.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	roles, err := r.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("load roles: %w", err)
+	}
+
+	perms := domain.BuiltinRolePermissions()
+	for _, role := range roles {
+		perms[role.Name] = role.Permissions
+	}
+
+	r.snapshot.Store(newSnapshot(perms))
+
+	return nil
+}
+
+// PutRole stores a role that was just written, so it can be assigned before the next refresh.
+func (r *Resolver) PutRole(role *domain.RoleDefinition) {
+	r.update(func(roles map[string][]domain.Permission) {
+		roles[role.Name] = role.Permissions
+	})
+}
+
+// RemoveRole forgets a deleted role; a built-in one falls back to its defaults.
+func (r *Resolver) RemoveRole(name string) {
+	r.update(func(roles map[string][]domain.Permission) {
+		delete(roles, name)
+
+		if perms, ok := domain.BuiltinRolePermissions()[name]; ok {
+			roles[name] = perms
+		}
+	})
+}
+
+func (r *Resolver) update(fn func(roles map[string][]domain.Permission)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	roles := maps.Clone(r.snapshot.Load().roles)
+	fn(roles)
+	r.snapshot.Store(newSnapshot(roles))
+}
+
+func (r *Resolver) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil {
+				r.log.WarnCtx(ctx, "Refresh role permissions failed", "error", err)
+			}
+		}
+	}
+}
+
+func (r *Resolver) RoleExists(role string) bool {
+	return r.snapshot.Load().RoleExists(role)
+}
+
+func (r *Resolver) Permissions(role string) []domain.Permission {
+	return r.snapshot.Load().Permissions(role)
+}
+
+func (r *Resolver) HasPermission(role string, perm domain.Permission) bool {
+	return r.snapshot.Load().HasPermission(role, perm)
+}
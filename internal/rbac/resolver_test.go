@@ -0,0 +1,113 @@
+package rbac_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/rbac"
+	"go-auth/pkg/logger"
+	_ "go-auth/pkg/logger/adapter/nop"
+)
+
+type stubRoleRepo struct {
+	domain.RoleRepository
+
+	roles   []*domain.RoleDefinition
+	listErr error
+}
+
+func (s *stubRoleRepo) List(ctx context.Context) ([]*domain.RoleDefinition, error) {
+	return s.roles, s.listErr
+}
+
+func newNopLogger(t *testing.T) logger.Logger {
+	t.Helper()
+
+	log, err := logger.New(logger.WithDriver(logger.DriverNop))
+	require.NoError(t, err)
+
+	return log
+}
+
+func TestNewResolver(t *testing.T) {
+	_, err := rbac.NewResolver(nil, newNopLogger(t), 0)
+	require.Error(t, err)
+
+	_, err = rbac.NewResolver(&stubRoleRepo{}, nil, 0)
+	require.Error(t, err)
+}
+
+func TestResolverDefaultsBeforeRefresh(t *testing.T) {
+	r, err := rbac.NewResolver(&stubRoleRepo{}, newNopLogger(t), 0)
+	require.NoError(t, err)
+
+	assert.True(t, r.RoleExists(domain.RoleAdmin))
+	assert.True(t, r.HasPermission(domain.RoleSuperAdmin, domain.PermRoleManage))
+	assert.False(t, r.RoleExists("support"))
+}
+
+func TestResolverRefresh(t *testing.T) {
+	repo := &stubRoleRepo{roles: []*domain.RoleDefinition{
+		{Name: "support", Permissions: []domain.Permission{domain.PermUserRead}},
+		{Name: domain.RoleAdmin, Permissions: []domain.Permission{domain.PermUserRead}, BuiltIn: true},
+	}}
+
+	r, err := rbac.NewResolver(repo, newNopLogger(t), 0)
+	require.NoError(t, err)
+	require.NoError(t, r.Refresh(context.Background()))
+
+	assert.True(t, r.RoleExists("support"))
+	assert.True(t, r.HasPermission("support", domain.PermUserRead))
+	assert.False(t, r.HasPermission("support", domain.PermUserWrite))
+
+	assert.False(t, r.HasPermission(domain.RoleAdmin, domain.PermUserBan), "database overrides built-in defaults")
+	assert.True(t, r.HasPermission(domain.RoleSuperAdmin, domain.PermUserDelete), "missing built-ins keep defaults")
+}
+
+func TestResolverRefreshErrorKeepsSnapshot(t *testing.T) {
+	repo := &stubRoleRepo{roles: []*domain.RoleDefinition{
+		{Name: "support", Permissions: []domain.Permission{domain.PermUserRead}},
+	}}
+
+	r, err := rbac.NewResolver(repo, newNopLogger(t), 0)
+	require.NoError(t, err)
+	require.NoError(t, r.Refresh(context.Background()))
+
+	repo.listErr = errors.New("db down")
+	require.Error(t, r.Refresh(context.Background()))
+	assert.True(t, r.HasPermission("support", domain.PermUserRead))
+}
+
+func TestResolverPutRole(t *testing.T) {
+	repo := &stubRoleRepo{listErr: errors.New("db down")}
+
+	r, err := rbac.NewResolver(repo, newNopLogger(t), 0)
+	require.NoError(t, err)
+
+	r.PutRole(&domain.RoleDefinition{Name: "support", Permissions: []domain.Permission{domain.PermUserRead}})
+	require.Error(t, r.Refresh(context.Background()))
+
+	assert.True(t, r.RoleExists("support"))
+	assert.True(t, r.HasPermission("support", domain.PermUserRead))
+	assert.True(t, r.RoleExists(domain.RoleAdmin))
+}
+
+func TestResolverRemoveRole(t *testing.T) {
+	r, err := rbac.NewResolver(&stubRoleRepo{}, newNopLogger(t), 0)
+	require.NoError(t, err)
+
+	r.PutRole(&domain.RoleDefinition{Name: "support", Permissions: []domain.Permission{domain.PermUserRead}})
+	r.PutRole(&domain.RoleDefinition{Name: domain.RoleAdmin, BuiltIn: true})
+	require.False(t, r.HasPermission(domain.RoleAdmin, domain.PermUserBan))
+
+	r.RemoveRole("support")
+	r.RemoveRole(domain.RoleAdmin)
+
+	assert.False(t, r.RoleExists("support"))
+	assert.True(t, r.HasPermission(domain.RoleAdmin, domain.PermUserBan), "built-in role falls back to defaults")
+}
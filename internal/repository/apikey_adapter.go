@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var _ domain.APIKeyRepository = (*APIKeyRepository)(nil)
+
+type APIKeyRepository struct {
+	q *gen.Queries
+}
+
+func NewAPIKeyRepository(q *gen.Queries) *APIKeyRepository {
+	return &APIKeyRepository{q: q}
+}
+
+func (ar *APIKeyRepository) Save(ctx context.Context, key *domain.APIKey) error {
+	err := ar.q.CreateAPIKey(ctx, gen.CreateAPIKeyParams{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		SecretHash: key.SecretHash,
+		Scopes:     nonNilStrings(key.Scopes),
+		ExpiresAt:  key.ExpiresAt,
+		CreatedAt:  key.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("save api key: %w", err)
+	}
+
+	return nil
+}
+
+func (ar *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	repoKey, err := ar.q.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get api key by id: %w", err)
+	}
+
+	return toDomainAPIKey(&repoKey), nil
+}
+
+func (ar *APIKeyRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	repoKeys, err := ar.q.ListAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys by user id: %w", err)
+	}
+
+	out := make([]*domain.APIKey, len(repoKeys))
+	for i := range repoKeys {
+		out[i] = toDomainAPIKey(&repoKeys[i])
+	}
+
+	return out, nil
+}
+
+func (ar *APIKeyRepository) Use(ctx context.Context, key *domain.APIKey) error {
+	if err := ar.q.UseAPIKey(ctx, gen.UseAPIKeyParams{ID: key.ID, LastUsedAt: key.LastUsedAt}); err != nil {
+		return fmt.Errorf("use api key: %w", err)
+	}
+
+	return nil
+}
+
+func (ar *APIKeyRepository) Revoke(ctx context.Context, userID, id uuid.UUID, revokedAt time.Time) (bool, error) {
+	n, err := ar.q.RevokeAPIKey(ctx, gen.RevokeAPIKeyParams{ID: id, UserID: userID, RevokedAt: &revokedAt})
+	if err != nil {
+		return false, fmt.Errorf("revoke api key: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+func toDomainAPIKey(repoKey *gen.APIKey) *domain.APIKey {
+	return &domain.APIKey{
+		ID:         repoKey.ID,
+		UserID:     repoKey.UserID,
+		Name:       repoKey.Name,
+		SecretHash: repoKey.SecretHash,
+		Scopes:     repoKey.Scopes,
+		ExpiresAt:  repoKey.ExpiresAt,
+		LastUsedAt: repoKey.LastUsedAt,
+		RevokedAt:  repoKey.RevokedAt,
+		CreatedAt:  repoKey.CreatedAt,
+	}
+}
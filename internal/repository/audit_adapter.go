@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var (
+	_ domain.AuditRepository = (*AuditRepository)(nil)
+	_ domain.AuditLogger     = (*AuditRepository)(nil)
+)
+
+type AuditRepository struct {
+	q *gen.Queries
+}
+
+func NewAuditRepository(q *gen.Queries) *AuditRepository {
+	return &AuditRepository{q: q}
+}
+
+func (ar *AuditRepository) Save(ctx context.Context, event *domain.AuditEvent) error {
+	params, err := toCreateAuditEventParams(event)
+	if err != nil {
+		return err
+	}
+
+	return ar.q.CreateAuditEvent(ctx, params)
+}
+
+func (ar *AuditRepository) Log(ctx context.Context, event *domain.AuditEvent) error {
+	return ar.Save(ctx, event)
+}
+
+func (ar *AuditRepository) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	repoEvents, err := ar.q.ListAuditEvents(ctx, toListAuditEventsParams(filter))
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+
+	out := make([]*domain.AuditEvent, len(repoEvents))
+	for i := range repoEvents {
+		if out[i], err = toDomainAuditEvent(&repoEvents[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func toCreateAuditEventParams(event *domain.AuditEvent) (gen.CreateAuditEventParams, error) {
+	metadata := event.Metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return gen.CreateAuditEventParams{}, fmt.Errorf("marshal audit metadata: %w", err)
+	}
+
+	return gen.CreateAuditEventParams{
+		ID:        event.ID,
+		ActorID:   event.ActorID,
+		TargetID:  event.TargetID,
+		Action:    event.Action.String(),
+		Outcome:   event.Outcome.String(),
+		ClientIP:  event.ClientIP,
+		UserAgent: event.UserAgent,
+		Metadata:  raw,
+		CreatedAt: event.CreatedAt,
+	}, nil
+}
+
+func toListAuditEventsParams(filter domain.AuditFilter) gen.ListAuditEventsParams {
+	params := gen.ListAuditEventsParams{
+		ActorID:   filter.ActorID,
+		TargetID:  filter.TargetID,
+		Since:     filter.Since,
+		Until:     filter.Until,
+		RowLimit:  int32(filter.Limit),  //nolint:gosec // bounded by the service
+		RowOffset: int32(filter.Offset), //nolint:gosec // bounded by the service
+	}
+
+	if len(filter.Actions) > 0 {
+		params.Actions = make([]string, len(filter.Actions))
+		for i, action := range filter.Actions {
+			params.Actions[i] = action.String()
+		}
+	}
+
+	return params
+}
+
+func toDomainAuditEvent(repoEvent *gen.AuditEvent) (*domain.AuditEvent, error) {
+	metadata := map[string]any{}
+	if len(repoEvent.Metadata) > 0 {
+		if err := json.Unmarshal(repoEvent.Metadata, &metadata); err != nil {
+			return nil, fmt.Errorf("unmarshal audit metadata: %w", err)
+		}
+	}
+
+	return &domain.AuditEvent{
+		ID:        repoEvent.ID,
+		ActorID:   repoEvent.ActorID,
+		TargetID:  repoEvent.TargetID,
+		Action:    domain.AuditAction(repoEvent.Action),
+		Outcome:   domain.AuditOutcome(repoEvent.Outcome),
+		ClientIP:  repoEvent.ClientIP,
+		UserAgent: repoEvent.UserAgent,
+		Metadata:  metadata,
+		CreatedAt: repoEvent.CreatedAt,
+	}, nil
+}
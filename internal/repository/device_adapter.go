@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var _ domain.KnownDeviceRepository = (*KnownDeviceRepository)(nil)
+
+type KnownDeviceRepository struct {
+	q *gen.Queries
+}
+
+func NewKnownDeviceRepository(q *gen.Queries) *KnownDeviceRepository {
+	return &KnownDeviceRepository{q: q}
+}
+
+func (kr *KnownDeviceRepository) Upsert(ctx context.Context, device *domain.KnownDevice) (bool, error) {
+	inserted, err := kr.q.UpsertKnownDevice(ctx, gen.UpsertKnownDeviceParams{
+		ID:          device.ID,
+		UserID:      device.UserID,
+		Fingerprint: device.Fingerprint,
+		UAFamily:    device.UAFamily,
+		IPPrefix:    device.IPPrefix,
+		FirstSeenAt: device.FirstSeenAt,
+		LastSeenAt:  device.LastSeenAt,
+	})
+	if err != nil {
+		return false, fmt.Errorf("upsert known device: %w", err)
+	}
+
+	return inserted, nil
+}
+
+func (kr *KnownDeviceRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.KnownDevice, error) {
+	repoDevices, err := kr.q.ListKnownDevicesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list known devices: %w", err)
+	}
+
+	out := make([]*domain.KnownDevice, len(repoDevices))
+	for i := range repoDevices {
+		out[i] = toDomainKnownDevice(&repoDevices[i])
+	}
+
+	return out, nil
+}
+
+func (kr *KnownDeviceRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return kr.q.CountKnownDevicesByUserID(ctx, userID)
+}
+
+func toDomainKnownDevice(repoDevice *gen.KnownDevice) *domain.KnownDevice {
+	return &domain.KnownDevice{
+		ID:          repoDevice.ID,
+		UserID:      repoDevice.UserID,
+		Fingerprint: repoDevice.Fingerprint,
+		UAFamily:    repoDevice.UAFamily,
+		IPPrefix:    repoDevice.IPPrefix,
+		FirstSeenAt: repoDevice.FirstSeenAt,
+		LastSeenAt:  repoDevice.LastSeenAt,
+	}
+}
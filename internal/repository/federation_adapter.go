@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var (
+	_ domain.UserIdentityRepository        = (*UserIdentityRepository)(nil)
+	_ domain.FederatedLoginStateRepository = (*FederatedLoginStateRepository)(nil)
+)
+
+type UserIdentityRepository struct {
+	q *gen.Queries
+}
+
+func NewUserIdentityRepository(q *gen.Queries) *UserIdentityRepository {
+	return &UserIdentityRepository{q: q}
+}
+
+func (ir *UserIdentityRepository) Save(ctx context.Context, identity *domain.UserIdentity) error {
+	n, err := ir.q.CreateUserIdentity(ctx, gen.CreateUserIdentityParams{
+		ID:        identity.ID,
+		UserID:    identity.UserID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("save user identity: %w", err)
+	}
+
+	if n == 0 {
+		return domain.ErrIdentityAlreadyLinked
+	}
+
+	return nil
+}
+
+func (ir *UserIdentityRepository) GetByProviderSubject(
+	ctx context.Context,
+	provider, subject string,
+) (*domain.UserIdentity, error) {
+	repoIdentity, err := ir.q.GetUserIdentityByProviderSubject(ctx, gen.GetUserIdentityByProviderSubjectParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get user identity by provider subject: %w", err)
+	}
+
+	return &domain.UserIdentity{
+		ID:        repoIdentity.ID,
+		UserID:    repoIdentity.UserID,
+		Provider:  repoIdentity.Provider,
+		Subject:   repoIdentity.Subject,
+		Email:     repoIdentity.Email,
+		CreatedAt: repoIdentity.CreatedAt,
+	}, nil
+}
+
+type FederatedLoginStateRepository struct {
+	q *gen.Queries
+}
+
+func NewFederatedLoginStateRepository(q *gen.Queries) *FederatedLoginStateRepository {
+	return &FederatedLoginStateRepository{q: q}
+}
+
+func (sr *FederatedLoginStateRepository) Save(ctx context.Context, state *domain.FederatedLoginState) error {
+	return sr.q.CreateFederatedLoginState(ctx, gen.CreateFederatedLoginStateParams{
+		StateHash:    state.StateHash,
+		Provider:     state.Provider,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+		UserID:       state.UserID,
+		ExpiresAt:    state.ExpiresAt,
+		CreatedAt:    state.CreatedAt,
+	})
+}
+
+func (sr *FederatedLoginStateRepository) Consume(
+	ctx context.Context,
+	stateHash string,
+) (*domain.FederatedLoginState, error) {
+	repoState, err := sr.q.ConsumeFederatedLoginState(ctx, stateHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("consume federated login state: %w", err)
+	}
+
+	return &domain.FederatedLoginState{
+		StateHash:    repoState.StateHash,
+		Provider:     repoState.Provider,
+		Nonce:        repoState.Nonce,
+		CodeVerifier: repoState.CodeVerifier,
+		UserID:       repoState.UserID,
+		ExpiresAt:    repoState.ExpiresAt,
+		CreatedAt:    repoState.CreatedAt,
+	}, nil
+}
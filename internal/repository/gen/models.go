@@ -10,38 +10,226 @@ import (
 	"github.com/google/uuid"
 )
 
-type Session struct {
+type APIKey struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	SecretHash string
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+type AuditEvent struct {
 	ID        uuid.UUID
-	UserID    uuid.UUID
-	Token     string
-	UserAgent string
+	ActorID   *uuid.UUID
+	TargetID  *uuid.UUID
+	Action    string
+	Outcome   string
 	ClientIP  string
-	ExpiresAt time.Time
-	RevokedAt *time.Time
+	UserAgent string
+	Metadata  []byte
+	CreatedAt time.Time
+}
+
+type FederatedLoginState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	UserID       *uuid.UUID
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+type KnownDevice struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Fingerprint string
+	UAFamily    string
+	IPPrefix    string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+type OAuthAuthorizationCode struct {
+	CodeHash            string
+	ClientID            string
+	UserID              uuid.UUID
+	RedirectURI         string
+	Scopes              []string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	ExpiresAt           time.Time
+	UsedAt              *time.Time
+	CreatedAt           time.Time
+	Nonce               string
+}
+
+type OAuthClient struct {
+	ID           string
+	Name         string
+	SecretHash   *string
+	RedirectURIs []string
+	Scopes       []string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	GrantTypes   []string
+	DisabledAt   *time.Time
+}
+
+type Organization struct {
+	ID        uuid.UUID
+	Slug      string
+	Name      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+type OrganizationInvitation struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	InviterID      uuid.UUID
+	Email          string
+	Role           string
+	Token          string
+	ExpiresAt      time.Time
+	AcceptedAt     *time.Time
+	AcceptedBy     *uuid.UUID
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+}
+
+type OrganizationMember struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	Role           string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type PasskeyChallenge struct {
+	ChallengeHash string
+	Ceremony      string
+	UserID        *uuid.UUID
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
+
+type PasskeyCredential struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	CredentialID []byte
+	PublicKey    []byte
+	SignCount    int64
+	AAGUID       []byte
+	Transports   []string
+	Name         string
+	CreatedAt    time.Time
+	LastUsedAt   *time.Time
+}
+
+type Permission struct {
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
+
+type Role struct {
+	Name        string
+	Description string
+	BuiltIn     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type RolePermission struct {
+	Role       string
+	Permission string
+}
+
+type Session struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Token           string
+	UserAgent       string
+	ClientIP        string
+	ExpiresAt       time.Time
+	RevokedAt       *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	FlaggedAt       *time.Time
+	AuthenticatedAt time.Time
+	ReplacedBy      *uuid.UUID
+	ClientID        *string
+	Scopes          []string
+	OrganizationID  *uuid.UUID
+}
+
 type Token struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Token       string
+	Type        string
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+	CreatedAt   time.Time
+	BindingHash *string
+	CodeHash    *string
+	Attempts    int32
+}
+
+type User struct {
+	ID             uuid.UUID
+	Username       string
+	Email          string
+	Password       string
+	FirstName      string
+	LastName       string
+	Role           string
+	Status         string
+	VerifiedAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	MFAFactor      *string
+	OrganizationID *uuid.UUID
+}
+
+type UserIdentity struct {
 	ID        uuid.UUID
 	UserID    uuid.UUID
-	Token     string
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
+
+type WebhookEvent struct {
+	ID        uuid.UUID
 	Type      string
-	ExpiresAt time.Time
-	UsedAt    *time.Time
+	Data      []byte
 	CreatedAt time.Time
 }
 
-type User struct {
+type WebhookSubscription struct {
 	ID         uuid.UUID
-	Username   string
-	Email      string
-	Password   string
-	FirstName  string
-	LastName   string
-	Role       string
-	Status     string
-	VerifiedAt *time.Time
+	URL        string
+	Secret     string
+	Events     []string
+	DisabledAt *time.Time
 	CreatedAt  time.Time
-	UpdatedAt  time.Time
 }
@@ -22,23 +22,35 @@ INSERT INTO sessions (
   expires_at,
   revoked_at,
   created_at,
-  updated_at
+  updated_at,
+  flagged_at,
+  authenticated_at,
+  replaced_by,
+  client_id,
+  scopes,
+  organization_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 )
-RETURNING id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at
+RETURNING id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at, flagged_at, authenticated_at, replaced_by, client_id, scopes, organization_id
 `
 
 type CreateSessionParams struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Token     string
-	UserAgent string
-	ClientIP  string
-	ExpiresAt time.Time
-	RevokedAt *time.Time
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Token           string
+	UserAgent       string
+	ClientIP        string
+	ExpiresAt       time.Time
+	RevokedAt       *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	FlaggedAt       *time.Time
+	AuthenticatedAt time.Time
+	ReplacedBy      *uuid.UUID
+	ClientID        *string
+	Scopes          []string
+	OrganizationID  *uuid.UUID
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -52,6 +64,12 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.RevokedAt,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.FlaggedAt,
+		arg.AuthenticatedAt,
+		arg.ReplacedBy,
+		arg.ClientID,
+		arg.Scopes,
+		arg.OrganizationID,
 	)
 	var i Session
 	err := row.Scan(
@@ -64,6 +82,12 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.RevokedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FlaggedAt,
+		&i.AuthenticatedAt,
+		&i.ReplacedBy,
+		&i.ClientID,
+		&i.Scopes,
+		&i.OrganizationID,
 	)
 	return i, err
 }
@@ -89,7 +113,7 @@ func (q *Queries) DeleteSessionsByUserID(ctx context.Context, userID uuid.UUID)
 }
 
 const getSessionByID = `-- name: GetSessionByID :one
-SELECT id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at
+SELECT id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at, flagged_at, authenticated_at, replaced_by, client_id, scopes, organization_id
 FROM sessions
 WHERE id = $1
 LIMIT 1
@@ -108,12 +132,18 @@ func (q *Queries) GetSessionByID(ctx context.Context, id uuid.UUID) (Session, er
 		&i.RevokedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FlaggedAt,
+		&i.AuthenticatedAt,
+		&i.ReplacedBy,
+		&i.ClientID,
+		&i.Scopes,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const getSessionByToken = `-- name: GetSessionByToken :one
-SELECT id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at
+SELECT id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at, flagged_at, authenticated_at, replaced_by, client_id, scopes, organization_id
 FROM sessions
 WHERE token = $1
 LIMIT 1
@@ -132,12 +162,18 @@ func (q *Queries) GetSessionByToken(ctx context.Context, token string) (Session,
 		&i.RevokedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FlaggedAt,
+		&i.AuthenticatedAt,
+		&i.ReplacedBy,
+		&i.ClientID,
+		&i.Scopes,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const getSessionsByUserID = `-- name: GetSessionsByUserID :many
-SELECT id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at
+SELECT id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at, flagged_at, authenticated_at, replaced_by, client_id, scopes, organization_id
 FROM sessions
 WHERE user_id = $1
 ORDER BY created_at DESC
@@ -162,6 +198,12 @@ func (q *Queries) GetSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]
 			&i.RevokedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.FlaggedAt,
+			&i.AuthenticatedAt,
+			&i.ReplacedBy,
+			&i.ClientID,
+			&i.Scopes,
+			&i.OrganizationID,
 		); err != nil {
 			return nil, err
 		}
@@ -173,6 +215,102 @@ func (q *Queries) GetSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]
 	return items, nil
 }
 
+const rotateSession = `-- name: RotateSession :one
+WITH rotated AS (
+  UPDATE sessions
+  SET
+    revoked_at = $1,
+    updated_at = $1,
+    flagged_at = $2,
+    replaced_by = $3
+  WHERE sessions.id = $4
+    AND sessions.revoked_at IS NULL
+  RETURNING sessions.id, sessions.client_id, sessions.scopes
+)
+INSERT INTO sessions (
+  id,
+  user_id,
+  token,
+  user_agent,
+  client_ip,
+  expires_at,
+  created_at,
+  updated_at,
+  flagged_at,
+  authenticated_at,
+  client_id,
+  scopes,
+  organization_id
+)
+SELECT
+  $3,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9,
+  $10,
+  $10,
+  $2,
+  $11,
+  rotated.client_id,
+  rotated.scopes,
+  $12
+FROM rotated
+RETURNING id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at, flagged_at, authenticated_at, replaced_by, client_id, scopes, organization_id
+`
+
+type RotateSessionParams struct {
+	RevokedAt       time.Time
+	FlaggedAt       *time.Time
+	SuccessorID     uuid.UUID
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Token           string
+	UserAgent       string
+	ClientIP        string
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+	AuthenticatedAt time.Time
+	OrganizationID  *uuid.UUID
+}
+
+func (q *Queries) RotateSession(ctx context.Context, arg RotateSessionParams) (Session, error) {
+	row := q.db.QueryRow(ctx, rotateSession,
+		arg.RevokedAt,
+		arg.FlaggedAt,
+		arg.SuccessorID,
+		arg.ID,
+		arg.UserID,
+		arg.Token,
+		arg.UserAgent,
+		arg.ClientIP,
+		arg.ExpiresAt,
+		arg.CreatedAt,
+		arg.AuthenticatedAt,
+		arg.OrganizationID,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.UserAgent,
+		&i.ClientIP,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FlaggedAt,
+		&i.AuthenticatedAt,
+		&i.ReplacedBy,
+		&i.ClientID,
+		&i.Scopes,
+		&i.OrganizationID,
+	)
+	return i, err
+}
+
 const updateSession = `-- name: UpdateSession :one
 UPDATE sessions
 SET
@@ -181,19 +319,23 @@ SET
   client_ip = $4,
   expires_at = $5,
   revoked_at = $6,
-  updated_at = $7
+  updated_at = $7,
+  flagged_at = $8,
+  replaced_by = $9
 WHERE id = $1
-RETURNING id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at
+RETURNING id, user_id, token, user_agent, client_ip, expires_at, revoked_at, created_at, updated_at, flagged_at, authenticated_at, replaced_by, client_id, scopes, organization_id
 `
 
 type UpdateSessionParams struct {
-	ID        uuid.UUID
-	Token     string
-	UserAgent string
-	ClientIP  string
-	ExpiresAt time.Time
-	RevokedAt *time.Time
-	UpdatedAt time.Time
+	ID         uuid.UUID
+	Token      string
+	UserAgent  string
+	ClientIP   string
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	UpdatedAt  time.Time
+	FlaggedAt  *time.Time
+	ReplacedBy *uuid.UUID
 }
 
 func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (Session, error) {
@@ -205,6 +347,8 @@ func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (S
 		arg.ExpiresAt,
 		arg.RevokedAt,
 		arg.UpdatedAt,
+		arg.FlaggedAt,
+		arg.ReplacedBy,
 	)
 	var i Session
 	err := row.Scan(
@@ -217,6 +361,12 @@ func (q *Queries) UpdateSession(ctx context.Context, arg UpdateSessionParams) (S
 		&i.RevokedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.FlaggedAt,
+		&i.AuthenticatedAt,
+		&i.ReplacedBy,
+		&i.ClientID,
+		&i.Scopes,
+		&i.OrganizationID,
 	)
 	return i, err
 }
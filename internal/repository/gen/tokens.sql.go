@@ -20,21 +20,25 @@ INSERT INTO tokens (
   type,
   expires_at,
   used_at,
-  created_at
+  created_at,
+  binding_hash,
+  code_hash
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, user_id, token, type, expires_at, used_at, created_at
+RETURNING id, user_id, token, type, expires_at, used_at, created_at, binding_hash, code_hash, attempts
 `
 
 type CreateTokenParams struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Token     string
-	Type      string
-	ExpiresAt time.Time
-	UsedAt    *time.Time
-	CreatedAt time.Time
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Token       string
+	Type        string
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+	CreatedAt   time.Time
+	BindingHash *string
+	CodeHash    *string
 }
 
 func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (Token, error) {
@@ -46,6 +50,8 @@ func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (Token
 		arg.ExpiresAt,
 		arg.UsedAt,
 		arg.CreatedAt,
+		arg.BindingHash,
+		arg.CodeHash,
 	)
 	var i Token
 	err := row.Scan(
@@ -56,6 +62,9 @@ func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (Token
 		&i.ExpiresAt,
 		&i.UsedAt,
 		&i.CreatedAt,
+		&i.BindingHash,
+		&i.CodeHash,
+		&i.Attempts,
 	)
 	return i, err
 }
@@ -80,8 +89,33 @@ func (q *Queries) DeleteTokensByUserID(ctx context.Context, userID uuid.UUID) er
 	return err
 }
 
+const getTokenByHash = `-- name: GetTokenByHash :one
+SELECT id, user_id, token, type, expires_at, used_at, created_at, binding_hash, code_hash, attempts
+FROM tokens
+WHERE token = $1
+LIMIT 1
+`
+
+func (q *Queries) GetTokenByHash(ctx context.Context, token string) (Token, error) {
+	row := q.db.QueryRow(ctx, getTokenByHash, token)
+	var i Token
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.Type,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+		&i.BindingHash,
+		&i.CodeHash,
+		&i.Attempts,
+	)
+	return i, err
+}
+
 const getTokenByID = `-- name: GetTokenByID :one
-SELECT id, user_id, token, type, expires_at, used_at, created_at
+SELECT id, user_id, token, type, expires_at, used_at, created_at, binding_hash, code_hash, attempts
 FROM tokens
 WHERE id = $1
 LIMIT 1
@@ -98,12 +132,15 @@ func (q *Queries) GetTokenByID(ctx context.Context, id uuid.UUID) (Token, error)
 		&i.ExpiresAt,
 		&i.UsedAt,
 		&i.CreatedAt,
+		&i.BindingHash,
+		&i.CodeHash,
+		&i.Attempts,
 	)
 	return i, err
 }
 
 const getTokensByUserID = `-- name: GetTokensByUserID :many
-SELECT id, user_id, token, type, expires_at, used_at, created_at
+SELECT id, user_id, token, type, expires_at, used_at, created_at, binding_hash, code_hash, attempts
 FROM tokens
 WHERE user_id = $1
 ORDER BY created_at DESC
@@ -126,6 +163,9 @@ func (q *Queries) GetTokensByUserID(ctx context.Context, userID uuid.UUID) ([]To
 			&i.ExpiresAt,
 			&i.UsedAt,
 			&i.CreatedAt,
+			&i.BindingHash,
+			&i.CodeHash,
+			&i.Attempts,
 		); err != nil {
 			return nil, err
 		}
@@ -137,6 +177,47 @@ func (q *Queries) GetTokensByUserID(ctx context.Context, userID uuid.UUID) ([]To
 	return items, nil
 }
 
+const incrementTokenAttempts = `-- name: IncrementTokenAttempts :one
+UPDATE tokens
+SET attempts = attempts + 1
+WHERE id = $1
+  AND used_at IS NULL
+  AND attempts < $2
+RETURNING attempts
+`
+
+type IncrementTokenAttemptsParams struct {
+	ID          uuid.UUID
+	MaxAttempts int32
+}
+
+func (q *Queries) IncrementTokenAttempts(ctx context.Context, arg IncrementTokenAttemptsParams) (int32, error) {
+	row := q.db.QueryRow(ctx, incrementTokenAttempts, arg.ID, arg.MaxAttempts)
+	var attempts int32
+	err := row.Scan(&attempts)
+	return attempts, err
+}
+
+const markTokenUsed = `-- name: MarkTokenUsed :execrows
+UPDATE tokens
+SET used_at = $2
+WHERE id = $1
+  AND used_at IS NULL
+`
+
+type MarkTokenUsedParams struct {
+	ID     uuid.UUID
+	UsedAt *time.Time
+}
+
+func (q *Queries) MarkTokenUsed(ctx context.Context, arg MarkTokenUsedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markTokenUsed, arg.ID, arg.UsedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateToken = `-- name: UpdateToken :one
 UPDATE tokens
 SET
@@ -145,7 +226,7 @@ SET
   expires_at = $4,
   used_at = $5
 WHERE id = $1
-RETURNING id, user_id, token, type, expires_at, used_at, created_at
+RETURNING id, user_id, token, type, expires_at, used_at, created_at, binding_hash, code_hash, attempts
 `
 
 type UpdateTokenParams struct {
@@ -173,6 +254,9 @@ func (q *Queries) UpdateToken(ctx context.Context, arg UpdateTokenParams) (Token
 		&i.ExpiresAt,
 		&i.UsedAt,
 		&i.CreatedAt,
+		&i.BindingHash,
+		&i.CodeHash,
+		&i.Attempts,
 	)
 	return i, err
 }
@@ -24,25 +24,29 @@ INSERT INTO users (
   status,
   verified_at,
   created_at,
-  updated_at
+  updated_at,
+  mfa_factor,
+  organization_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
-RETURNING id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at
+RETURNING id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at, mfa_factor, organization_id
 `
 
 type CreateUserParams struct {
-	ID         uuid.UUID
-	Username   string
-	Email      string
-	Password   string
-	FirstName  string
-	LastName   string
-	Role       string
-	Status     string
-	VerifiedAt *time.Time
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID             uuid.UUID
+	Username       string
+	Email          string
+	Password       string
+	FirstName      string
+	LastName       string
+	Role           string
+	Status         string
+	VerifiedAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	MFAFactor      *string
+	OrganizationID *uuid.UUID
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -58,6 +62,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.VerifiedAt,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.MFAFactor,
+		arg.OrganizationID,
 	)
 	var i User
 	err := row.Scan(
@@ -72,6 +78,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.VerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MFAFactor,
+		&i.OrganizationID,
 	)
 	return i, err
 }
@@ -97,6 +105,48 @@ func (q *Queries) ExistsByEmail(ctx context.Context, email string) (bool, error)
 	return exists, err
 }
 
+const existsByOrganizationAndEmail = `-- name: ExistsByOrganizationAndEmail :one
+SELECT EXISTS(
+  SELECT 1 FROM users
+  WHERE COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)
+      = COALESCE($1::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+    AND email = $2
+)
+`
+
+type ExistsByOrganizationAndEmailParams struct {
+	OrganizationID *uuid.UUID
+	Email          string
+}
+
+func (q *Queries) ExistsByOrganizationAndEmail(ctx context.Context, arg ExistsByOrganizationAndEmailParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existsByOrganizationAndEmail, arg.OrganizationID, arg.Email)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const existsByOrganizationAndUsername = `-- name: ExistsByOrganizationAndUsername :one
+SELECT EXISTS(
+  SELECT 1 FROM users
+  WHERE COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)
+      = COALESCE($1::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+    AND username = $2
+)
+`
+
+type ExistsByOrganizationAndUsernameParams struct {
+	OrganizationID *uuid.UUID
+	Username       string
+}
+
+func (q *Queries) ExistsByOrganizationAndUsername(ctx context.Context, arg ExistsByOrganizationAndUsernameParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existsByOrganizationAndUsername, arg.OrganizationID, arg.Username)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const existsByUsername = `-- name: ExistsByUsername :one
 SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)
 `
@@ -109,7 +159,7 @@ func (q *Queries) ExistsByUsername(ctx context.Context, username string) (bool,
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at
+SELECT id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at, mfa_factor, organization_id
 FROM users
 WHERE email = $1
 LIMIT 1
@@ -130,12 +180,14 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.VerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MFAFactor,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at
+SELECT id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at, mfa_factor, organization_id
 FROM users
 WHERE id = $1
 LIMIT 1
@@ -156,12 +208,84 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.VerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MFAFactor,
+		&i.OrganizationID,
+	)
+	return i, err
+}
+
+const getUserByOrganizationAndEmail = `-- name: GetUserByOrganizationAndEmail :one
+SELECT id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at, mfa_factor, organization_id
+FROM users
+WHERE COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)
+    = COALESCE($1::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+  AND email = $2
+LIMIT 1
+`
+
+type GetUserByOrganizationAndEmailParams struct {
+	OrganizationID *uuid.UUID
+	Email          string
+}
+
+func (q *Queries) GetUserByOrganizationAndEmail(ctx context.Context, arg GetUserByOrganizationAndEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByOrganizationAndEmail, arg.OrganizationID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.FirstName,
+		&i.LastName,
+		&i.Role,
+		&i.Status,
+		&i.VerifiedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MFAFactor,
+		&i.OrganizationID,
+	)
+	return i, err
+}
+
+const getUserByOrganizationAndUsername = `-- name: GetUserByOrganizationAndUsername :one
+SELECT id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at, mfa_factor, organization_id
+FROM users
+WHERE COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)
+    = COALESCE($1::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+  AND username = $2
+LIMIT 1
+`
+
+type GetUserByOrganizationAndUsernameParams struct {
+	OrganizationID *uuid.UUID
+	Username       string
+}
+
+func (q *Queries) GetUserByOrganizationAndUsername(ctx context.Context, arg GetUserByOrganizationAndUsernameParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByOrganizationAndUsername, arg.OrganizationID, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.FirstName,
+		&i.LastName,
+		&i.Role,
+		&i.Status,
+		&i.VerifiedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MFAFactor,
+		&i.OrganizationID,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at
+SELECT id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at, mfa_factor, organization_id
 FROM users
 WHERE username = $1
 LIMIT 1
@@ -182,6 +306,8 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.VerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MFAFactor,
+		&i.OrganizationID,
 	)
 	return i, err
 }
@@ -197,9 +323,10 @@ SET
   role = $7,
   status = $8,
   verified_at = $9,
-  updated_at = $10
+  updated_at = $10,
+  mfa_factor = $11
 WHERE id = $1
-RETURNING id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at
+RETURNING id, username, email, password, first_name, last_name, role, status, verified_at, created_at, updated_at, mfa_factor, organization_id
 `
 
 type UpdateUserParams struct {
@@ -213,6 +340,7 @@ type UpdateUserParams struct {
 	Status     string
 	VerifiedAt *time.Time
 	UpdatedAt  time.Time
+	MFAFactor  *string
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -227,6 +355,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Status,
 		arg.VerifiedAt,
 		arg.UpdatedAt,
+		arg.MFAFactor,
 	)
 	var i User
 	err := row.Scan(
@@ -241,6 +370,8 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.VerifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MFAFactor,
+		&i.OrganizationID,
 	)
 	return i, err
 }
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var _ domain.InvitationRepository = (*InvitationRepository)(nil)
+
+type InvitationRepository struct {
+	q *gen.Queries
+}
+
+func NewInvitationRepository(q *gen.Queries) *InvitationRepository {
+	return &InvitationRepository{q: q}
+}
+
+func (ir *InvitationRepository) Save(ctx context.Context, invitation *domain.Invitation) error {
+	return ir.q.CreateOrganizationInvitation(ctx, gen.CreateOrganizationInvitationParams{
+		ID:             invitation.ID,
+		OrganizationID: invitation.OrganizationID,
+		InviterID:      invitation.InviterID,
+		Email:          invitation.Email.String(),
+		Role:           invitation.Role.String(),
+		Token:          invitation.Token,
+		ExpiresAt:      invitation.ExpiresAt,
+		CreatedAt:      invitation.CreatedAt,
+	})
+}
+
+func (ir *InvitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Invitation, error) {
+	repoInvitation, err := ir.q.GetOrganizationInvitationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get invitation by id: %w", err)
+	}
+
+	return toDomainInvitation(&repoInvitation)
+}
+
+func (ir *InvitationRepository) GetByToken(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	repoInvitation, err := ir.q.GetOrganizationInvitationByToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get invitation by token: %w", err)
+	}
+
+	return toDomainInvitation(&repoInvitation)
+}
+
+func (ir *InvitationRepository) ListByOrganization(
+	ctx context.Context,
+	organizationID uuid.UUID,
+) ([]*domain.Invitation, error) {
+	repoInvitations, err := ir.q.ListOrganizationInvitations(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("list invitations: %w", err)
+	}
+
+	out := make([]*domain.Invitation, len(repoInvitations))
+	for i := range repoInvitations {
+		invitation, convErr := toDomainInvitation(&repoInvitations[i])
+		if convErr != nil {
+			return nil, convErr
+		}
+
+		out[i] = invitation
+	}
+
+	return out, nil
+}
+
+func (ir *InvitationRepository) Accept(ctx context.Context, invitation *domain.Invitation) error {
+	if invitation.AcceptedAt == nil {
+		return errors.New("accept invitation: invitation is not marked accepted")
+	}
+
+	n, err := ir.q.AcceptOrganizationInvitation(ctx, gen.AcceptOrganizationInvitationParams{
+		ID:         invitation.ID,
+		AcceptedAt: invitation.AcceptedAt,
+		AcceptedBy: invitation.AcceptedBy,
+	})
+	if err != nil {
+		return fmt.Errorf("accept invitation: %w", err)
+	}
+
+	if n == 0 {
+		return domain.ErrInvitationNotPending
+	}
+
+	return nil
+}
+
+func (ir *InvitationRepository) Revoke(ctx context.Context, invitation *domain.Invitation) error {
+	if invitation.RevokedAt == nil {
+		return errors.New("revoke invitation: invitation is not marked revoked")
+	}
+
+	n, err := ir.q.RevokeOrganizationInvitation(ctx, gen.RevokeOrganizationInvitationParams{
+		ID:        invitation.ID,
+		RevokedAt: invitation.RevokedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("revoke invitation: %w", err)
+	}
+
+	if n == 0 {
+		return domain.ErrInvitationNotPending
+	}
+
+	return nil
+}
+
+func toDomainInvitation(repoInvitation *gen.OrganizationInvitation) (*domain.Invitation, error) {
+	email, err := domain.NewEmail(repoInvitation.Email)
+	if err != nil {
+		return nil, fmt.Errorf("email: %w", err)
+	}
+
+	role, err := domain.NewRole(repoInvitation.Role)
+	if err != nil {
+		return nil, fmt.Errorf("role: %w", err)
+	}
+
+	return &domain.Invitation{
+		ID:             repoInvitation.ID,
+		OrganizationID: repoInvitation.OrganizationID,
+		InviterID:      repoInvitation.InviterID,
+		Email:          email,
+		Role:           role,
+		Token:          repoInvitation.Token,
+		ExpiresAt:      repoInvitation.ExpiresAt,
+		AcceptedAt:     repoInvitation.AcceptedAt,
+		AcceptedBy:     repoInvitation.AcceptedBy,
+		RevokedAt:      repoInvitation.RevokedAt,
+		CreatedAt:      repoInvitation.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var (
+	_ domain.OAuthClientRepository       = (*OAuthClientRepository)(nil)
+	_ domain.AuthorizationCodeRepository = (*AuthorizationCodeRepository)(nil)
+)
+
+type OAuthClientRepository struct {
+	q *gen.Queries
+}
+
+func NewOAuthClientRepository(q *gen.Queries) *OAuthClientRepository {
+	return &OAuthClientRepository{q: q}
+}
+
+func (cr *OAuthClientRepository) Save(ctx context.Context, client *domain.OAuthClient) error {
+	_, err := cr.q.CreateOAuthClient(ctx, gen.CreateOAuthClientParams{
+		ID:           client.ID,
+		Name:         client.Name,
+		SecretHash:   nullableString(client.SecretHash),
+		RedirectURIs: client.RedirectURIs,
+		Scopes:       nonNilStrings(client.Scopes),
+		GrantTypes:   client.GrantTypes,
+		DisabledAt:   client.DisabledAt,
+		CreatedAt:    client.CreatedAt,
+		UpdatedAt:    client.UpdatedAt,
+	})
+
+	return err
+}
+
+func (cr *OAuthClientRepository) GetByID(ctx context.Context, id string) (*domain.OAuthClient, error) {
+	repoClient, err := cr.q.GetOAuthClientByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get oauth client by id: %w", err)
+	}
+
+	return &domain.OAuthClient{
+		ID:           repoClient.ID,
+		Name:         repoClient.Name,
+		SecretHash:   derefString(repoClient.SecretHash),
+		RedirectURIs: repoClient.RedirectURIs,
+		Scopes:       repoClient.Scopes,
+		GrantTypes:   repoClient.GrantTypes,
+		DisabledAt:   repoClient.DisabledAt,
+		CreatedAt:    repoClient.CreatedAt,
+		UpdatedAt:    repoClient.UpdatedAt,
+	}, nil
+}
+
+func (cr *OAuthClientRepository) Update(ctx context.Context, client *domain.OAuthClient) error {
+	_, err := cr.q.UpdateOAuthClient(ctx, gen.UpdateOAuthClientParams{
+		ID:           client.ID,
+		Name:         client.Name,
+		SecretHash:   nullableString(client.SecretHash),
+		RedirectURIs: nonNilStrings(client.RedirectURIs),
+		Scopes:       nonNilStrings(client.Scopes),
+		GrantTypes:   client.GrantTypes,
+		DisabledAt:   client.DisabledAt,
+		UpdatedAt:    client.UpdatedAt,
+	})
+
+	return err
+}
+
+type AuthorizationCodeRepository struct {
+	q *gen.Queries
+}
+
+func NewAuthorizationCodeRepository(q *gen.Queries) *AuthorizationCodeRepository {
+	return &AuthorizationCodeRepository{q: q}
+}
+
+func (ar *AuthorizationCodeRepository) Save(ctx context.Context, code *domain.AuthorizationCode) error {
+	_, err := ar.q.CreateOAuthAuthorizationCode(ctx, gen.CreateOAuthAuthorizationCodeParams{
+		CodeHash:            code.CodeHash,
+		ClientID:            code.ClientID,
+		UserID:              code.UserID,
+		RedirectURI:         code.RedirectURI,
+		Scopes:              nonNilStrings(code.Scopes),
+		CodeChallenge:       code.PKCE.Challenge,
+		CodeChallengeMethod: code.PKCE.Method,
+		Nonce:               code.Nonce,
+		ExpiresAt:           code.ExpiresAt,
+		UsedAt:              code.UsedAt,
+		CreatedAt:           code.CreatedAt,
+	})
+
+	return err
+}
+
+func (ar *AuthorizationCodeRepository) GetByHash(ctx context.Context, codeHash string) (*domain.AuthorizationCode, error) {
+	repoCode, err := ar.q.GetOAuthAuthorizationCodeByHash(ctx, codeHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get authorization code by hash: %w", err)
+	}
+
+	return &domain.AuthorizationCode{
+		CodeHash:    repoCode.CodeHash,
+		ClientID:    repoCode.ClientID,
+		UserID:      repoCode.UserID,
+		RedirectURI: repoCode.RedirectURI,
+		Scopes:      repoCode.Scopes,
+		PKCE: domain.PKCEChallenge{
+			Challenge: repoCode.CodeChallenge,
+			Method:    repoCode.CodeChallengeMethod,
+		},
+		Nonce:     repoCode.Nonce,
+		ExpiresAt: repoCode.ExpiresAt,
+		UsedAt:    repoCode.UsedAt,
+		CreatedAt: repoCode.CreatedAt,
+	}, nil
+}
+
+func (ar *AuthorizationCodeRepository) Use(ctx context.Context, code *domain.AuthorizationCode) error {
+	if code.UsedAt == nil {
+		return errors.New("use authorization code: code is not marked used")
+	}
+
+	n, err := ar.q.MarkOAuthAuthorizationCodeUsed(ctx, gen.MarkOAuthAuthorizationCodeUsedParams{
+		CodeHash: code.CodeHash,
+		UsedAt:   code.UsedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("use authorization code: %w", err)
+	}
+
+	if n == 0 {
+		return domain.ErrTokenUsed
+	}
+
+	return nil
+}
+
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var _ domain.OrganizationRepository = (*OrganizationRepository)(nil)
+
+type OrganizationRepository struct {
+	q *gen.Queries
+}
+
+func NewOrganizationRepository(q *gen.Queries) *OrganizationRepository {
+	return &OrganizationRepository{q: q}
+}
+
+func (or *OrganizationRepository) Save(ctx context.Context, organization *domain.Organization) error {
+	n, err := or.q.CreateOrganization(ctx, gen.CreateOrganizationParams{
+		ID:        organization.ID,
+		Slug:      organization.Slug,
+		Name:      organization.Name,
+		CreatedAt: organization.CreatedAt,
+		UpdatedAt: organization.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("save organization: %w", err)
+	}
+
+	if n == 0 {
+		return domain.ErrOrganizationSlugTaken
+	}
+
+	return nil
+}
+
+func (or *OrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	repoOrganization, err := or.q.GetOrganizationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get organization by id: %w", err)
+	}
+
+	return toDomainOrganization(&repoOrganization), nil
+}
+
+func (or *OrganizationRepository) GetBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	repoOrganization, err := or.q.GetOrganizationBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get organization by slug: %w", err)
+	}
+
+	return toDomainOrganization(&repoOrganization), nil
+}
+
+func (or *OrganizationRepository) SaveMember(ctx context.Context, member *domain.OrganizationMember) error {
+	return or.q.UpsertOrganizationMember(ctx, gen.UpsertOrganizationMemberParams{
+		OrganizationID: member.OrganizationID,
+		UserID:         member.UserID,
+		Role:           member.Role.String(),
+		CreatedAt:      member.CreatedAt,
+		UpdatedAt:      member.UpdatedAt,
+	})
+}
+
+func (or *OrganizationRepository) GetMember(
+	ctx context.Context,
+	organizationID, userID uuid.UUID,
+) (*domain.OrganizationMember, error) {
+	repoMember, err := or.q.GetOrganizationMember(ctx, gen.GetOrganizationMemberParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get organization member: %w", err)
+	}
+
+	role, err := domain.NewRole(repoMember.Role)
+	if err != nil {
+		return nil, fmt.Errorf("role: %w", err)
+	}
+
+	return &domain.OrganizationMember{
+		OrganizationID: repoMember.OrganizationID,
+		UserID:         repoMember.UserID,
+		Role:           role,
+		CreatedAt:      repoMember.CreatedAt,
+		UpdatedAt:      repoMember.UpdatedAt,
+	}, nil
+}
+
+func (or *OrganizationRepository) ListMemberships(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*domain.OrganizationMembership, error) {
+	rows, err := or.q.ListOrganizationMembershipsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list organization memberships: %w", err)
+	}
+
+	out := make([]*domain.OrganizationMembership, len(rows))
+	for i, row := range rows {
+		role, roleErr := domain.NewRole(row.Role)
+		if roleErr != nil {
+			return nil, fmt.Errorf("role: %w", roleErr)
+		}
+
+		out[i] = &domain.OrganizationMembership{
+			Organization: &domain.Organization{
+				ID:        row.ID,
+				Slug:      row.Slug,
+				Name:      row.Name,
+				CreatedAt: row.CreatedAt,
+				UpdatedAt: row.UpdatedAt,
+			},
+			Role:     role,
+			JoinedAt: row.JoinedAt,
+		}
+	}
+
+	return out, nil
+}
+
+func (or *OrganizationRepository) RemoveMember(ctx context.Context, organizationID, userID uuid.UUID) (bool, error) {
+	n, err := or.q.DeleteOrganizationMember(ctx, gen.DeleteOrganizationMemberParams{
+		OrganizationID: organizationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("remove organization member: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+func toDomainOrganization(repoOrganization *gen.Organization) *domain.Organization {
+	return &domain.Organization{
+		ID:        repoOrganization.ID,
+		Slug:      repoOrganization.Slug,
+		Name:      repoOrganization.Name,
+		CreatedAt: repoOrganization.CreatedAt,
+		UpdatedAt: repoOrganization.UpdatedAt,
+	}
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var (
+	_ domain.PasskeyCredentialRepository = (*PasskeyCredentialRepository)(nil)
+	_ domain.PasskeyChallengeRepository  = (*PasskeyChallengeRepository)(nil)
+)
+
+type PasskeyCredentialRepository struct {
+	q *gen.Queries
+}
+
+func NewPasskeyCredentialRepository(q *gen.Queries) *PasskeyCredentialRepository {
+	return &PasskeyCredentialRepository{q: q}
+}
+
+func (pr *PasskeyCredentialRepository) Save(ctx context.Context, credential *domain.PasskeyCredential) error {
+	n, err := pr.q.CreatePasskeyCredential(ctx, gen.CreatePasskeyCredentialParams{
+		ID:           credential.ID,
+		UserID:       credential.UserID,
+		CredentialID: credential.CredentialID,
+		PublicKey:    credential.PublicKey,
+		SignCount:    int64(credential.SignCount),
+		AAGUID:       credential.AAGUID,
+		Transports:   nonNilStrings(credential.Transports),
+		Name:         credential.Name,
+		CreatedAt:    credential.CreatedAt,
+		LastUsedAt:   credential.LastUsedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("save passkey credential: %w", err)
+	}
+
+	if n == 0 {
+		return domain.ErrPasskeyAlreadyRegistered
+	}
+
+	return nil
+}
+
+func (pr *PasskeyCredentialRepository) GetByCredentialID(
+	ctx context.Context,
+	credentialID []byte,
+) (*domain.PasskeyCredential, error) {
+	repoCredential, err := pr.q.GetPasskeyCredentialByCredentialID(ctx, credentialID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get passkey credential by credential id: %w", err)
+	}
+
+	return toDomainPasskeyCredential(&repoCredential), nil
+}
+
+func (pr *PasskeyCredentialRepository) ListByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*domain.PasskeyCredential, error) {
+	repoCredentials, err := pr.q.ListPasskeyCredentialsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list passkey credentials by user id: %w", err)
+	}
+
+	out := make([]*domain.PasskeyCredential, len(repoCredentials))
+	for i := range repoCredentials {
+		out[i] = toDomainPasskeyCredential(&repoCredentials[i])
+	}
+
+	return out, nil
+}
+
+func (pr *PasskeyCredentialRepository) Use(ctx context.Context, credential *domain.PasskeyCredential) error {
+	n, err := pr.q.UsePasskeyCredential(ctx, gen.UsePasskeyCredentialParams{
+		SignCount:  int64(credential.SignCount),
+		LastUsedAt: credential.LastUsedAt,
+		ID:         credential.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("use passkey credential: %w", err)
+	}
+
+	if n == 0 {
+		return domain.ErrPasskeySignCount
+	}
+
+	return nil
+}
+
+func (pr *PasskeyCredentialRepository) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	n, err := pr.q.DeletePasskeyCredential(ctx, gen.DeletePasskeyCredentialParams{ID: id, UserID: userID})
+	if err != nil {
+		return false, fmt.Errorf("delete passkey credential: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+func toDomainPasskeyCredential(repoCredential *gen.PasskeyCredential) *domain.PasskeyCredential {
+	return &domain.PasskeyCredential{
+		ID:           repoCredential.ID,
+		UserID:       repoCredential.UserID,
+		CredentialID: repoCredential.CredentialID,
+		PublicKey:    repoCredential.PublicKey,
+		SignCount:    uint32(repoCredential.SignCount), //nolint:gosec // only ever written from a uint32
+		AAGUID:       repoCredential.AAGUID,
+		Transports:   repoCredential.Transports,
+		Name:         repoCredential.Name,
+		CreatedAt:    repoCredential.CreatedAt,
+		LastUsedAt:   repoCredential.LastUsedAt,
+	}
+}
+
+type PasskeyChallengeRepository struct {
+	q *gen.Queries
+}
+
+func NewPasskeyChallengeRepository(q *gen.Queries) *PasskeyChallengeRepository {
+	return &PasskeyChallengeRepository{q: q}
+}
+
+func (cr *PasskeyChallengeRepository) Save(ctx context.Context, challenge *domain.PasskeyChallenge) error {
+	return cr.q.CreatePasskeyChallenge(ctx, gen.CreatePasskeyChallengeParams{
+		ChallengeHash: challenge.ChallengeHash,
+		Ceremony:      challenge.Ceremony.String(),
+		UserID:        challenge.UserID,
+		ExpiresAt:     challenge.ExpiresAt,
+		CreatedAt:     challenge.CreatedAt,
+	})
+}
+
+func (cr *PasskeyChallengeRepository) Consume(
+	ctx context.Context,
+	challengeHash string,
+) (*domain.PasskeyChallenge, error) {
+	repoChallenge, err := cr.q.ConsumePasskeyChallenge(ctx, challengeHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("consume passkey challenge: %w", err)
+	}
+
+	return &domain.PasskeyChallenge{
+		ChallengeHash: repoChallenge.ChallengeHash,
+		Ceremony:      domain.PasskeyCeremony(repoChallenge.Ceremony),
+		UserID:        repoChallenge.UserID,
+		ExpiresAt:     repoChallenge.ExpiresAt,
+		CreatedAt:     repoChallenge.CreatedAt,
+	}, nil
+}
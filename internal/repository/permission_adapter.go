@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var _ domain.PermissionRepository = (*PermissionRepository)(nil)
+
+type PermissionRepository struct {
+	q *gen.Queries
+}
+
+func NewPermissionRepository(q *gen.Queries) *PermissionRepository {
+	return &PermissionRepository{q: q}
+}
+
+func (pr *PermissionRepository) Save(ctx context.Context, perm *domain.PermissionDefinition) error {
+	_, err := pr.q.CreatePermission(ctx, gen.CreatePermissionParams{
+		Name:        perm.Name.String(),
+		Description: perm.Description,
+		CreatedAt:   perm.CreatedAt,
+	})
+
+	return err
+}
+
+func (pr *PermissionRepository) GetByName(
+	ctx context.Context,
+	name domain.Permission,
+) (*domain.PermissionDefinition, error) {
+	repoPerm, err := pr.q.GetPermissionByName(ctx, name.String())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get permission by name: %w", err)
+	}
+
+	return toDomainPermission(&repoPerm), nil
+}
+
+func (pr *PermissionRepository) List(ctx context.Context) ([]*domain.PermissionDefinition, error) {
+	repoPerms, err := pr.q.ListPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list permissions: %w", err)
+	}
+
+	out := make([]*domain.PermissionDefinition, len(repoPerms))
+	for i := range repoPerms {
+		out[i] = toDomainPermission(&repoPerms[i])
+	}
+
+	return out, nil
+}
+
+func (pr *PermissionRepository) Delete(ctx context.Context, name domain.Permission) error {
+	return pr.q.DeletePermission(ctx, name.String())
+}
+
+func toDomainPermission(repoPerm *gen.Permission) *domain.PermissionDefinition {
+	return &domain.PermissionDefinition{
+		Name:        domain.Permission(repoPerm.Name),
+		Description: repoPerm.Description,
+		CreatedAt:   repoPerm.CreatedAt,
+	}
+}
@@ -7,8 +7,48 @@ import (
 	"go-auth/internal/repository/gen"
 )
 
-func NewRepositories(pool *pgxpool.Pool) (domain.UserRepository, domain.SessionRepository, domain.TokenRepository) {
-	q := gen.New(pool)
+type Repositories struct {
+	Users             domain.UserRepository
+	Sessions          domain.SessionRepository
+	Tokens            domain.TokenRepository
+	Roles             domain.RoleRepository
+	Permissions       domain.PermissionRepository
+	Audit             *AuditRepository
+	Devices           domain.KnownDeviceRepository
+	OAuthClients      domain.OAuthClientRepository
+	AuthCodes         domain.AuthorizationCodeRepository
+	Identities        domain.UserIdentityRepository
+	LoginStates       domain.FederatedLoginStateRepository
+	Passkeys          domain.PasskeyCredentialRepository
+	PasskeyChallenges domain.PasskeyChallengeRepository
+	Organizations     domain.OrganizationRepository
+	Invitations       domain.InvitationRepository
+	APIKeys           domain.APIKeyRepository
+	Webhooks          domain.WebhookRepository
+	Transactor        domain.Transactor
+}
+
+func NewRepositories(pool *pgxpool.Pool) *Repositories {
+	q := gen.New(conn{pool: pool})
 
-	return NewUserRepository(q), NewSessionRepository(q), NewTokenRepository(q)
+	return &Repositories{
+		Users:             NewUserRepository(q),
+		Sessions:          NewSessionRepository(q),
+		Tokens:            NewTokenRepository(q),
+		Roles:             NewRoleRepository(q),
+		Permissions:       NewPermissionRepository(q),
+		Audit:             NewAuditRepository(q),
+		Devices:           NewKnownDeviceRepository(q),
+		OAuthClients:      NewOAuthClientRepository(q),
+		AuthCodes:         NewAuthorizationCodeRepository(q),
+		Identities:        NewUserIdentityRepository(q),
+		LoginStates:       NewFederatedLoginStateRepository(q),
+		Passkeys:          NewPasskeyCredentialRepository(q),
+		PasskeyChallenges: NewPasskeyChallengeRepository(q),
+		Organizations:     NewOrganizationRepository(q),
+		Invitations:       NewInvitationRepository(q),
+		APIKeys:           NewAPIKeyRepository(q),
+		Webhooks:          NewWebhookRepository(q),
+		Transactor:        NewTransactor(pool),
+	}
 }
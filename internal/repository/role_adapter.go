@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var _ domain.RoleRepository = (*RoleRepository)(nil)
+
+type RoleRepository struct {
+	q *gen.Queries
+}
+
+func NewRoleRepository(q *gen.Queries) *RoleRepository {
+	return &RoleRepository{q: q}
+}
+
+func (rr *RoleRepository) Save(ctx context.Context, role *domain.RoleDefinition) error {
+	if _, err := rr.q.CreateRole(ctx, toCreateRoleParams(role)); err != nil {
+		return err
+	}
+
+	return rr.q.AddRolePermissions(ctx, gen.AddRolePermissionsParams{
+		Role:        role.Name,
+		Permissions: toPermissionNames(role.Permissions),
+	})
+}
+
+func (rr *RoleRepository) GetByName(ctx context.Context, name string) (*domain.RoleDefinition, error) {
+	repoRole, err := rr.q.GetRoleByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get role by name: %w", err)
+	}
+
+	perms, err := rr.q.GetPermissionsByRole(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("get permissions by role: %w", err)
+	}
+
+	return toDomainRole(&repoRole, perms), nil
+}
+
+func (rr *RoleRepository) List(ctx context.Context) ([]*domain.RoleDefinition, error) {
+	repoRoles, err := rr.q.ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list roles: %w", err)
+	}
+
+	repoRolePerms, err := rr.q.ListRolePermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list role permissions: %w", err)
+	}
+
+	permsByRole := make(map[string][]string, len(repoRoles))
+	for _, rp := range repoRolePerms {
+		permsByRole[rp.Role] = append(permsByRole[rp.Role], rp.Permission)
+	}
+
+	out := make([]*domain.RoleDefinition, len(repoRoles))
+	for i := range repoRoles {
+		out[i] = toDomainRole(&repoRoles[i], permsByRole[repoRoles[i].Name])
+	}
+
+	return out, nil
+}
+
+func (rr *RoleRepository) Update(ctx context.Context, role *domain.RoleDefinition) error {
+	if _, err := rr.q.UpdateRole(ctx, toUpdateRoleParams(role)); err != nil {
+		return err
+	}
+
+	perms := toPermissionNames(role.Permissions)
+
+	if err := rr.q.DeleteRolePermissionsExcept(ctx, gen.DeleteRolePermissionsExceptParams{
+		Role:        role.Name,
+		Permissions: perms,
+	}); err != nil {
+		return err
+	}
+
+	return rr.q.AddRolePermissions(ctx, gen.AddRolePermissionsParams{
+		Role:        role.Name,
+		Permissions: perms,
+	})
+}
+
+func (rr *RoleRepository) Delete(ctx context.Context, name string) error {
+	return rr.q.DeleteRole(ctx, name)
+}
+
+func (rr *RoleRepository) IsAssigned(ctx context.Context, name string) (bool, error) {
+	return rr.q.ExistsRoleAssignment(ctx, name)
+}
+
+func toCreateRoleParams(role *domain.RoleDefinition) gen.CreateRoleParams {
+	return gen.CreateRoleParams{
+		Name:        role.Name,
+		Description: role.Description,
+		BuiltIn:     role.BuiltIn,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func toUpdateRoleParams(role *domain.RoleDefinition) gen.UpdateRoleParams {
+	return gen.UpdateRoleParams{
+		Name:        role.Name,
+		Description: role.Description,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func toPermissionNames(perms []domain.Permission) []string {
+	out := make([]string, len(perms))
+	for i, perm := range perms {
+		out[i] = perm.String()
+	}
+
+	return out
+}
+
+func toDomainRole(repoRole *gen.Role, perms []string) *domain.RoleDefinition {
+	domainPerms := make([]domain.Permission, len(perms))
+	for i, perm := range perms {
+		domainPerms[i] = domain.Permission(perm)
+	}
+
+	return &domain.RoleDefinition{
+		Name:        repoRole.Name,
+		Description: repoRole.Description,
+		Permissions: domainPerms,
+		BuiltIn:     repoRole.BuiltIn,
+		CreatedAt:   repoRole.CreatedAt,
+		UpdatedAt:   repoRole.UpdatedAt,
+	}
+}
@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/repository"
+	"go-auth/internal/repository/gen"
+)
+
+// recordingDB captures the last single-row query and answers it with result.
+type recordingDB struct {
+	gen.DBTX
+
+	query  string
+	args   []any
+	result bool
+}
+
+func (db *recordingDB) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	db.query, db.args = query, args
+
+	return boolRow(db.result)
+}
+
+type boolRow bool
+
+func (r boolRow) Scan(dest ...any) error {
+	*dest[0].(*bool) = bool(r)
+
+	return nil
+}
+
+func TestRoleRepositoryIsAssigned(t *testing.T) {
+	db := &recordingDB{result: true}
+	repo := repository.NewRoleRepository(gen.New(db))
+
+	assigned, err := repo.IsAssigned(context.Background(), "support")
+	require.NoError(t, err)
+	assert.True(t, assigned)
+	assert.Equal(t, []any{"support"}, db.args)
+
+	// Roles referenced by a foreign key must count, or DeleteRole fails on the constraint.
+	for _, table := range []string{"users", "organization_members", "organization_invitations"} {
+		assert.Contains(t, db.query, "FROM "+table+" WHERE role = $1")
+	}
+}
@@ -74,6 +74,36 @@ func (sr *SessionRepository) Update(ctx context.Context, session *domain.Session
 	return err
 }
 
+func (sr *SessionRepository) Rotate(ctx context.Context, session, successor *domain.Session) error {
+	if session.RevokedAt == nil || session.ReplacedBy == nil || *session.ReplacedBy != successor.ID {
+		return errors.New("rotate session: session is not replaced by successor")
+	}
+
+	_, err := sr.q.RotateSession(ctx, gen.RotateSessionParams{
+		RevokedAt:       *session.RevokedAt,
+		FlaggedAt:       session.FlaggedAt,
+		SuccessorID:     successor.ID,
+		ID:              session.ID,
+		UserID:          successor.UserID,
+		Token:           successor.Token,
+		UserAgent:       successor.UserAgent,
+		ClientIP:        successor.ClientIP,
+		ExpiresAt:       successor.ExpiresAt,
+		CreatedAt:       successor.CreatedAt,
+		AuthenticatedAt: successor.AuthenticatedAt,
+		OrganizationID:  successor.OrganizationID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrSessionRevoked
+		}
+
+		return fmt.Errorf("rotate session: %w", err)
+	}
+
+	return nil
+}
+
 func (sr *SessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return sr.q.DeleteSession(ctx, id)
 }
@@ -84,40 +114,54 @@ func (sr *SessionRepository) DeleteByUserID(ctx context.Context, userID uuid.UUI
 
 func toCreateSessionParams(session *domain.Session) gen.CreateSessionParams {
 	return gen.CreateSessionParams{
-		ID:        session.ID,
-		UserID:    session.UserID,
-		Token:     session.Token,
-		UserAgent: session.UserAgent,
-		ClientIP:  session.ClientIP,
-		ExpiresAt: session.ExpiresAt,
-		RevokedAt: session.RevokedAt,
-		CreatedAt: session.CreatedAt,
-		UpdatedAt: session.UpdatedAt,
+		ID:              session.ID,
+		UserID:          session.UserID,
+		Token:           session.Token,
+		UserAgent:       session.UserAgent,
+		ClientIP:        session.ClientIP,
+		ExpiresAt:       session.ExpiresAt,
+		RevokedAt:       session.RevokedAt,
+		CreatedAt:       session.CreatedAt,
+		UpdatedAt:       session.UpdatedAt,
+		FlaggedAt:       session.FlaggedAt,
+		AuthenticatedAt: session.AuthenticatedAt,
+		ReplacedBy:      session.ReplacedBy,
+		ClientID:        nullableString(session.ClientID),
+		Scopes:          nonNilStrings(session.Scopes),
+		OrganizationID:  session.OrganizationID,
 	}
 }
 
 func toUpdateSessionParams(session *domain.Session) gen.UpdateSessionParams {
 	return gen.UpdateSessionParams{
-		ID:        session.ID,
-		Token:     session.Token,
-		UserAgent: session.UserAgent,
-		ClientIP:  session.ClientIP,
-		ExpiresAt: session.ExpiresAt,
-		RevokedAt: session.RevokedAt,
-		UpdatedAt: session.UpdatedAt,
+		ID:         session.ID,
+		Token:      session.Token,
+		UserAgent:  session.UserAgent,
+		ClientIP:   session.ClientIP,
+		ExpiresAt:  session.ExpiresAt,
+		RevokedAt:  session.RevokedAt,
+		UpdatedAt:  session.UpdatedAt,
+		FlaggedAt:  session.FlaggedAt,
+		ReplacedBy: session.ReplacedBy,
 	}
 }
 
 func toDomainSession(repoSession *gen.Session) *domain.Session {
 	return &domain.Session{
-		ID:        repoSession.ID,
-		UserID:    repoSession.UserID,
-		Token:     repoSession.Token,
-		UserAgent: repoSession.UserAgent,
-		ClientIP:  repoSession.ClientIP,
-		ExpiresAt: repoSession.ExpiresAt,
-		RevokedAt: repoSession.RevokedAt,
-		CreatedAt: repoSession.CreatedAt,
-		UpdatedAt: repoSession.UpdatedAt,
+		ID:              repoSession.ID,
+		UserID:          repoSession.UserID,
+		Token:           repoSession.Token,
+		UserAgent:       repoSession.UserAgent,
+		ClientIP:        repoSession.ClientIP,
+		ExpiresAt:       repoSession.ExpiresAt,
+		RevokedAt:       repoSession.RevokedAt,
+		CreatedAt:       repoSession.CreatedAt,
+		UpdatedAt:       repoSession.UpdatedAt,
+		FlaggedAt:       repoSession.FlaggedAt,
+		AuthenticatedAt: repoSession.AuthenticatedAt,
+		ReplacedBy:      repoSession.ReplacedBy,
+		ClientID:        derefString(repoSession.ClientID),
+		Scopes:          repoSession.Scopes,
+		OrganizationID:  repoSession.OrganizationID,
 	}
 }
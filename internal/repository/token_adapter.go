@@ -41,6 +41,19 @@ func (tr *TokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.T
 	return toDomainToken(&repoToken), nil
 }
 
+func (tr *TokenRepository) GetByToken(ctx context.Context, tokenHash string) (*domain.Token, error) {
+	repoToken, err := tr.q.GetTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get token by hash: %w", err)
+	}
+
+	return toDomainToken(&repoToken), nil
+}
+
 func (tr *TokenRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Token, error) {
 	repoTokens, err := tr.q.GetTokensByUserID(ctx, userID)
 	if err != nil {
@@ -61,6 +74,44 @@ func (tr *TokenRepository) Update(ctx context.Context, token *domain.Token) erro
 	return err
 }
 
+func (tr *TokenRepository) Use(ctx context.Context, token *domain.Token) error {
+	if token.UsedAt == nil {
+		return errors.New("use token: token is not marked used")
+	}
+
+	n, err := tr.q.MarkTokenUsed(ctx, gen.MarkTokenUsedParams{
+		ID:     token.ID,
+		UsedAt: token.UsedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("use token: %w", err)
+	}
+
+	if n == 0 {
+		return domain.ErrTokenUsed
+	}
+
+	return nil
+}
+
+func (tr *TokenRepository) Attempt(ctx context.Context, token *domain.Token, maxAttempts int) error {
+	attempts, err := tr.q.IncrementTokenAttempts(ctx, gen.IncrementTokenAttemptsParams{
+		ID:          token.ID,
+		MaxAttempts: int32(maxAttempts), //nolint:gosec // bounded by the service
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrTokenUsed
+		}
+
+		return fmt.Errorf("attempt token: %w", err)
+	}
+
+	token.Attempts = int(attempts)
+
+	return nil
+}
+
 func (tr *TokenRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return tr.q.DeleteToken(ctx, id)
 }
@@ -71,13 +122,15 @@ func (tr *TokenRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID)
 
 func toCreateTokenParams(token *domain.Token) gen.CreateTokenParams {
 	return gen.CreateTokenParams{
-		ID:        token.ID,
-		UserID:    token.UserID,
-		Token:     token.Token,
-		Type:      string(token.Type),
-		ExpiresAt: token.ExpiresAt,
-		UsedAt:    token.UsedAt,
-		CreatedAt: token.CreatedAt,
+		ID:          token.ID,
+		UserID:      token.UserID,
+		Token:       token.Token,
+		Type:        string(token.Type),
+		ExpiresAt:   token.ExpiresAt,
+		UsedAt:      token.UsedAt,
+		CreatedAt:   token.CreatedAt,
+		BindingHash: nullableString(token.BindingHash),
+		CodeHash:    nullableString(token.CodeHash),
 	}
 }
 
@@ -93,12 +146,15 @@ func toUpdateTokenParams(token *domain.Token) gen.UpdateTokenParams {
 
 func toDomainToken(repoToken *gen.Token) *domain.Token {
 	return &domain.Token{
-		ID:        repoToken.ID,
-		UserID:    repoToken.UserID,
-		Type:      domain.TokenType(repoToken.Type),
-		Token:     repoToken.Token,
-		ExpiresAt: repoToken.ExpiresAt,
-		UsedAt:    repoToken.UsedAt,
-		CreatedAt: repoToken.CreatedAt,
+		ID:          repoToken.ID,
+		UserID:      repoToken.UserID,
+		Type:        domain.TokenType(repoToken.Type),
+		Token:       repoToken.Token,
+		BindingHash: derefString(repoToken.BindingHash),
+		CodeHash:    derefString(repoToken.CodeHash),
+		Attempts:    int(repoToken.Attempts),
+		ExpiresAt:   repoToken.ExpiresAt,
+		UsedAt:      repoToken.UsedAt,
+		CreatedAt:   repoToken.CreatedAt,
 	}
 }
@@ -67,6 +67,46 @@ func (ur *UserRepository) GetByEmail(ctx context.Context, email domain.Email) (*
 	return toDomainUser(&repoUser)
 }
 
+func (ur *UserRepository) GetByUsernameInOrganization(
+	ctx context.Context,
+	organizationID *uuid.UUID,
+	username domain.Username,
+) (*domain.User, error) {
+	repoUser, err := ur.q.GetUserByOrganizationAndUsername(ctx, gen.GetUserByOrganizationAndUsernameParams{
+		OrganizationID: organizationID,
+		Username:       username.String(),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get user by organization and username: %w", err)
+	}
+
+	return toDomainUser(&repoUser)
+}
+
+func (ur *UserRepository) GetByEmailInOrganization(
+	ctx context.Context,
+	organizationID *uuid.UUID,
+	email domain.Email,
+) (*domain.User, error) {
+	repoUser, err := ur.q.GetUserByOrganizationAndEmail(ctx, gen.GetUserByOrganizationAndEmailParams{
+		OrganizationID: organizationID,
+		Email:          email.String(),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get user by organization and email: %w", err)
+	}
+
+	return toDomainUser(&repoUser)
+}
+
 func (ur *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	_, err := ur.q.UpdateUser(ctx, toUpdateUserParams(user))
 
@@ -85,19 +125,43 @@ func (ur *UserRepository) ExistsByEmail(ctx context.Context, email domain.Email)
 	return ur.q.ExistsByEmail(ctx, email.String())
 }
 
+func (ur *UserRepository) ExistsByUsernameInOrganization(
+	ctx context.Context,
+	organizationID *uuid.UUID,
+	username domain.Username,
+) (bool, error) {
+	return ur.q.ExistsByOrganizationAndUsername(ctx, gen.ExistsByOrganizationAndUsernameParams{
+		OrganizationID: organizationID,
+		Username:       username.String(),
+	})
+}
+
+func (ur *UserRepository) ExistsByEmailInOrganization(
+	ctx context.Context,
+	organizationID *uuid.UUID,
+	email domain.Email,
+) (bool, error) {
+	return ur.q.ExistsByOrganizationAndEmail(ctx, gen.ExistsByOrganizationAndEmailParams{
+		OrganizationID: organizationID,
+		Email:          email.String(),
+	})
+}
+
 func toCreateUserParams(user *domain.User) gen.CreateUserParams {
 	return gen.CreateUserParams{
-		ID:         user.ID,
-		Username:   user.Username.String(),
-		Email:      user.Email.String(),
-		Password:   user.Password.Hash(),
-		FirstName:  user.FirstName,
-		LastName:   user.LastName,
-		Role:       user.Role.String(),
-		Status:     user.Status.String(),
-		VerifiedAt: user.VerifiedAt,
-		CreatedAt:  user.CreatedAt,
-		UpdatedAt:  user.UpdatedAt,
+		ID:             user.ID,
+		Username:       user.Username.String(),
+		Email:          user.Email.String(),
+		Password:       user.Password.Hash(),
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		Role:           user.Role.String(),
+		Status:         user.Status.String(),
+		VerifiedAt:     user.VerifiedAt,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+		MFAFactor:      nullableString(user.MFAFactor.String()),
+		OrganizationID: user.OrganizationID,
 	}
 }
 
@@ -113,6 +177,7 @@ func toUpdateUserParams(user *domain.User) gen.UpdateUserParams {
 		Status:     user.Status.String(),
 		VerifiedAt: user.VerifiedAt,
 		UpdatedAt:  user.UpdatedAt,
+		MFAFactor:  nullableString(user.MFAFactor.String()),
 	}
 }
 
@@ -142,17 +207,24 @@ func toDomainUser(repoUser *gen.User) (*domain.User, error) {
 		return nil, fmt.Errorf("invalid status: %q", repoUser.Status)
 	}
 
+	mfaFactor := domain.MFAFactor(derefString(repoUser.MFAFactor))
+	if !mfaFactor.IsValid() {
+		return nil, fmt.Errorf("invalid MFA factor: %q", mfaFactor)
+	}
+
 	return &domain.User{
-		ID:         repoUser.ID,
-		Username:   username,
-		Email:      email,
-		Password:   password,
-		FirstName:  repoUser.FirstName,
-		LastName:   repoUser.LastName,
-		Role:       role,
-		Status:     status,
-		VerifiedAt: repoUser.VerifiedAt,
-		CreatedAt:  repoUser.CreatedAt,
-		UpdatedAt:  repoUser.UpdatedAt,
+		ID:             repoUser.ID,
+		Username:       username,
+		Email:          email,
+		Password:       password,
+		FirstName:      repoUser.FirstName,
+		LastName:       repoUser.LastName,
+		Role:           role,
+		Status:         status,
+		VerifiedAt:     repoUser.VerifiedAt,
+		MFAFactor:      mfaFactor,
+		OrganizationID: repoUser.OrganizationID,
+		CreatedAt:      repoUser.CreatedAt,
+		UpdatedAt:      repoUser.UpdatedAt,
 	}, nil
 }
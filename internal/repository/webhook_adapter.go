@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/repository/gen"
+)
+
+var _ domain.WebhookRepository = (*WebhookRepository)(nil)
+
+type WebhookRepository struct {
+	q *gen.Queries
+}
+
+func NewWebhookRepository(q *gen.Queries) *WebhookRepository {
+	return &WebhookRepository{q: q}
+}
+
+func (wr *WebhookRepository) SaveSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	events := make([]string, len(subscription.Events))
+	for i, t := range subscription.Events {
+		events[i] = t.String()
+	}
+
+	err := wr.q.CreateWebhookSubscription(ctx, gen.CreateWebhookSubscriptionParams{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Secret:    subscription.Secret,
+		Events:    events,
+		CreatedAt: subscription.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("save webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	repoSubscriptions, err := wr.q.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+
+	out := make([]*domain.WebhookSubscription, len(repoSubscriptions))
+	for i := range repoSubscriptions {
+		out[i] = toDomainWebhookSubscription(&repoSubscriptions[i])
+	}
+
+	return out, nil
+}
+
+func (wr *WebhookRepository) DisableSubscription(
+	ctx context.Context,
+	id uuid.UUID,
+	disabledAt time.Time,
+) (bool, error) {
+	n, err := wr.q.DisableWebhookSubscription(ctx, gen.DisableWebhookSubscriptionParams{
+		ID:         id,
+		DisabledAt: &disabledAt,
+	})
+	if err != nil {
+		return false, fmt.Errorf("disable webhook subscription: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+func (wr *WebhookRepository) Enqueue(ctx context.Context, event *domain.WebhookEvent) error {
+	data := event.Data
+	if data == nil {
+		data = map[string]any{}
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal webhook event data: %w", err)
+	}
+
+	err = wr.q.CreateWebhookEvent(ctx, gen.CreateWebhookEventParams{
+		ID:        event.ID,
+		Type:      event.Type.String(),
+		Data:      raw,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("save webhook event: %w", err)
+	}
+
+	err = wr.q.CreateWebhookDeliveries(ctx, gen.CreateWebhookDeliveriesParams{
+		EventID:   event.ID,
+		CreatedAt: event.CreatedAt,
+		Type:      event.Type.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("save webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) ClaimDeliveries(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]*domain.WebhookDelivery, error) {
+	rows, err := wr.q.ClaimWebhookDeliveries(ctx, gen.ClaimWebhookDeliveriesParams{
+		Now:           now,
+		MaxDeliveries: int32(limit), //nolint:gosec // bounded by the worker
+		LeaseUntil:    leaseUntil,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+
+	out := make([]*domain.WebhookDelivery, len(rows))
+	for i := range rows {
+		if out[i], err = toDomainWebhookDelivery(&rows[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+func (wr *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	err := wr.q.UpdateWebhookDelivery(ctx, gen.UpdateWebhookDeliveryParams{
+		ID:            delivery.ID,
+		Status:        delivery.Status.String(),
+		Attempts:      int32(delivery.Attempts), //nolint:gosec // bounded by the worker
+		NextAttemptAt: delivery.NextAttemptAt,
+		LastError:     delivery.LastError,
+		DeliveredAt:   delivery.DeliveredAt,
+	})
+	if err != nil {
+		return fmt.Errorf("update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func toDomainWebhookSubscription(repoSubscription *gen.WebhookSubscription) *domain.WebhookSubscription {
+	events := make([]domain.WebhookEventType, len(repoSubscription.Events))
+	for i, t := range repoSubscription.Events {
+		events[i] = domain.WebhookEventType(t)
+	}
+
+	return &domain.WebhookSubscription{
+		ID:         repoSubscription.ID,
+		URL:        repoSubscription.URL,
+		Secret:     repoSubscription.Secret,
+		Events:     events,
+		DisabledAt: repoSubscription.DisabledAt,
+		CreatedAt:  repoSubscription.CreatedAt,
+	}
+}
+
+func toDomainWebhookDelivery(row *gen.ClaimWebhookDeliveriesRow) (*domain.WebhookDelivery, error) {
+	var data map[string]any
+	if err := json.Unmarshal(row.EventData, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal webhook event data: %w", err)
+	}
+
+	return &domain.WebhookDelivery{
+		ID:             row.ID,
+		SubscriptionID: row.SubscriptionID,
+		URL:            row.URL,
+		Secret:         row.Secret,
+		Event: &domain.WebhookEvent{
+			ID:        row.EventID,
+			Type:      domain.WebhookEventType(row.EventType),
+			Data:      data,
+			CreatedAt: row.EventCreatedAt,
+		},
+		Status:        domain.WebhookDeliveryStatus(row.Status),
+		Attempts:      int(row.Attempts),
+		NextAttemptAt: row.NextAttemptAt,
+		LastError:     row.LastError,
+		DeliveredAt:   row.DeliveredAt,
+		CreatedAt:     row.CreatedAt,
+	}, nil
+}
@@ -41,6 +41,10 @@ func writeJSON(writer http.ResponseWriter, status int, data any) {
 	_, _ = writer.Write(js)
 }
 
+func JSON(writer http.ResponseWriter, status int, data any) {
+	writeJSON(writer, status, data)
+}
+
 func OK(writer http.ResponseWriter, data any) {
 	writeJSON(writer, http.StatusOK, successResponse{Data: data})
 }
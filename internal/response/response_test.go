@@ -171,6 +171,19 @@ func TestAccepted(t *testing.T) {
 	})
 }
 
+func TestJSON(t *testing.T) {
+	t.Run("writes data without envelope", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		response.JSON(rec, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, contentTypeJSON, rec.Header().Get(headerContentType))
+		assert.JSONEq(t, `{"error":"invalid_grant"}`, rec.Body.String())
+	})
+}
+
 func TestNoContent(t *testing.T) {
 	t.Run("writes 204 with empty body", func(t *testing.T) {
 		t.Parallel()
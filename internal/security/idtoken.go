@@ -0,0 +1,163 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"go-auth/internal/domain"
+)
+
+const (
+	idTokenAlgorithm = "RS256"
+	rsaKeyBits       = 2048
+)
+
+type idTokenSigner struct {
+	key    *rsa.PrivateKey
+	keyID  string
+	issuer string
+	ttl    time.Duration
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+
+	Nonce             string `json:"nonce,omitempty"`
+	Name              string `json:"name,omitempty"`
+	GivenName         string `json:"given_name,omitempty"`
+	FamilyName        string `json:"family_name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
+}
+
+func NewIDTokenSigner(key *rsa.PrivateKey, issuer string, ttl time.Duration) (domain.IDTokenManager, error) {
+	if key == nil {
+		return nil, domain.ErrSigningKeyRequired
+	}
+
+	if issuer == "" {
+		return nil, domain.ErrIssuerRequired
+	}
+
+	if ttl <= 0 {
+		return nil, domain.ErrIDTokenTTLRequired
+	}
+
+	return &idTokenSigner{
+		key:    key,
+		keyID:  thumbprint(&key.PublicKey),
+		issuer: issuer,
+		ttl:    ttl,
+	}, nil
+}
+
+func (s *idTokenSigner) Generate(claims domain.IDClaims) (string, error) {
+	if claims.Subject == "" {
+		return "", domain.ErrUserIDRequired
+	}
+
+	if claims.ClientID == "" {
+		return "", domain.ErrOAuthClientIDRequired
+	}
+
+	now := time.Now().UTC()
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, idTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   claims.Subject,
+			Audience:  jwt.ClaimStrings{claims.ClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.ttl)),
+		},
+		Nonce:             claims.Nonce,
+		Name:              claims.Name,
+		GivenName:         claims.GivenName,
+		FamilyName:        claims.FamilyName,
+		PreferredUsername: claims.PreferredUsername,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+	})
+	tok.Header["kid"] = s.keyID
+
+	signed, err := tok.SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("sign id token: %w", err)
+	}
+
+	return signed, nil
+}
+
+func (s *idTokenSigner) PublicKeys() []domain.JSONWebKey {
+	pub := &s.key.PublicKey
+
+	return []domain.JSONWebKey{{
+		KeyType:   "RSA",
+		KeyID:     s.keyID,
+		Use:       "sig",
+		Algorithm: idTokenAlgorithm,
+		N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}
+}
+
+func (s *idTokenSigner) Algorithm() string {
+	return idTokenAlgorithm
+}
+
+func (s *idTokenSigner) TTL() time.Duration {
+	return s.ttl
+}
+
+func thumbprint(pub *rsa.PublicKey) string {
+	n := base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	sum := sha256.Sum256([]byte(`{"e":"` + e + `","kty":"RSA","n":"` + n + `"}`))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func ParseRSAPrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("parse rsa private key: no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse rsa private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("parse rsa private key: not an RSA key")
+	}
+
+	return key, nil
+}
+
+// GenerateRSAKey creates a throwaway signing key; ID tokens signed with it stop
+// verifying when the process restarts, so it is only meant for development.
+func GenerateRSAKey() (*rsa.PrivateKey, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("generate rsa key: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,117 @@
+package security_test
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/security"
+)
+
+const idTokenIssuer = "https://id.example.com"
+
+func TestNewIDTokenSigner(t *testing.T) {
+	key, err := security.GenerateRSAKey()
+	require.NoError(t, err)
+
+	_, err = security.NewIDTokenSigner(nil, idTokenIssuer, time.Hour)
+	require.ErrorIs(t, err, domain.ErrSigningKeyRequired)
+
+	_, err = security.NewIDTokenSigner(key, "", time.Hour)
+	require.ErrorIs(t, err, domain.ErrIssuerRequired)
+
+	_, err = security.NewIDTokenSigner(key, idTokenIssuer, 0)
+	require.ErrorIs(t, err, domain.ErrIDTokenTTLRequired)
+}
+
+func TestIDTokenSignerVerifiesWithPublishedKey(t *testing.T) {
+	key, err := security.GenerateRSAKey()
+	require.NoError(t, err)
+
+	signer, err := security.NewIDTokenSigner(key, idTokenIssuer, time.Hour)
+	require.NoError(t, err)
+
+	verified := true
+	token, err := signer.Generate(domain.IDClaims{
+		UserInfo: domain.UserInfo{Subject: userID, Email: "alice@example.com", EmailVerified: &verified},
+		ClientID: "client-1",
+		Nonce:    "n-0S6_WzA2Mj",
+	})
+	require.NoError(t, err)
+
+	keys := signer.PublicKeys()
+	require.Len(t, keys, 1)
+	assert.Equal(t, "RS256", keys[0].Algorithm)
+
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(tok *jwt.Token) (any, error) {
+		assert.Equal(t, keys[0].KeyID, tok.Header["kid"])
+
+		return publicKeyFromJWK(t, keys[0]), nil
+	}, jwt.WithValidMethods([]string{signer.Algorithm()}), jwt.WithIssuer(idTokenIssuer), jwt.WithAudience("client-1"))
+	require.NoError(t, err)
+	require.True(t, parsed.Valid)
+
+	assert.Equal(t, userID, claims["sub"])
+	assert.Equal(t, "n-0S6_WzA2Mj", claims["nonce"])
+	assert.Equal(t, "alice@example.com", claims["email"])
+	assert.Equal(t, true, claims["email_verified"])
+	assert.NotContains(t, claims, "name")
+}
+
+func TestIDTokenSignerRequiresSubjectAndClient(t *testing.T) {
+	key, err := security.GenerateRSAKey()
+	require.NoError(t, err)
+
+	signer, err := security.NewIDTokenSigner(key, idTokenIssuer, time.Hour)
+	require.NoError(t, err)
+
+	_, err = signer.Generate(domain.IDClaims{ClientID: "client-1"})
+	require.ErrorIs(t, err, domain.ErrUserIDRequired)
+
+	_, err = signer.Generate(domain.IDClaims{UserInfo: domain.UserInfo{Subject: userID}})
+	require.ErrorIs(t, err, domain.ErrOAuthClientIDRequired)
+}
+
+func TestParseRSAPrivateKey(t *testing.T) {
+	key, err := security.GenerateRSAKey()
+	require.NoError(t, err)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	for name, block := range map[string]*pem.Block{
+		"pkcs1": {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)},
+		"pkcs8": {Type: "PRIVATE KEY", Bytes: pkcs8},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := security.ParseRSAPrivateKey(string(pem.EncodeToMemory(block)))
+			require.NoError(t, err)
+			assert.True(t, key.Equal(got))
+		})
+	}
+
+	_, err = security.ParseRSAPrivateKey("not a key")
+	require.Error(t, err)
+}
+
+func publicKeyFromJWK(t *testing.T, jwk domain.JSONWebKey) *rsa.PublicKey {
+	t.Helper()
+
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	require.NoError(t, err)
+
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	require.NoError(t, err)
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+}
@@ -3,6 +3,7 @@ package security
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -20,8 +21,20 @@ type jwtManager struct {
 type jwtClaims struct {
 	jwt.RegisteredClaims
 
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	SubjectType string   `json:"sub_type,omitempty"`
+	UserID      string   `json:"user_id,omitempty"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Scope       string   `json:"scope,omitempty"`
+	ClientID    string   `json:"client_id,omitempty"`
+	OrgID       string   `json:"org_id,omitempty"`
+
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	Actor    *jwtActor        `json:"act,omitempty"`
+}
+
+type jwtActor struct {
+	Subject string `json:"sub"`
 }
 
 func NewJWT(secret, issuer string, accessTTL time.Duration) (domain.AccessTokenManager, error) {
@@ -43,16 +56,58 @@ func NewJWT(secret, issuer string, accessTTL time.Duration) (domain.AccessTokenM
 func (m *jwtManager) Generate(claims domain.AccessClaims) (string, error) {
 	now := time.Now().UTC()
 
+	expiresAt := now.Add(m.accessTTL)
+	if !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(expiresAt) {
+		expiresAt = claims.ExpiresAt
+	}
+
 	rc := jwt.RegisteredClaims{
 		Issuer:    m.issuer,
 		IssuedAt:  jwt.NewNumericDate(now),
-		ExpiresAt: jwt.NewNumericDate(now.Add(m.accessTTL)),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
 		NotBefore: jwt.NewNumericDate(now),
+		Subject:   claims.Subject(),
+	}
+
+	if len(claims.Audience) > 0 {
+		rc.Audience = jwt.ClaimStrings(claims.Audience)
 	}
+
 	claimsData := jwtClaims{
 		RegisteredClaims: rc,
+		SubjectType:      string(domain.SubjectTypeUser),
 		UserID:           claims.UserID.String(),
 		Role:             claims.Role.String(),
+		Permissions:      toPermissionStrings(claims.Permissions),
+		Scope:            claims.Scope(),
+		ClientID:         claims.ClientID,
+	}
+
+	if !claims.AuthTime.IsZero() {
+		claimsData.AuthTime = jwt.NewNumericDate(claims.AuthTime)
+	}
+
+	if claims.IsImpersonated() {
+		claimsData.Actor = &jwtActor{Subject: claims.ImpersonatorID.String()}
+	}
+
+	if claims.InOrganization() {
+		claimsData.OrgID = claims.OrganizationID.String()
+	}
+
+	// Client tokens identify the client alone; they carry no user, role or permissions.
+	if claims.IsClient() {
+		if claims.ClientID == "" {
+			return "", domain.ErrOAuthClientIDRequired
+		}
+
+		claimsData.SubjectType = string(domain.SubjectTypeClient)
+		claimsData.UserID = ""
+		claimsData.Role = ""
+		claimsData.Permissions = nil
+		claimsData.AuthTime = nil
+		claimsData.Actor = nil
+		claimsData.OrgID = ""
 	}
 
 	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, claimsData)
@@ -65,7 +120,7 @@ func (m *jwtManager) Generate(claims domain.AccessClaims) (string, error) {
 	return signed, nil
 }
 
-func (m *jwtManager) Validate(token string) (*domain.AccessClaims, error) {
+func (m *jwtManager) Validate(token string, audience ...string) (*domain.AccessClaims, error) {
 	parsed, err := jwt.ParseWithClaims(token, &jwtClaims{}, m.keyFunc, jwt.WithIssuer(m.issuer))
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -80,7 +135,28 @@ func (m *jwtManager) Validate(token string) (*domain.AccessClaims, error) {
 		return nil, domain.ErrTokenInvalid
 	}
 
-	return m.parseClaims(claims)
+	if len(audience) > 0 && !containsAny(claims.Audience, audience) {
+		return nil, domain.ErrTokenAudienceInvalid
+	}
+
+	out, err := m.parseClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.IssuedAt != nil {
+		out.IssuedAt = claims.IssuedAt.UTC()
+	}
+
+	if claims.ExpiresAt != nil {
+		out.ExpiresAt = claims.ExpiresAt.UTC()
+	}
+
+	if claims.AuthTime != nil && !out.IsClient() {
+		out.AuthTime = claims.AuthTime.UTC()
+	}
+
+	return out, nil
 }
 
 func (m *jwtManager) keyFunc(t *jwt.Token) (any, error) {
@@ -92,6 +168,14 @@ func (m *jwtManager) keyFunc(t *jwt.Token) (any, error) {
 }
 
 func (m *jwtManager) parseClaims(claims *jwtClaims) (*domain.AccessClaims, error) {
+	switch domain.SubjectType(claims.SubjectType) {
+	case domain.SubjectTypeClient:
+		return parseClientClaims(claims)
+	case domain.SubjectTypeUser, "":
+	default:
+		return nil, domain.ErrTokenInvalid
+	}
+
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
 		return nil, domain.ErrTokenInvalid
@@ -102,8 +186,74 @@ func (m *jwtManager) parseClaims(claims *jwtClaims) (*domain.AccessClaims, error
 		return nil, domain.ErrRoleInvalid
 	}
 
+	perms := make([]domain.Permission, len(claims.Permissions))
+	for i, perm := range claims.Permissions {
+		perms[i] = domain.Permission(perm)
+	}
+
+	var impersonatorID uuid.UUID
+	if claims.Actor != nil {
+		if impersonatorID, err = uuid.Parse(claims.Actor.Subject); err != nil || impersonatorID == uuid.Nil {
+			return nil, domain.ErrTokenInvalid
+		}
+	}
+
+	var organizationID uuid.UUID
+	if claims.OrgID != "" {
+		if organizationID, err = uuid.Parse(claims.OrgID); err != nil || organizationID == uuid.Nil {
+			return nil, domain.ErrTokenInvalid
+		}
+	}
+
+	return &domain.AccessClaims{
+		SubjectType:    domain.SubjectTypeUser,
+		UserID:         userID,
+		Role:           role,
+		Permissions:    perms,
+		Scopes:         domain.ParseScope(claims.Scope),
+		Audience:       claims.Audience,
+		ClientID:       claims.ClientID,
+		ImpersonatorID: impersonatorID,
+		OrganizationID: organizationID,
+	}, nil
+}
+
+func parseClientClaims(claims *jwtClaims) (*domain.AccessClaims, error) {
+	if claims.ClientID == "" || claims.Subject != claims.ClientID || claims.UserID != "" {
+		return nil, domain.ErrTokenInvalid
+	}
+
+	if claims.Actor != nil || claims.OrgID != "" {
+		return nil, domain.ErrTokenInvalid
+	}
+
 	return &domain.AccessClaims{
-		UserID: userID,
-		Role:   role,
+		SubjectType: domain.SubjectTypeClient,
+		Scopes:      domain.ParseScope(claims.Scope),
+		Audience:    claims.Audience,
+		ClientID:    claims.ClientID,
 	}, nil
 }
+
+func toPermissionStrings(perms []domain.Permission) []string {
+	if len(perms) == 0 {
+		return nil
+	}
+
+	out := make([]string, len(perms))
+	for i, perm := range perms {
+		out[i] = perm.String()
+	}
+
+	return out
+}
+
+func containsAny(have, want []string) bool {
+	for _, w := range want {
+		if slices.Contains(have, w) {
+			return true
+		}
+	}
+
+	return false
+}
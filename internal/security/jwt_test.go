@@ -92,6 +92,8 @@ func TestJWTRoundTrip(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, claims.UserID, got.UserID)
 	assert.Equal(t, claims.Role.String(), got.Role.String())
+	assert.WithinDuration(t, time.Now(), got.IssuedAt, 2*time.Second)
+	assert.WithinDuration(t, got.IssuedAt.Add(time.Hour), got.ExpiresAt, time.Second)
 }
 
 func TestJWTInvalidToken(t *testing.T) {
@@ -142,3 +144,206 @@ func TestJWTExpiredToken(t *testing.T) {
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, domain.ErrTokenExpired)
 }
+
+func TestJWTPermissionAndScopeClaims(t *testing.T) {
+	role, _ := domain.NewRole(domain.RoleAdmin)
+	claims := domain.AccessClaims{
+		UserID:      uuid.MustParse(userID),
+		Role:        role,
+		Permissions: []domain.Permission{domain.PermUserRead, domain.PermUserBan},
+		Scopes:      []string{"user:read", "user:ban"},
+	}
+
+	m, err := security.NewJWT(jwtTestSecret, jwtTestIssuer, time.Hour)
+	require.NoError(t, err)
+	token, err := m.Generate(claims)
+	require.NoError(t, err)
+
+	got, err := m.Validate(token)
+	require.NoError(t, err)
+	assert.Equal(t, claims.Permissions, got.Permissions)
+	assert.Equal(t, claims.Scopes, got.Scopes)
+	assert.True(t, got.HasScopes("user:read", "user:ban"))
+	assert.False(t, got.HasScopes("user:delete"))
+}
+
+func TestJWTClientIDClaim(t *testing.T) {
+	role, _ := domain.NewRole(domain.RoleUser)
+	claims := domain.AccessClaims{
+		UserID:   uuid.MustParse(userID),
+		Role:     role,
+		Scopes:   []string{"user:read"},
+		ClientID: "9b2f4c1e-client",
+	}
+
+	m, err := security.NewJWT(jwtTestSecret, jwtTestIssuer, time.Hour)
+	require.NoError(t, err)
+	token, err := m.Generate(claims)
+	require.NoError(t, err)
+
+	got, err := m.Validate(token)
+	require.NoError(t, err)
+	assert.Equal(t, claims.ClientID, got.ClientID)
+	assert.Equal(t, claims.Scopes, got.Scopes)
+	assert.Equal(t, domain.SubjectTypeUser, got.SubjectType)
+	assert.Equal(t, userID, got.Subject())
+}
+
+func TestJWTAuthTime(t *testing.T) {
+	role, _ := domain.NewRole(domain.RoleUser)
+	authTime := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	m, err := security.NewJWT(jwtTestSecret, jwtTestIssuer, time.Hour)
+	require.NoError(t, err)
+
+	token, err := m.Generate(domain.AccessClaims{UserID: uuid.MustParse(userID), Role: role, AuthTime: authTime})
+	require.NoError(t, err)
+
+	got, err := m.Validate(token)
+	require.NoError(t, err)
+	assert.Equal(t, authTime, got.AuthTime)
+
+	// ExpiresAt can shorten the token's lifetime but not extend it.
+	short := time.Now().UTC().Add(5 * time.Minute)
+
+	token, err = m.Generate(domain.AccessClaims{UserID: uuid.MustParse(userID), Role: role, ExpiresAt: short})
+	require.NoError(t, err)
+
+	got, err = m.Validate(token)
+	require.NoError(t, err)
+	assert.WithinDuration(t, short, got.ExpiresAt, time.Second)
+	assert.True(t, got.AuthTime.IsZero())
+
+	token, err = m.Generate(domain.AccessClaims{
+		UserID:    uuid.MustParse(userID),
+		Role:      role,
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	got, err = m.Validate(token)
+	require.NoError(t, err)
+	assert.WithinDuration(t, got.IssuedAt.Add(time.Hour), got.ExpiresAt, time.Second)
+}
+
+func TestJWTActorClaim(t *testing.T) {
+	role, _ := domain.NewRole(domain.RoleUser)
+	adminID := uuid.New()
+
+	m, err := security.NewJWT(jwtTestSecret, jwtTestIssuer, time.Hour)
+	require.NoError(t, err)
+
+	token, err := m.Generate(domain.AccessClaims{UserID: uuid.MustParse(userID), Role: role, ImpersonatorID: adminID})
+	require.NoError(t, err)
+
+	got, err := m.Validate(token)
+	require.NoError(t, err)
+	assert.True(t, got.IsImpersonated())
+	assert.Equal(t, adminID, got.ImpersonatorID)
+	assert.Equal(t, userID, got.Subject())
+
+	token, err = m.Generate(domain.AccessClaims{UserID: uuid.MustParse(userID), Role: role})
+	require.NoError(t, err)
+
+	got, err = m.Validate(token)
+	require.NoError(t, err)
+	assert.False(t, got.IsImpersonated())
+}
+
+func TestJWTOrganizationClaim(t *testing.T) {
+	role, _ := domain.NewRole(domain.RoleUser)
+	organizationID := uuid.New()
+
+	m, err := security.NewJWT(jwtTestSecret, jwtTestIssuer, time.Hour)
+	require.NoError(t, err)
+
+	claims := domain.AccessClaims{UserID: uuid.MustParse(userID), Role: role, OrganizationID: organizationID}
+	token, err := m.Generate(claims)
+	require.NoError(t, err)
+
+	got, err := m.Validate(token)
+	require.NoError(t, err)
+	assert.True(t, got.InOrganization())
+	assert.Equal(t, organizationID, got.OrganizationID)
+
+	token, err = m.Generate(domain.AccessClaims{
+		SubjectType:    domain.SubjectTypeClient,
+		ClientID:       "billing-service",
+		OrganizationID: organizationID,
+	})
+	require.NoError(t, err)
+
+	got, err = m.Validate(token)
+	require.NoError(t, err)
+	assert.False(t, got.InOrganization())
+}
+
+func TestJWTClientSubject(t *testing.T) {
+	m, err := security.NewJWT(jwtTestSecret, jwtTestIssuer, time.Hour)
+	require.NoError(t, err)
+
+	t.Run("round trip", func(t *testing.T) {
+		token, err := m.Generate(domain.AccessClaims{
+			SubjectType: domain.SubjectTypeClient,
+			ClientID:    "billing-service",
+			Scopes:      []string{"user:read"},
+			Permissions: []domain.Permission{domain.PermUserRead},
+		})
+		require.NoError(t, err)
+
+		got, err := m.Validate(token)
+		require.NoError(t, err)
+		assert.True(t, got.IsClient())
+		assert.Equal(t, "billing-service", got.Subject())
+		assert.Equal(t, uuid.Nil, got.UserID)
+		assert.Empty(t, got.Role)
+		assert.Empty(t, got.Permissions)
+		assert.True(t, got.HasScopes("user:read"))
+	})
+
+	t.Run("client id required", func(t *testing.T) {
+		_, err := m.Generate(domain.AccessClaims{SubjectType: domain.SubjectTypeClient})
+		assert.ErrorIs(t, err, domain.ErrOAuthClientIDRequired)
+	})
+}
+
+func TestJWTAudience(t *testing.T) {
+	role, _ := domain.NewRole(domain.RoleUser)
+
+	m, err := security.NewJWT(jwtTestSecret, jwtTestIssuer, time.Hour)
+	require.NoError(t, err)
+
+	withAud, err := m.Generate(domain.AccessClaims{UserID: uuid.MustParse(userID), Role: role, Audience: []string{"billing"}})
+	require.NoError(t, err)
+
+	withoutAud, err := m.Generate(domain.AccessClaims{UserID: uuid.MustParse(userID), Role: role})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		token    string
+		audience []string
+		wantErr  error
+	}{
+		{"no expectation", withAud, nil, nil},
+		{"matching audience", withAud, []string{"billing"}, nil},
+		{"one of several", withAud, []string{"crm", "billing"}, nil},
+		{"wrong audience", withAud, []string{"crm"}, domain.ErrTokenAudienceInvalid},
+		{"missing audience", withoutAud, []string{"billing"}, domain.ErrTokenAudienceInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Validate(tt.token, tt.audience...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, got)
+		})
+	}
+}
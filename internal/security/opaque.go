@@ -1,6 +1,7 @@
 package security
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -9,16 +10,19 @@ import (
 	"go-auth/internal/domain"
 )
 
+const deriveLabel = "opaque-token-successor:"
+
 type opaqueManager struct {
 	length int
+	secret []byte
 }
 
-func NewOpaque(length int) domain.OpaqueTokenManager {
+func NewOpaque(length int, secret []byte) domain.OpaqueTokenManager {
 	if length <= 0 {
 		length = 32
 	}
 
-	return &opaqueManager{length: length}
+	return &opaqueManager{length: length, secret: secret}
 }
 
 func (m *opaqueManager) Generate() (string, error) {
@@ -39,3 +43,18 @@ func (m *opaqueManager) Hash(token string) (string, error) {
 
 	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
 }
+
+func (m *opaqueManager) Derive(token string) (string, error) {
+	if token == "" {
+		return "", domain.ErrTokenRequired
+	}
+
+	if len(m.secret) == 0 {
+		return "", domain.ErrTokenSecretRequired
+	}
+
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(deriveLabel + token))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
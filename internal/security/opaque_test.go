@@ -11,7 +11,7 @@ import (
 
 func TestNewOpaque(t *testing.T) {
 	t.Run("positive length", func(t *testing.T) {
-		m := security.NewOpaque(24)
+		m := security.NewOpaque(24, nil)
 		assert.NotNil(t, m)
 
 		token, err := m.Generate()
@@ -20,7 +20,7 @@ func TestNewOpaque(t *testing.T) {
 	})
 
 	t.Run("zero length uses default", func(t *testing.T) {
-		m := security.NewOpaque(0)
+		m := security.NewOpaque(0, nil)
 		assert.NotNil(t, m)
 
 		token, err := m.Generate()
@@ -29,7 +29,7 @@ func TestNewOpaque(t *testing.T) {
 	})
 
 	t.Run("negative length uses default", func(t *testing.T) {
-		m := security.NewOpaque(-1)
+		m := security.NewOpaque(-1, nil)
 		assert.NotNil(t, m)
 
 		token, err := m.Generate()
@@ -39,7 +39,7 @@ func TestNewOpaque(t *testing.T) {
 }
 
 func TestOpaqueGenerate(t *testing.T) {
-	m := security.NewOpaque(32)
+	m := security.NewOpaque(32, nil)
 
 	t.Run("returns non-empty token", func(t *testing.T) {
 		token, err := m.Generate()
@@ -67,7 +67,7 @@ func TestOpaqueGenerate(t *testing.T) {
 }
 
 func TestOpaqueHash(t *testing.T) {
-	m := security.NewOpaque(32)
+	m := security.NewOpaque(32, nil)
 
 	t.Run("same input produces same hash", func(t *testing.T) {
 		input := "some-token-value"
@@ -100,3 +100,49 @@ func TestOpaqueHash(t *testing.T) {
 		assert.NotEmpty(t, hash)
 	})
 }
+
+func TestOpaqueDerive(t *testing.T) {
+	m := security.NewOpaque(32, []byte("derive-secret"))
+
+	t.Run("same input produces same token", func(t *testing.T) {
+		token1, err1 := m.Derive("some-token-value")
+		token2, err2 := m.Derive("some-token-value")
+
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Equal(t, token1, token2)
+		assert.NotEqual(t, "some-token-value", token1)
+	})
+
+	t.Run("different secrets produce different tokens", func(t *testing.T) {
+		other := security.NewOpaque(32, []byte("other-secret"))
+
+		token1, err1 := m.Derive("some-token-value")
+		token2, err2 := other.Derive("some-token-value")
+
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.NotEqual(t, token1, token2)
+	})
+
+	t.Run("derived token differs from hash", func(t *testing.T) {
+		derived, err := m.Derive("some-token-value")
+		assert.NoError(t, err)
+
+		hash, err := m.Hash("some-token-value")
+		assert.NoError(t, err)
+		assert.NotEqual(t, hash, derived)
+	})
+
+	t.Run("empty token returns error", func(t *testing.T) {
+		token, err := m.Derive("")
+		assert.ErrorIs(t, err, domain.ErrTokenRequired)
+		assert.Empty(t, token)
+	})
+
+	t.Run("missing secret returns error", func(t *testing.T) {
+		token, err := security.NewOpaque(32, nil).Derive("some-token-value")
+		assert.ErrorIs(t, err, domain.ErrTokenSecretRequired)
+		assert.Empty(t, token)
+	})
+}
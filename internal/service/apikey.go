@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+const defaultAPIKeyMaxTTL = 365 * 24 * time.Hour
+
+type CreateAPIKeyRequest struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt time.Time
+	UserAgent string
+	ClientIP  string
+}
+
+type APIKeyResponse struct {
+	ID         uuid.UUID
+	Name       string
+	Key        string
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// CreateAPIKey issues a personal API key for scripts and CI. Keys outlive sessions, so
+// creating one is a sensitive operation: the caller must have re-authenticated within the
+// step-up window, which also keeps API keys and application tokens from minting more keys.
+func (s *service) CreateAPIKey(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *CreateAPIKeyRequest,
+) (*APIKeyResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if !s.apiKeys {
+		return nil, errAPIKeysDisabled()
+	}
+
+	if err := s.requireRecentAuth(actor); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgAPIKeyRequired, nil)
+	}
+
+	latest := time.Now().UTC().Add(s.apiKeyMaxTTL)
+
+	expiresAt := req.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = latest
+	}
+
+	if expiresAt.After(latest) {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgAPIKeyExpiryTooLong, nil)
+	}
+
+	secret, secretHash, err := s.generateOpaque()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateAPIKey, err)
+	}
+
+	key, err := domain.NewAPIKey(actor.UserID, req.Name, secretHash, req.Scopes, expiresAt)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	if err = s.apiKeyRepo.Save(ctx, key); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveAPIKey, err)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionAPIKeyCreated, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithTarget(actor.UserID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(map[string]any{
+			"api_key_id": key.ID.String(),
+			"name":       key.Name,
+			"scopes":     key.Scopes,
+			"expires_at": key.ExpiresAt,
+		}))
+
+	res := toAPIKeyResponse(key)
+	res.Key = domain.FormatAPIKey(key.ID, secret)
+
+	return res, nil
+}
+
+func (s *service) ListAPIKeys(ctx context.Context, actor *domain.AccessClaims) ([]*APIKeyResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if !s.apiKeys {
+		return nil, errAPIKeysDisabled()
+	}
+
+	keys, err := s.apiKeyRepo.ListByUserID(ctx, actor.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListAPIKeys, err)
+	}
+
+	out := make([]*APIKeyResponse, len(keys))
+	for i, key := range keys {
+		out[i] = toAPIKeyResponse(key)
+	}
+
+	return out, nil
+}
+
+func (s *service) RevokeAPIKey(ctx context.Context, actor *domain.AccessClaims, id uuid.UUID) error {
+	if err := s.authenticate(actor); err != nil {
+		return err
+	}
+
+	if !s.apiKeys {
+		return errAPIKeysDisabled()
+	}
+
+	revoked, err := s.apiKeyRepo.Revoke(ctx, actor.UserID, id, time.Now().UTC())
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgRevokeAPIKey, err)
+	}
+
+	if !revoked {
+		return apperror.NotFound(apperror.ErrCodeAPIKeyNotFound, apperror.MsgAPIKeyNotFound, nil)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionAPIKeyRevoked, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithTarget(actor.UserID).
+		WithMetadata(map[string]any{"api_key_id": id.String()}))
+
+	return nil
+}
+
+// AuthenticateAPIKey returns the claims of a request made with an API key. The claims act
+// for the key's owner with their current role, limited to the key's scopes; they never
+// satisfy requireRecentAuth.
+func (s *service) AuthenticateAPIKey(ctx context.Context, raw string) (*domain.AccessClaims, error) {
+	if !s.apiKeys {
+		return nil, errAPIKeysDisabled()
+	}
+
+	id, secret, err := domain.ParseAPIKey(raw)
+	if err != nil {
+		return nil, errAPIKeyInvalid(err)
+	}
+
+	secretHash, err := s.opaqueTokenManager.Hash(secret)
+	if err != nil {
+		return nil, errAPIKeyInvalid(err)
+	}
+
+	key, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetAPIKey, err)
+	}
+
+	if key == nil || subtle.ConstantTimeCompare([]byte(secretHash), []byte(key.SecretHash)) != 1 || !key.IsActive() {
+		return nil, errAPIKeyInvalid(nil)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil || !user.CanLogin() {
+		return nil, errAPIKeyInvalid(nil)
+	}
+
+	key.Use()
+
+	if err = s.apiKeyRepo.Use(ctx, key); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUseAPIKey, err)
+	}
+
+	claims := s.userAccessClaims(user)
+	claims.Scopes = slices.Clone(key.Scopes)
+	claims.Permissions = nil
+	claims.APIKeyID = key.ID
+	claims.ExpiresAt = key.ExpiresAt
+
+	return &claims, nil
+}
+
+func toAPIKeyResponse(key *domain.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func errAPIKeysDisabled() error {
+	return apperror.NotImplemented(apperror.ErrCodeAPIKeysDisabled, apperror.MsgAPIKeysDisabled, nil)
+}
+
+func errAPIKeyInvalid(cause error) error {
+	return apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgAPIKeyInvalid, cause)
+}
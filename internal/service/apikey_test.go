@@ -0,0 +1,302 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+// mustAPIKey stores a key for userID whose secret is raw and returns the key handed to the user.
+func mustAPIKey(t *testing.T, repo *mockAPIKeyRepo, userID uuid.UUID, raw string, scopes ...string) string {
+	t.Helper()
+
+	key, err := domain.NewAPIKey(userID, "ci", "hashed-"+raw, scopes, farFutureExpiry)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), key))
+
+	return domain.FormatAPIKey(key.ID, raw)
+}
+
+func mustAdminUser(t *testing.T) *domain.User {
+	t.Helper()
+
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "hash")
+
+	role, err := domain.NewRole(domain.RoleAdmin)
+	require.NoError(t, err)
+	require.NoError(t, user.UpdateRole(role))
+
+	return user
+}
+
+func TestServiceCreateAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	recentActor := func(t *testing.T) *domain.AccessClaims {
+		t.Helper()
+
+		actor := mustActor(t, domain.RoleUser)
+		actor.AuthTime = time.Now()
+
+		return actor
+	}
+
+	t.Run("returns the key once and stores its hash", func(t *testing.T) {
+		t.Parallel()
+
+		keys := &mockAPIKeyRepo{}
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			APIKeys:     keys,
+			AuditLogger: auditLog,
+			Opaque:      &mockOpaqueTokenManager{generateToken: "key-secret"},
+		})
+		require.NoError(t, err)
+
+		actor := recentActor(t)
+
+		res, err := svc.CreateAPIKey(ctx, actor, &service.CreateAPIKeyRequest{
+			Name:   "deploy",
+			Scopes: []string{"User:Read", "user:read"},
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(res.Key, domain.APIKeyPrefix))
+		assert.True(t, strings.HasSuffix(res.Key, "_key-secret"))
+		assert.Equal(t, []string{"user:read"}, res.Scopes)
+		assert.WithinDuration(t, time.Now().Add(365*24*time.Hour), res.ExpiresAt, time.Minute)
+
+		require.Len(t, keys.keys, 1)
+		assert.Equal(t, actor.UserID, keys.keys[0].UserID)
+		assert.Equal(t, "hashed-key-secret", keys.keys[0].SecretHash)
+
+		event := auditLog.last()
+		require.NotNil(t, event)
+		assert.Equal(t, domain.AuditActionAPIKeyCreated, event.Action)
+	})
+	t.Run("requires recent authentication", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{APIKeys: &mockAPIKeyRepo{}})
+		require.NoError(t, err)
+
+		_, err = svc.CreateAPIKey(ctx, mustActor(t, domain.RoleUser), &service.CreateAPIKeyRequest{Name: "ci"})
+		assertAppErrorCode(t, err, apperror.ErrCodeReauthenticationRequired)
+	})
+	t.Run("API keys cannot create keys", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{APIKeys: &mockAPIKeyRepo{}})
+		require.NoError(t, err)
+
+		actor := recentActor(t)
+		actor.APIKeyID = uuid.New()
+
+		_, err = svc.CreateAPIKey(ctx, actor, &service.CreateAPIKeyRequest{Name: "ci"})
+		assertAppErrorCode(t, err, apperror.ErrCodeReauthenticationRequired)
+	})
+	t.Run("expiry beyond the maximum", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{APIKeys: &mockAPIKeyRepo{}})
+		require.NoError(t, err)
+
+		_, err = svc.CreateAPIKey(ctx, recentActor(t), &service.CreateAPIKeyRequest{
+			Name:      "ci",
+			ExpiresAt: time.Now().Add(2 * 365 * 24 * time.Hour),
+		})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+	})
+	t.Run("invalid scope", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{APIKeys: &mockAPIKeyRepo{}})
+		require.NoError(t, err)
+
+		_, err = svc.CreateAPIKey(ctx, recentActor(t), &service.CreateAPIKeyRequest{
+			Name:   "ci",
+			Scopes: []string{"everything"},
+		})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.CreateAPIKey(ctx, recentActor(t), &service.CreateAPIKeyRequest{Name: "ci"})
+		assertAppErrorCode(t, err, apperror.ErrCodeAPIKeysDisabled)
+	})
+}
+
+func TestServiceAuthenticateAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("acts for the owner within the key's scopes", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustAdminUser(t)
+		keys := &mockAPIKeyRepo{}
+		raw := mustAPIKey(t, keys, user.ID, "secret_with_underscores", "user:read")
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo: &mockUserRepo{getByIDUser: user},
+			APIKeys:  keys,
+		})
+		require.NoError(t, err)
+
+		claims, err := svc.AuthenticateAPIKey(ctx, raw)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, claims.UserID)
+		assert.Equal(t, keys.keys[0].ID, claims.APIKeyID)
+		assert.Equal(t, []string{"user:read"}, claims.Scopes)
+		assert.NotNil(t, keys.keys[0].LastUsedAt)
+
+		// The owner's role allows audit:read, but the key was not granted it.
+		_, err = svc.ListAuditEvents(ctx, claims, nil)
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+	})
+	t.Run("scope grants the owner's permission", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustAdminUser(t)
+		keys := &mockAPIKeyRepo{}
+		raw := mustAPIKey(t, keys, user.ID, "secret", "audit:read")
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo: &mockUserRepo{getByIDUser: user},
+			APIKeys:  keys,
+		})
+		require.NoError(t, err)
+
+		claims, err := svc.AuthenticateAPIKey(ctx, raw)
+		require.NoError(t, err)
+
+		_, err = svc.ListAuditEvents(ctx, claims, nil)
+		require.NoError(t, err)
+	})
+
+	rejected := []struct {
+		name  string
+		setup func(t *testing.T, keys *mockAPIKeyRepo, user *domain.User) string
+	}{
+		{
+			name: "wrong secret",
+			setup: func(t *testing.T, keys *mockAPIKeyRepo, user *domain.User) string {
+				id, _, err := domain.ParseAPIKey(mustAPIKey(t, keys, user.ID, "secret"))
+				require.NoError(t, err)
+
+				return domain.FormatAPIKey(id, "guess")
+			},
+		},
+		{
+			name: "unknown key",
+			setup: func(t *testing.T, keys *mockAPIKeyRepo, user *domain.User) string {
+				return domain.FormatAPIKey(uuid.New(), "secret")
+			},
+		},
+		{
+			name: "malformed key",
+			setup: func(t *testing.T, keys *mockAPIKeyRepo, user *domain.User) string {
+				return domain.APIKeyPrefix + "short"
+			},
+		},
+		{
+			name: "revoked key",
+			setup: func(t *testing.T, keys *mockAPIKeyRepo, user *domain.User) string {
+				raw := mustAPIKey(t, keys, user.ID, "secret")
+
+				revoked, err := keys.Revoke(context.Background(), user.ID, keys.keys[0].ID, time.Now())
+				require.NoError(t, err)
+				require.True(t, revoked)
+
+				return raw
+			},
+		},
+		{
+			name: "expired key",
+			setup: func(t *testing.T, keys *mockAPIKeyRepo, user *domain.User) string {
+				raw := mustAPIKey(t, keys, user.ID, "secret")
+				keys.keys[0].ExpiresAt = time.Now().Add(-time.Minute)
+
+				return raw
+			},
+		},
+		{
+			name: "banned owner",
+			setup: func(t *testing.T, keys *mockAPIKeyRepo, user *domain.User) string {
+				require.NoError(t, user.Ban())
+
+				return mustAPIKey(t, keys, user.ID, "secret")
+			},
+		},
+	}
+
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := mustVerifiedUser(t, "alice", "alice@example.com", "hash")
+			keys := &mockAPIKeyRepo{}
+			raw := tt.setup(t, keys, user)
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo: &mockUserRepo{getByIDUser: user},
+				APIKeys:  keys,
+			})
+			require.NoError(t, err)
+
+			_, err = svc.AuthenticateAPIKey(ctx, raw)
+			assertAppErrorCode(t, err, apperror.ErrCodeInvalidToken)
+		})
+	}
+}
+
+func TestServiceRevokeAPIKey(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("revokes the caller's key", func(t *testing.T) {
+		t.Parallel()
+
+		actor := mustActor(t, domain.RoleUser)
+		keys := &mockAPIKeyRepo{}
+		mustAPIKey(t, keys, actor.UserID, "secret")
+
+		svc, err := newTestServiceWith(testDeps{APIKeys: keys})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.RevokeAPIKey(ctx, actor, keys.keys[0].ID))
+
+		listed, err := svc.ListAPIKeys(ctx, actor)
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.NotNil(t, listed[0].RevokedAt)
+		assert.Empty(t, listed[0].Key)
+
+		err = svc.RevokeAPIKey(ctx, actor, keys.keys[0].ID)
+		assertAppErrorCode(t, err, apperror.ErrCodeAPIKeyNotFound)
+	})
+	t.Run("another user's key", func(t *testing.T) {
+		t.Parallel()
+
+		keys := &mockAPIKeyRepo{}
+		mustAPIKey(t, keys, uuid.New(), "secret")
+
+		svc, err := newTestServiceWith(testDeps{APIKeys: keys})
+		require.NoError(t, err)
+
+		err = svc.RevokeAPIKey(ctx, mustActor(t, domain.RoleUser), keys.keys[0].ID)
+		assertAppErrorCode(t, err, apperror.ErrCodeAPIKeyNotFound)
+		assert.Nil(t, keys.keys[0].RevokedAt)
+	})
+}
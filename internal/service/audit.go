@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+type ListAuditEventsRequest struct {
+	ActorID  *uuid.UUID
+	TargetID *uuid.UUID
+	Action   string
+	Since    *time.Time
+	Until    *time.Time
+	Limit    int
+	Offset   int
+}
+
+type AuditEventResponse struct {
+	ID        uuid.UUID
+	ActorID   *uuid.UUID
+	TargetID  *uuid.UUID
+	Action    string
+	Outcome   string
+	ClientIP  string
+	UserAgent string
+	Metadata  map[string]any
+	CreatedAt time.Time
+}
+
+type nopAuditLogger struct{}
+
+func (nopAuditLogger) Log(context.Context, *domain.AuditEvent) error { return nil }
+
+// audit hands the event to the configured logger. Failures are deliberately
+// ignored: auditing must never fail or slow down the operation it records.
+func (s *service) audit(ctx context.Context, event *domain.AuditEvent) {
+	_ = s.auditLogger.Log(ctx, event)
+}
+
+func (s *service) ListAuditEvents(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *ListAuditEventsRequest,
+) ([]*AuditEventResponse, error) {
+	if err := s.authorize(actor, domain.PermAuditRead); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		req = &ListAuditEventsRequest{}
+	}
+
+	if req.Limit < 0 || req.Offset < 0 || req.Limit > maxAuditPageSize {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgAuditPageInvalid, nil)
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultAuditPageSize
+	}
+
+	filter := domain.AuditFilter{
+		ActorID:  req.ActorID,
+		TargetID: req.TargetID,
+		Since:    req.Since,
+		Until:    req.Until,
+		Limit:    limit,
+		Offset:   req.Offset,
+	}
+
+	if req.Action != "" {
+		filter.Actions = []domain.AuditAction{domain.AuditAction(req.Action)}
+	}
+
+	events, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListAuditEvents, err)
+	}
+
+	out := make([]*AuditEventResponse, len(events))
+	for i, event := range events {
+		out[i] = toAuditEventResponse(event)
+	}
+
+	return out, nil
+}
+
+func toAuditEventResponse(event *domain.AuditEvent) *AuditEventResponse {
+	return &AuditEventResponse{
+		ID:        event.ID,
+		ActorID:   event.ActorID,
+		TargetID:  event.TargetID,
+		Action:    event.Action.String(),
+		Outcome:   event.Outcome.String(),
+		ClientIP:  event.ClientIP,
+		UserAgent: event.UserAgent,
+		Metadata:  event.Metadata,
+		CreatedAt: event.CreatedAt,
+	}
+}
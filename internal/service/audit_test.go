@@ -0,0 +1,106 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+func TestServiceListAuditEvents(t *testing.T) {
+	ctx := context.Background()
+	admin := mustActor(t, domain.RoleAdmin)
+	user := mustActor(t, domain.RoleUser)
+	event := domain.NewAuditEvent(domain.AuditActionLogin, domain.AuditOutcomeSuccess).WithActor(uuid.New())
+
+	tests := []struct {
+		name      string
+		actor     *domain.AccessClaims
+		req       *service.ListAuditEventsRequest
+		repo      *mockAuditRepo
+		wantCode  apperror.Code
+		wantLimit int
+	}{
+		{
+			name:     "no actor",
+			actor:    nil,
+			wantCode: apperror.ErrCodeUnauthorized,
+		},
+		{
+			name:     "actor lacks audit:read",
+			actor:    user,
+			wantCode: apperror.ErrCodePermissionDenied,
+		},
+		{
+			name:     "limit too large",
+			actor:    admin,
+			req:      &service.ListAuditEventsRequest{Limit: 10_000},
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:     "negative offset",
+			actor:    admin,
+			req:      &service.ListAuditEventsRequest{Offset: -1},
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:     "list error",
+			actor:    admin,
+			repo:     &mockAuditRepo{listErr: errors.New("db error")},
+			wantCode: apperror.ErrCodeInternalServer,
+		},
+		{
+			name:      "default limit",
+			actor:     admin,
+			repo:      &mockAuditRepo{events: []*domain.AuditEvent{event}},
+			wantLimit: 50,
+		},
+		{
+			name:      "filtered",
+			actor:     admin,
+			req:       &service.ListAuditEventsRequest{Action: "auth.login", Limit: 10, Offset: 20},
+			repo:      &mockAuditRepo{events: []*domain.AuditEvent{event}},
+			wantLimit: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := tt.repo
+			if repo == nil {
+				repo = &mockAuditRepo{}
+			}
+
+			svc, err := newTestServiceWith(testDeps{AuditRepo: repo})
+			require.NoError(t, err)
+
+			got, err := svc.ListAuditEvents(ctx, tt.actor, tt.req)
+
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			assert.Equal(t, event.ID, got[0].ID)
+			assert.Equal(t, "auth.login", got[0].Action)
+			assert.Equal(t, tt.wantLimit, repo.filter.Limit)
+
+			if tt.req != nil {
+				assert.Equal(t, []domain.AuditAction{domain.AuditAction(tt.req.Action)}, repo.filter.Actions)
+				assert.Equal(t, tt.req.Offset, repo.filter.Offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/device"
+	"go-auth/internal/domain"
+)
+
+// checkSessionBinding compares the refresh caller with the client the session
+// was issued to. Depending on the policy a mismatch revokes the session and
+// fails the refresh, or flags the session and lets the refresh proceed.
+func (s *service) checkSessionBinding(ctx context.Context, session *domain.Session, req *RefreshRequest) error {
+	if s.sessionBinding == SessionBindingOff {
+		return nil
+	}
+
+	mismatch := bindingMismatch(session, req.UserAgent, req.ClientIP)
+	if len(mismatch) == 0 {
+		return nil
+	}
+
+	event := domain.NewAuditEvent(domain.AuditActionSessionBinding, domain.AuditOutcomeSuccess).
+		WithActor(session.UserID).
+		WithTarget(session.UserID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(map[string]any{
+			"session_id":       session.ID.String(),
+			"policy":           string(s.sessionBinding),
+			"mismatch":         mismatch,
+			"expected_device":  device.Family(session.UserAgent),
+			"expected_network": device.Prefix(session.ClientIP),
+		})
+
+	if s.sessionBinding == SessionBindingWarn {
+		session.Flag()
+		s.audit(ctx, event.WithMetadata(map[string]any{"flagged": true}))
+
+		return nil
+	}
+
+	event.Outcome = domain.AuditOutcomeDenied
+
+	if err := session.Revoke(); err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgRevokeSession, err)
+	}
+
+	if err := s.sessionRepo.Update(ctx, session); err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUpdateSession, err)
+	}
+
+	s.audit(ctx, event)
+	s.publish(ctx, sessionRevokedEvent(session, domain.SessionRevokedBindingMismatch))
+
+	return apperror.Unauthorized(apperror.ErrCodeSessionBinding, apperror.MsgSessionBindingMismatch, nil)
+}
+
+func bindingMismatch(session *domain.Session, userAgent, clientIP string) []string {
+	var mismatch []string
+
+	if device.Family(session.UserAgent) != device.Family(userAgent) {
+		mismatch = append(mismatch, "user_agent")
+	}
+
+	if device.Prefix(session.ClientIP) != device.Prefix(clientIP) {
+		mismatch = append(mismatch, "network")
+	}
+
+	return mismatch
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+const chromeWindowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 " +
+	"(KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+
+func TestServiceRefreshSessionBinding(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		policy       service.SessionBinding
+		userAgent    string
+		clientIP     string
+		wantCode     apperror.Code
+		wantFlagged  bool
+		wantMismatch []string
+	}{
+		{
+			name:      "off ignores mismatch",
+			policy:    service.SessionBindingOff,
+			userAgent: chromeWindowsUA,
+			clientIP:  "203.0.113.1",
+		},
+		{
+			name:      "strict same device and network",
+			policy:    service.SessionBindingStrict,
+			userAgent: firefoxLinuxUA,
+			clientIP:  "198.51.100.200",
+		},
+		{
+			name:         "strict different network",
+			policy:       service.SessionBindingStrict,
+			userAgent:    firefoxLinuxUA,
+			clientIP:     "203.0.113.1",
+			wantCode:     apperror.ErrCodeSessionBinding,
+			wantMismatch: []string{"network"},
+		},
+		{
+			name:         "strict different device",
+			policy:       service.SessionBindingStrict,
+			userAgent:    chromeWindowsUA,
+			clientIP:     "198.51.100.10",
+			wantCode:     apperror.ErrCodeSessionBinding,
+			wantMismatch: []string{"user_agent"},
+		},
+		{
+			name:         "warn flags and continues",
+			policy:       service.SessionBindingWarn,
+			userAgent:    chromeWindowsUA,
+			clientIP:     "203.0.113.1",
+			wantFlagged:  true,
+			wantMismatch: []string{"user_agent", "network"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+			session, err := domain.NewSession(user.ID, "token-hash", firefoxLinuxUA, "198.51.100.10", farFutureExpiry)
+			require.NoError(t, err)
+
+			sessionRepo := &mockSessionRepo{getByToken: session}
+			auditLog := &mockAuditLogger{}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:       &mockUserRepo{getByIDUser: user},
+				SessionRepo:    sessionRepo,
+				AuditLogger:    auditLog,
+				SessionBinding: tt.policy,
+			})
+			require.NoError(t, err)
+
+			_, err = svc.Refresh(ctx, &service.RefreshRequest{
+				RefreshToken: "token",
+				UserAgent:    tt.userAgent,
+				ClientIP:     tt.clientIP,
+			})
+
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+				assert.True(t, session.IsRevoked())
+				assert.Same(t, session, sessionRepo.updatedSession)
+				assert.Nil(t, sessionRepo.savedSession)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, sessionRepo.savedSession)
+				assert.Equal(t, tt.wantFlagged, sessionRepo.savedSession.IsFlagged())
+			}
+
+			if tt.wantMismatch == nil {
+				assert.NotContains(t, auditLog.actions(), domain.AuditActionSessionBinding)
+
+				return
+			}
+
+			var event *domain.AuditEvent
+
+			for _, e := range auditLog.events {
+				if e.Action == domain.AuditActionSessionBinding {
+					event = e
+				}
+			}
+
+			require.NotNil(t, event)
+			assert.Equal(t, tt.wantMismatch, event.Metadata["mismatch"])
+			assert.Equal(t, session.ID.String(), event.Metadata["session_id"])
+
+			if tt.wantCode != "" {
+				assert.Equal(t, domain.AuditOutcomeDenied, event.Outcome)
+			} else {
+				assert.Equal(t, true, event.Metadata["flagged"])
+			}
+		})
+	}
+}
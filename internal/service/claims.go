@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+func (s *service) userAccessClaims(user *domain.User) domain.AccessClaims {
+	claims := domain.AccessClaims{
+		UserID:   user.ID,
+		Audience: slices.Clone(s.tokenAudience),
+	}
+
+	s.applyRole(&claims, user.Role)
+
+	return claims
+}
+
+func (s *service) applyRole(claims *domain.AccessClaims, role domain.Role) {
+	claims.Role = role
+	claims.Permissions = nil
+	claims.Scopes = nil
+
+	perms := role.Permissions()
+	if claims.InOrganization() {
+		perms = slices.DeleteFunc(slices.Clone(perms), domain.Permission.IsDeploymentWide)
+	}
+
+	switch s.permissionClaims {
+	case PermissionClaimsPermissions:
+		claims.Permissions = perms
+	case PermissionClaimsScope:
+		claims.Scopes = make([]string, len(perms))
+		for i, perm := range perms {
+			claims.Scopes[i] = perm.String()
+		}
+	case PermissionClaimsNone:
+	}
+}
+
+// organizationAccessClaims scopes claims to organizationID, replacing the user's own role with
+// their role in the organisation. It fails when the user is no longer a member.
+func (s *service) organizationAccessClaims(
+	ctx context.Context,
+	claims domain.AccessClaims,
+	organizationID uuid.UUID,
+) (domain.AccessClaims, error) {
+	if s.organizationRepo == nil {
+		return domain.AccessClaims{}, errOrganizationsDisabled()
+	}
+
+	member, err := s.organizationRepo.GetMember(ctx, organizationID, claims.UserID)
+	if err != nil {
+		return domain.AccessClaims{}, apperror.InternalServerError(
+			apperror.ErrCodeInternalServer,
+			apperror.MsgGetMember,
+			err,
+		)
+	}
+
+	if member == nil {
+		return domain.AccessClaims{}, errOrganizationMembershipRequired()
+	}
+
+	claims.OrganizationID = organizationID
+	s.applyRole(&claims, member.Role)
+
+	return claims, nil
+}
+
+func (s *service) sessionAccessClaims(
+	ctx context.Context,
+	user *domain.User,
+	session *domain.Session,
+) (domain.AccessClaims, error) {
+	if session.ClientID == "" {
+		claims := s.userAccessClaims(user)
+		claims.AuthTime = session.AuthenticatedAt
+
+		if session.OrganizationID != nil {
+			return s.organizationAccessClaims(ctx, claims, *session.OrganizationID)
+		}
+
+		return claims, nil
+	}
+
+	return domain.AccessClaims{
+		UserID:   user.ID,
+		Role:     user.Role,
+		Scopes:   slices.Clone(session.Scopes),
+		Audience: slices.Clone(s.tokenAudience),
+		ClientID: session.ClientID,
+	}, nil
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"net/netip"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/clientip"
+)
+
+func parseClientIP(raw string) (netip.Addr, error) {
+	addr, err := clientip.Parse(raw)
+	if err != nil {
+		return netip.Addr{}, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgClientIPInvalid, err)
+	}
+
+	return addr, nil
+}
+
+func (s *service) clientIPAllowed(addr netip.Addr) bool {
+	return s.clientIPPolicy == nil || s.clientIPPolicy.Allowed(addr)
+}
+
+func errIPNotAllowed() error {
+	return apperror.Forbidden(apperror.ErrCodeIPNotAllowed, apperror.MsgIPNotAllowed, nil)
+}
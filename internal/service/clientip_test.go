@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/clientip"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+func TestServiceLoginClientIP(t *testing.T) {
+	ctx := context.Background()
+	passHash, _ := domain.NewPasswordFromHash("$hash")
+
+	policy, err := clientip.NewPolicy(nil, []string{"203.0.113.0/24"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		clientIP  string
+		wantCode  apperror.Code
+		wantAudit string
+	}{
+		{name: "missing", clientIP: "", wantCode: apperror.ErrCodeInvalidParam},
+		{name: "invalid", clientIP: "not-an-ip", wantCode: apperror.ErrCodeInvalidParam},
+		{name: "denied", clientIP: "203.0.113.5", wantCode: apperror.ErrCodeIPNotAllowed, wantAudit: "203.0.113.5"},
+		{name: "denied mapped", clientIP: "::ffff:203.0.113.5", wantCode: apperror.ErrCodeIPNotAllowed, wantAudit: "203.0.113.5"},
+		{name: "allowed and normalised", clientIP: "::ffff:198.51.100.5", wantAudit: "198.51.100.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+			user.Password = passHash
+			auditLog := &mockAuditLogger{}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:       &mockUserRepo{getByUsernameUser: user},
+				Hasher:         &mockPasswordHasher{compareOk: true},
+				AuditLogger:    auditLog,
+				ClientIPPolicy: policy,
+			})
+			require.NoError(t, err)
+
+			_, err = svc.Login(ctx, &service.LoginRequest{
+				Login:     "alice",
+				Password:  "pass",
+				UserAgent: "ua",
+				ClientIP:  tt.clientIP,
+			})
+
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.wantAudit == "" {
+				assert.Nil(t, auditLog.last())
+
+				return
+			}
+
+			require.NotNil(t, auditLog.last())
+			assert.Equal(t, tt.wantAudit, auditLog.last().ClientIP)
+		})
+	}
+}
+
+func TestServiceRefreshClientIP(t *testing.T) {
+	ctx := context.Background()
+
+	policy, err := clientip.NewPolicy([]string{"10.0.0.0/8"}, nil)
+	require.NoError(t, err)
+
+	svc, err := newTestServiceWith(testDeps{ClientIPPolicy: policy})
+	require.NoError(t, err)
+
+	_, err = svc.Refresh(ctx, &service.RefreshRequest{RefreshToken: "token", UserAgent: "ua", ClientIP: "bogus"})
+	assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+
+	_, err = svc.Refresh(ctx, &service.RefreshRequest{RefreshToken: "token", UserAgent: "ua", ClientIP: "192.0.2.1"})
+	assertAppErrorCode(t, err, apperror.ErrCodeIPNotAllowed)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/device"
+	"go-auth/internal/domain"
+)
+
+const (
+	defaultLoginHistorySize = 20
+	maxLoginHistorySize     = 100
+	notificationTimeout     = 30 * time.Second
+)
+
+type LoginAttemptResponse struct {
+	At        time.Time
+	Success   bool
+	Outcome   string
+	Reason    string
+	Device    string
+	ClientIP  string
+	UserAgent string
+}
+
+type KnownDeviceResponse struct {
+	ID          uuid.UUID
+	Device      string
+	Network     string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+// trackDevice records the device behind a successful login and reports whether
+// it was new. A first-ever device is not reported as new, so registration does
+// not trigger a notification. Failures are ignored: tracking must not block a login.
+func (s *service) trackDevice(ctx context.Context, user *domain.User, req *LoginRequest) (device.Info, bool) {
+	info := device.Identify(req.UserAgent, req.ClientIP)
+
+	known, err := s.deviceRepo.CountByUserID(ctx, user.ID)
+	if err != nil {
+		return info, false
+	}
+
+	d, err := domain.NewKnownDevice(user.ID, info.Fingerprint, info.Family, info.Prefix)
+	if err != nil {
+		return info, false
+	}
+
+	created, err := s.deviceRepo.Upsert(ctx, d)
+	if err != nil || !created || known == 0 {
+		return info, false
+	}
+
+	if s.mailer != nil {
+		go s.notifyNewDevice(context.WithoutCancel(ctx), user, info, req.ClientIP, d.FirstSeenAt)
+	}
+
+	return info, true
+}
+
+func (s *service) notifyNewDevice(ctx context.Context, user *domain.User, info device.Info, clientIP string, at time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	_ = s.mailer.Send(ctx, &domain.EmailMessage{
+		To:      user.Email,
+		Subject: "New sign-in to your account",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n"+
+				"Your account was just accessed from a device we have not seen before.\n\n"+
+				"Device: %s\nIP address: %s\nTime: %s\n\n"+
+				"If this was you, no action is needed. "+
+				"If not, change your password immediately.\n",
+			user.FirstName, info.Family, clientIP, at.Format(time.RFC1123),
+		),
+	})
+}
+
+func (s *service) ListLoginHistory(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	limit int,
+) ([]*LoginAttemptResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if limit < 0 || limit > maxLoginHistorySize {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgHistoryLimitInvalid, nil)
+	}
+
+	if limit == 0 {
+		limit = defaultLoginHistorySize
+	}
+
+	userID := actor.UserID
+
+	events, err := s.auditRepo.List(ctx, domain.AuditFilter{
+		TargetID: &userID,
+		Actions:  []domain.AuditAction{domain.AuditActionLogin, domain.AuditActionLoginFailed},
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListLoginHistory, err)
+	}
+
+	out := make([]*LoginAttemptResponse, len(events))
+	for i, event := range events {
+		out[i] = toLoginAttemptResponse(event)
+	}
+
+	return out, nil
+}
+
+func (s *service) ListKnownDevices(ctx context.Context, actor *domain.AccessClaims) ([]*KnownDeviceResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	devices, err := s.deviceRepo.ListByUserID(ctx, actor.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListKnownDevices, err)
+	}
+
+	out := make([]*KnownDeviceResponse, len(devices))
+	for i, d := range devices {
+		out[i] = &KnownDeviceResponse{
+			ID:          d.ID,
+			Device:      d.UAFamily,
+			Network:     d.IPPrefix,
+			FirstSeenAt: d.FirstSeenAt,
+			LastSeenAt:  d.LastSeenAt,
+		}
+	}
+
+	return out, nil
+}
+
+func toLoginAttemptResponse(event *domain.AuditEvent) *LoginAttemptResponse {
+	reason, _ := event.Metadata["reason"].(string)
+
+	return &LoginAttemptResponse{
+		At:        event.CreatedAt,
+		Success:   event.Outcome == domain.AuditOutcomeSuccess,
+		Outcome:   event.Outcome.String(),
+		Reason:    reason,
+		Device:    device.Family(event.UserAgent),
+		ClientIP:  event.ClientIP,
+		UserAgent: event.UserAgent,
+	}
+}
@@ -0,0 +1,184 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+const firefoxLinuxUA = "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0"
+
+func TestServiceLoginNewDeviceNotification(t *testing.T) {
+	ctx := context.Background()
+	passHash, _ := domain.NewPasswordFromHash("$hash")
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	user.Password = passHash
+
+	devices := &mockDeviceRepo{}
+	mailer := newMockMailer()
+	auditLog := &mockAuditLogger{}
+
+	svc, err := newTestServiceWith(testDeps{
+		UserRepo:    &mockUserRepo{getByUsernameUser: user},
+		Hasher:      &mockPasswordHasher{compareOk: true},
+		DeviceRepo:  devices,
+		Mailer:      mailer,
+		AuditLogger: auditLog,
+	})
+	require.NoError(t, err)
+
+	login := func(ua, ip string) {
+		t.Helper()
+
+		_, err := svc.Login(ctx, &service.LoginRequest{Login: "alice", Password: "pass", UserAgent: ua, ClientIP: ip})
+		require.NoError(t, err)
+	}
+
+	// First device ever: remembered, but no notification.
+	login(firefoxLinuxUA, "198.51.100.10")
+	assert.Equal(t, false, auditLog.last().Metadata["new_device"])
+
+	// Same UA family and /24: still known.
+	login(firefoxLinuxUA, "198.51.100.99")
+	assert.Equal(t, false, auditLog.last().Metadata["new_device"])
+
+	select {
+	case msg := <-mailer.sent:
+		t.Fatalf("unexpected notification: %q", msg.Subject)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// New network: notify.
+	login(firefoxLinuxUA, "203.0.113.7")
+	assert.Equal(t, true, auditLog.last().Metadata["new_device"])
+	assert.Equal(t, "Firefox on Linux", auditLog.last().Metadata["device"])
+
+	select {
+	case msg := <-mailer.sent:
+		assert.Equal(t, user.Email, msg.To)
+		assert.Contains(t, msg.Body, "Firefox on Linux")
+		assert.Contains(t, msg.Body, "203.0.113.7")
+	case <-time.After(time.Second):
+		t.Fatal("expected a new-device notification")
+	}
+
+	assert.Len(t, devices.devices, 2)
+}
+
+func TestServiceLoginDeviceTrackingFailureDoesNotBlock(t *testing.T) {
+	passHash, _ := domain.NewPasswordFromHash("$hash")
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	user.Password = passHash
+
+	svc, err := newTestServiceWith(testDeps{
+		UserRepo:   &mockUserRepo{getByUsernameUser: user},
+		Hasher:     &mockPasswordHasher{compareOk: true},
+		DeviceRepo: &mockDeviceRepo{upsertErr: errors.New("db error")},
+		Mailer:     newMockMailer(),
+	})
+	require.NoError(t, err)
+
+	_, err = svc.Login(context.Background(), validLoginReq)
+	require.NoError(t, err)
+}
+
+func TestServiceListLoginHistory(t *testing.T) {
+	ctx := context.Background()
+	actor := mustActor(t, domain.RoleUser)
+
+	failed := domain.NewAuditEvent(domain.AuditActionLoginFailed, domain.AuditOutcomeFailure).
+		WithTarget(actor.UserID).
+		WithClient(firefoxLinuxUA, "198.51.100.10").
+		WithMetadata(map[string]any{"reason": "invalid_password"})
+	ok := domain.NewAuditEvent(domain.AuditActionLogin, domain.AuditOutcomeSuccess).
+		WithTarget(actor.UserID).
+		WithClient(firefoxLinuxUA, "198.51.100.10")
+
+	tests := []struct {
+		name      string
+		actor     *domain.AccessClaims
+		limit     int
+		repo      *mockAuditRepo
+		wantCode  apperror.Code
+		wantLimit int
+	}{
+		{name: "no actor", actor: nil, wantCode: apperror.ErrCodeUnauthorized},
+		{name: "limit too large", actor: actor, limit: 1000, wantCode: apperror.ErrCodeInvalidParam},
+		{
+			name:     "list error",
+			actor:    actor,
+			repo:     &mockAuditRepo{listErr: errors.New("db error")},
+			wantCode: apperror.ErrCodeInternalServer,
+		},
+		{
+			name:      "success",
+			actor:     actor,
+			repo:      &mockAuditRepo{events: []*domain.AuditEvent{ok, failed}},
+			wantLimit: 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := tt.repo
+			if repo == nil {
+				repo = &mockAuditRepo{}
+			}
+
+			svc, err := newTestServiceWith(testDeps{AuditRepo: repo})
+			require.NoError(t, err)
+
+			got, err := svc.ListLoginHistory(ctx, tt.actor, tt.limit)
+
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, repo.filter.TargetID)
+			assert.Equal(t, actor.UserID, *repo.filter.TargetID)
+			assert.Equal(t, tt.wantLimit, repo.filter.Limit)
+			assert.ElementsMatch(t,
+				[]domain.AuditAction{domain.AuditActionLogin, domain.AuditActionLoginFailed},
+				repo.filter.Actions,
+			)
+
+			require.Len(t, got, 2)
+			assert.True(t, got[0].Success)
+			assert.False(t, got[1].Success)
+			assert.Equal(t, "invalid_password", got[1].Reason)
+			assert.Equal(t, "Firefox on Linux", got[1].Device)
+		})
+	}
+}
+
+func TestServiceListKnownDevices(t *testing.T) {
+	actor := mustActor(t, domain.RoleUser)
+
+	d, err := domain.NewKnownDevice(actor.UserID, "fp", "Firefox on Linux", "198.51.100.0/24")
+	require.NoError(t, err)
+
+	svc, err := newTestServiceWith(testDeps{DeviceRepo: &mockDeviceRepo{devices: map[string]*domain.KnownDevice{"fp": d}}})
+	require.NoError(t, err)
+
+	_, err = svc.ListKnownDevices(context.Background(), nil)
+	assertAppErrorCode(t, err, apperror.ErrCodeUnauthorized)
+
+	got, err := svc.ListKnownDevices(context.Background(), actor)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Firefox on Linux", got[0].Device)
+	assert.Equal(t, "198.51.100.0/24", got[0].Network)
+}
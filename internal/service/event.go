@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"go-auth/internal/domain"
+)
+
+type nopEventPublisher struct{}
+
+func (nopEventPublisher) Publish(context.Context, domain.Event) {}
+
+// publish hands event to the configured publisher. Callers publish once the change the event
+// reports has been stored, so that subscribers never see a change that was rolled back.
+func (s *service) publish(ctx context.Context, event domain.Event) {
+	s.events.Publish(ctx, event)
+}
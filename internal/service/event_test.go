@@ -0,0 +1,210 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+func TestServiceLoginPublishesEvents(t *testing.T) {
+	ctx := context.Background()
+	passHash, _ := domain.NewPasswordFromHash("$hash")
+
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	user.Password = passHash
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		events := &mockEventPublisher{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:    &mockUserRepo{getByUsernameUser: user},
+			SessionRepo: &mockSessionRepo{},
+			Hasher:      &mockPasswordHasher{compareOk: true},
+			Events:      events,
+		})
+		require.NoError(t, err)
+
+		_, err = svc.Login(ctx, validLoginReq)
+		require.NoError(t, err)
+
+		event, ok := events.last().(domain.UserLoggedIn)
+		require.True(t, ok, "got %T", events.last())
+		assert.Equal(t, user.ID, event.UserID)
+		assert.Equal(t, "password", event.Method)
+		assert.Equal(t, "1.2.3.4", event.ClientIP)
+		assert.Equal(t, "ua", event.UserAgent)
+		assert.WithinDuration(t, time.Now(), event.OccurredAt(), time.Minute)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		t.Parallel()
+
+		events := &mockEventPublisher{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo: &mockUserRepo{getByUsernameUser: user},
+			Hasher:   &mockPasswordHasher{compareOk: false},
+			Events:   events,
+		})
+		require.NoError(t, err)
+
+		_, err = svc.Login(ctx, validLoginReq)
+		require.Error(t, err)
+
+		event, ok := events.last().(domain.LoginFailed)
+		require.True(t, ok, "got %T", events.last())
+		require.NotNil(t, event.UserID)
+		assert.Equal(t, user.ID, *event.UserID)
+		assert.Empty(t, event.Login)
+		assert.Equal(t, "invalid_password", event.Reason)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		t.Parallel()
+
+		events := &mockEventPublisher{}
+
+		svc, err := newTestServiceWith(testDeps{UserRepo: &mockUserRepo{}, Events: events})
+		require.NoError(t, err)
+
+		_, err = svc.Login(ctx, validLoginReq)
+		require.Error(t, err)
+
+		event, ok := events.last().(domain.LoginFailed)
+		require.True(t, ok, "got %T", events.last())
+		assert.Nil(t, event.UserID)
+		assert.Equal(t, "alice", event.Login)
+		assert.Equal(t, "unknown_user", event.Reason)
+	})
+}
+
+func TestServiceRegisterPublishesEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		users := &mockUserRepo{}
+		events := &mockEventPublisher{}
+
+		svc, err := newTestServiceWith(testDeps{UserRepo: users, Events: events})
+		require.NoError(t, err)
+
+		res, err := svc.Register(ctx, validRegisterReq)
+		require.NoError(t, err)
+
+		require.Len(t, events.events, 1)
+		event, ok := events.last().(domain.UserRegistered)
+		require.True(t, ok, "got %T", events.last())
+		assert.Equal(t, res.UserID, event.UserID)
+		assert.Equal(t, users.savedUser.IsVerified(), event.Verified)
+		assert.Empty(t, event.Provider)
+		assert.Nil(t, event.OrganizationID)
+	})
+
+	t.Run("nothing is published when the account is not stored", func(t *testing.T) {
+		t.Parallel()
+
+		events := &mockEventPublisher{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo: &mockUserRepo{saveErr: errors.New("db error")},
+			Events:   events,
+		})
+		require.NoError(t, err)
+
+		_, err = svc.Register(ctx, validRegisterReq)
+		require.Error(t, err)
+		assert.Empty(t, events.events)
+	})
+}
+
+func TestServiceSessionPublishesEvents(t *testing.T) {
+	ctx := context.Background()
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+
+	t.Run("refresh", func(t *testing.T) {
+		t.Parallel()
+
+		session := mustSession(t, user.ID, 24*time.Hour, false)
+		events := &mockEventPublisher{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:    &mockUserRepo{getByIDUser: user},
+			SessionRepo: &mockSessionRepo{getByToken: session},
+			Opaque:      &mockOpaqueTokenManager{hashResult: "h", generateToken: "new-rt"},
+			Events:      events,
+		})
+		require.NoError(t, err)
+
+		_, err = svc.Refresh(ctx, validRefreshReq)
+		require.NoError(t, err)
+
+		event, ok := events.last().(domain.SessionRefreshed)
+		require.True(t, ok, "got %T", events.last())
+		assert.Equal(t, user.ID, event.UserID)
+		assert.Equal(t, session.ID, event.PreviousSessionID)
+		assert.NotEqual(t, session.ID, event.SessionID)
+		assert.Empty(t, event.ClientID)
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		t.Parallel()
+
+		session := mustSession(t, user.ID, 24*time.Hour, false)
+		events := &mockEventPublisher{}
+
+		svc, err := newTestServiceWith(testDeps{
+			SessionRepo: &mockSessionRepo{getByToken: session},
+			Events:      events,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.Logout(ctx, "token"))
+
+		event, ok := events.last().(domain.SessionRevoked)
+		require.True(t, ok, "got %T", events.last())
+		assert.Equal(t, user.ID, event.UserID)
+		assert.Equal(t, session.ID, event.SessionID)
+		assert.Equal(t, domain.SessionRevokedLogout, event.Reason)
+	})
+}
+
+func TestServiceBanPublishesEvents(t *testing.T) {
+	ctx := context.Background()
+
+	target := mustUserWithRole(t, domain.RoleUser)
+	events := &mockEventPublisher{}
+
+	svc, err := newTestServiceWith(testDeps{UserRepo: &mockUserRepo{getByIDUser: target}, Events: events})
+	require.NoError(t, err)
+
+	admin := mustActor(t, domain.RoleAdmin)
+	req := &service.UserActionRequest{UserID: target.ID}
+
+	require.NoError(t, svc.BanUser(ctx, admin, req))
+
+	banned, ok := events.last().(domain.UserBanned)
+	require.True(t, ok, "got %T", events.last())
+	assert.Equal(t, target.ID, banned.UserID)
+	assert.Equal(t, admin.UserID, banned.ActorID)
+
+	require.NoError(t, svc.UnbanUser(ctx, admin, req))
+
+	unbanned, ok := events.last().(domain.UserUnbanned)
+	require.True(t, ok, "got %T", events.last())
+	assert.Equal(t, target.ID, unbanned.UserID)
+
+	err = svc.BanUser(ctx, mustActor(t, domain.RoleUser), req)
+	require.Error(t, err)
+	assert.Len(t, events.events, 2, "denied operations publish nothing")
+}
@@ -0,0 +1,496 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+const (
+	defaultFederatedStateTTL = 10 * time.Minute
+	usernameAttempts         = 5
+)
+
+type FederatedStartResponse struct {
+	AuthURL string
+	// State is also embedded in AuthURL; the HTTP layer binds it to the browser so a
+	// callback cannot be replayed into another user's session.
+	State     string
+	ExpiresAt time.Time
+}
+
+type FederatedCallbackRequest struct {
+	Provider  string
+	Code      string
+	State     string
+	UserAgent string
+	ClientIP  string
+}
+
+type IdentityResponse struct {
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+func (s *service) StartFederatedLogin(ctx context.Context, provider string) (*FederatedStartResponse, error) {
+	return s.startFederated(ctx, provider, nil)
+}
+
+func (s *service) StartFederatedLink(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	provider string,
+) (*FederatedStartResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireRecentAuth(actor); err != nil {
+		return nil, err
+	}
+
+	return s.startFederated(ctx, provider, &actor.UserID)
+}
+
+// CompleteFederatedLogin signs the user in with the identity the provider asserted. Unknown
+// identities are linked to an existing account only when both sides have verified the
+// email; otherwise the user must sign in and link explicitly. A user with an MFA factor gets
+// a challenge instead of a session, as with a password.
+func (s *service) CompleteFederatedLogin(ctx context.Context, req *FederatedCallbackRequest) (*LoginResponse, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgFederatedRequestRequired, nil)
+	}
+
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	loginReq := &LoginRequest{UserAgent: req.UserAgent, ClientIP: clientIP.String()}
+
+	if !s.clientIPAllowed(clientIP) {
+		metadata := federatedMetadata(req.Provider, "reason", "ip_denied")
+		s.auditLogin(ctx, loginReq, nil, domain.AuditOutcomeDenied, metadata)
+
+		return nil, errIPNotAllowed()
+	}
+
+	state, err := s.consumeFederatedState(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.IsLink() {
+		return nil, errFederatedStateInvalid()
+	}
+
+	identity, err := s.exchangeIdentity(ctx, state, req.Code)
+	if err != nil {
+		metadata := federatedMetadata(req.Provider, "reason", "exchange_failed")
+		s.auditLogin(ctx, loginReq, nil, domain.AuditOutcomeFailure, metadata)
+
+		return nil, err
+	}
+
+	loginReq.Login = identity.Email
+
+	user, err := s.federatedUser(ctx, identity, loginReq)
+	if err != nil {
+		metadata := federatedMetadata(req.Provider, "reason", "no_account")
+		s.auditLogin(ctx, loginReq, nil, domain.AuditOutcomeFailure, metadata)
+
+		return nil, err
+	}
+
+	if user.IsBanned() || !user.CanLogin() {
+		metadata := federatedMetadata(req.Provider, "reason", "account_blocked")
+		s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeDenied, metadata)
+
+		return nil, apperror.Forbidden(apperror.ErrCodeUserBlocked, apperror.MsgAccountAccessRevoked, nil)
+	}
+
+	if user.RequiresMFA() {
+		return s.challengeMFA(ctx, user, loginReq)
+	}
+
+	resp, err := s.createSession(ctx, user, loginReq)
+	if err != nil {
+		return nil, err
+	}
+
+	info, isNew := s.trackDevice(ctx, user, loginReq)
+
+	s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeSuccess, federatedMetadata(req.Provider,
+		"device", info.Family,
+		"new_device", isNew,
+	))
+
+	return resp, nil
+}
+
+func (s *service) CompleteFederatedLink(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *FederatedCallbackRequest,
+) (*IdentityResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireRecentAuth(actor); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgFederatedRequestRequired, nil)
+	}
+
+	state, err := s.consumeFederatedState(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !state.IsLink() || *state.UserID != actor.UserID {
+		return nil, errFederatedStateInvalid()
+	}
+
+	identity, err := s.exchangeIdentity(ctx, state, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.userIdentityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUserIdentity, err)
+	}
+
+	if existing != nil {
+		if existing.UserID != actor.UserID {
+			return nil, apperror.Conflict(apperror.ErrCodeIdentityAlreadyLinked, apperror.MsgIdentityAlreadyLinked, nil)
+		}
+
+		return toIdentityResponse(existing), nil
+	}
+
+	link, err := s.linkIdentity(ctx, actor.UserID, identity, "explicit", req.UserAgent, req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	return toIdentityResponse(link), nil
+}
+
+func (s *service) startFederated(
+	ctx context.Context,
+	name string,
+	userID *uuid.UUID,
+) (*FederatedStartResponse, error) {
+	provider, err := s.identityProvider(name)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.opaqueTokenManager.Generate()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateLoginState, err)
+	}
+
+	stateHash, err := s.opaqueTokenManager.Hash(state)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateLoginState, err)
+	}
+
+	nonce, err := s.opaqueTokenManager.Generate()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateLoginState, err)
+	}
+
+	verifier, err := domain.NewCodeVerifier()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateLoginState, err)
+	}
+
+	loginState, err := domain.NewFederatedLoginState(stateHash, name, nonce, verifier, userID, s.federatedStateTTL)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateLoginState, err)
+	}
+
+	pkce, err := domain.PKCEChallengeFor(verifier)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateLoginState, err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, pkce)
+	if err != nil {
+		return nil, apperror.BadGateway(apperror.ErrCodeProviderUnavailable, apperror.MsgProviderUnavailable, err)
+	}
+
+	if err = s.loginStateRepo.Save(ctx, loginState); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveLoginState, err)
+	}
+
+	return &FederatedStartResponse{AuthURL: authURL, State: state, ExpiresAt: loginState.ExpiresAt}, nil
+}
+
+// consumeFederatedState spends the state of the callback; it is gone even when the flow fails later.
+func (s *service) consumeFederatedState(
+	ctx context.Context,
+	req *FederatedCallbackRequest,
+) (*domain.FederatedLoginState, error) {
+	if _, err := s.identityProvider(req.Provider); err != nil {
+		return nil, err
+	}
+
+	if req.Code == "" || req.State == "" {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgFederatedCallbackInvalid, nil)
+	}
+
+	stateHash, err := s.opaqueTokenManager.Hash(req.State)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgConsumeLoginState, err)
+	}
+
+	state, err := s.loginStateRepo.Consume(ctx, stateHash)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgConsumeLoginState, err)
+	}
+
+	if state == nil || state.IsExpired() || state.Provider != req.Provider {
+		return nil, errFederatedStateInvalid()
+	}
+
+	return state, nil
+}
+
+func (s *service) exchangeIdentity(
+	ctx context.Context,
+	state *domain.FederatedLoginState,
+	code string,
+) (*domain.ExternalIdentity, error) {
+	provider, err := s.identityProvider(state.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := provider.Exchange(ctx, code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return nil, apperror.Unauthorized(apperror.ErrCodeFederatedLoginFailed, apperror.MsgFederatedLoginFailed, err)
+	}
+
+	identity.Provider = state.Provider
+
+	return identity, nil
+}
+
+func (s *service) federatedUser(
+	ctx context.Context,
+	identity *domain.ExternalIdentity,
+	req *LoginRequest,
+) (*domain.User, error) {
+	link, err := s.userIdentityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUserIdentity, err)
+	}
+
+	if link != nil {
+		user, getErr := s.userRepo.GetByID(ctx, link.UserID)
+		if getErr != nil {
+			return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, getErr)
+		}
+
+		if user == nil {
+			return nil, apperror.Unauthorized(apperror.ErrCodeFederatedLoginFailed, apperror.MsgFederatedLoginFailed, nil)
+		}
+
+		return user, nil
+	}
+
+	email, err := domain.NewEmail(identity.Email)
+	if err != nil || !identity.EmailVerified {
+		return nil, apperror.UnprocessableEntity(apperror.ErrCodeFederatedLoginFailed, apperror.MsgFederatedProfile, err)
+	}
+
+	// Federated sign-ins only ever match and create global accounts.
+	user, err := s.userByEmail(ctx, nil, email)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUserByEmail, err)
+	}
+
+	if user != nil {
+		// An unverified local account may have been registered by someone who does not own
+		// the address, so linking it would hand them this identity's sign-ins.
+		if !user.IsVerified() {
+			return nil, apperror.Conflict(apperror.ErrCodeAccountLinkRequired, apperror.MsgAccountLinkRequired, nil)
+		}
+
+		if _, err = s.linkIdentity(ctx, user.ID, identity, "verified_email", req.UserAgent, req.ClientIP); err != nil {
+			return nil, err
+		}
+
+		return user, nil
+	}
+
+	return s.registerFederatedUser(ctx, identity, email, req)
+}
+
+func (s *service) registerFederatedUser(
+	ctx context.Context,
+	identity *domain.ExternalIdentity,
+	email domain.Email,
+	req *LoginRequest,
+) (*domain.User, error) {
+	firstName, lastName := identity.Names()
+	if firstName == "" || lastName == "" {
+		return nil, apperror.UnprocessableEntity(apperror.ErrCodeFederatedLoginFailed, apperror.MsgFederatedProfile, nil)
+	}
+
+	hint := identity.PreferredUsername
+	if hint == "" {
+		hint = email.Local()
+	}
+
+	username, err := s.availableUsername(ctx, hint)
+	if err != nil {
+		return nil, err
+	}
+
+	// The account has no usable password until the user sets one through a reset.
+	secret, err := s.opaqueTokenManager.Generate()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	password, err := s.passwordHasher.Hash(secret)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	user, err := domain.NewUser(username, email, password, firstName, lastName)
+	if err != nil {
+		return nil, apperror.UnprocessableEntity(apperror.ErrCodeFederatedLoginFailed, err.Error(), err)
+	}
+
+	if err = user.Verify(); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if saveErr := s.userRepo.Save(ctx, user); saveErr != nil {
+			return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, saveErr)
+		}
+
+		if _, linkErr := s.linkIdentity(ctx, user.ID, identity, "signup", req.UserAgent, req.ClientIP); linkErr != nil {
+			return linkErr
+		}
+
+		return s.publishUserEvents(ctx, user, userCreatedEvents(user)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, userRegisteredEvent(user, identity.Provider))
+
+	return user, nil
+}
+
+func (s *service) availableUsername(ctx context.Context, hint string) (domain.Username, error) {
+	suffix := ""
+
+	for range usernameAttempts {
+		username, err := domain.UsernameFrom(hint, suffix)
+		if err == nil {
+			exists, existsErr := s.usernameTaken(ctx, nil, username)
+			if existsErr != nil {
+				return domain.Username{}, apperror.InternalServerError(
+					apperror.ErrCodeInternalServer,
+					apperror.MsgOperationFailed,
+					existsErr,
+				)
+			}
+
+			if !exists {
+				return username, nil
+			}
+		}
+
+		suffix = fmt.Sprintf("%04d", rand.IntN(10000)) //nolint:gosec // Uniqueness only, not a secret.
+	}
+
+	return domain.Username{}, apperror.Conflict(
+		apperror.ErrCodeUsernameAlreadyUsed,
+		apperror.MsgUsernameAlreadyInUse,
+		nil,
+	)
+}
+
+func (s *service) linkIdentity(
+	ctx context.Context,
+	userID uuid.UUID,
+	identity *domain.ExternalIdentity,
+	method, userAgent, clientIP string,
+) (*domain.UserIdentity, error) {
+	link, err := domain.NewUserIdentity(userID, identity.Provider, identity.Subject, identity.Email)
+	if err != nil {
+		return nil, apperror.Unauthorized(apperror.ErrCodeFederatedLoginFailed, apperror.MsgFederatedLoginFailed, err)
+	}
+
+	if err = s.userIdentityRepo.Save(ctx, link); err != nil {
+		if errors.Is(err, domain.ErrIdentityAlreadyLinked) {
+			return nil, apperror.Conflict(apperror.ErrCodeIdentityAlreadyLinked, apperror.MsgIdentityAlreadyLinked, err)
+		}
+
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveUserIdentity, err)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionIdentityLinked, domain.AuditOutcomeSuccess).
+		WithActor(userID).
+		WithTarget(userID).
+		WithClient(userAgent, clientIP).
+		WithMetadata(map[string]any{"provider": identity.Provider, "method": method}))
+
+	return link, nil
+}
+
+func (s *service) identityProvider(name string) (domain.IdentityProvider, error) {
+	provider, ok := s.identityProviders[name]
+	if !ok {
+		return nil, apperror.NotFound(apperror.ErrCodeProviderNotFound, apperror.MsgProviderNotFound, nil)
+	}
+
+	return provider, nil
+}
+
+func errFederatedStateInvalid() error {
+	return apperror.BadRequest(apperror.ErrCodeFederatedStateInvalid, apperror.MsgFederatedStateInvalid, nil)
+}
+
+func federatedMetadata(provider string, kv ...any) map[string]any {
+	metadata := map[string]any{"method": "federated", "provider": provider}
+
+	for i := 0; i+1 < len(kv); i += 2 {
+		if key, ok := kv[i].(string); ok {
+			metadata[key] = kv[i+1]
+		}
+	}
+
+	return metadata
+}
+
+func toIdentityResponse(link *domain.UserIdentity) *IdentityResponse {
+	return &IdentityResponse{
+		Provider:  link.Provider,
+		Subject:   link.Subject,
+		Email:     link.Email,
+		CreatedAt: link.CreatedAt,
+	}
+}
@@ -0,0 +1,517 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+// The mock opaque token manager hands out "refresh-token" for every state, so callbacks use it too.
+const testFederatedState = "refresh-token"
+
+func newFederatedService(
+	t *testing.T,
+	provider *mockIdentityProvider,
+	deps testDeps,
+) (service.Service, *mockLoginStateRepo, *mockUserIdentityRepo) {
+	t.Helper()
+
+	if deps.LoginStates == nil {
+		deps.LoginStates = &mockLoginStateRepo{}
+	}
+
+	if deps.Identities == nil {
+		deps.Identities = &mockUserIdentityRepo{}
+	}
+
+	deps.IdentityProviders = []domain.IdentityProvider{provider}
+
+	svc, err := newTestServiceWith(deps)
+	require.NoError(t, err)
+
+	return svc, deps.LoginStates, deps.Identities
+}
+
+func mustLoginState(t *testing.T, userID *uuid.UUID, ttl time.Duration) *domain.FederatedLoginState {
+	t.Helper()
+
+	state, err := domain.NewFederatedLoginState(
+		"hashed-"+testFederatedState, "google", "nonce", testCodeVerifier, userID, ttl,
+	)
+	require.NoError(t, err)
+
+	return state
+}
+
+func TestServiceStartFederatedLogin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown provider", func(t *testing.T) {
+		t.Parallel()
+
+		svc, _, _ := newFederatedService(t, &mockIdentityProvider{name: "google"}, testDeps{})
+
+		_, err := svc.StartFederatedLogin(ctx, "github")
+		assertAppErrorCode(t, err, apperror.ErrCodeProviderNotFound)
+	})
+	t.Run("provider unavailable", func(t *testing.T) {
+		t.Parallel()
+
+		provider := &mockIdentityProvider{name: "google", authURLErr: errors.New("discovery failed")}
+		svc, states, _ := newFederatedService(t, provider, testDeps{})
+
+		_, err := svc.StartFederatedLogin(ctx, "google")
+		assertAppErrorCode(t, err, apperror.ErrCodeProviderUnavailable)
+		assert.Nil(t, states.saved)
+	})
+	t.Run("login", func(t *testing.T) {
+		t.Parallel()
+
+		provider := &mockIdentityProvider{name: "google"}
+		svc, states, _ := newFederatedService(t, provider, testDeps{})
+
+		got, err := svc.StartFederatedLogin(ctx, "google")
+		require.NoError(t, err)
+		assert.Equal(t, testFederatedState, got.State)
+		assert.Contains(t, got.AuthURL, "state="+testFederatedState)
+
+		require.NotNil(t, states.saved)
+		assert.Equal(t, "hashed-"+testFederatedState, states.saved.StateHash)
+		assert.Equal(t, "google", states.saved.Provider)
+		assert.False(t, states.saved.IsLink())
+		assert.NoError(t, provider.gotChallenge.Verify(states.saved.CodeVerifier))
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), got.ExpiresAt, time.Minute)
+	})
+	t.Run("link requires a user", func(t *testing.T) {
+		t.Parallel()
+
+		svc, _, _ := newFederatedService(t, &mockIdentityProvider{name: "google"}, testDeps{})
+
+		_, err := svc.StartFederatedLink(ctx, nil, "google")
+		assertAppErrorCode(t, err, apperror.ErrCodeUnauthorized)
+	})
+	t.Run("link refuses stale, delegated and impersonated actors", func(t *testing.T) {
+		t.Parallel()
+
+		svc, states, _ := newFederatedService(t, &mockIdentityProvider{name: "google"}, testDeps{})
+
+		_, err := svc.StartFederatedLink(ctx, mustActor(t, domain.RoleUser), "google")
+		assertAppErrorCode(t, err, apperror.ErrCodeReauthenticationRequired)
+
+		delegated := mustRecentActor(t, domain.RoleUser)
+		delegated.ClientID = "client-1"
+
+		_, err = svc.StartFederatedLink(ctx, delegated, "google")
+		assertAppErrorCode(t, err, apperror.ErrCodeReauthenticationRequired)
+
+		impersonated := mustRecentActor(t, domain.RoleUser)
+		impersonated.ImpersonatorID = uuid.New()
+
+		_, err = svc.StartFederatedLink(ctx, impersonated, "google")
+		assertAppErrorCode(t, err, apperror.ErrCodeImpersonationRestricted)
+		assert.Nil(t, states.saved)
+	})
+	t.Run("link", func(t *testing.T) {
+		t.Parallel()
+
+		svc, states, _ := newFederatedService(t, &mockIdentityProvider{name: "google"}, testDeps{})
+		actor := mustRecentActor(t, domain.RoleUser)
+
+		_, err := svc.StartFederatedLink(ctx, actor, "google")
+		require.NoError(t, err)
+		require.True(t, states.saved.IsLink())
+		assert.Equal(t, actor.UserID, *states.saved.UserID)
+	})
+}
+
+func TestServiceCompleteFederatedLogin(t *testing.T) {
+	ctx := context.Background()
+
+	verifiedIdentity := func() *domain.ExternalIdentity {
+		return &domain.ExternalIdentity{
+			Subject:           "google-sub",
+			Email:             "jane@example.com",
+			EmailVerified:     true,
+			GivenName:         "Jane",
+			FamilyName:        "Doe",
+			PreferredUsername: "jane.doe",
+		}
+	}
+
+	callback := &service.FederatedCallbackRequest{
+		Provider:  "google",
+		Code:      "code",
+		State:     testFederatedState,
+		UserAgent: "Mozilla/5.0",
+		ClientIP:  "203.0.113.7",
+	}
+
+	existing := mustVerifiedUser(t, "jane", "jane@example.com", "hash")
+
+	unverified := mustVerifiedUser(t, "jane", "jane@example.com", "hash")
+	unverified.VerifiedAt = nil
+
+	banned := mustVerifiedUser(t, "jane", "jane@example.com", "hash")
+	require.NoError(t, banned.Ban())
+
+	tests := []struct {
+		name         string
+		req          *service.FederatedCallbackRequest
+		noState      bool
+		state        func(*testing.T) *domain.FederatedLoginState
+		identity     func() *domain.ExternalIdentity
+		exchangeErr  error
+		linked       *domain.UserIdentity
+		users        *mockUserRepo
+		wantCode     apperror.Code
+		wantUser     *domain.User
+		wantNewUser  bool
+		wantLinked   bool
+		wantUsername string
+	}{
+		{name: "nil request", wantCode: apperror.ErrCodeInvalidParam},
+		{
+			name:     "unknown provider",
+			req:      &service.FederatedCallbackRequest{Provider: "github", Code: "c", State: "s", ClientIP: "203.0.113.7"},
+			wantCode: apperror.ErrCodeProviderNotFound,
+		},
+		{
+			name:     "missing code",
+			req:      &service.FederatedCallbackRequest{Provider: "google", State: "s", ClientIP: "203.0.113.7"},
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{name: "unknown state", req: callback, noState: true, wantCode: apperror.ErrCodeFederatedStateInvalid},
+		{
+			name:     "expired state",
+			req:      callback,
+			state:    func(t *testing.T) *domain.FederatedLoginState { return mustLoginState(t, nil, -time.Second) },
+			wantCode: apperror.ErrCodeFederatedStateInvalid,
+		},
+		{
+			name: "link state",
+			req:  callback,
+			state: func(t *testing.T) *domain.FederatedLoginState {
+				userID := uuid.New()
+
+				return mustLoginState(t, &userID, time.Minute)
+			},
+			wantCode: apperror.ErrCodeFederatedStateInvalid,
+		},
+		{
+			name:        "exchange fails",
+			req:         callback,
+			exchangeErr: domain.ErrIDTokenInvalid,
+			wantCode:    apperror.ErrCodeFederatedLoginFailed,
+		},
+		{
+			name:     "linked identity",
+			req:      callback,
+			linked:   &domain.UserIdentity{UserID: existing.ID, Provider: "google", Subject: "google-sub"},
+			users:    &mockUserRepo{getByIDUser: existing},
+			wantUser: existing,
+		},
+		{
+			name:       "verified email links existing account",
+			req:        callback,
+			users:      &mockUserRepo{getByEmailUser: existing},
+			wantUser:   existing,
+			wantLinked: true,
+		},
+		{
+			name:     "unverified local account needs explicit link",
+			req:      callback,
+			users:    &mockUserRepo{getByEmailUser: unverified},
+			wantCode: apperror.ErrCodeAccountLinkRequired,
+		},
+		{
+			name: "unverified provider email",
+			req:  callback,
+			identity: func() *domain.ExternalIdentity {
+				identity := verifiedIdentity()
+				identity.EmailVerified = false
+
+				return identity
+			},
+			users:    &mockUserRepo{getByEmailUser: existing},
+			wantCode: apperror.ErrCodeFederatedLoginFailed,
+		},
+		{
+			name:         "new account",
+			req:          callback,
+			users:        &mockUserRepo{},
+			wantNewUser:  true,
+			wantLinked:   true,
+			wantUsername: "jane_doe",
+		},
+		{
+			name: "new account without names",
+			req:  callback,
+			identity: func() *domain.ExternalIdentity {
+				identity := verifiedIdentity()
+				identity.GivenName, identity.FamilyName = "", ""
+
+				return identity
+			},
+			users:    &mockUserRepo{},
+			wantCode: apperror.ErrCodeFederatedLoginFailed,
+		},
+		{
+			name:     "banned user",
+			req:      callback,
+			linked:   &domain.UserIdentity{UserID: banned.ID, Provider: "google", Subject: "google-sub"},
+			users:    &mockUserRepo{getByIDUser: banned},
+			wantCode: apperror.ErrCodeUserBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			identity := verifiedIdentity
+			if tt.identity != nil {
+				identity = tt.identity
+			}
+
+			provider := &mockIdentityProvider{name: "google", identity: identity(), exchangeErr: tt.exchangeErr}
+
+			states := &mockLoginStateRepo{}
+
+			switch {
+			case tt.noState:
+			case tt.state != nil:
+				require.NoError(t, states.Save(ctx, tt.state(t)))
+			default:
+				require.NoError(t, states.Save(ctx, mustLoginState(t, nil, time.Minute)))
+			}
+
+			identities := &mockUserIdentityRepo{}
+			if tt.linked != nil {
+				identities.identities = []*domain.UserIdentity{tt.linked}
+			}
+
+			users := tt.users
+			if users == nil {
+				users = &mockUserRepo{}
+			}
+
+			auditLog := &mockAuditLogger{}
+			sessions := &mockSessionRepo{}
+			svc, _, _ := newFederatedService(t, provider, testDeps{
+				UserRepo:    users,
+				SessionRepo: sessions,
+				AuditLogger: auditLog,
+				Identities:  identities,
+				LoginStates: states,
+			})
+
+			got, err := svc.CompleteFederatedLogin(ctx, tt.req)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+				assert.Nil(t, sessions.savedSession)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "nonce", provider.gotNonce)
+			assert.Equal(t, testCodeVerifier, provider.gotVerifier)
+			assert.Empty(t, states.states, "state must be single use")
+			require.NotNil(t, sessions.savedSession)
+
+			user := tt.wantUser
+			if tt.wantNewUser {
+				require.NotNil(t, users.savedUser)
+				user = users.savedUser
+				assert.True(t, user.IsVerified())
+				assert.Equal(t, tt.wantUsername, user.Username.String())
+				assert.Equal(t, "Jane", user.FirstName)
+			} else {
+				assert.Nil(t, users.savedUser)
+			}
+
+			assert.Equal(t, user.ID, got.UserID)
+
+			wantActions := []domain.AuditAction{domain.AuditActionLogin}
+			if tt.wantLinked {
+				require.Len(t, identities.saved, 1)
+				assert.Equal(t, user.ID, identities.saved[0].UserID)
+				assert.Equal(t, "google-sub", identities.saved[0].Subject)
+
+				wantActions = []domain.AuditAction{domain.AuditActionIdentityLinked, domain.AuditActionLogin}
+			} else {
+				assert.Empty(t, identities.saved)
+			}
+
+			assert.Equal(t, wantActions, auditLog.actions())
+			assert.Equal(t, "federated", auditLog.last().Metadata["method"])
+		})
+	}
+}
+
+func TestServiceCompleteFederatedLoginMFA(t *testing.T) {
+	ctx := context.Background()
+	user := mustEmailOTPUser(t)
+
+	provider := &mockIdentityProvider{
+		name:     "google",
+		identity: &domain.ExternalIdentity{Subject: "google-sub", Email: "alice@example.com", EmailVerified: true},
+	}
+
+	states := &mockLoginStateRepo{}
+	require.NoError(t, states.Save(ctx, mustLoginState(t, nil, time.Minute)))
+
+	sessions := &mockSessionRepo{}
+	tokens := &mockTokenRepo{}
+	svc, _, _ := newFederatedService(t, provider, testDeps{
+		UserRepo:    &mockUserRepo{getByIDUser: user},
+		SessionRepo: sessions,
+		Tokens:      tokens,
+		Mailer:      newMockMailer(),
+		Identities: &mockUserIdentityRepo{identities: []*domain.UserIdentity{
+			{UserID: user.ID, Provider: "google", Subject: "google-sub"},
+		}},
+		LoginStates: states,
+		EmailOTP:    true,
+	})
+
+	got, err := svc.CompleteFederatedLogin(ctx, &service.FederatedCallbackRequest{
+		Provider: "google",
+		Code:     "code",
+		State:    testFederatedState,
+		ClientIP: "203.0.113.7",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, got.MFA)
+	assert.Equal(t, domain.MFAFactorEmailOTP, got.MFA.Factor)
+	assert.Empty(t, got.AccessToken)
+	assert.Nil(t, sessions.savedSession)
+	require.NotNil(t, tokens.saved)
+	assert.Equal(t, domain.TokenTypeEmailOTP, tokens.saved.Type)
+}
+
+func TestServiceCompleteFederatedLink(t *testing.T) {
+	ctx := context.Background()
+	actor := mustRecentActor(t, domain.RoleUser)
+	otherUser := uuid.New()
+
+	stale := *actor
+	stale.AuthTime = time.Time{}
+
+	delegated := *actor
+	delegated.APIKeyID = uuid.New()
+
+	impersonated := *actor
+	impersonated.ImpersonatorID = uuid.New()
+
+	callback := &service.FederatedCallbackRequest{Provider: "google", Code: "code", State: testFederatedState}
+
+	tests := []struct {
+		name       string
+		actor      *domain.AccessClaims
+		stateUser  *uuid.UUID
+		linked     *domain.UserIdentity
+		wantCode   apperror.Code
+		wantLinked bool
+	}{
+		{name: "unauthenticated", stateUser: &actor.UserID, wantCode: apperror.ErrCodeUnauthorized},
+		{
+			name:      "stale authentication",
+			actor:     &stale,
+			stateUser: &actor.UserID,
+			wantCode:  apperror.ErrCodeReauthenticationRequired,
+		},
+		{
+			name:      "delegated actor",
+			actor:     &delegated,
+			stateUser: &actor.UserID,
+			wantCode:  apperror.ErrCodeReauthenticationRequired,
+		},
+		{
+			name:      "impersonated actor",
+			actor:     &impersonated,
+			stateUser: &actor.UserID,
+			wantCode:  apperror.ErrCodeImpersonationRestricted,
+		},
+		{name: "login state", actor: actor, wantCode: apperror.ErrCodeFederatedStateInvalid},
+		{
+			name:      "started by another user",
+			actor:     actor,
+			stateUser: &otherUser,
+			wantCode:  apperror.ErrCodeFederatedStateInvalid,
+		},
+		{
+			name:      "identity linked to another user",
+			actor:     actor,
+			stateUser: &actor.UserID,
+			linked:    &domain.UserIdentity{UserID: otherUser, Provider: "google", Subject: "google-sub"},
+			wantCode:  apperror.ErrCodeIdentityAlreadyLinked,
+		},
+		{
+			name:      "already linked to this user",
+			actor:     actor,
+			stateUser: &actor.UserID,
+			linked:    &domain.UserIdentity{UserID: actor.UserID, Provider: "google", Subject: "google-sub"},
+		},
+		{name: "links identity", actor: actor, stateUser: &actor.UserID, wantLinked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &mockIdentityProvider{
+				name:     "google",
+				identity: &domain.ExternalIdentity{Subject: "google-sub", Email: "jane@other.example"},
+			}
+
+			states := &mockLoginStateRepo{}
+			require.NoError(t, states.Save(ctx, mustLoginState(t, tt.stateUser, time.Minute)))
+
+			identities := &mockUserIdentityRepo{}
+			if tt.linked != nil {
+				identities.identities = []*domain.UserIdentity{tt.linked}
+			}
+
+			auditLog := &mockAuditLogger{}
+			svc, _, _ := newFederatedService(t, provider, testDeps{
+				AuditLogger: auditLog,
+				Identities:  identities,
+				LoginStates: states,
+			})
+
+			got, err := svc.CompleteFederatedLink(ctx, tt.actor, callback)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+				assert.Empty(t, identities.saved)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "google", got.Provider)
+			assert.Equal(t, "google-sub", got.Subject)
+
+			if !tt.wantLinked {
+				assert.Empty(t, identities.saved)
+				assert.Empty(t, auditLog.actions())
+
+				return
+			}
+
+			require.Len(t, identities.saved, 1)
+			assert.Equal(t, actor.UserID, identities.saved[0].UserID)
+			assert.Equal(t, "jane@other.example", got.Email)
+			assert.Equal(t, []domain.AuditAction{domain.AuditActionIdentityLinked}, auditLog.actions())
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+func TestServiceImpersonate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("issues a marked short-lived token", func(t *testing.T) {
+		t.Parallel()
+
+		superadmin := mustActor(t, domain.RoleSuperAdmin)
+		target := mustUserWithRole(t, domain.RoleUser)
+		access := &mockAccessTokenManager{generateToken: "impersonated"}
+		sessions := &mockSessionRepo{}
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:    &mockUserRepo{getByIDUser: target},
+			SessionRepo: sessions,
+			Access:      access,
+			AuditLogger: auditLog,
+		})
+		require.NoError(t, err)
+
+		res, err := svc.Impersonate(ctx, superadmin, &service.ImpersonateRequest{
+			UserID:   target.ID,
+			Reason:   "ticket 4711",
+			ClientIP: "198.51.100.10",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, target.ID, res.UserID)
+		assert.Equal(t, "impersonated", res.AccessToken)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), res.ExpiresAt, time.Second)
+		assert.Nil(t, sessions.savedSession)
+
+		claims := access.lastClaims
+		assert.Equal(t, target.ID, claims.UserID)
+		assert.Equal(t, superadmin.UserID, claims.ImpersonatorID)
+		assert.Equal(t, res.ExpiresAt, claims.ExpiresAt)
+		assert.True(t, claims.AuthTime.IsZero())
+
+		event := auditLog.last()
+		assert.Equal(t, domain.AuditActionImpersonate, event.Action)
+		assert.Equal(t, superadmin.UserID, *event.ActorID)
+		assert.Equal(t, target.ID, *event.TargetID)
+		assert.Equal(t, "ticket 4711", event.Metadata["reason"])
+
+		impersonated := &claims
+
+		_, err = svc.Impersonate(ctx, impersonated, &service.ImpersonateRequest{UserID: uuid.New()})
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+
+		err = svc.SetMFAFactor(ctx, impersonated, &service.SetMFAFactorRequest{})
+		assertAppErrorCode(t, err, apperror.ErrCodeImpersonationRestricted)
+
+		_, err = svc.Reauthenticate(ctx, impersonated, &service.ReauthenticateRequest{Password: "secret"})
+		assertAppErrorCode(t, err, apperror.ErrCodeImpersonationRestricted)
+	})
+
+	tests := []struct {
+		name        string
+		actor       func(t *testing.T) *domain.AccessClaims
+		target      *domain.User
+		wantCode    apperror.Code
+		wantOutcome domain.AuditOutcome
+	}{
+		{
+			name:        "admin lacks user:impersonate",
+			actor:       func(t *testing.T) *domain.AccessClaims { return mustActor(t, domain.RoleAdmin) },
+			target:      mustUserWithRole(t, domain.RoleUser),
+			wantCode:    apperror.ErrCodePermissionDenied,
+			wantOutcome: domain.AuditOutcomeDenied,
+		},
+		{
+			name:     "superadmin target",
+			actor:    func(t *testing.T) *domain.AccessClaims { return mustActor(t, domain.RoleSuperAdmin) },
+			target:   mustUserWithRole(t, domain.RoleSuperAdmin),
+			wantCode: apperror.ErrCodeTargetProtected,
+		},
+		{
+			name: "impersonated superadmin",
+			actor: func(t *testing.T) *domain.AccessClaims {
+				actor := mustActor(t, domain.RoleSuperAdmin)
+				actor.ImpersonatorID = uuid.New()
+
+				return actor
+			},
+			target:      mustUserWithRole(t, domain.RoleUser),
+			wantCode:    apperror.ErrCodeImpersonationRestricted,
+			wantOutcome: domain.AuditOutcomeDenied,
+		},
+		{
+			name: "token issued to an application",
+			actor: func(t *testing.T) *domain.AccessClaims {
+				actor := mustActor(t, domain.RoleSuperAdmin)
+				actor.ClientID = "app"
+				actor.Scopes = []string{domain.PermUserImpersonate.String()}
+
+				return actor
+			},
+			target:      mustUserWithRole(t, domain.RoleUser),
+			wantCode:    apperror.ErrCodeImpersonationRestricted,
+			wantOutcome: domain.AuditOutcomeDenied,
+		},
+		{
+			name:  "banned target",
+			actor: func(t *testing.T) *domain.AccessClaims { return mustActor(t, domain.RoleSuperAdmin) },
+			target: func() *domain.User {
+				user := mustUserWithRole(t, domain.RoleUser)
+				require.NoError(t, user.Ban())
+
+				return user
+			}(),
+			wantCode: apperror.ErrCodeUserBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			auditLog := &mockAuditLogger{}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:    &mockUserRepo{getByIDUser: tt.target},
+				AuditLogger: auditLog,
+			})
+			require.NoError(t, err)
+
+			_, err = svc.Impersonate(ctx, tt.actor(t), &service.ImpersonateRequest{UserID: tt.target.ID})
+			assertAppErrorCode(t, err, tt.wantCode)
+
+			if tt.wantOutcome != "" {
+				require.NotNil(t, auditLog.last())
+				assert.Equal(t, tt.wantOutcome, auditLog.last().Outcome)
+			}
+		})
+	}
+
+	t.Run("nil request", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.Impersonate(ctx, mustActor(t, domain.RoleSuperAdmin), nil)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+	})
+}
+
+func TestServiceImpersonatedActorCannotCreateCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	// A recent AuthTime shows the refusal does not depend on the step-up window.
+	impersonated := mustRecentActor(t, domain.RoleUser)
+	impersonated.ImpersonatorID = uuid.New()
+
+	tests := []struct {
+		name string
+		call func(svc service.Service) error
+	}{
+		{
+			name: "oauth consent",
+			call: func(svc service.Service) error {
+				_, err := svc.Authorize(ctx, impersonated, validAuthorizeReq("client-1"))
+
+				return err
+			},
+		},
+		{
+			name: "api key",
+			call: func(svc service.Service) error {
+				_, err := svc.CreateAPIKey(ctx, impersonated, &service.CreateAPIKeyRequest{Name: "ci"})
+
+				return err
+			},
+		},
+		{
+			name: "begin passkey registration",
+			call: func(svc service.Service) error {
+				_, err := svc.BeginPasskeyRegistration(ctx, impersonated)
+
+				return err
+			},
+		},
+		{
+			name: "finish passkey registration",
+			call: func(svc service.Service) error {
+				_, err := svc.FinishPasskeyRegistration(ctx, impersonated, &service.FinishPasskeyRegistrationRequest{
+					Name:        "laptop",
+					Attestation: &domain.PasskeyAttestation{},
+				})
+
+				return err
+			},
+		},
+		{
+			name: "delete passkey",
+			call: func(svc service.Service) error {
+				return svc.DeletePasskey(ctx, impersonated, uuid.New())
+			},
+		},
+		{
+			name: "start federated link",
+			call: func(svc service.Service) error {
+				_, err := svc.StartFederatedLink(ctx, impersonated, "google")
+
+				return err
+			},
+		},
+		{
+			name: "complete federated link",
+			call: func(svc service.Service) error {
+				_, err := svc.CompleteFederatedLink(ctx, impersonated, &service.FederatedCallbackRequest{
+					Provider: "google",
+					Code:     "code",
+					State:    testFederatedState,
+				})
+
+				return err
+			},
+		},
+		{
+			name: "mfa factor",
+			call: func(svc service.Service) error {
+				return svc.SetMFAFactor(ctx, impersonated, &service.SetMFAFactorRequest{})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, err := newTestServiceWith(testDeps{
+				OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+					"client-1": mustOAuthClient(t, "client-1", false),
+				}},
+				APIKeys:         &mockAPIKeyRepo{},
+				PasskeyVerifier: newTestRelyingParty(t),
+				Passkeys:        &mockPasskeyRepo{},
+				Challenges:      &mockPasskeyChallengeRepo{},
+			})
+			require.NoError(t, err)
+
+			assertAppErrorCode(t, tt.call(svc), apperror.ErrCodeImpersonationRestricted)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+type IntrospectRequest struct {
+	ClientID      string
+	ClientSecret  string
+	Token         string
+	TokenTypeHint string
+}
+
+type IntrospectResponse struct {
+	Active    bool
+	TokenType string
+	Subject   string
+	Actor     string
+	ClientID  string
+	Scopes    []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Introspect reports whether an access or refresh token is currently active. Only
+// confidential clients may introspect. Access tokens are self-contained, so one stays
+// active until it expires even if its session was revoked.
+func (s *service) Introspect(ctx context.Context, req *IntrospectRequest) (*IntrospectResponse, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgIntrospectRequired, nil)
+	}
+
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsPublic() {
+		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidClient, apperror.MsgPublicClientIntrospect, nil)
+	}
+
+	if req.Token == "" {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgTokenParamRequired, nil)
+	}
+
+	if req.TokenTypeHint == domain.TokenTypeRefreshToken {
+		res, err := s.introspectRefreshToken(ctx, req.Token)
+		if err != nil || res.Active {
+			return res, err
+		}
+
+		return s.introspectAccessToken(req.Token), nil
+	}
+
+	if res := s.introspectAccessToken(req.Token); res.Active {
+		return res, nil
+	}
+
+	return s.introspectRefreshToken(ctx, req.Token)
+}
+
+func (s *service) introspectAccessToken(token string) *IntrospectResponse {
+	claims, err := s.accessTokenManager.Validate(token, s.tokenAudience...)
+	if err != nil || claims == nil {
+		return &IntrospectResponse{}
+	}
+
+	res := &IntrospectResponse{
+		Active:    true,
+		TokenType: domain.TokenTypeAccessToken,
+		Subject:   claims.Subject(),
+		ClientID:  claims.ClientID,
+		Scopes:    slices.Clone(claims.Scopes),
+		IssuedAt:  claims.IssuedAt,
+		ExpiresAt: claims.ExpiresAt,
+	}
+
+	if claims.IsImpersonated() {
+		res.Actor = claims.ImpersonatorID.String()
+	}
+
+	return res
+}
+
+func (s *service) introspectRefreshToken(ctx context.Context, token string) (*IntrospectResponse, error) {
+	session, err := s.sessionByRefreshToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil || !session.IsActive() || session.CheckLimits(s.sessionLimits) != nil {
+		return &IntrospectResponse{}, nil
+	}
+
+	return &IntrospectResponse{
+		Active:    true,
+		TokenType: domain.TokenTypeRefreshToken,
+		Subject:   session.UserID.String(),
+		ClientID:  session.ClientID,
+		Scopes:    slices.Clone(session.Scopes),
+		IssuedAt:  session.CreatedAt,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func (s *service) sessionByRefreshToken(ctx context.Context, token string) (*domain.Session, error) {
+	tokenHash, err := s.opaqueTokenManager.Hash(token)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgHashRefreshToken, err)
+	}
+
+	session, err := s.sessionRepo.GetByToken(ctx, tokenHash)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetSession, err)
+	}
+
+	return session, nil
+}
@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+func TestServiceIntrospect(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	expiresAt := time.Now().UTC().Add(time.Minute).Truncate(time.Second)
+
+	accessClaims := &domain.AccessClaims{
+		UserID:    userID,
+		ClientID:  "app",
+		Scopes:    []string{"profile"},
+		ExpiresAt: expiresAt,
+	}
+
+	clientSession := func(t *testing.T, revoked bool) *domain.Session {
+		t.Helper()
+
+		session := mustSession(t, userID, time.Hour, revoked)
+		session.ClientID = "app"
+		session.Scopes = []string{"profile", "user:read"}
+
+		return session
+	}
+
+	introspect := func(token, hint string) *service.IntrospectRequest {
+		return &service.IntrospectRequest{
+			ClientID:      "resource-server",
+			ClientSecret:  testClientSecret,
+			Token:         token,
+			TokenTypeHint: hint,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		req        *service.IntrospectRequest
+		public     bool
+		access     *domain.AccessClaims
+		session    func(*testing.T) *domain.Session
+		wantCode   apperror.Code
+		wantActive bool
+		wantType   string
+		wantScopes []string
+	}{
+		{name: "nil request", wantCode: apperror.ErrCodeInvalidParam},
+		{
+			name:     "wrong secret",
+			req:      &service.IntrospectRequest{ClientID: "resource-server", ClientSecret: "wrong", Token: "t"},
+			wantCode: apperror.ErrCodeInvalidClient,
+		},
+		{
+			name:     "public client",
+			req:      &service.IntrospectRequest{ClientID: "resource-server", Token: "t"},
+			public:   true,
+			wantCode: apperror.ErrCodeInvalidClient,
+		},
+		{name: "missing token", req: introspect("", ""), wantCode: apperror.ErrCodeInvalidParam},
+		{
+			name:       "access token",
+			req:        introspect("jwt", ""),
+			access:     accessClaims,
+			wantActive: true,
+			wantType:   domain.TokenTypeAccessToken,
+			wantScopes: []string{"profile"},
+		},
+		{
+			name:       "refresh token",
+			req:        introspect("refresh-token", domain.TokenTypeRefreshToken),
+			session:    func(t *testing.T) *domain.Session { return clientSession(t, false) },
+			wantActive: true,
+			wantType:   domain.TokenTypeRefreshToken,
+			wantScopes: []string{"profile", "user:read"},
+		},
+		{
+			name:       "refresh token without hint",
+			req:        introspect("refresh-token", ""),
+			session:    func(t *testing.T) *domain.Session { return clientSession(t, false) },
+			wantActive: true,
+			wantType:   domain.TokenTypeRefreshToken,
+			wantScopes: []string{"profile", "user:read"},
+		},
+		{
+			name:       "access token with wrong hint",
+			req:        introspect("jwt", domain.TokenTypeRefreshToken),
+			access:     accessClaims,
+			wantActive: true,
+			wantType:   domain.TokenTypeAccessToken,
+			wantScopes: []string{"profile"},
+		},
+		{
+			name:    "revoked refresh token",
+			req:     introspect("refresh-token", ""),
+			session: func(t *testing.T) *domain.Session { return clientSession(t, true) },
+		},
+		{name: "unknown token", req: introspect("garbage", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sessions := &mockSessionRepo{}
+			if tt.session != nil {
+				sessions.getByToken = tt.session(t)
+			}
+
+			svc, err := newTestServiceWith(testDeps{
+				SessionRepo: sessions,
+				OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+					"resource-server": mustOAuthClient(t, "resource-server", tt.public),
+				}},
+				Access: &mockAccessTokenManager{validateClaims: tt.access},
+			})
+			require.NoError(t, err)
+
+			got, err := svc.Introspect(ctx, tt.req)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if !tt.wantActive {
+				assert.Equal(t, &service.IntrospectResponse{}, got)
+
+				return
+			}
+
+			assert.True(t, got.Active)
+			assert.Equal(t, tt.wantType, got.TokenType)
+			assert.Equal(t, userID.String(), got.Subject)
+			assert.Equal(t, "app", got.ClientID)
+			assert.Equal(t, tt.wantScopes, got.Scopes)
+			assert.False(t, got.ExpiresAt.IsZero())
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+const defaultInvitationTTL = 7 * 24 * time.Hour
+
+type InviteRequest struct {
+	Organization string
+	Email        string
+	Role         string
+	UserAgent    string
+	ClientIP     string
+}
+
+type InvitationActionRequest struct {
+	Organization string
+	InvitationID uuid.UUID
+	UserAgent    string
+	ClientIP     string
+}
+
+type AcceptInvitationRequest struct {
+	Token     string
+	UserAgent string
+	ClientIP  string
+}
+
+type InvitationResponse struct {
+	ID         uuid.UUID
+	Email      string
+	Role       string
+	Status     string
+	InviterID  uuid.UUID
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// InviteToOrganization emails an invitation to join the organisation. The secret in it is
+// never returned to the caller, so only the owner of the email address can redeem it.
+func (s *service) InviteToOrganization(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *InviteRequest,
+) (*InvitationResponse, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgInvitationRequired, nil)
+	}
+
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	organization, err := s.invitingOrganization(
+		ctx, actor, req.Organization, domain.AuditActionInviteCreated, req.UserAgent, req.ClientIP,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	email, err := domain.NewEmail(req.Email)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	role, err := domain.NewRole(req.Role)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	raw, hash, err := s.generateOpaque()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateInvitation, err)
+	}
+
+	invitation, err := domain.NewInvitation(
+		organization.ID, actor.UserID, email, role, hash, time.Now().UTC().Add(s.invitationTTL),
+	)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateInvitation, err)
+	}
+
+	if err = s.invitationRepo.Save(ctx, invitation); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveInvitation, err)
+	}
+
+	go s.sendInvitation(context.WithoutCancel(ctx), organization, invitation, s.invitationLinkFor(raw))
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionInviteCreated, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(invitationMetadata(invitation)))
+
+	return toInvitationResponse(invitation), nil
+}
+
+func (s *service) ListInvitations(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	organization string,
+) ([]*InvitationResponse, error) {
+	if err := s.checkInvitations(); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(actor, domain.PermOrganizationManage); err != nil {
+		return nil, err
+	}
+
+	org, err := s.getOrganization(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.ListByOrganization(ctx, org.ID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListInvitations, err)
+	}
+
+	out := make([]*InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		out[i] = toInvitationResponse(invitation)
+	}
+
+	return out, nil
+}
+
+func (s *service) RevokeInvitation(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *InvitationActionRequest,
+) error {
+	if req == nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgInvitationRequired, nil)
+	}
+
+	organization, err := s.invitingOrganization(
+		ctx, actor, req.Organization, domain.AuditActionInviteRevoked, req.UserAgent, req.ClientIP,
+	)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.GetByID(ctx, req.InvitationID)
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetInvitation, err)
+	}
+
+	if invitation == nil || invitation.OrganizationID != organization.ID {
+		return apperror.NotFound(apperror.ErrCodeInvitationNotFound, apperror.MsgInvitationNotFound, nil)
+	}
+
+	if err = invitation.Revoke(); err != nil {
+		return errInvitationNotPending(err)
+	}
+
+	if err = s.invitationRepo.Revoke(ctx, invitation); err != nil {
+		if errors.Is(err, domain.ErrInvitationNotPending) {
+			return errInvitationNotPending(err)
+		}
+
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgRevokeInvitation, err)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionInviteRevoked, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(invitationMetadata(invitation)))
+
+	return nil
+}
+
+// AcceptInvitation adds the caller to the inviting organisation with the invited role. The
+// invitation must have been sent to the caller's email address.
+func (s *service) AcceptInvitation(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *AcceptInvitationRequest,
+) (*OrganizationMembershipResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgInvitationRequired, nil)
+	}
+
+	if err := s.checkInvitations(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil {
+		return nil, apperror.NotFound(apperror.ErrCodeUserNotFound, apperror.MsgUserNotFound, nil)
+	}
+
+	invitation, err := s.pendingInvitation(ctx, req.Token, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	organization, err := s.organizationRepo.GetByID(ctx, invitation.OrganizationID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetOrganization, err)
+	}
+
+	if organization == nil {
+		return nil, errInvitationInvalid()
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if acceptErr := s.acceptInvitation(ctx, invitation, user.ID); acceptErr != nil {
+			return acceptErr
+		}
+
+		return s.joinOrganization(ctx, organization.ID, user.ID, invitation.Role)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, invitationAcceptedEvent(invitation).WithClient(req.UserAgent, req.ClientIP))
+
+	return &OrganizationMembershipResponse{
+		ID:       organization.ID,
+		Slug:     organization.Slug,
+		Name:     organization.Name,
+		Role:     invitation.Role.String(),
+		JoinedAt: *invitation.AcceptedAt,
+		Active:   organization.ID == actor.OrganizationID,
+	}, nil
+}
+
+func (s *service) invitingOrganization(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	slug string,
+	action domain.AuditAction,
+	userAgent, clientIP string,
+) (*domain.Organization, error) {
+	if err := s.checkInvitations(); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(actor, domain.PermOrganizationManage); err != nil {
+		s.audit(ctx, domain.NewAuditEvent(action, domain.AuditOutcomeDenied).
+			WithActorClaims(actor).
+			WithClient(userAgent, clientIP))
+
+		return nil, err
+	}
+
+	return s.getOrganization(ctx, slug)
+}
+
+func (s *service) pendingInvitation(ctx context.Context, raw string, email domain.Email) (*domain.Invitation, error) {
+	if err := s.checkInvitations(); err != nil {
+		return nil, err
+	}
+
+	if raw == "" {
+		return nil, apperror.BadRequest(apperror.ErrCodeTokenRequired, apperror.MsgTokenParamRequired, nil)
+	}
+
+	hash, err := s.opaqueTokenManager.Hash(raw)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	invitation, err := s.invitationRepo.GetByToken(ctx, hash)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetInvitation, err)
+	}
+
+	if invitation == nil || invitation.Status() != domain.InvitationStatusPending {
+		return nil, errInvitationInvalid()
+	}
+
+	if invitation.Email != email {
+		return nil, apperror.Forbidden(
+			apperror.ErrCodeInvitationEmailMismatch,
+			apperror.MsgInvitationEmailMismatch,
+			nil,
+		)
+	}
+
+	return invitation, nil
+}
+
+func (s *service) acceptInvitation(ctx context.Context, invitation *domain.Invitation, userID uuid.UUID) error {
+	if err := invitation.Accept(userID); err != nil {
+		return errInvitationInvalid()
+	}
+
+	if err := s.invitationRepo.Accept(ctx, invitation); err != nil {
+		if errors.Is(err, domain.ErrInvitationNotPending) {
+			return errInvitationInvalid()
+		}
+
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgAcceptInvitation, err)
+	}
+
+	return nil
+}
+
+func (s *service) checkInvitations() error {
+	if s.invitationURL == nil {
+		return apperror.NotImplemented(apperror.ErrCodeInvitationsDisabled, apperror.MsgInvitationsDisabled, nil)
+	}
+
+	return nil
+}
+
+func (s *service) invitationLinkFor(token string) string {
+	link := *s.invitationURL
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
+
+func (s *service) sendInvitation(
+	ctx context.Context,
+	organization *domain.Organization,
+	invitation *domain.Invitation,
+	link string,
+) {
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	_ = s.mailer.Send(ctx, &domain.EmailMessage{
+		To:      invitation.Email,
+		Subject: "You have been invited to join " + organization.Name,
+		Body: fmt.Sprintf(
+			"Hi,\n\n"+
+				"You have been invited to join %s. Use the link below to accept the invitation; "+
+				"it can be used once and expires at %s.\n\n"+
+				"%s\n\n"+
+				"If you were not expecting this invitation, you can ignore this email.\n",
+			organization.Name, invitation.ExpiresAt.Format(time.RFC1123), link,
+		),
+	})
+}
+
+func invitationAcceptedEvent(invitation *domain.Invitation) *domain.AuditEvent {
+	return domain.NewAuditEvent(domain.AuditActionInviteAccepted, domain.AuditOutcomeSuccess).
+		WithActor(*invitation.AcceptedBy).
+		WithTarget(*invitation.AcceptedBy).
+		WithMetadata(invitationMetadata(invitation))
+}
+
+func invitationMetadata(invitation *domain.Invitation) map[string]any {
+	return map[string]any{
+		"invitation_id":   invitation.ID.String(),
+		"organization_id": invitation.OrganizationID.String(),
+		"email":           invitation.Email.String(),
+		"role":            invitation.Role.String(),
+	}
+}
+
+func toInvitationResponse(invitation *domain.Invitation) *InvitationResponse {
+	return &InvitationResponse{
+		ID:         invitation.ID,
+		Email:      invitation.Email.String(),
+		Role:       invitation.Role.String(),
+		Status:     invitation.Status().String(),
+		InviterID:  invitation.InviterID,
+		ExpiresAt:  invitation.ExpiresAt,
+		AcceptedAt: invitation.AcceptedAt,
+		CreatedAt:  invitation.CreatedAt,
+	}
+}
+
+func errInvitationInvalid() error {
+	return apperror.BadRequest(apperror.ErrCodeInvitationInvalid, apperror.MsgInvitationInvalid, nil)
+}
+
+func errInvitationNotPending(err error) error {
+	return apperror.Conflict(apperror.ErrCodeInvitationNotPending, apperror.MsgInvitationNotPending, err)
+}
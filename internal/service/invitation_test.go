@@ -0,0 +1,428 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+const testInvitationURL = "https://app.example.com/invitations"
+
+func mustPendingInvitation(
+	t *testing.T,
+	repo *mockInvitationRepo,
+	organizationID uuid.UUID,
+	email, raw string,
+) *domain.Invitation {
+	t.Helper()
+
+	em, err := domain.NewEmail(email)
+	require.NoError(t, err)
+
+	role, err := domain.NewRole(domain.RoleAdmin)
+	require.NoError(t, err)
+
+	invitation, err := domain.NewInvitation(organizationID, uuid.New(), em, role, "hashed-"+raw, farFutureExpiry)
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), invitation))
+
+	return invitation
+}
+
+func TestServiceInviteToOrganization(t *testing.T) {
+	ctx := context.Background()
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+
+	t.Run("emails the secret and stores its hash", func(t *testing.T) {
+		t.Parallel()
+
+		organizations := &mockOrganizationRepo{}
+		acme := mustOrganization(t, organizations, "acme")
+		invitations := &mockInvitationRepo{}
+		mailer := newMockMailer()
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			Organizations: organizations,
+			Invitations:   invitations,
+			Mailer:        mailer,
+			AuditLogger:   auditLog,
+			Opaque:        &mockOpaqueTokenManager{generateToken: "invite-secret"},
+			InvitationURL: testInvitationURL,
+		})
+		require.NoError(t, err)
+
+		res, err := svc.InviteToOrganization(ctx, superadmin, &service.InviteRequest{
+			Organization: "acme",
+			Email:        "Bob@Example.com",
+			Role:         domain.RoleAdmin,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "bob@example.com", res.Email)
+		assert.Equal(t, domain.InvitationStatusPending.String(), res.Status)
+		assert.Equal(t, superadmin.UserID, res.InviterID)
+
+		require.Len(t, invitations.invitations, 1)
+		assert.Equal(t, acme.ID, invitations.invitations[0].OrganizationID)
+		assert.Equal(t, "hashed-invite-secret", invitations.invitations[0].Token)
+		assert.Equal(t, domain.AuditActionInviteCreated, auditLog.last().Action)
+
+		select {
+		case msg := <-mailer.sent:
+			assert.Equal(t, "bob@example.com", msg.To.String())
+			assert.Contains(t, msg.Body, testInvitationURL+"?token=invite-secret")
+		case <-time.After(time.Second):
+			t.Fatal("invitation email not sent")
+		}
+	})
+	t.Run("admin forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			Organizations: &mockOrganizationRepo{},
+			Invitations:   &mockInvitationRepo{},
+			Mailer:        newMockMailer(),
+			AuditLogger:   auditLog,
+			InvitationURL: testInvitationURL,
+		})
+		require.NoError(t, err)
+
+		req := &service.InviteRequest{Organization: "acme", Email: "bob@example.com", Role: domain.RoleUser}
+		_, err = svc.InviteToOrganization(ctx, mustActor(t, domain.RoleAdmin), req)
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+		assert.Equal(t, domain.AuditOutcomeDenied, auditLog.last().Outcome)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{Organizations: &mockOrganizationRepo{}})
+		require.NoError(t, err)
+
+		req := &service.InviteRequest{Organization: "acme", Email: "bob@example.com", Role: domain.RoleUser}
+		_, err = svc.InviteToOrganization(ctx, superadmin, req)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvitationsDisabled)
+
+		_, err = svc.AcceptInvitation(ctx, superadmin, &service.AcceptInvitationRequest{Token: "secret"})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvitationsDisabled)
+
+		regReq := *validRegisterReq
+		regReq.Invitation = "secret"
+		_, err = svc.Register(ctx, &regReq)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvitationsDisabled)
+	})
+	t.Run("requires its repositories", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestServiceWith(testDeps{Mailer: newMockMailer(), InvitationURL: testInvitationURL})
+		require.Error(t, err)
+	})
+}
+
+func TestServiceAcceptInvitation(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func(t *testing.T, user *domain.User) (service.Service, *mockOrganizationRepo, *mockInvitationRepo) {
+		t.Helper()
+
+		organizations := &mockOrganizationRepo{}
+		invitations := &mockInvitationRepo{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      &mockUserRepo{getByIDUser: user},
+			Organizations: organizations,
+			Invitations:   invitations,
+			Mailer:        newMockMailer(),
+			InvitationURL: testInvitationURL,
+		})
+		require.NoError(t, err)
+
+		return svc, organizations, invitations
+	}
+
+	t.Run("joins with the invited role once", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "bob", "bob@example.com", "$hash")
+		svc, organizations, invitations := newService(t, user)
+		acme := mustOrganization(t, organizations, "acme")
+		mustPendingInvitation(t, invitations, acme.ID, "bob@example.com", "secret")
+
+		actor := &domain.AccessClaims{UserID: user.ID}
+		req := &service.AcceptInvitationRequest{Token: "secret"}
+
+		res, err := svc.AcceptInvitation(ctx, actor, req)
+		require.NoError(t, err)
+		assert.Equal(t, "acme", res.Slug)
+		assert.Equal(t, domain.RoleAdmin, res.Role)
+
+		member, err := organizations.GetMember(ctx, acme.ID, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, member)
+		assert.Equal(t, domain.RoleAdmin, member.Role.String())
+
+		_, err = svc.AcceptInvitation(ctx, actor, req)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvitationInvalid)
+	})
+	t.Run("accepts and joins in one transaction", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "bob", "bob@example.com", "$hash")
+		organizations := &mockOrganizationRepo{}
+		acme := mustOrganization(t, organizations, "acme")
+		invitations := &mockInvitationRepo{}
+		mustPendingInvitation(t, invitations, acme.ID, "bob@example.com", "secret")
+		transactor := &mockTransactor{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      &mockUserRepo{getByIDUser: user},
+			Organizations: organizations,
+			Invitations:   invitations,
+			Mailer:        newMockMailer(),
+			InvitationURL: testInvitationURL,
+			Transactor:    transactor,
+		})
+		require.NoError(t, err)
+
+		_, err = svc.AcceptInvitation(ctx, &domain.AccessClaims{UserID: user.ID}, &service.AcceptInvitationRequest{
+			Token: "secret",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, transactor.calls)
+	})
+	t.Run("rolls back a partial acceptance", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name          string
+			acceptErr     error
+			saveMemberErr error
+		}{
+			{name: "membership not saved", saveMemberErr: errors.New("db down")},
+			{name: "invitation not marked used", acceptErr: errors.New("db down")},
+		}
+
+		for _, tt := range tests {
+			user := mustVerifiedUser(t, "bob", "bob@example.com", "$hash")
+			organizations := &mockOrganizationRepo{saveMemberErr: tt.saveMemberErr}
+			acme := mustOrganization(t, organizations, "acme")
+			invitations := &mockInvitationRepo{acceptErr: tt.acceptErr}
+			invitation := mustPendingInvitation(t, invitations, acme.ID, "bob@example.com", "secret")
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:      &mockUserRepo{getByIDUser: user},
+				Organizations: organizations,
+				Invitations:   invitations,
+				Mailer:        newMockMailer(),
+				InvitationURL: testInvitationURL,
+				Transactor:    &mockTransactor{rollback: []snapshotter{organizations, invitations}},
+			})
+			require.NoError(t, err)
+
+			_, err = svc.AcceptInvitation(ctx, &domain.AccessClaims{UserID: user.ID}, &service.AcceptInvitationRequest{
+				Token: "secret",
+			})
+			assertAppErrorCode(t, err, apperror.ErrCodeInternalServer)
+
+			stored, err := invitations.GetByID(ctx, invitation.ID)
+			require.NoError(t, err)
+			assert.Nil(t, stored.AcceptedAt, tt.name)
+
+			member, err := organizations.GetMember(ctx, acme.ID, user.ID)
+			require.NoError(t, err)
+			assert.Nil(t, member, tt.name)
+		}
+	})
+	t.Run("sent to another address", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "bob", "bob@example.com", "$hash")
+		svc, organizations, invitations := newService(t, user)
+		acme := mustOrganization(t, organizations, "acme")
+		mustPendingInvitation(t, invitations, acme.ID, "carol@example.com", "secret")
+
+		_, err := svc.AcceptInvitation(ctx, &domain.AccessClaims{UserID: user.ID},
+			&service.AcceptInvitationRequest{Token: "secret"})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvitationEmailMismatch)
+	})
+	t.Run("revoked", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "bob", "bob@example.com", "$hash")
+		svc, organizations, invitations := newService(t, user)
+		acme := mustOrganization(t, organizations, "acme")
+		invitation := mustPendingInvitation(t, invitations, acme.ID, "bob@example.com", "secret")
+
+		superadmin := mustActor(t, domain.RoleSuperAdmin)
+		revokeReq := &service.InvitationActionRequest{Organization: "acme", InvitationID: invitation.ID}
+		require.NoError(t, svc.RevokeInvitation(ctx, superadmin, revokeReq))
+
+		err := svc.RevokeInvitation(ctx, superadmin, revokeReq)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvitationNotPending)
+
+		_, err = svc.AcceptInvitation(ctx, &domain.AccessClaims{UserID: user.ID},
+			&service.AcceptInvitationRequest{Token: "secret"})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvitationInvalid)
+	})
+	t.Run("revoke in another organisation", func(t *testing.T) {
+		t.Parallel()
+
+		svc, organizations, invitations := newService(t, nil)
+		acme := mustOrganization(t, organizations, "acme")
+		mustOrganization(t, organizations, "globex")
+		invitation := mustPendingInvitation(t, invitations, acme.ID, "bob@example.com", "secret")
+
+		err := svc.RevokeInvitation(ctx, mustActor(t, domain.RoleSuperAdmin),
+			&service.InvitationActionRequest{Organization: "globex", InvitationID: invitation.ID})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvitationNotFound)
+	})
+	t.Run("missing token", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "bob", "bob@example.com", "$hash")
+		svc, _, _ := newService(t, user)
+
+		_, err := svc.AcceptInvitation(ctx, &domain.AccessClaims{UserID: user.ID}, &service.AcceptInvitationRequest{})
+		assertAppErrorCode(t, err, apperror.ErrCodeTokenRequired)
+	})
+}
+
+func TestServiceListInvitations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	organizations := &mockOrganizationRepo{}
+	acme := mustOrganization(t, organizations, "acme")
+	invitations := &mockInvitationRepo{}
+	first := mustPendingInvitation(t, invitations, acme.ID, "bob@example.com", "one")
+	second := mustPendingInvitation(t, invitations, acme.ID, "carol@example.com", "two")
+	require.NoError(t, second.Revoke())
+
+	svc, err := newTestServiceWith(testDeps{
+		Organizations: organizations,
+		Invitations:   invitations,
+		Mailer:        newMockMailer(),
+		InvitationURL: testInvitationURL,
+	})
+	require.NoError(t, err)
+
+	res, err := svc.ListInvitations(ctx, mustActor(t, domain.RoleSuperAdmin), "acme")
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, second.ID, res[0].ID)
+	assert.Equal(t, domain.InvitationStatusRevoked.String(), res[0].Status)
+	assert.Equal(t, first.ID, res[1].ID)
+
+	_, err = svc.ListInvitations(ctx, mustActor(t, domain.RoleAdmin), "acme")
+	assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+}
+
+func TestServiceRegisterWithInvitation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("joins the inviting organisation", func(t *testing.T) {
+		t.Parallel()
+
+		users := &mockUserRepo{}
+		organizations := &mockOrganizationRepo{}
+		acme := mustOrganization(t, organizations, "acme")
+		invitations := &mockInvitationRepo{}
+		invitation := mustPendingInvitation(t, invitations, acme.ID, "alice@example.com", "secret")
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      users,
+			Organizations: organizations,
+			Invitations:   invitations,
+			Mailer:        newMockMailer(),
+			AuditLogger:   auditLog,
+			InvitationURL: testInvitationURL,
+		})
+		require.NoError(t, err)
+
+		req := *validRegisterReq
+		req.Invitation = "secret"
+		req.Organization = "ignored"
+
+		res, err := svc.Register(ctx, &req)
+		require.NoError(t, err)
+		assert.True(t, users.savedUser.IsVerified())
+		assert.Equal(t, &acme.ID, users.savedUser.OrganizationID)
+		assert.Equal(t, domain.RoleUser, users.savedUser.Role.String())
+
+		member, err := organizations.GetMember(ctx, acme.ID, res.UserID)
+		require.NoError(t, err)
+		require.NotNil(t, member)
+		assert.Equal(t, domain.RoleAdmin, member.Role.String())
+
+		stored, err := invitations.GetByID(ctx, invitation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, &res.UserID, stored.AcceptedBy)
+		assert.Equal(t, domain.AuditActionInviteAccepted, auditLog.last().Action)
+	})
+	t.Run("sent to another address", func(t *testing.T) {
+		t.Parallel()
+
+		users := &mockUserRepo{}
+		organizations := &mockOrganizationRepo{}
+		acme := mustOrganization(t, organizations, "acme")
+		invitations := &mockInvitationRepo{}
+		mustPendingInvitation(t, invitations, acme.ID, "bob@example.com", "secret")
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      users,
+			Organizations: organizations,
+			Invitations:   invitations,
+			Mailer:        newMockMailer(),
+			InvitationURL: testInvitationURL,
+		})
+		require.NoError(t, err)
+
+		req := *validRegisterReq
+		req.Invitation = "secret"
+
+		_, err = svc.Register(ctx, &req)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvitationEmailMismatch)
+		assert.Nil(t, users.savedUser)
+	})
+	t.Run("failed save leaves the invitation pending", func(t *testing.T) {
+		t.Parallel()
+
+		organizations := &mockOrganizationRepo{}
+		acme := mustOrganization(t, organizations, "acme")
+		invitations := &mockInvitationRepo{}
+		invitation := mustPendingInvitation(t, invitations, acme.ID, "alice@example.com", "secret")
+		transactor := &mockTransactor{rollback: []snapshotter{organizations, invitations}}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      &mockUserRepo{saveErr: errors.New("duplicate key")},
+			Organizations: organizations,
+			Invitations:   invitations,
+			Mailer:        newMockMailer(),
+			InvitationURL: testInvitationURL,
+			Transactor:    transactor,
+		})
+		require.NoError(t, err)
+
+		req := *validRegisterReq
+		req.Invitation = "secret"
+
+		_, err = svc.Register(ctx, &req)
+		assertAppErrorCode(t, err, apperror.ErrCodeInternalServer)
+		assert.Equal(t, 1, transactor.calls)
+
+		stored, err := invitations.GetByID(ctx, invitation.ID)
+		require.NoError(t, err)
+		assert.Nil(t, stored.AcceptedAt)
+	})
+}
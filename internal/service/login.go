@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
+
 	"go-auth/internal/apperror"
 	"go-auth/internal/domain"
 )
@@ -13,20 +15,136 @@ func (s *service) Login(ctx context.Context, req *LoginRequest) (*LoginResponse,
 		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgLoginRequestRequired, nil)
 	}
 
-	user, err := s.resolveUserByLogin(ctx, req.Login)
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := *req
+	normalized.ClientIP = clientIP.String()
+	req = &normalized
+
+	if !s.clientIPAllowed(clientIP) {
+		s.auditLogin(ctx, req, nil, domain.AuditOutcomeDenied, map[string]any{"reason": "ip_denied"})
+
+		return nil, errIPNotAllowed()
+	}
+
+	scope, ok, err := s.accountScope(ctx, req.Organization)
 	if err != nil {
 		return nil, err
 	}
 
+	var user *domain.User
+
+	if ok {
+		if user, err = s.resolveUserByLogin(ctx, scope, req.Login); err != nil {
+			return nil, err
+		}
+	}
+
+	if user == nil {
+		s.auditLogin(ctx, req, nil, domain.AuditOutcomeFailure, map[string]any{"reason": "unknown_user"})
+
+		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidCredentials, apperror.MsgInvalidCredentials, nil)
+	}
+
 	if !s.passwordHasher.Compare(req.Password, user.Password) {
+		s.auditLogin(ctx, req, user, domain.AuditOutcomeFailure, map[string]any{"reason": "invalid_password"})
+
 		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidCredentials, apperror.MsgInvalidCredentials, nil)
 	}
 
 	if user.IsBanned() || !user.CanLogin() {
+		s.auditLogin(ctx, req, user, domain.AuditOutcomeDenied, map[string]any{"reason": "account_blocked"})
+
 		return nil, apperror.Forbidden(apperror.ErrCodeUserBlocked, apperror.MsgAccountAccessRevoked, nil)
 	}
 
-	return s.createSession(ctx, user, req)
+	if user.RequiresMFA() {
+		return s.challengeMFA(ctx, user, req)
+	}
+
+	resp, err := s.createSession(ctx, user, req)
+	if err != nil {
+		return nil, err
+	}
+
+	info, isNew := s.trackDevice(ctx, user, req)
+
+	s.auditLogin(ctx, req, user, domain.AuditOutcomeSuccess, map[string]any{
+		"device":     info.Family,
+		"new_device": isNew,
+	})
+
+	return resp, nil
+}
+
+func (s *service) auditLogin(
+	ctx context.Context,
+	req *LoginRequest,
+	user *domain.User,
+	outcome domain.AuditOutcome,
+	metadata map[string]any,
+) {
+	action := domain.AuditActionLogin
+	if outcome != domain.AuditOutcomeSuccess {
+		action = domain.AuditActionLoginFailed
+	}
+
+	event := domain.NewAuditEvent(action, outcome).WithClient(req.UserAgent, req.ClientIP)
+
+	if user != nil {
+		event.WithActor(user.ID).WithTarget(user.ID)
+	} else {
+		event.WithMetadata(map[string]any{"login": req.Login})
+	}
+
+	s.audit(ctx, event.WithMetadata(metadata))
+	s.publish(ctx, loginEvent(req, user, outcome, metadata))
+}
+
+func loginEvent(
+	req *LoginRequest,
+	user *domain.User,
+	outcome domain.AuditOutcome,
+	metadata map[string]any,
+) domain.Event {
+	method, ok := metadata["method"].(string)
+	if !ok {
+		method = "password"
+	}
+
+	now := time.Now().UTC()
+
+	if outcome == domain.AuditOutcomeSuccess {
+		return domain.UserLoggedIn{
+			UserID:    user.ID,
+			Method:    method,
+			ClientIP:  req.ClientIP,
+			UserAgent: req.UserAgent,
+			At:        now,
+		}
+	}
+
+	reason, _ := metadata["reason"].(string)
+
+	event := domain.LoginFailed{
+		Method:    method,
+		Reason:    reason,
+		ClientIP:  req.ClientIP,
+		UserAgent: req.UserAgent,
+		At:        now,
+	}
+
+	if user != nil {
+		id := user.ID
+		event.UserID = &id
+	} else {
+		event.Login = req.Login
+	}
+
+	return event
 }
 
 func (s *service) createSession(ctx context.Context, user *domain.User, req *LoginRequest) (*LoginResponse, error) {
@@ -41,23 +159,29 @@ func (s *service) createSession(ctx context.Context, user *domain.User, req *Log
 	}
 
 	now := time.Now().UTC()
-	refreshExpiresAt := now.Add(s.refreshTokenTTL)
+	refreshExpiresAt := s.sessionLimits.CapExpiry(now, now.Add(s.refreshTokenTTL))
 
 	session, err := domain.NewSession(user.ID, refreshTokenHash, req.UserAgent, req.ClientIP, refreshExpiresAt)
 	if err != nil {
 		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
 	}
 
+	if s.organizationRepo != nil {
+		session.OrganizationID = user.OrganizationID
+	}
+
+	claims, err := s.sessionAccessClaims(ctx, user, session)
+	if err != nil {
+		return nil, err
+	}
+
 	if saveErr := s.sessionRepo.Save(ctx, session); saveErr != nil {
 		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, saveErr)
 	}
 
 	accessExpiresAt := now.Add(s.accessTokenTTL)
 
-	accessToken, err := s.accessTokenManager.Generate(domain.AccessClaims{
-		UserID: user.ID,
-		Role:   user.Role,
-	})
+	accessToken, err := s.accessTokenManager.Generate(claims)
 	if err != nil {
 		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
 	}
@@ -71,9 +195,9 @@ func (s *service) createSession(ctx context.Context, user *domain.User, req *Log
 	}, nil
 }
 
-func (s *service) resolveUserByLogin(ctx context.Context, login string) (*domain.User, error) {
+func (s *service) resolveUserByLogin(ctx context.Context, scope *uuid.UUID, login string) (*domain.User, error) {
 	if u, err := domain.NewUsername(login); err == nil {
-		user, err := s.userRepo.GetByUsername(ctx, u)
+		user, err := s.userByUsername(ctx, scope, u)
 		if err != nil {
 			return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUserByUsername, err)
 		}
@@ -84,7 +208,7 @@ func (s *service) resolveUserByLogin(ctx context.Context, login string) (*domain
 	}
 
 	if e, err := domain.NewEmail(login); err == nil {
-		user, err := s.userRepo.GetByEmail(ctx, e)
+		user, err := s.userByEmail(ctx, scope, e)
 		if err != nil {
 			return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUserByEmail, err)
 		}
@@ -94,5 +218,5 @@ func (s *service) resolveUserByLogin(ctx context.Context, login string) (*domain
 		}
 	}
 
-	return nil, apperror.Unauthorized(apperror.ErrCodeInvalidCredentials, apperror.MsgInvalidCredentials, nil)
+	return nil, nil
 }
@@ -140,3 +140,134 @@ func assertLoginResult(
 	assert.False(t, got.AccessExpiresAt.IsZero())
 	assert.False(t, got.RefreshExpiresAt.IsZero())
 }
+
+func TestServiceLoginAccessClaims(t *testing.T) {
+	ctx := context.Background()
+	passHash, _ := domain.NewPasswordFromHash("$hash")
+
+	tests := []struct {
+		name     string
+		mode     service.PermissionClaims
+		wantPerm []domain.Permission
+		wantScp  []string
+	}{
+		{name: "none", mode: service.PermissionClaimsNone},
+		{name: "permissions", mode: service.PermissionClaimsPermissions, wantPerm: []domain.Permission{"user:read"}},
+		{name: "scope", mode: service.PermissionClaimsScope, wantScp: []string{"user:read"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+			user.Password = passHash
+			access := &mockAccessTokenManager{}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:         &mockUserRepo{getByUsernameUser: user},
+				Hasher:           &mockPasswordHasher{compareOk: true},
+				Access:           access,
+				PermissionClaims: tt.mode,
+				TokenAudience:    []string{"api"},
+			})
+			require.NoError(t, err)
+
+			_, err = svc.Login(ctx, validLoginReq)
+			require.NoError(t, err)
+
+			assert.Equal(t, user.ID, access.lastClaims.UserID)
+			assert.Equal(t, []string{"api"}, access.lastClaims.Audience)
+			assert.Equal(t, tt.wantPerm, access.lastClaims.Permissions)
+			assert.Equal(t, tt.wantScp, access.lastClaims.Scopes)
+		})
+	}
+}
+
+func TestServiceLoginAudit(t *testing.T) {
+	ctx := context.Background()
+	passHash, _ := domain.NewPasswordFromHash("$hash")
+
+	tests := []struct {
+		name        string
+		found       bool
+		compareOk   bool
+		banned      bool
+		wantAction  domain.AuditAction
+		wantOutcome domain.AuditOutcome
+		wantReason  any
+	}{
+		{
+			name:        "unknown user",
+			wantAction:  domain.AuditActionLoginFailed,
+			wantOutcome: domain.AuditOutcomeFailure,
+			wantReason:  "unknown_user",
+		},
+		{
+			name:        "wrong password",
+			found:       true,
+			wantAction:  domain.AuditActionLoginFailed,
+			wantOutcome: domain.AuditOutcomeFailure,
+			wantReason:  "invalid_password",
+		},
+		{
+			name:        "banned",
+			found:       true,
+			compareOk:   true,
+			banned:      true,
+			wantAction:  domain.AuditActionLoginFailed,
+			wantOutcome: domain.AuditOutcomeDenied,
+			wantReason:  "account_blocked",
+		},
+		{
+			name:        "success",
+			found:       true,
+			compareOk:   true,
+			wantAction:  domain.AuditActionLogin,
+			wantOutcome: domain.AuditOutcomeSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+			user.Password = passHash
+
+			if tt.banned {
+				require.NoError(t, user.Ban())
+			}
+
+			repo := &mockUserRepo{}
+			if tt.found {
+				repo.getByUsernameUser = user
+			}
+
+			auditLog := &mockAuditLogger{}
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:    repo,
+				Hasher:      &mockPasswordHasher{compareOk: tt.compareOk},
+				AuditLogger: auditLog,
+			})
+			require.NoError(t, err)
+
+			_, _ = svc.Login(ctx, validLoginReq)
+
+			event := auditLog.last()
+			require.NotNil(t, event)
+			assert.Equal(t, tt.wantAction, event.Action)
+			assert.Equal(t, tt.wantOutcome, event.Outcome)
+			assert.Equal(t, validLoginReq.ClientIP, event.ClientIP)
+			assert.Equal(t, validLoginReq.UserAgent, event.UserAgent)
+			assert.Equal(t, tt.wantReason, event.Metadata["reason"])
+
+			if tt.found {
+				require.NotNil(t, event.TargetID)
+				assert.Equal(t, user.ID, *event.TargetID)
+			} else {
+				assert.Nil(t, event.TargetID)
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
 )
 
 func (s *service) Logout(ctx context.Context, refreshToken string) error {
@@ -37,5 +39,23 @@ func (s *service) Logout(ctx context.Context, refreshToken string) error {
 		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUpdateSession, err)
 	}
 
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionLogout, domain.AuditOutcomeSuccess).
+		WithActor(session.UserID).
+		WithTarget(session.UserID).
+		WithClient(session.UserAgent, session.ClientIP).
+		WithMetadata(map[string]any{"session_id": session.ID.String()}))
+
+	s.publish(ctx, sessionRevokedEvent(session, domain.SessionRevokedLogout))
+
 	return nil
 }
+
+func sessionRevokedEvent(session *domain.Session, reason domain.SessionRevokeReason) domain.SessionRevoked {
+	return domain.SessionRevoked{
+		UserID:    session.UserID,
+		SessionID: session.ID,
+		ClientID:  session.ClientID,
+		Reason:    reason,
+		At:        time.Now().UTC(),
+	}
+}
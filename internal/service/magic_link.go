@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+const defaultMagicLinkTTL = 15 * time.Minute
+
+type MagicLinkRequest struct {
+	Email        string
+	UserAgent    string
+	ClientIP     string
+	Organization string
+}
+
+type MagicLinkResponse struct {
+	Binding   string
+	ExpiresAt time.Time
+}
+
+type ConsumeMagicLinkRequest struct {
+	Token     string
+	Binding   string
+	UserAgent string
+	ClientIP  string
+}
+
+// RequestMagicLink emails a single-use sign-in link. The response is the same whether or
+// not the email belongs to an account that may sign in, so it cannot be used to probe for
+// accounts.
+func (s *service) RequestMagicLink(ctx context.Context, req *MagicLinkRequest) (*MagicLinkResponse, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgMagicLinkRequestRequired, nil)
+	}
+
+	if s.magicLinkURL == nil {
+		return nil, errMagicLinkDisabled()
+	}
+
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.clientIPAllowed(clientIP) {
+		s.auditMagicLink(ctx, req, clientIP.String(), nil, domain.AuditOutcomeDenied, "ip_denied")
+
+		return nil, errIPNotAllowed()
+	}
+
+	email, err := domain.NewEmail(req.Email)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	resp := &MagicLinkResponse{ExpiresAt: time.Now().UTC().Add(s.magicLinkTTL)}
+
+	var bindingHash string
+
+	if s.magicLinkBinding {
+		resp.Binding, bindingHash, err = s.generateOpaque()
+		if err != nil {
+			return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateMagicLink, err)
+		}
+	}
+
+	scope, ok, err := s.accountScope(ctx, req.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *domain.User
+
+	if ok {
+		if user, err = s.userByEmail(ctx, scope, email); err != nil {
+			return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUserByEmail, err)
+		}
+	}
+
+	if user == nil {
+		s.auditMagicLink(ctx, req, clientIP.String(), nil, domain.AuditOutcomeFailure, "unknown_user")
+
+		return resp, nil
+	}
+
+	if user.IsBanned() || !user.CanLogin() {
+		s.auditMagicLink(ctx, req, clientIP.String(), user, domain.AuditOutcomeDenied, "account_blocked")
+
+		return resp, nil
+	}
+
+	raw, hash, err := s.generateOpaque()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateMagicLink, err)
+	}
+
+	token, err := domain.NewToken(user.ID, domain.TokenTypeMagicLink, hash, resp.ExpiresAt)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateMagicLink, err)
+	}
+
+	token.BindingHash = bindingHash
+
+	if err = s.tokenRepo.Save(ctx, token); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveToken, err)
+	}
+
+	go s.sendMagicLink(context.WithoutCancel(ctx), user, s.magicLinkFor(raw), resp.ExpiresAt)
+
+	s.auditMagicLink(ctx, req, clientIP.String(), user, domain.AuditOutcomeSuccess, "")
+
+	return resp, nil
+}
+
+// ConsumeMagicLink redeems a link sent by RequestMagicLink and signs the user in, or
+// challenges a user with an MFA factor. A bound link is not spent when the binding does not
+// match, so a leaked link cannot be used to burn the owner's sign-in.
+func (s *service) ConsumeMagicLink(ctx context.Context, req *ConsumeMagicLinkRequest) (*LoginResponse, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgMagicLinkRequestRequired, nil)
+	}
+
+	if s.magicLinkURL == nil {
+		return nil, errMagicLinkDisabled()
+	}
+
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	loginReq := &LoginRequest{UserAgent: req.UserAgent, ClientIP: clientIP.String()}
+
+	if !s.clientIPAllowed(clientIP) {
+		s.auditLogin(ctx, loginReq, nil, domain.AuditOutcomeDenied, magicLinkMetadata("reason", "ip_denied"))
+
+		return nil, errIPNotAllowed()
+	}
+
+	if req.Token == "" {
+		return nil, apperror.BadRequest(apperror.ErrCodeTokenRequired, apperror.MsgTokenParamRequired, nil)
+	}
+
+	token, reason, err := s.redeemMagicLink(ctx, req)
+	if err != nil {
+		if reason != "" {
+			s.auditLogin(ctx, loginReq, nil, domain.AuditOutcomeFailure, magicLinkMetadata("reason", reason))
+		}
+
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil {
+		return nil, errMagicLinkInvalid()
+	}
+
+	loginReq.Login = user.Email.String()
+
+	if user.IsBanned() || !user.CanLogin() {
+		s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeDenied, magicLinkMetadata("reason", "account_blocked"))
+
+		return nil, apperror.Forbidden(apperror.ErrCodeUserBlocked, apperror.MsgAccountAccessRevoked, nil)
+	}
+
+	if user.RequiresMFA() {
+		return s.challengeMFA(ctx, user, loginReq)
+	}
+
+	resp, err := s.createSession(ctx, user, loginReq)
+	if err != nil {
+		return nil, err
+	}
+
+	info, isNew := s.trackDevice(ctx, user, loginReq)
+
+	s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeSuccess, magicLinkMetadata(
+		"device", info.Family,
+		"new_device", isNew,
+	))
+
+	return resp, nil
+}
+
+func (s *service) redeemMagicLink(ctx context.Context, req *ConsumeMagicLinkRequest) (*domain.Token, string, error) {
+	hash, err := s.opaqueTokenManager.Hash(req.Token)
+	if err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	token, err := s.tokenRepo.GetByToken(ctx, hash)
+	if err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetToken, err)
+	}
+
+	if token == nil || token.Type != domain.TokenTypeMagicLink {
+		return nil, "invalid_link", errMagicLinkInvalid()
+	}
+
+	if token.BindingHash != "" {
+		bindingHash, hashErr := s.opaqueTokenManager.Hash(req.Binding)
+		if hashErr != nil || subtle.ConstantTimeCompare([]byte(bindingHash), []byte(token.BindingHash)) != 1 {
+			return nil, "binding_mismatch", apperror.Unauthorized(
+				apperror.ErrCodeMagicLinkBinding,
+				apperror.MsgMagicLinkBinding,
+				hashErr,
+			)
+		}
+	}
+
+	if err = token.Use(); err != nil {
+		return nil, "invalid_link", errMagicLinkInvalid()
+	}
+
+	if err = s.tokenRepo.Use(ctx, token); err != nil {
+		if errors.Is(err, domain.ErrTokenUsed) {
+			return nil, "invalid_link", errMagicLinkInvalid()
+		}
+
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUseToken, err)
+	}
+
+	return token, "", nil
+}
+
+func (s *service) generateOpaque() (string, string, error) {
+	raw, err := s.opaqueTokenManager.Generate()
+	if err != nil {
+		return "", "", err
+	}
+
+	hash, err := s.opaqueTokenManager.Hash(raw)
+	if err != nil {
+		return "", "", err
+	}
+
+	return raw, hash, nil
+}
+
+func (s *service) magicLinkFor(token string) string {
+	link := *s.magicLinkURL
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
+
+func (s *service) sendMagicLink(ctx context.Context, user *domain.User, link string, expiresAt time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	_ = s.mailer.Send(ctx, &domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n"+
+				"Use the link below to sign in. It can be used once and expires at %s.\n\n"+
+				"%s\n\n"+
+				"If you did not ask to sign in, you can ignore this email.\n",
+			user.FirstName, expiresAt.Format(time.RFC1123), link,
+		),
+	})
+}
+
+func (s *service) auditMagicLink(
+	ctx context.Context,
+	req *MagicLinkRequest,
+	clientIP string,
+	user *domain.User,
+	outcome domain.AuditOutcome,
+	reason string,
+) {
+	event := domain.NewAuditEvent(domain.AuditActionMagicLink, outcome).WithClient(req.UserAgent, clientIP)
+
+	if user != nil {
+		event.WithActor(user.ID).WithTarget(user.ID)
+	} else {
+		event.WithMetadata(map[string]any{"login": req.Email})
+	}
+
+	if reason != "" {
+		event.WithMetadata(map[string]any{"reason": reason})
+	}
+
+	s.audit(ctx, event)
+}
+
+func magicLinkMetadata(kv ...any) map[string]any {
+	metadata := map[string]any{"method": "magic_link"}
+
+	for i := 0; i+1 < len(kv); i += 2 {
+		if key, ok := kv[i].(string); ok {
+			metadata[key] = kv[i+1]
+		}
+	}
+
+	return metadata
+}
+
+func errMagicLinkDisabled() error {
+	return apperror.NotImplemented(apperror.ErrCodeMagicLinkDisabled, apperror.MsgMagicLinkDisabled, nil)
+}
+
+func errMagicLinkInvalid() error {
+	return apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgMagicLinkInvalid, nil)
+}
@@ -0,0 +1,336 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+const testMagicLinkURL = "https://app.example.com/login/link?lang=en"
+
+func mustMagicLinkToken(t *testing.T, userID uuid.UUID, raw, bindingHash string) *domain.Token {
+	t.Helper()
+
+	token, err := domain.NewToken(userID, domain.TokenTypeMagicLink, "hashed-"+raw, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	token.BindingHash = bindingHash
+
+	return token
+}
+
+func TestServiceRequestMagicLink(t *testing.T) {
+	ctx := context.Background()
+	req := &service.MagicLinkRequest{Email: "alice@example.com", UserAgent: "ua", ClientIP: "198.51.100.10"}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.RequestMagicLink(ctx, req)
+		assertAppErrorCode(t, err, apperror.ErrCodeMagicLinkDisabled)
+	})
+	t.Run("invalid email", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{
+			MagicLinkURL: testMagicLinkURL,
+			Tokens:       &mockTokenRepo{},
+			Mailer:       newMockMailer(),
+		})
+		require.NoError(t, err)
+
+		_, err = svc.RequestMagicLink(ctx, &service.MagicLinkRequest{Email: "nope", ClientIP: "198.51.100.10"})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+	})
+	t.Run("sends a link", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+		tokens := &mockTokenRepo{}
+		mailer := newMockMailer()
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:     &mockUserRepo{getByEmailUser: user},
+			Tokens:       tokens,
+			Mailer:       mailer,
+			AuditLogger:  auditLog,
+			MagicLinkURL: testMagicLinkURL,
+		})
+		require.NoError(t, err)
+
+		res, err := svc.RequestMagicLink(ctx, req)
+		require.NoError(t, err)
+		assert.Empty(t, res.Binding)
+
+		require.NotNil(t, tokens.saved)
+		assert.Equal(t, user.ID, tokens.saved.UserID)
+		assert.Equal(t, domain.TokenTypeMagicLink, tokens.saved.Type)
+		assert.Equal(t, "hashed-refresh-token", tokens.saved.Token)
+		assert.Empty(t, tokens.saved.BindingHash)
+		assert.WithinDuration(t, res.ExpiresAt, tokens.saved.ExpiresAt, time.Second)
+
+		select {
+		case msg := <-mailer.sent:
+			assert.Equal(t, user.Email, msg.To)
+			assert.Contains(t, msg.Body, "https://app.example.com/login/link?lang=en&token=refresh-token")
+		case <-time.After(time.Second):
+			t.Fatal("expected a sign-in link email")
+		}
+
+		assert.Equal(t, domain.AuditActionMagicLink, auditLog.last().Action)
+		assert.Equal(t, domain.AuditOutcomeSuccess, auditLog.last().Outcome)
+	})
+	t.Run("does not reveal unknown or blocked accounts", func(t *testing.T) {
+		t.Parallel()
+
+		blocked := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+		require.NoError(t, blocked.Ban())
+
+		for _, user := range []*domain.User{nil, blocked} {
+			tokens := &mockTokenRepo{}
+			mailer := newMockMailer()
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:         &mockUserRepo{getByEmailUser: user},
+				Tokens:           tokens,
+				Mailer:           mailer,
+				MagicLinkURL:     testMagicLinkURL,
+				MagicLinkBinding: true,
+			})
+			require.NoError(t, err)
+
+			res, err := svc.RequestMagicLink(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, "refresh-token", res.Binding)
+			assert.Nil(t, tokens.saved)
+
+			select {
+			case msg := <-mailer.sent:
+				t.Fatalf("unexpected email: %q", msg.Subject)
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+	})
+	t.Run("binds the link to the requester", func(t *testing.T) {
+		t.Parallel()
+
+		tokens := &mockTokenRepo{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:         &mockUserRepo{getByEmailUser: mustVerifiedUser(t, "alice", "alice@example.com", "$hash")},
+			Tokens:           tokens,
+			Mailer:           newMockMailer(),
+			MagicLinkURL:     testMagicLinkURL,
+			MagicLinkBinding: true,
+		})
+		require.NoError(t, err)
+
+		res, err := svc.RequestMagicLink(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, "refresh-token", res.Binding)
+		require.NotNil(t, tokens.saved)
+		assert.Equal(t, "hashed-refresh-token", tokens.saved.BindingHash)
+	})
+}
+
+func TestServiceConsumeMagicLink(t *testing.T) {
+	ctx := context.Background()
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+
+	blocked := mustVerifiedUser(t, "bob", "bob@example.com", "$hash")
+	require.NoError(t, blocked.Ban())
+
+	expired := mustMagicLinkToken(t, user.ID, "link", "")
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	used := mustMagicLinkToken(t, user.ID, "link", "")
+	require.NoError(t, used.Use())
+
+	reset := mustMagicLinkToken(t, user.ID, "link", "")
+	reset.Type = domain.TokenTypePasswordReset
+
+	tests := []struct {
+		name      string
+		token     *domain.Token
+		user      *domain.User
+		req       service.ConsumeMagicLinkRequest
+		wantCode  apperror.Code
+		wantSpent bool
+	}{
+		{
+			name:      "valid",
+			token:     mustMagicLinkToken(t, user.ID, "link", ""),
+			user:      user,
+			req:       service.ConsumeMagicLinkRequest{Token: "link"},
+			wantSpent: true,
+		},
+		{
+			name:      "valid bound",
+			token:     mustMagicLinkToken(t, user.ID, "link", "hashed-binding"),
+			user:      user,
+			req:       service.ConsumeMagicLinkRequest{Token: "link", Binding: "binding"},
+			wantSpent: true,
+		},
+		{
+			name:     "binding mismatch",
+			token:    mustMagicLinkToken(t, user.ID, "link", "hashed-binding"),
+			user:     user,
+			req:      service.ConsumeMagicLinkRequest{Token: "link", Binding: "other"},
+			wantCode: apperror.ErrCodeMagicLinkBinding,
+		},
+		{
+			name:     "missing token",
+			req:      service.ConsumeMagicLinkRequest{},
+			wantCode: apperror.ErrCodeTokenRequired,
+		},
+		{
+			name:     "unknown token",
+			token:    mustMagicLinkToken(t, user.ID, "other", ""),
+			req:      service.ConsumeMagicLinkRequest{Token: "link"},
+			wantCode: apperror.ErrCodeInvalidToken,
+		},
+		{
+			name:     "expired",
+			token:    expired,
+			req:      service.ConsumeMagicLinkRequest{Token: "link"},
+			wantCode: apperror.ErrCodeInvalidToken,
+		},
+		{
+			name:      "already used",
+			token:     used,
+			req:       service.ConsumeMagicLinkRequest{Token: "link"},
+			wantCode:  apperror.ErrCodeInvalidToken,
+			wantSpent: true,
+		},
+		{
+			name:     "other token type",
+			token:    reset,
+			req:      service.ConsumeMagicLinkRequest{Token: "link"},
+			wantCode: apperror.ErrCodeInvalidToken,
+		},
+		{
+			name:      "blocked user",
+			token:     mustMagicLinkToken(t, blocked.ID, "link", ""),
+			user:      blocked,
+			req:       service.ConsumeMagicLinkRequest{Token: "link"},
+			wantCode:  apperror.ErrCodeUserBlocked,
+			wantSpent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tokens := &mockTokenRepo{}
+			if tt.token != nil {
+				clone := *tt.token
+				require.NoError(t, tokens.Save(ctx, &clone))
+			}
+
+			sessions := &mockSessionRepo{}
+			auditLog := &mockAuditLogger{}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:     &mockUserRepo{getByIDUser: tt.user},
+				SessionRepo:  sessions,
+				Tokens:       tokens,
+				Mailer:       newMockMailer(),
+				AuditLogger:  auditLog,
+				MagicLinkURL: testMagicLinkURL,
+			})
+			require.NoError(t, err)
+
+			req := tt.req
+			req.UserAgent = "ua"
+			req.ClientIP = "198.51.100.10"
+
+			res, err := svc.ConsumeMagicLink(ctx, &req)
+
+			if tt.token != nil {
+				stored, getErr := tokens.GetByToken(ctx, tt.token.Token)
+				require.NoError(t, getErr)
+				assert.Equal(t, tt.wantSpent, stored.IsUsed())
+			}
+
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+				assert.Nil(t, sessions.savedSession)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, res.UserID)
+			assert.Equal(t, "refresh-token", res.RefreshToken)
+			require.NotNil(t, sessions.savedSession)
+
+			event := auditLog.last()
+			assert.Equal(t, domain.AuditActionLogin, event.Action)
+			assert.Equal(t, "magic_link", event.Metadata["method"])
+		})
+	}
+
+	t.Run("MFA user is challenged", func(t *testing.T) {
+		t.Parallel()
+
+		mfaUser := mustEmailOTPUser(t)
+
+		tokens := &mockTokenRepo{}
+		require.NoError(t, tokens.Save(ctx, mustMagicLinkToken(t, mfaUser.ID, "link", "")))
+
+		sessions := &mockSessionRepo{}
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:     &mockUserRepo{getByIDUser: mfaUser},
+			SessionRepo:  sessions,
+			Tokens:       tokens,
+			Mailer:       newMockMailer(),
+			MagicLinkURL: testMagicLinkURL,
+			EmailOTP:     true,
+		})
+		require.NoError(t, err)
+
+		res, err := svc.ConsumeMagicLink(ctx, &service.ConsumeMagicLinkRequest{Token: "link", ClientIP: "198.51.100.10"})
+		require.NoError(t, err)
+		require.NotNil(t, res.MFA)
+		assert.Equal(t, domain.MFAFactorEmailOTP, res.MFA.Factor)
+		assert.Empty(t, res.AccessToken)
+		assert.Nil(t, sessions.savedSession)
+		require.NotNil(t, tokens.saved)
+		assert.Equal(t, domain.TokenTypeEmailOTP, tokens.saved.Type)
+	})
+	t.Run("second use", func(t *testing.T) {
+		t.Parallel()
+
+		tokens := &mockTokenRepo{}
+		require.NoError(t, tokens.Save(ctx, mustMagicLinkToken(t, user.ID, "link", "")))
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:     &mockUserRepo{getByIDUser: user},
+			Tokens:       tokens,
+			Mailer:       newMockMailer(),
+			MagicLinkURL: testMagicLinkURL,
+		})
+		require.NoError(t, err)
+
+		req := &service.ConsumeMagicLinkRequest{Token: "link", ClientIP: "198.51.100.10"}
+
+		_, err = svc.ConsumeMagicLink(ctx, req)
+		require.NoError(t, err)
+
+		_, err = svc.ConsumeMagicLink(ctx, req)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidToken)
+	})
+}
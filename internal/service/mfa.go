@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+const (
+	defaultEmailOTPTTL         = 10 * time.Minute
+	defaultEmailOTPMaxAttempts = 5
+	emailOTPDigits             = 6
+)
+
+type MFAChallenge struct {
+	Token     string
+	Factor    domain.MFAFactor
+	ExpiresAt time.Time
+}
+
+type VerifyMFARequest struct {
+	Token     string
+	Code      string
+	UserAgent string
+	ClientIP  string
+}
+
+type SetMFAFactorRequest struct {
+	Factor    string
+	UserAgent string
+	ClientIP  string
+}
+
+func (s *service) SetMFAFactor(ctx context.Context, actor *domain.AccessClaims, req *SetMFAFactorRequest) error {
+	if err := s.authenticate(actor); err != nil {
+		return err
+	}
+
+	if err := s.requireRecentAuth(actor); err != nil {
+		return err
+	}
+
+	if req == nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgMFARequestRequired, nil)
+	}
+
+	factor := domain.MFAFactor(req.Factor)
+	if !factor.IsValid() {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, domain.ErrMFAFactorInvalid.Error(), nil)
+	}
+
+	if !s.mfaFactorEnabled(factor) {
+		return errMFAFactorDisabled()
+	}
+
+	user, err := s.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil {
+		return apperror.NotFound(apperror.ErrCodeUserNotFound, apperror.MsgUserNotFound, nil)
+	}
+
+	previous := user.MFAFactor
+
+	if err = user.SetMFAFactor(factor); err != nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	if err = s.userRepo.Update(ctx, user); err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUpdateUser, err)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionMFAChanged, domain.AuditOutcomeSuccess).
+		WithActor(user.ID).
+		WithTarget(user.ID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(map[string]any{"from": previous.String(), "to": factor.String()}))
+
+	return nil
+}
+
+func (s *service) VerifyMFA(ctx context.Context, req *VerifyMFARequest) (*LoginResponse, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgMFARequestRequired, nil)
+	}
+
+	if !s.emailOTP {
+		return nil, errMFAFactorDisabled()
+	}
+
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	loginReq := &LoginRequest{UserAgent: req.UserAgent, ClientIP: clientIP.String()}
+
+	if !s.clientIPAllowed(clientIP) {
+		s.auditLogin(ctx, loginReq, nil, domain.AuditOutcomeDenied, mfaMetadata("", "reason", "ip_denied"))
+
+		return nil, errIPNotAllowed()
+	}
+
+	if req.Token == "" || strings.TrimSpace(req.Code) == "" {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgMFACodeRequired, nil)
+	}
+
+	token, err := s.pendingMFAChallenge(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == nil {
+		s.auditLogin(ctx, loginReq, nil, domain.AuditOutcomeFailure, mfaMetadata("", "reason", "invalid_challenge"))
+
+		return nil, errMFAChallengeInvalid()
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil || user.MFAFactor.ChallengeTokenType() != token.Type {
+		return nil, errMFAChallengeInvalid()
+	}
+
+	loginReq.Login = user.Email.String()
+
+	if reason, verifyErr := s.verifyMFACode(ctx, token, req.Token, req.Code); verifyErr != nil {
+		if reason != "" {
+			s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeFailure, mfaMetadata(user.MFAFactor, "reason", reason))
+		}
+
+		return nil, verifyErr
+	}
+
+	if user.IsBanned() || !user.CanLogin() {
+		metadata := mfaMetadata(user.MFAFactor, "reason", "account_blocked")
+		s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeDenied, metadata)
+
+		return nil, apperror.Forbidden(apperror.ErrCodeUserBlocked, apperror.MsgAccountAccessRevoked, nil)
+	}
+
+	resp, err := s.createSession(ctx, user, loginReq)
+	if err != nil {
+		return nil, err
+	}
+
+	info, isNew := s.trackDevice(ctx, user, loginReq)
+
+	s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeSuccess, mfaMetadata(user.MFAFactor,
+		"device", info.Family,
+		"new_device", isNew,
+	))
+
+	return resp, nil
+}
+
+func (s *service) challengeMFA(ctx context.Context, user *domain.User, req *LoginRequest) (*LoginResponse, error) {
+	if !s.mfaFactorEnabled(user.MFAFactor) {
+		s.auditLogin(ctx, req, user, domain.AuditOutcomeDenied, mfaMetadata(user.MFAFactor, "reason", "factor_disabled"))
+
+		return nil, errMFAFactorDisabled()
+	}
+
+	challenge, err := s.issueMFAChallenge(ctx, user, req.UserAgent, req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{UserID: user.ID, MFA: challenge}, nil
+}
+
+func (s *service) issueMFAChallenge(
+	ctx context.Context,
+	user *domain.User,
+	userAgent, clientIP string,
+) (*MFAChallenge, error) {
+	raw, hash, err := s.generateOpaque()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateMFACode, err)
+	}
+
+	expiresAt := time.Now().UTC().Add(s.emailOTPTTL)
+
+	token, err := domain.NewToken(user.ID, user.MFAFactor.ChallengeTokenType(), hash, expiresAt)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateMFACode, err)
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateMFACode, err)
+	}
+
+	if token.CodeHash, err = s.hashOTP(raw, code); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateMFACode, err)
+	}
+
+	if err = s.tokenRepo.Save(ctx, token); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveToken, err)
+	}
+
+	go s.sendEmailOTP(context.WithoutCancel(ctx), user, code, expiresAt)
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionMFAChallenge, domain.AuditOutcomeSuccess).
+		WithActor(user.ID).
+		WithTarget(user.ID).
+		WithClient(userAgent, clientIP).
+		WithMetadata(map[string]any{"factor": user.MFAFactor.String()}))
+
+	return &MFAChallenge{Token: raw, Factor: user.MFAFactor, ExpiresAt: expiresAt}, nil
+}
+
+func (s *service) pendingMFAChallenge(ctx context.Context, raw string) (*domain.Token, error) {
+	hash, err := s.opaqueTokenManager.Hash(raw)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	token, err := s.tokenRepo.GetByToken(ctx, hash)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetToken, err)
+	}
+
+	if token == nil || token.Type != domain.TokenTypeEmailOTP || token.IsUsed() || token.IsExpired() {
+		return nil, nil
+	}
+
+	return token, nil
+}
+
+// Attempts are counted before the code is compared, so concurrent guesses cannot exceed the limit.
+func (s *service) verifyMFACode(ctx context.Context, token *domain.Token, raw, code string) (string, error) {
+	if err := s.tokenRepo.Attempt(ctx, token, s.emailOTPMaxAttempts); err != nil {
+		if errors.Is(err, domain.ErrTokenUsed) {
+			return "attempts_exhausted", errMFAChallengeInvalid()
+		}
+
+		return "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUseToken, err)
+	}
+
+	codeHash, err := s.hashOTP(raw, strings.TrimSpace(code))
+	if err != nil || subtle.ConstantTimeCompare([]byte(codeHash), []byte(token.CodeHash)) != 1 {
+		return "invalid_code", apperror.Unauthorized(apperror.ErrCodeMFACodeInvalid, apperror.MsgMFACodeInvalid, err)
+	}
+
+	if err = token.Use(); err != nil {
+		return "invalid_challenge", errMFAChallengeInvalid()
+	}
+
+	if err = s.tokenRepo.Use(ctx, token); err != nil {
+		if errors.Is(err, domain.ErrTokenUsed) {
+			return "invalid_challenge", errMFAChallengeInvalid()
+		}
+
+		return "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUseToken, err)
+	}
+
+	return "", nil
+}
+
+func (s *service) mfaFactorEnabled(factor domain.MFAFactor) bool {
+	switch factor {
+	case domain.MFAFactorNone:
+		return true
+	case domain.MFAFactorEmailOTP:
+		return s.emailOTP
+	default:
+		return false
+	}
+}
+
+// hashOTP hashes a code together with its challenge token, so a leaked hash cannot be
+// matched against the few possible codes without the token, which is never stored.
+func (s *service) hashOTP(challenge, code string) (string, error) {
+	return s.opaqueTokenManager.Hash(challenge + ":" + code)
+}
+
+func (s *service) sendEmailOTP(ctx context.Context, user *domain.User, code string, expiresAt time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	_ = s.mailer.Send(ctx, &domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Your sign-in code",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n"+
+				"Your sign-in code is %s. It expires at %s.\n\n"+
+				"If you did not just sign in, someone else knows your password; please change it.\n",
+			user.FirstName, code, expiresAt.Format(time.RFC1123),
+		),
+	})
+}
+
+func generateOTP() (string, error) {
+	limit := big.NewInt(1)
+	for range emailOTPDigits {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", fmt.Errorf("generate code: %w", err)
+	}
+
+	return fmt.Sprintf("%0*d", emailOTPDigits, n), nil
+}
+
+func mfaMetadata(factor domain.MFAFactor, kv ...any) map[string]any {
+	metadata := map[string]any{"method": "password", "mfa": factor.String()}
+
+	for i := 0; i+1 < len(kv); i += 2 {
+		if key, ok := kv[i].(string); ok {
+			metadata[key] = kv[i+1]
+		}
+	}
+
+	return metadata
+}
+
+func errMFAFactorDisabled() error {
+	return apperror.NotImplemented(apperror.ErrCodeMFAFactorDisabled, apperror.MsgMFAFactorDisabled, nil)
+}
+
+func errMFAChallengeInvalid() error {
+	return apperror.Unauthorized(apperror.ErrCodeMFAChallengeInvalid, apperror.MsgMFAChallengeInvalid, nil)
+}
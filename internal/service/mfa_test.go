@@ -0,0 +1,254 @@
+package service_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+var otpPattern = regexp.MustCompile(`\b\d{6}\b`)
+
+func mustEmailOTPUser(t *testing.T) *domain.User {
+	t.Helper()
+
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	require.NoError(t, user.SetMFAFactor(domain.MFAFactorEmailOTP))
+
+	return user
+}
+
+func receiveOTP(t *testing.T, mailer *mockMailer) string {
+	t.Helper()
+
+	select {
+	case msg := <-mailer.sent:
+		code := otpPattern.FindString(msg.Body)
+		require.NotEmpty(t, code, "expected a code in %q", msg.Body)
+
+		return code
+	case <-time.After(time.Second):
+		t.Fatal("expected a sign-in code email")
+
+		return ""
+	}
+}
+
+func TestServiceLoginWithEmailOTP(t *testing.T) {
+	ctx := context.Background()
+	loginReq := &service.LoginRequest{Login: "alice", Password: "secret", UserAgent: "ua", ClientIP: "198.51.100.10"}
+
+	t.Run("challenges then signs in", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustEmailOTPUser(t)
+		sessions := &mockSessionRepo{}
+		tokens := &mockTokenRepo{}
+		mailer := newMockMailer()
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:    &mockUserRepo{getByUsernameUser: user, getByIDUser: user},
+			SessionRepo: sessions,
+			Hasher:      &mockPasswordHasher{compareOk: true},
+			Tokens:      tokens,
+			Mailer:      mailer,
+			AuditLogger: auditLog,
+			EmailOTP:    true,
+		})
+		require.NoError(t, err)
+
+		res, err := svc.Login(ctx, loginReq)
+		require.NoError(t, err)
+		require.NotNil(t, res.MFA)
+		assert.Equal(t, domain.MFAFactorEmailOTP, res.MFA.Factor)
+		assert.Empty(t, res.AccessToken)
+		assert.Empty(t, res.RefreshToken)
+		assert.Nil(t, sessions.savedSession)
+		assert.Equal(t, domain.AuditActionMFAChallenge, auditLog.last().Action)
+
+		require.NotNil(t, tokens.saved)
+		assert.Equal(t, domain.TokenTypeEmailOTP, tokens.saved.Type)
+		assert.Equal(t, "hashed-"+res.MFA.Token, tokens.saved.Token)
+
+		code := receiveOTP(t, mailer)
+		assert.NotEmpty(t, tokens.saved.CodeHash)
+
+		verified, err := svc.VerifyMFA(ctx, &service.VerifyMFARequest{
+			Token:     res.MFA.Token,
+			Code:      " " + code + " ",
+			UserAgent: "ua",
+			ClientIP:  "198.51.100.10",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, verified.UserID)
+		assert.NotEmpty(t, verified.AccessToken)
+		assert.Nil(t, verified.MFA)
+		require.NotNil(t, sessions.savedSession)
+		assert.Equal(t, domain.AuditActionLogin, auditLog.last().Action)
+		assert.Equal(t, "email_otp", auditLog.last().Metadata["mfa"])
+
+		_, err = svc.VerifyMFA(ctx, &service.VerifyMFARequest{Token: res.MFA.Token, Code: code, ClientIP: "198.51.100.10"})
+		assertAppErrorCode(t, err, apperror.ErrCodeMFAChallengeInvalid)
+	})
+	t.Run("factor disabled", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustEmailOTPUser(t)
+		sessions := &mockSessionRepo{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:    &mockUserRepo{getByUsernameUser: user},
+			SessionRepo: sessions,
+			Hasher:      &mockPasswordHasher{compareOk: true},
+		})
+		require.NoError(t, err)
+
+		_, err = svc.Login(ctx, loginReq)
+		assertAppErrorCode(t, err, apperror.ErrCodeMFAFactorDisabled)
+		assert.Nil(t, sessions.savedSession)
+	})
+	t.Run("invalidated after too many wrong codes", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustEmailOTPUser(t)
+		sessions := &mockSessionRepo{}
+		mailer := newMockMailer()
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:    &mockUserRepo{getByUsernameUser: user, getByIDUser: user},
+			SessionRepo: sessions,
+			Hasher:      &mockPasswordHasher{compareOk: true},
+			Tokens:      &mockTokenRepo{},
+			Mailer:      mailer,
+			AuditLogger: auditLog,
+			EmailOTP:    true,
+		})
+		require.NoError(t, err)
+
+		res, err := svc.Login(ctx, loginReq)
+		require.NoError(t, err)
+
+		code := receiveOTP(t, mailer)
+		wrong := "000000"
+
+		if code == wrong {
+			wrong = "111111"
+		}
+
+		verify := func(code string) error {
+			_, verifyErr := svc.VerifyMFA(ctx, &service.VerifyMFARequest{
+				Token:    res.MFA.Token,
+				Code:     code,
+				ClientIP: "198.51.100.10",
+			})
+
+			return verifyErr
+		}
+
+		for range 5 {
+			assertAppErrorCode(t, verify(wrong), apperror.ErrCodeMFACodeInvalid)
+			assert.Equal(t, "invalid_code", auditLog.last().Metadata["reason"])
+		}
+
+		assertAppErrorCode(t, verify(code), apperror.ErrCodeMFAChallengeInvalid)
+		assert.Equal(t, "attempts_exhausted", auditLog.last().Metadata["reason"])
+		assert.Nil(t, sessions.savedSession)
+	})
+	t.Run("disabled or unknown challenge", func(t *testing.T) {
+		t.Parallel()
+
+		disabled, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = disabled.VerifyMFA(ctx, &service.VerifyMFARequest{Token: "nope", Code: "123456"})
+		assertAppErrorCode(t, err, apperror.ErrCodeMFAFactorDisabled)
+
+		svc, err := newTestServiceWith(testDeps{Tokens: &mockTokenRepo{}, Mailer: newMockMailer(), EmailOTP: true})
+		require.NoError(t, err)
+
+		_, err = svc.VerifyMFA(ctx, &service.VerifyMFARequest{Token: "nope", Code: "123456", ClientIP: "198.51.100.10"})
+		assertAppErrorCode(t, err, apperror.ErrCodeMFAChallengeInvalid)
+
+		_, err = svc.VerifyMFA(ctx, &service.VerifyMFARequest{Token: "nope", ClientIP: "198.51.100.10"})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+	})
+}
+
+func TestServiceSetMFAFactor(t *testing.T) {
+	ctx := context.Background()
+	recent := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		emailOTP bool
+		authTime time.Time
+		factor   string
+		wantCode apperror.Code
+	}{
+		{name: "enables email OTP", emailOTP: true, authTime: recent, factor: "email_otp"},
+		{name: "turns MFA off", authTime: recent, factor: ""},
+		{
+			name:     "stale authentication",
+			emailOTP: true,
+			authTime: time.Now().Add(-time.Hour),
+			factor:   "email_otp",
+			wantCode: apperror.ErrCodeReauthenticationRequired,
+		},
+		{name: "no auth time", emailOTP: true, factor: "email_otp", wantCode: apperror.ErrCodeReauthenticationRequired},
+		{name: "disabled factor", authTime: recent, factor: "email_otp", wantCode: apperror.ErrCodeMFAFactorDisabled},
+		{name: "unknown factor", emailOTP: true, authTime: recent, factor: "sms", wantCode: apperror.ErrCodeInvalidParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+			users := &mockUserRepo{getByIDUser: user}
+			auditLog := &mockAuditLogger{}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:    users,
+				Tokens:      &mockTokenRepo{},
+				Mailer:      newMockMailer(),
+				AuditLogger: auditLog,
+				EmailOTP:    tt.emailOTP,
+			})
+			require.NoError(t, err)
+
+			actor := &domain.AccessClaims{UserID: user.ID, AuthTime: tt.authTime}
+
+			err = svc.SetMFAFactor(ctx, actor, &service.SetMFAFactorRequest{Factor: tt.factor})
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+				assert.Nil(t, users.updatedUser)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, users.updatedUser)
+			assert.Equal(t, domain.MFAFactor(tt.factor), users.updatedUser.MFAFactor)
+			assert.Equal(t, domain.AuditActionMFAChanged, auditLog.last().Action)
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		err = svc.SetMFAFactor(ctx, nil, &service.SetMFAFactorRequest{})
+		assertAppErrorCode(t, err, apperror.ErrCodeUnauthorized)
+	})
+}
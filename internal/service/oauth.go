@@ -0,0 +1,530 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+const defaultAuthorizationCodeTTL = time.Minute
+
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+}
+
+type AuthorizeResponse struct {
+	RedirectTo string
+	Code       string
+	State      string
+}
+
+type TokenRequest struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+	UserAgent    string
+	ClientIP     string
+}
+
+type TokenResponse struct {
+	AccessToken      string
+	RefreshToken     string
+	IDToken          string
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+	Scopes           []string
+}
+
+type authorization struct {
+	client *domain.OAuthClient
+	scopes []string
+	pkce   domain.PKCEChallenge
+}
+
+// ValidateAuthorization checks an authorization request before the user is asked to sign in.
+// Errors coded ErrCodeInvalidClient or ErrCodeInvalidRedirectURI must not be sent to the redirect URI.
+func (s *service) ValidateAuthorization(ctx context.Context, req *AuthorizeRequest) error {
+	_, err := s.validateAuthorization(ctx, req)
+
+	return err
+}
+
+func (s *service) Authorize(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *AuthorizeRequest,
+) (*AuthorizeResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if actor.IsDelegated() {
+		return nil, apperror.Forbidden(apperror.ErrCodePermissionDenied, apperror.MsgClientCannotAuthorize, nil)
+	}
+
+	// Consent issues the client a credential for the account; an operator acting as the user
+	// must not be able to grant it.
+	if actor.IsImpersonated() {
+		return nil, errImpersonationRestricted()
+	}
+
+	if err := s.requireRecentAuth(actor); err != nil {
+		return nil, err
+	}
+
+	authz, err := s.validateAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.opaqueTokenManager.Generate()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateAuthCode, err)
+	}
+
+	codeHash, err := s.opaqueTokenManager.Hash(code)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateAuthCode, err)
+	}
+
+	authCode, err := domain.NewAuthorizationCode(
+		codeHash,
+		authz.client.ID,
+		actor.UserID,
+		req.RedirectURI,
+		authz.scopes,
+		authz.pkce,
+		time.Now().UTC().Add(s.authCodeTTL),
+	)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateAuthCode, err)
+	}
+
+	authCode.Nonce = req.Nonce
+
+	if err = s.authCodeRepo.Save(ctx, authCode); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveAuthCode, err)
+	}
+
+	redirectTo, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidRedirectURI, apperror.MsgInvalidRedirectURI, err)
+	}
+
+	query := redirectTo.Query()
+	query.Set("code", code)
+
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+
+	redirectTo.RawQuery = query.Encode()
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionOAuthAuthorize, domain.AuditOutcomeSuccess).
+		WithActor(actor.UserID).
+		WithTarget(actor.UserID).
+		WithMetadata(map[string]any{"client_id": authz.client.ID, "scope": domain.JoinScope(authz.scopes)}))
+
+	return &AuthorizeResponse{
+		RedirectTo: redirectTo.String(),
+		Code:       code,
+		State:      req.State,
+	}, nil
+}
+
+func (s *service) validateAuthorization(ctx context.Context, req *AuthorizeRequest) (*authorization, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgAuthorizeRequired, nil)
+	}
+
+	client, err := s.getOAuthClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil || client.IsDisabled() {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidClient, apperror.MsgInvalidClient, nil)
+	}
+
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidRedirectURI, apperror.MsgInvalidRedirectURI, nil)
+	}
+
+	if !client.AllowsGrantType(domain.GrantTypeAuthorizationCode) {
+		return nil, apperror.BadRequest(apperror.ErrCodeUnauthorizedClient, apperror.MsgUnauthorizedClient, nil)
+	}
+
+	if req.ResponseType != domain.ResponseTypeCode {
+		return nil, apperror.BadRequest(apperror.ErrCodeUnsupportedResponseType, apperror.MsgUnsupportedResponseType, nil)
+	}
+
+	scopes, err := client.GrantScopes(domain.ParseScope(req.Scope))
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidScope, apperror.MsgInvalidScope, err)
+	}
+
+	if s.idTokenManager == nil && slices.Contains(scopes, domain.ScopeOpenID) {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidScope, apperror.MsgInvalidScope, nil)
+	}
+
+	if err = domain.ValidateNonce(req.Nonce); err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgNonceInvalid, err)
+	}
+
+	pkce, err := domain.NewPKCEChallenge(req.CodeChallenge, req.CodeChallengeMethod)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgPKCERequired, err)
+	}
+
+	return &authorization{client: client, scopes: scopes, pkce: pkce}, nil
+}
+
+func (s *service) Token(ctx context.Context, req *TokenRequest) (*TokenResponse, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgTokenRequestRequired, nil)
+	}
+
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	var grant func(context.Context, *domain.OAuthClient, *TokenRequest) (*TokenResponse, error)
+
+	switch req.GrantType {
+	case domain.GrantTypeAuthorizationCode:
+		grant = s.exchangeAuthorizationCode
+	case domain.GrantTypeRefreshToken:
+		grant = s.refreshClientSession
+	case domain.GrantTypeClientCredentials:
+		grant = s.issueClientCredentials
+	default:
+		return nil, apperror.BadRequest(apperror.ErrCodeUnsupportedGrantType, apperror.MsgUnsupportedGrantType, nil)
+	}
+
+	if !client.AllowsGrantType(req.GrantType) {
+		return nil, apperror.BadRequest(apperror.ErrCodeUnauthorizedClient, apperror.MsgUnauthorizedClient, nil)
+	}
+
+	return grant(ctx, client, req)
+}
+
+func (s *service) issueClientCredentials(
+	ctx context.Context,
+	client *domain.OAuthClient,
+	req *TokenRequest,
+) (*TokenResponse, error) {
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.clientIPAllowed(clientIP) {
+		return nil, errIPNotAllowed()
+	}
+
+	scopes, err := client.GrantScopes(domain.ParseScope(req.Scope))
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidScope, apperror.MsgInvalidScope, err)
+	}
+
+	accessToken, err := s.accessTokenManager.Generate(domain.AccessClaims{
+		SubjectType: domain.SubjectTypeClient,
+		ClientID:    client.ID,
+		Scopes:      scopes,
+		Audience:    slices.Clone(s.tokenAudience),
+	})
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateAccessToken, err)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionOAuthToken, domain.AuditOutcomeSuccess).
+		WithClient(req.UserAgent, clientIP.String()).
+		WithMetadata(map[string]any{
+			"client_id":  client.ID,
+			"grant_type": domain.GrantTypeClientCredentials,
+			"scope":      domain.JoinScope(scopes),
+		}))
+
+	return &TokenResponse{
+		AccessToken:     accessToken,
+		AccessExpiresAt: time.Now().UTC().Add(s.accessTokenTTL),
+		Scopes:          scopes,
+	}, nil
+}
+
+func (s *service) exchangeAuthorizationCode(
+	ctx context.Context,
+	client *domain.OAuthClient,
+	req *TokenRequest,
+) (*TokenResponse, error) {
+	if req.Code == "" {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgAuthCodeRequired, nil)
+	}
+
+	if req.CodeVerifier == "" {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgCodeVerifierRequired, nil)
+	}
+
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.clientIPAllowed(clientIP) {
+		return nil, errIPNotAllowed()
+	}
+
+	code, err := s.redeemAuthorizationCode(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, code.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil || user.IsBanned() || !user.CanLogin() {
+		return nil, errInvalidGrant(nil)
+	}
+
+	session, refreshToken, err := s.startClientSession(ctx, user, code, req.UserAgent, clientIP.String())
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.sessionAccessClaims(ctx, user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.accessTokenManager.Generate(claims)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateAccessToken, err)
+	}
+
+	idToken, err := s.issueIDToken(user, code)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionOAuthToken, domain.AuditOutcomeSuccess).
+		WithActor(user.ID).
+		WithTarget(user.ID).
+		WithClient(req.UserAgent, clientIP.String()).
+		WithMetadata(map[string]any{
+			"client_id":  client.ID,
+			"grant_type": domain.GrantTypeAuthorizationCode,
+			"session_id": session.ID.String(),
+		}))
+
+	return &TokenResponse{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		IDToken:          idToken,
+		AccessExpiresAt:  time.Now().UTC().Add(s.accessTokenTTL),
+		RefreshExpiresAt: session.ExpiresAt,
+		Scopes:           slices.Clone(session.Scopes),
+	}, nil
+}
+
+// redeemAuthorizationCode checks the code against the client, redirect URI and PKCE
+// verifier, then marks it used so it cannot be exchanged twice.
+func (s *service) redeemAuthorizationCode(
+	ctx context.Context,
+	client *domain.OAuthClient,
+	req *TokenRequest,
+) (*domain.AuthorizationCode, error) {
+	codeHash, err := s.opaqueTokenManager.Hash(req.Code)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetAuthCode, err)
+	}
+
+	code, err := s.authCodeRepo.GetByHash(ctx, codeHash)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetAuthCode, err)
+	}
+
+	if code == nil || code.ClientID != client.ID || code.RedirectURI != req.RedirectURI {
+		return nil, errInvalidGrant(nil)
+	}
+
+	if err = code.PKCE.Verify(req.CodeVerifier); err != nil {
+		return nil, errInvalidGrant(err)
+	}
+
+	if err = code.Use(); err != nil {
+		if errors.Is(err, domain.ErrTokenUsed) {
+			s.auditCodeReuse(ctx, client, code, req)
+		}
+
+		return nil, errInvalidGrant(err)
+	}
+
+	if err = s.authCodeRepo.Use(ctx, code); err != nil {
+		if errors.Is(err, domain.ErrTokenUsed) {
+			s.auditCodeReuse(ctx, client, code, req)
+
+			return nil, errInvalidGrant(err)
+		}
+
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUseAuthCode, err)
+	}
+
+	return code, nil
+}
+
+func (s *service) auditCodeReuse(
+	ctx context.Context,
+	client *domain.OAuthClient,
+	code *domain.AuthorizationCode,
+	req *TokenRequest,
+) {
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionOAuthToken, domain.AuditOutcomeDenied).
+		WithTarget(code.UserID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(map[string]any{"client_id": client.ID, "reason": "code_reused"}))
+}
+
+func (s *service) startClientSession(
+	ctx context.Context,
+	user *domain.User,
+	code *domain.AuthorizationCode,
+	userAgent, clientIP string,
+) (*domain.Session, string, error) {
+	refreshToken, err := s.opaqueTokenManager.Generate()
+	if err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateRefreshToken, err)
+	}
+
+	refreshTokenHash, err := s.opaqueTokenManager.Hash(refreshToken)
+	if err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgHashRefreshToken, err)
+	}
+
+	now := time.Now().UTC()
+	refreshExpiresAt := s.sessionLimits.CapExpiry(now, now.Add(s.refreshTokenTTL))
+
+	session, err := domain.NewSession(user.ID, refreshTokenHash, userAgent, clientIP, refreshExpiresAt)
+	if err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveNewSession, err)
+	}
+
+	session.ClientID = code.ClientID
+	session.Scopes = slices.Clone(code.Scopes)
+
+	if err = s.sessionRepo.Save(ctx, session); err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveNewSession, err)
+	}
+
+	return session, refreshToken, nil
+}
+
+func (s *service) refreshClientSession(
+	ctx context.Context,
+	client *domain.OAuthClient,
+	req *TokenRequest,
+) (*TokenResponse, error) {
+	res, err := s.refresh(ctx, &RefreshRequest{
+		RefreshToken: req.RefreshToken,
+		UserAgent:    req.UserAgent,
+		ClientIP:     req.ClientIP,
+	}, client.ID)
+	if err != nil {
+		return nil, asInvalidGrant(err)
+	}
+
+	return &TokenResponse{
+		AccessToken:      res.AccessToken,
+		RefreshToken:     res.RefreshToken,
+		AccessExpiresAt:  res.AccessExpiresAt,
+		RefreshExpiresAt: res.RefreshExpiresAt,
+		Scopes:           res.Scopes,
+	}, nil
+}
+
+// authenticateClient checks the client's secret; public clients must not send one.
+func (s *service) authenticateClient(ctx context.Context, clientID, secret string) (*domain.OAuthClient, error) {
+	client, err := s.getOAuthClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil || client.IsDisabled() {
+		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidClient, apperror.MsgInvalidClient, nil)
+	}
+
+	if client.IsPublic() {
+		if secret != "" {
+			return nil, apperror.Unauthorized(apperror.ErrCodeInvalidClient, apperror.MsgInvalidClient, nil)
+		}
+
+		return client, nil
+	}
+
+	if secret == "" {
+		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidClient, apperror.MsgInvalidClient, nil)
+	}
+
+	secretHash, err := s.opaqueTokenManager.Hash(secret)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetOAuthClient, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secretHash), []byte(client.SecretHash)) != 1 {
+		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidClient, apperror.MsgInvalidClient, nil)
+	}
+
+	return client, nil
+}
+
+func (s *service) getOAuthClient(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	if clientID == "" {
+		return nil, nil
+	}
+
+	client, err := s.oauthClientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetOAuthClient, err)
+	}
+
+	return client, nil
+}
+
+func errInvalidGrant(cause error) error {
+	return apperror.BadRequest(apperror.ErrCodeInvalidGrant, apperror.MsgInvalidGrant, cause)
+}
+
+func asInvalidGrant(err error) error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		return err
+	}
+
+	switch appErr.Status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return errInvalidGrant(err)
+	}
+
+	return err
+}
@@ -0,0 +1,850 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+const (
+	testRedirectURI  = "https://app.example.com/callback"
+	testCodeVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testClientSecret = "client-secret"
+)
+
+func testCodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func mustOAuthClient(t *testing.T, id string, public bool) *domain.OAuthClient {
+	t.Helper()
+
+	secretHash := "hashed-" + testClientSecret
+	if public {
+		secretHash = ""
+	}
+
+	client, err := domain.NewOAuthClient(id, "Example App", secretHash, []string{testRedirectURI}, []string{"profile", "user:read"})
+	require.NoError(t, err)
+
+	return client
+}
+
+func mustServiceClient(t *testing.T, id string) *domain.OAuthClient {
+	t.Helper()
+
+	client, err := domain.NewServiceClient(id, "Billing", "hashed-"+testClientSecret, []string{"audit:read", "user:read"})
+	require.NoError(t, err)
+
+	return client
+}
+
+func validAuthorizeReq(clientID string) *service.AuthorizeRequest {
+	return &service.AuthorizeRequest{
+		ResponseType:        domain.ResponseTypeCode,
+		ClientID:            clientID,
+		RedirectURI:         testRedirectURI,
+		Scope:               "profile",
+		State:               "xyz",
+		CodeChallenge:       testCodeChallenge(testCodeVerifier),
+		CodeChallengeMethod: domain.PKCEMethodS256,
+	}
+}
+
+func TestServiceCreateOAuthClient(t *testing.T) {
+	ctx := context.Background()
+	admin := mustActor(t, domain.RoleAdmin)
+
+	t.Run("actor lacks oauth_client:manage", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.CreateOAuthClient(ctx, mustActor(t, domain.RoleUser), &service.CreateOAuthClientRequest{})
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+	})
+
+	t.Run("nil request", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.CreateOAuthClient(ctx, admin, nil)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+	})
+
+	t.Run("invalid redirect uri", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.CreateOAuthClient(ctx, admin, &service.CreateOAuthClientRequest{
+			Name:         "App",
+			RedirectURIs: []string{"http://app.example.com/callback"},
+		})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+	})
+
+	t.Run("confidential client gets a secret", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockOAuthClientRepo{}
+		svc, err := newTestServiceWith(testDeps{
+			OAuthClients: repo,
+			Opaque:       &mockOpaqueTokenManager{generateToken: "generated-secret"},
+		})
+		require.NoError(t, err)
+
+		got, err := svc.CreateOAuthClient(ctx, admin, &service.CreateOAuthClientRequest{
+			Name:         "App",
+			RedirectURIs: []string{testRedirectURI},
+			Scopes:       []string{"profile"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "generated-secret", got.Secret)
+		require.NotNil(t, repo.savedClient)
+		assert.Equal(t, got.ID, repo.savedClient.ID)
+		assert.Equal(t, "hashed-generated-secret", repo.savedClient.SecretHash)
+	})
+
+	t.Run("public client has no secret", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockOAuthClientRepo{}
+		svc, err := newTestServiceWith(testDeps{OAuthClients: repo})
+		require.NoError(t, err)
+
+		got, err := svc.CreateOAuthClient(ctx, admin, &service.CreateOAuthClientRequest{
+			Name:         "SPA",
+			RedirectURIs: []string{testRedirectURI},
+			Public:       true,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, got.Secret)
+		assert.True(t, repo.savedClient.IsPublic())
+	})
+
+	t.Run("service account", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockOAuthClientRepo{}
+		svc, err := newTestServiceWith(testDeps{OAuthClients: repo})
+		require.NoError(t, err)
+
+		got, err := svc.CreateOAuthClient(ctx, admin, &service.CreateOAuthClientRequest{
+			Name:           "Billing",
+			Scopes:         []string{"user:read"},
+			ServiceAccount: true,
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, got.Secret)
+		assert.Empty(t, got.RedirectURIs)
+		assert.Equal(t, []string{domain.GrantTypeClientCredentials}, got.GrantTypes)
+		assert.False(t, repo.savedClient.IsPublic())
+	})
+
+	t.Run("scopes beyond the actor's role", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockOAuthClientRepo{}
+		svc, err := newTestServiceWith(testDeps{OAuthClients: repo})
+		require.NoError(t, err)
+
+		_, err = svc.CreateOAuthClient(ctx, admin, &service.CreateOAuthClientRequest{
+			Name:           "Escalate",
+			Scopes:         []string{"user:read", "role:manage"},
+			ServiceAccount: true,
+		})
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+		assert.Nil(t, repo.savedClient)
+
+		_, err = svc.CreateOAuthClient(ctx, mustActor(t, domain.RoleSuperAdmin), &service.CreateOAuthClientRequest{
+			Name:           "Provisioning",
+			Scopes:         []string{"role:manage"},
+			ServiceAccount: true,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("public service account", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.CreateOAuthClient(ctx, admin, &service.CreateOAuthClientRequest{
+			Name:           "Billing",
+			Public:         true,
+			ServiceAccount: true,
+		})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+	})
+}
+
+func TestServiceManageOAuthClientSecret(t *testing.T) {
+	ctx := context.Background()
+	admin := mustActor(t, domain.RoleAdmin)
+
+	newSvc := func(t *testing.T, repo *mockOAuthClientRepo, audit *mockAuditLogger) service.Service {
+		t.Helper()
+
+		svc, err := newTestServiceWith(testDeps{
+			OAuthClients: repo,
+			AuditLogger:  audit,
+			Opaque:       &mockOpaqueTokenManager{generateToken: "new-secret"},
+		})
+		require.NoError(t, err)
+
+		return svc
+	}
+
+	t.Run("rotate", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{"svc": mustServiceClient(t, "svc")}}
+		audit := &mockAuditLogger{}
+
+		got, err := newSvc(t, repo, audit).RotateOAuthClientSecret(ctx, admin, "svc")
+		require.NoError(t, err)
+		assert.Equal(t, "new-secret", got.Secret)
+		require.NotNil(t, repo.updatedClient)
+		assert.Equal(t, "hashed-new-secret", repo.updatedClient.SecretHash)
+		assert.Equal(t, domain.AuditActionOAuthRotate, audit.last().Action)
+	})
+
+	t.Run("rotate public client", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{"spa": mustOAuthClient(t, "spa", true)}}
+
+		_, err := newSvc(t, repo, nil).RotateOAuthClientSecret(ctx, admin, "spa")
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+		assert.Nil(t, repo.updatedClient)
+	})
+
+	t.Run("unknown client", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newSvc(t, &mockOAuthClientRepo{}, nil).RotateOAuthClientSecret(ctx, admin, "nope")
+		assertAppErrorCode(t, err, apperror.ErrCodeOAuthClientNotFound)
+	})
+
+	t.Run("actor lacks oauth_client:manage", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{"svc": mustServiceClient(t, "svc")}}
+
+		err := newSvc(t, repo, nil).DisableOAuthClient(ctx, mustActor(t, domain.RoleUser), "svc")
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+	})
+
+	t.Run("client scopes beyond the actor's role", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := domain.NewServiceClient("svc", "Provisioning", "hashed-"+testClientSecret, []string{"role:manage"})
+		require.NoError(t, err)
+
+		repo := &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{"svc": client}}
+		svc := newSvc(t, repo, nil)
+
+		_, err = svc.RotateOAuthClientSecret(ctx, admin, "svc")
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+
+		err = svc.DisableOAuthClient(ctx, admin, "svc")
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+		assert.Nil(t, repo.updatedClient)
+
+		_, err = svc.RotateOAuthClientSecret(ctx, mustActor(t, domain.RoleSuperAdmin), "svc")
+		require.NoError(t, err)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{"svc": mustServiceClient(t, "svc")}}
+		audit := &mockAuditLogger{}
+		svc := newSvc(t, repo, audit)
+
+		require.NoError(t, svc.DisableOAuthClient(ctx, admin, "svc"))
+		require.NotNil(t, repo.updatedClient)
+		assert.True(t, repo.updatedClient.IsDisabled())
+		assert.Equal(t, domain.AuditActionOAuthDisable, audit.last().Action)
+
+		err := svc.DisableOAuthClient(ctx, admin, "svc")
+		assertAppErrorCode(t, err, apperror.ErrCodeOAuthClientDisabled)
+
+		_, err = svc.Token(ctx, &service.TokenRequest{
+			GrantType:    domain.GrantTypeClientCredentials,
+			ClientID:     "svc",
+			ClientSecret: testClientSecret,
+			ClientIP:     "1.2.3.4",
+		})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidClient)
+	})
+}
+
+func TestServiceAuthorize(t *testing.T) {
+	ctx := context.Background()
+	const clientID = "client-1"
+
+	withReq := func(fn func(*service.AuthorizeRequest)) *service.AuthorizeRequest {
+		req := validAuthorizeReq(clientID)
+		fn(req)
+
+		return req
+	}
+
+	user := mustRecentActor(t, domain.RoleUser)
+	clientActor := mustRecentActor(t, domain.RoleUser)
+	clientActor.ClientID = "other-client"
+
+	tests := []struct {
+		name     string
+		actor    *domain.AccessClaims
+		req      *service.AuthorizeRequest
+		wantCode apperror.Code
+	}{
+		{
+			name:     "no actor",
+			actor:    nil,
+			req:      validAuthorizeReq(clientID),
+			wantCode: apperror.ErrCodeUnauthorized,
+		},
+		{
+			name:     "actor is an oauth client token",
+			actor:    clientActor,
+			req:      validAuthorizeReq(clientID),
+			wantCode: apperror.ErrCodePermissionDenied,
+		},
+		{
+			name:     "stale authentication",
+			actor:    mustActor(t, domain.RoleUser),
+			req:      validAuthorizeReq(clientID),
+			wantCode: apperror.ErrCodeReauthenticationRequired,
+		},
+		{
+			name:     "unknown client",
+			actor:    user,
+			req:      validAuthorizeReq("nope"),
+			wantCode: apperror.ErrCodeInvalidClient,
+		},
+		{
+			name:     "unregistered redirect uri",
+			actor:    user,
+			req:      withReq(func(r *service.AuthorizeRequest) { r.RedirectURI = "https://evil.example.com/cb" }),
+			wantCode: apperror.ErrCodeInvalidRedirectURI,
+		},
+		{
+			name:     "token response type",
+			actor:    user,
+			req:      withReq(func(r *service.AuthorizeRequest) { r.ResponseType = "token" }),
+			wantCode: apperror.ErrCodeUnsupportedResponseType,
+		},
+		{
+			name:     "scope not registered",
+			actor:    user,
+			req:      withReq(func(r *service.AuthorizeRequest) { r.Scope = "profile user:delete" }),
+			wantCode: apperror.ErrCodeInvalidScope,
+		},
+		{
+			name:     "missing code challenge",
+			actor:    user,
+			req:      withReq(func(r *service.AuthorizeRequest) { r.CodeChallenge = "" }),
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:     "plain challenge method",
+			actor:    user,
+			req:      withReq(func(r *service.AuthorizeRequest) { r.CodeChallengeMethod = "plain" }),
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, err := newTestServiceWith(testDeps{
+				OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+					clientID: mustOAuthClient(t, clientID, false),
+				}},
+			})
+			require.NoError(t, err)
+
+			_, err = svc.Authorize(ctx, tt.actor, tt.req)
+			assertAppErrorCode(t, err, tt.wantCode)
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		codes := &mockAuthCodeRepo{}
+		audit := &mockAuditLogger{}
+		svc, err := newTestServiceWith(testDeps{
+			OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+				clientID: mustOAuthClient(t, clientID, false),
+			}},
+			AuthCodes:   codes,
+			AuditLogger: audit,
+			Opaque:      &mockOpaqueTokenManager{generateToken: "auth-code"},
+		})
+		require.NoError(t, err)
+
+		actor := mustRecentActor(t, domain.RoleUser)
+
+		got, err := svc.Authorize(ctx, actor, validAuthorizeReq(clientID))
+		require.NoError(t, err)
+		assert.Equal(t, "auth-code", got.Code)
+
+		redirect, err := url.Parse(got.RedirectTo)
+		require.NoError(t, err)
+		assert.Equal(t, "app.example.com", redirect.Host)
+		assert.Equal(t, "auth-code", redirect.Query().Get("code"))
+		assert.Equal(t, "xyz", redirect.Query().Get("state"))
+
+		require.NotNil(t, codes.savedCode)
+		assert.Equal(t, "hashed-auth-code", codes.savedCode.CodeHash)
+		assert.Equal(t, actor.UserID, codes.savedCode.UserID)
+		assert.Equal(t, []string{"profile"}, codes.savedCode.Scopes)
+		assert.Equal(t, domain.AuditActionOAuthAuthorize, audit.last().Action)
+	})
+}
+
+// oauthTokenFixture is a service with a client and an authorization code issued to it.
+type oauthTokenFixture struct {
+	svc      service.Service
+	sessions *mockSessionRepo
+	codes    *mockAuthCodeRepo
+	access   *mockAccessTokenManager
+	audit    *mockAuditLogger
+	code     string
+}
+
+func newOAuthTokenFixture(t *testing.T, clientID string, public bool) *oauthTokenFixture {
+	t.Helper()
+
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	f := &oauthTokenFixture{
+		sessions: &mockSessionRepo{},
+		codes:    &mockAuthCodeRepo{},
+		access:   &mockAccessTokenManager{},
+		audit:    &mockAuditLogger{},
+	}
+
+	svc, err := newTestServiceWith(testDeps{
+		UserRepo: &mockUserRepo{getByIDUser: user},
+		OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+			clientID: mustOAuthClient(t, clientID, public),
+		}},
+		SessionRepo: f.sessions,
+		AuthCodes:   f.codes,
+		Access:      f.access,
+		AuditLogger: f.audit,
+	})
+	require.NoError(t, err)
+
+	actor := &domain.AccessClaims{UserID: user.ID, Role: user.Role, AuthTime: time.Now()}
+
+	authorized, err := svc.Authorize(context.Background(), actor, validAuthorizeReq(clientID))
+	require.NoError(t, err)
+
+	f.svc = svc
+	f.code = authorized.Code
+
+	return f
+}
+
+func (f *oauthTokenFixture) codeReq(clientID, secret string) *service.TokenRequest {
+	return &service.TokenRequest{
+		GrantType:    domain.GrantTypeAuthorizationCode,
+		ClientID:     clientID,
+		ClientSecret: secret,
+		Code:         f.code,
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: testCodeVerifier,
+		UserAgent:    "ua",
+		ClientIP:     "1.2.3.4",
+	}
+}
+
+func TestServiceTokenAuthorizationCode(t *testing.T) {
+	ctx := context.Background()
+	const clientID = "client-1"
+
+	tests := []struct {
+		name     string
+		public   bool
+		mutate   func(*service.TokenRequest)
+		wantCode apperror.Code
+	}{
+		{
+			name:     "unknown client",
+			mutate:   func(r *service.TokenRequest) { r.ClientID = "nope" },
+			wantCode: apperror.ErrCodeInvalidClient,
+		},
+		{
+			name:     "wrong client secret",
+			mutate:   func(r *service.TokenRequest) { r.ClientSecret = "wrong" },
+			wantCode: apperror.ErrCodeInvalidClient,
+		},
+		{
+			name:     "confidential client without secret",
+			mutate:   func(r *service.TokenRequest) { r.ClientSecret = "" },
+			wantCode: apperror.ErrCodeInvalidClient,
+		},
+		{
+			name:     "public client sending a secret",
+			public:   true,
+			mutate:   func(r *service.TokenRequest) { r.ClientSecret = testClientSecret },
+			wantCode: apperror.ErrCodeInvalidClient,
+		},
+		{
+			name:     "unsupported grant type",
+			mutate:   func(r *service.TokenRequest) { r.GrantType = "password" },
+			wantCode: apperror.ErrCodeUnsupportedGrantType,
+		},
+		{
+			name:     "missing code verifier",
+			mutate:   func(r *service.TokenRequest) { r.CodeVerifier = "" },
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:     "unknown code",
+			mutate:   func(r *service.TokenRequest) { r.Code = "other" },
+			wantCode: apperror.ErrCodeInvalidGrant,
+		},
+		{
+			name:     "redirect uri differs from authorize request",
+			mutate:   func(r *service.TokenRequest) { r.RedirectURI = "https://app.example.com/other" },
+			wantCode: apperror.ErrCodeInvalidGrant,
+		},
+		{
+			name:     "wrong code verifier",
+			mutate:   func(r *service.TokenRequest) { r.CodeVerifier = "x" + testCodeVerifier[1:] },
+			wantCode: apperror.ErrCodeInvalidGrant,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newOAuthTokenFixture(t, clientID, tt.public)
+
+			secret := testClientSecret
+			if tt.public {
+				secret = ""
+			}
+
+			req := f.codeReq(clientID, secret)
+			tt.mutate(req)
+
+			_, err := f.svc.Token(ctx, req)
+			assertAppErrorCode(t, err, tt.wantCode)
+			assert.Nil(t, f.sessions.savedSession)
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		f := newOAuthTokenFixture(t, clientID, false)
+
+		got, err := f.svc.Token(ctx, f.codeReq(clientID, testClientSecret))
+		require.NoError(t, err)
+		assert.NotEmpty(t, got.AccessToken)
+		assert.NotEmpty(t, got.RefreshToken)
+		assert.Equal(t, []string{"profile"}, got.Scopes)
+
+		require.NotNil(t, f.sessions.savedSession)
+		assert.Equal(t, clientID, f.sessions.savedSession.ClientID)
+		assert.Equal(t, []string{"profile"}, f.sessions.savedSession.Scopes)
+
+		assert.Equal(t, clientID, f.access.lastClaims.ClientID)
+		assert.Equal(t, []string{"profile"}, f.access.lastClaims.Scopes)
+		assert.Empty(t, f.access.lastClaims.Permissions)
+		assert.Equal(t, domain.AuditActionOAuthToken, f.audit.last().Action)
+	})
+
+	t.Run("public client", func(t *testing.T) {
+		t.Parallel()
+
+		f := newOAuthTokenFixture(t, clientID, true)
+
+		_, err := f.svc.Token(ctx, f.codeReq(clientID, ""))
+		require.NoError(t, err)
+	})
+
+	t.Run("code is single use", func(t *testing.T) {
+		t.Parallel()
+
+		f := newOAuthTokenFixture(t, clientID, false)
+
+		_, err := f.svc.Token(ctx, f.codeReq(clientID, testClientSecret))
+		require.NoError(t, err)
+
+		_, err = f.svc.Token(ctx, f.codeReq(clientID, testClientSecret))
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidGrant)
+
+		last := f.audit.last()
+		assert.Equal(t, domain.AuditOutcomeDenied, last.Outcome)
+		assert.Equal(t, "code_reused", last.Metadata["reason"])
+	})
+
+	t.Run("expired code", func(t *testing.T) {
+		t.Parallel()
+
+		f := newOAuthTokenFixture(t, clientID, false)
+		f.codes.savedCode.ExpiresAt = time.Now().UTC().Add(-time.Second)
+
+		_, err := f.svc.Token(ctx, f.codeReq(clientID, testClientSecret))
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidGrant)
+	})
+}
+
+func TestServiceTokenRefreshGrant(t *testing.T) {
+	ctx := context.Background()
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	const clientID = "client-1"
+
+	clientSession := func(t *testing.T, owner string) *domain.Session {
+		t.Helper()
+
+		session := mustSession(t, user.ID, 24*time.Hour, false)
+		session.ClientID = owner
+		session.Scopes = []string{"profile"}
+
+		return session
+	}
+
+	newSvc := func(t *testing.T, session *domain.Session, access *mockAccessTokenManager) service.Service {
+		t.Helper()
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:    &mockUserRepo{getByIDUser: user},
+			SessionRepo: &mockSessionRepo{getByToken: session},
+			OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+				clientID: mustOAuthClient(t, clientID, false),
+			}},
+			Access: access,
+		})
+		require.NoError(t, err)
+
+		return svc
+	}
+
+	refreshReq := &service.TokenRequest{
+		GrantType:    domain.GrantTypeRefreshToken,
+		ClientID:     clientID,
+		ClientSecret: testClientSecret,
+		RefreshToken: "token",
+		UserAgent:    "ua",
+		ClientIP:     "1.2.3.4",
+	}
+
+	t.Run("rotates the client's session", func(t *testing.T) {
+		t.Parallel()
+
+		access := &mockAccessTokenManager{}
+		svc := newSvc(t, clientSession(t, clientID), access)
+
+		got, err := svc.Token(ctx, refreshReq)
+		require.NoError(t, err)
+		assert.Equal(t, "derived-token", got.RefreshToken)
+		assert.Equal(t, []string{"profile"}, got.Scopes)
+		assert.Equal(t, clientID, access.lastClaims.ClientID)
+	})
+
+	t.Run("session of another client", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newSvc(t, clientSession(t, "client-2"), nil)
+
+		_, err := svc.Token(ctx, refreshReq)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidGrant)
+	})
+
+	t.Run("first-party session", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newSvc(t, clientSession(t, ""), nil)
+
+		_, err := svc.Token(ctx, refreshReq)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidGrant)
+	})
+
+	t.Run("client session on first-party refresh", func(t *testing.T) {
+		t.Parallel()
+
+		svc := newSvc(t, clientSession(t, clientID), nil)
+
+		_, err := svc.Refresh(ctx, validRefreshReq)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidToken)
+	})
+}
+
+func TestServiceTokenClientCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	newSvc := func(t *testing.T, access *mockAccessTokenManager) service.Service {
+		t.Helper()
+
+		svc, err := newTestServiceWith(testDeps{
+			OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+				"svc": mustServiceClient(t, "svc"),
+				"app": mustOAuthClient(t, "app", false),
+			}},
+			Access: access,
+		})
+		require.NoError(t, err)
+
+		return svc
+	}
+
+	credentialsReq := func(clientID, grantType, scope string) *service.TokenRequest {
+		return &service.TokenRequest{
+			GrantType:    grantType,
+			ClientID:     clientID,
+			ClientSecret: testClientSecret,
+			Scope:        scope,
+			Code:         "code",
+			CodeVerifier: testCodeVerifier,
+			UserAgent:    "ua",
+			ClientIP:     "1.2.3.4",
+		}
+	}
+
+	tests := []struct {
+		name     string
+		req      *service.TokenRequest
+		wantCode apperror.Code
+	}{
+		{
+			name:     "wrong secret",
+			req:      &service.TokenRequest{GrantType: domain.GrantTypeClientCredentials, ClientID: "svc", ClientSecret: "x"},
+			wantCode: apperror.ErrCodeInvalidClient,
+		},
+		{
+			name:     "scope not registered",
+			req:      credentialsReq("svc", domain.GrantTypeClientCredentials, "user:delete"),
+			wantCode: apperror.ErrCodeInvalidScope,
+		},
+		{
+			name:     "authorization code client",
+			req:      credentialsReq("app", domain.GrantTypeClientCredentials, ""),
+			wantCode: apperror.ErrCodeUnauthorizedClient,
+		},
+		{
+			name:     "service account redeeming a code",
+			req:      credentialsReq("svc", domain.GrantTypeAuthorizationCode, ""),
+			wantCode: apperror.ErrCodeUnauthorizedClient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			access := &mockAccessTokenManager{}
+
+			_, err := newSvc(t, access).Token(ctx, tt.req)
+			assertAppErrorCode(t, err, tt.wantCode)
+			assert.Empty(t, access.lastClaims.ClientID)
+		})
+	}
+
+	t.Run("issues a client token", func(t *testing.T) {
+		t.Parallel()
+
+		access := &mockAccessTokenManager{}
+
+		got, err := newSvc(t, access).Token(ctx, credentialsReq("svc", domain.GrantTypeClientCredentials, ""))
+		require.NoError(t, err)
+		assert.NotEmpty(t, got.AccessToken)
+		assert.Empty(t, got.RefreshToken)
+		assert.Equal(t, []string{"audit:read", "user:read"}, got.Scopes)
+
+		assert.True(t, access.lastClaims.IsClient())
+		assert.Equal(t, "svc", access.lastClaims.ClientID)
+		assert.Equal(t, uuid.Nil, access.lastClaims.UserID)
+		assert.Empty(t, access.lastClaims.Permissions)
+	})
+
+	t.Run("narrowed scope", func(t *testing.T) {
+		t.Parallel()
+
+		access := &mockAccessTokenManager{}
+
+		got, err := newSvc(t, access).Token(ctx, credentialsReq("svc", domain.GrantTypeClientCredentials, "user:read"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user:read"}, got.Scopes)
+	})
+}
+
+func TestServiceAuthorizeServiceAccount(t *testing.T) {
+	ctx := context.Background()
+
+	svc, err := newTestServiceWith(testDeps{})
+	require.NoError(t, err)
+
+	actor := &domain.AccessClaims{SubjectType: domain.SubjectTypeClient, ClientID: "svc", Scopes: []string{"user:read"}}
+
+	_, err = svc.ListAuditEvents(ctx, actor, &service.ListAuditEventsRequest{})
+	assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+
+	actor.Scopes = append(actor.Scopes, domain.PermAuditRead.String(), domain.PermRoleManage.String())
+
+	// audit:read is deployment-wide, so the scope alone is not enough.
+	_, err = svc.ListAuditEvents(ctx, actor, &service.ListAuditEventsRequest{})
+	assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+
+	err = svc.ChangeUserRole(ctx, actor, &service.ChangeUserRoleRequest{UserID: uuid.New(), Role: domain.RoleSuperAdmin})
+	assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+
+	adminSvc, err := newTestServiceWith(testDeps{ClientAdmin: true})
+	require.NoError(t, err)
+
+	_, err = adminSvc.ListAuditEvents(ctx, actor, &service.ListAuditEventsRequest{})
+	require.NoError(t, err)
+
+	// Endpoints acting on the caller's own account need a user.
+	_, err = svc.ListKnownDevices(ctx, actor)
+	assertAppErrorCode(t, err, apperror.ErrCodeUnauthorized)
+}
+
+func TestServiceAuthorizeOAuthClientScopes(t *testing.T) {
+	ctx := context.Background()
+
+	svc, err := newTestServiceWith(testDeps{})
+	require.NoError(t, err)
+
+	actor := mustActor(t, domain.RoleAdmin)
+	actor.ClientID = "client-1"
+	actor.Scopes = []string{"user:read"}
+
+	// The admin role grants audit:read, but the client was not granted that scope.
+	_, err = svc.ListAuditEvents(ctx, actor, &service.ListAuditEventsRequest{})
+	assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+
+	actor.Scopes = append(actor.Scopes, domain.PermAuditRead.String())
+
+	_, err = svc.ListAuditEvents(ctx, actor, &service.ListAuditEventsRequest{})
+	require.NoError(t, err)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+// firstPartyScopes are released by UserInfo to tokens we issued to ourselves,
+// which are not limited to the scopes of an OAuth grant.
+var firstPartyScopes = []string{domain.ScopeOpenID, domain.ScopeProfile, domain.ScopeEmail}
+
+type UserInfoResponse struct {
+	Subject           string
+	Name              string
+	GivenName         string
+	FamilyName        string
+	PreferredUsername string
+	Email             string
+	EmailVerified     *bool
+}
+
+func (s *service) UserInfo(ctx context.Context, actor *domain.AccessClaims) (*UserInfoResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	scopes := firstPartyScopes
+	if actor.ClientID != "" {
+		if !actor.HasScopes(domain.ScopeOpenID) {
+			return nil, apperror.Forbidden(apperror.ErrCodeInsufficientScope, apperror.MsgInsufficientScope, nil)
+		}
+
+		scopes = actor.Scopes
+	}
+
+	user, err := s.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil {
+		return nil, apperror.NotFound(apperror.ErrCodeUserNotFound, apperror.MsgUserNotFound, nil)
+	}
+
+	info := domain.NewUserInfo(user, scopes)
+
+	return &UserInfoResponse{
+		Subject:           info.Subject,
+		Name:              info.Name,
+		GivenName:         info.GivenName,
+		FamilyName:        info.FamilyName,
+		PreferredUsername: info.PreferredUsername,
+		Email:             info.Email,
+		EmailVerified:     info.EmailVerified,
+	}, nil
+}
+
+func (s *service) issueIDToken(user *domain.User, code *domain.AuthorizationCode) (string, error) {
+	if s.idTokenManager == nil || !slices.Contains(code.Scopes, domain.ScopeOpenID) {
+		return "", nil
+	}
+
+	idToken, err := s.idTokenManager.Generate(domain.IDClaims{
+		UserInfo: domain.NewUserInfo(user, code.Scopes),
+		ClientID: code.ClientID,
+		Nonce:    code.Nonce,
+	})
+	if err != nil {
+		return "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateIDToken, err)
+	}
+
+	return idToken, nil
+}
@@ -0,0 +1,205 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+const oidcClientID = "oidc-client"
+
+func mustOIDCClient(t *testing.T) *domain.OAuthClient {
+	t.Helper()
+
+	client, err := domain.NewOAuthClient(oidcClientID, "Example App", "hashed-"+testClientSecret,
+		[]string{testRedirectURI}, []string{domain.ScopeOpenID, domain.ScopeProfile, domain.ScopeEmail})
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestServiceAuthorizeOpenID(t *testing.T) {
+	ctx := context.Background()
+	actor := mustRecentActor(t, domain.RoleUser)
+
+	openIDReq := func(fn func(*service.AuthorizeRequest)) *service.AuthorizeRequest {
+		req := validAuthorizeReq(oidcClientID)
+		req.Scope = "openid email"
+		req.Nonce = "n-0S6_WzA2Mj"
+		fn(req)
+
+		return req
+	}
+
+	tests := []struct {
+		name     string
+		idTokens *mockIDTokenManager
+		req      *service.AuthorizeRequest
+		wantCode apperror.Code
+	}{
+		{
+			name:     "openid without an id token signer",
+			req:      openIDReq(func(*service.AuthorizeRequest) {}),
+			wantCode: apperror.ErrCodeInvalidScope,
+		},
+		{
+			name:     "all client scopes include openid",
+			req:      openIDReq(func(r *service.AuthorizeRequest) { r.Scope = "" }),
+			wantCode: apperror.ErrCodeInvalidScope,
+		},
+		{
+			name:     "nonce too long",
+			idTokens: &mockIDTokenManager{},
+			req:      openIDReq(func(r *service.AuthorizeRequest) { r.Nonce = strings.Repeat("n", 256) }),
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, err := newTestServiceWith(testDeps{
+				OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+					oidcClientID: mustOIDCClient(t),
+				}},
+				IDTokens: tt.idTokens,
+			})
+			require.NoError(t, err)
+
+			_, err = svc.Authorize(ctx, actor, tt.req)
+			assertAppErrorCode(t, err, tt.wantCode)
+		})
+	}
+}
+
+func TestServiceTokenIDToken(t *testing.T) {
+	ctx := context.Background()
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+
+	exchange := func(t *testing.T, scope string) (*service.TokenResponse, *mockIDTokenManager) {
+		t.Helper()
+
+		idTokens := &mockIDTokenManager{}
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo: &mockUserRepo{getByIDUser: user},
+			OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+				oidcClientID: mustOIDCClient(t),
+			}},
+			IDTokens: idTokens,
+		})
+		require.NoError(t, err)
+
+		req := validAuthorizeReq(oidcClientID)
+		req.Scope = scope
+		req.Nonce = "n-0S6_WzA2Mj"
+
+		actor := &domain.AccessClaims{UserID: user.ID, Role: user.Role, AuthTime: time.Now()}
+
+		authorized, err := svc.Authorize(ctx, actor, req)
+		require.NoError(t, err)
+
+		got, err := svc.Token(ctx, &service.TokenRequest{
+			GrantType:    domain.GrantTypeAuthorizationCode,
+			ClientID:     oidcClientID,
+			ClientSecret: testClientSecret,
+			Code:         authorized.Code,
+			RedirectURI:  testRedirectURI,
+			CodeVerifier: testCodeVerifier,
+			UserAgent:    "ua",
+			ClientIP:     "1.2.3.4",
+		})
+		require.NoError(t, err)
+
+		return got, idTokens
+	}
+
+	t.Run("openid email", func(t *testing.T) {
+		t.Parallel()
+
+		got, idTokens := exchange(t, "openid email")
+		assert.Equal(t, "id-token", got.IDToken)
+
+		require.NotNil(t, idTokens.lastClaims)
+		claims := idTokens.lastClaims
+		assert.Equal(t, user.ID.String(), claims.Subject)
+		assert.Equal(t, oidcClientID, claims.ClientID)
+		assert.Equal(t, "n-0S6_WzA2Mj", claims.Nonce)
+		assert.Equal(t, "alice@example.com", claims.Email)
+		require.NotNil(t, claims.EmailVerified)
+		assert.True(t, *claims.EmailVerified)
+		assert.Empty(t, claims.Name)
+	})
+
+	t.Run("without openid", func(t *testing.T) {
+		t.Parallel()
+
+		got, idTokens := exchange(t, "profile")
+		assert.Empty(t, got.IDToken)
+		assert.Nil(t, idTokens.lastClaims)
+	})
+}
+
+func TestServiceUserInfo(t *testing.T) {
+	ctx := context.Background()
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+
+	clientToken := func(scopes ...string) *domain.AccessClaims {
+		return &domain.AccessClaims{UserID: user.ID, ClientID: oidcClientID, Scopes: scopes}
+	}
+
+	tests := []struct {
+		name      string
+		actor     *domain.AccessClaims
+		wantCode  apperror.Code
+		wantName  string
+		wantEmail string
+	}{
+		{name: "anonymous", actor: nil, wantCode: apperror.ErrCodeUnauthorized},
+		{
+			name:     "service account",
+			actor:    &domain.AccessClaims{SubjectType: domain.SubjectTypeClient, ClientID: "svc", Scopes: []string{"openid"}},
+			wantCode: apperror.ErrCodeUnauthorized,
+		},
+		{name: "client token without openid", actor: clientToken("email"), wantCode: apperror.ErrCodeInsufficientScope},
+		{name: "openid only", actor: clientToken("openid")},
+		{name: "openid email", actor: clientToken("openid", "email"), wantEmail: "alice@example.com"},
+		{name: "openid profile", actor: clientToken("openid", "profile"), wantName: "First Last"},
+		{
+			name:      "first-party token",
+			actor:     &domain.AccessClaims{UserID: user.ID, Role: user.Role},
+			wantName:  "First Last",
+			wantEmail: "alice@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, err := newTestServiceWith(testDeps{UserRepo: &mockUserRepo{getByIDUser: user}})
+			require.NoError(t, err)
+
+			got, err := svc.UserInfo(ctx, tt.actor)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, user.ID.String(), got.Subject)
+			assert.Equal(t, tt.wantName, got.Name)
+			assert.Equal(t, tt.wantEmail, got.Email)
+			assert.Equal(t, tt.wantEmail != "", got.EmailVerified != nil)
+		})
+	}
+}
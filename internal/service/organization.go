@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+type SwitchOrganizationRequest struct {
+	Organization string
+	RefreshToken string
+	UserAgent    string
+	ClientIP     string
+}
+
+type CreateOrganizationRequest struct {
+	Slug      string
+	Name      string
+	UserAgent string
+	ClientIP  string
+}
+
+type OrganizationResponse struct {
+	ID        uuid.UUID
+	Slug      string
+	Name      string
+	CreatedAt time.Time
+}
+
+type OrganizationMemberRequest struct {
+	Organization string
+	UserID       uuid.UUID
+	Role         string
+	UserAgent    string
+	ClientIP     string
+}
+
+type OrganizationMembershipResponse struct {
+	ID       uuid.UUID
+	Slug     string
+	Name     string
+	Role     string
+	JoinedAt time.Time
+	Active   bool
+}
+
+func (s *service) ListOrganizations(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+) ([]*OrganizationMembershipResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if s.organizationRepo == nil {
+		return nil, errOrganizationsDisabled()
+	}
+
+	memberships, err := s.organizationRepo.ListMemberships(ctx, actor.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListOrganizations, err)
+	}
+
+	out := make([]*OrganizationMembershipResponse, len(memberships))
+	for i, membership := range memberships {
+		out[i] = &OrganizationMembershipResponse{
+			ID:       membership.Organization.ID,
+			Slug:     membership.Organization.Slug,
+			Name:     membership.Organization.Name,
+			Role:     membership.Role.String(),
+			JoinedAt: membership.JoinedAt,
+			Active:   membership.Organization.ID == actor.OrganizationID,
+		}
+	}
+
+	return out, nil
+}
+
+func (s *service) SwitchOrganization(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *SwitchOrganizationRequest,
+) (*RefreshResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgOrganizationRequired, nil)
+	}
+
+	if req.RefreshToken == "" {
+		return nil, apperror.BadRequest(apperror.ErrCodeTokenRequired, apperror.MsgRefreshTokenRequired, nil)
+	}
+
+	if s.organizationRepo == nil {
+		return nil, errOrganizationsDisabled()
+	}
+
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshReq := &RefreshRequest{RefreshToken: req.RefreshToken, UserAgent: req.UserAgent, ClientIP: clientIP.String()}
+
+	if !s.clientIPAllowed(clientIP) {
+		s.auditOrganizationSwitch(ctx, actor, refreshReq, domain.AuditOutcomeDenied, map[string]any{"reason": "ip_denied"})
+
+		return nil, errIPNotAllowed()
+	}
+
+	target, err := s.switchTarget(ctx, actor.UserID, req.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTokenHash, err := s.opaqueTokenManager.Hash(req.RefreshToken)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	session, err := s.getSessionForRefresh(ctx, refreshTokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.UserID != actor.UserID || session.ClientID != "" {
+		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgSessionNotActive, nil)
+	}
+
+	if err = s.checkRefreshable(session); err != nil {
+		return nil, err
+	}
+
+	if err = s.checkSessionBinding(ctx, session, refreshReq); err != nil {
+		return nil, err
+	}
+
+	newSession, newRefreshToken, err := s.rotateSession(ctx, session, refreshReq, target)
+	if errors.Is(err, domain.ErrSessionRevoked) {
+		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgSessionNotActive, nil)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.buildRefresh(ctx, newSession, newRefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditOrganizationSwitch(ctx, actor, refreshReq, domain.AuditOutcomeSuccess, map[string]any{
+		"from":                organizationString(session.OrganizationID),
+		"to":                  organizationString(target),
+		"session_id":          newSession.ID.String(),
+		"previous_session_id": session.ID.String(),
+	})
+
+	return resp, nil
+}
+
+func (s *service) switchTarget(ctx context.Context, userID uuid.UUID, slug string) (*uuid.UUID, error) {
+	if slug == "" {
+		return nil, nil
+	}
+
+	organization, err := s.getOrganization(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.organizationRepo.GetMember(ctx, organization.ID, userID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetMember, err)
+	}
+
+	if member == nil {
+		return nil, errOrganizationMembershipRequired()
+	}
+
+	return &organization.ID, nil
+}
+
+func (s *service) CreateOrganization(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *CreateOrganizationRequest,
+) (*OrganizationResponse, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgOrganizationRequired, nil)
+	}
+
+	if s.organizationRepo == nil {
+		return nil, errOrganizationsDisabled()
+	}
+
+	if err := s.authorize(actor, domain.PermOrganizationManage); err != nil {
+		return nil, err
+	}
+
+	organization, err := domain.NewOrganization(req.Slug, req.Name)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	if err = s.organizationRepo.Save(ctx, organization); err != nil {
+		if errors.Is(err, domain.ErrOrganizationSlugTaken) {
+			return nil, apperror.Conflict(apperror.ErrCodeOrganizationAlreadyExists, apperror.MsgOrganizationExists, err)
+		}
+
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveOrganization, err)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionOrgCreated, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(map[string]any{"organization_id": organization.ID.String(), "slug": organization.Slug}))
+
+	return &OrganizationResponse{
+		ID:        organization.ID,
+		Slug:      organization.Slug,
+		Name:      organization.Name,
+		CreatedAt: organization.CreatedAt,
+	}, nil
+}
+
+func (s *service) SetOrganizationMember(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *OrganizationMemberRequest,
+) error {
+	organization, err := s.managedOrganization(ctx, actor, req, domain.AuditActionMemberSet)
+	if err != nil {
+		return err
+	}
+
+	role, err := domain.NewRole(req.Role)
+	if err != nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil {
+		return apperror.NotFound(apperror.ErrCodeUserNotFound, apperror.MsgUserNotFound, nil)
+	}
+
+	member, err := domain.NewOrganizationMember(organization.ID, user.ID, role)
+	if err != nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	if err = s.organizationRepo.SaveMember(ctx, member); err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveMember, err)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionMemberSet, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithTarget(user.ID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(map[string]any{"organization_id": organization.ID.String(), "role": role.String()}))
+
+	return nil
+}
+
+func (s *service) RemoveOrganizationMember(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *OrganizationMemberRequest,
+) error {
+	organization, err := s.managedOrganization(ctx, actor, req, domain.AuditActionMemberRemoved)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.organizationRepo.RemoveMember(ctx, organization.ID, req.UserID)
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgRemoveMember, err)
+	}
+
+	if !removed {
+		return apperror.NotFound(
+			apperror.ErrCodeOrganizationMemberNotFound,
+			apperror.MsgOrganizationMemberAbsent,
+			nil,
+		)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionMemberRemoved, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithTarget(req.UserID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(map[string]any{"organization_id": organization.ID.String()}))
+
+	return nil
+}
+
+func (s *service) managedOrganization(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *OrganizationMemberRequest,
+	action domain.AuditAction,
+) (*domain.Organization, error) {
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgOrganizationRequired, nil)
+	}
+
+	if s.organizationRepo == nil {
+		return nil, errOrganizationsDisabled()
+	}
+
+	if err := s.authorize(actor, domain.PermOrganizationManage); err != nil {
+		s.auditDenied(ctx, action, actor, req.UserID, req.UserAgent, req.ClientIP)
+
+		return nil, err
+	}
+
+	return s.getOrganization(ctx, req.Organization)
+}
+
+func (s *service) getOrganization(ctx context.Context, slug string) (*domain.Organization, error) {
+	organization, err := s.findOrganization(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if organization == nil {
+		return nil, apperror.NotFound(apperror.ErrCodeOrganizationNotFound, apperror.MsgOrganizationNotFound, nil)
+	}
+
+	return organization, nil
+}
+
+func (s *service) findOrganization(ctx context.Context, slug string) (*domain.Organization, error) {
+	if s.organizationRepo == nil {
+		return nil, errOrganizationsDisabled()
+	}
+
+	normalized, err := domain.NormalizeOrganizationSlug(slug)
+	if err != nil {
+		return nil, nil //nolint:nilerr // A malformed slug names no organisation.
+	}
+
+	organization, err := s.organizationRepo.GetBySlug(ctx, normalized)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetOrganization, err)
+	}
+
+	return organization, nil
+}
+
+func (s *service) accountScope(ctx context.Context, slug string) (*uuid.UUID, bool, error) {
+	if s.userScope != UserScopeOrganization || slug == "" {
+		return nil, true, nil
+	}
+
+	organization, err := s.findOrganization(ctx, slug)
+	if err != nil || organization == nil {
+		return nil, false, err
+	}
+
+	return &organization.ID, true, nil
+}
+
+func (s *service) userByUsername(
+	ctx context.Context,
+	scope *uuid.UUID,
+	username domain.Username,
+) (*domain.User, error) {
+	if s.userScope == UserScopeOrganization {
+		return s.userRepo.GetByUsernameInOrganization(ctx, scope, username)
+	}
+
+	return s.userRepo.GetByUsername(ctx, username)
+}
+
+func (s *service) userByEmail(ctx context.Context, scope *uuid.UUID, email domain.Email) (*domain.User, error) {
+	if s.userScope == UserScopeOrganization {
+		return s.userRepo.GetByEmailInOrganization(ctx, scope, email)
+	}
+
+	return s.userRepo.GetByEmail(ctx, email)
+}
+
+func (s *service) usernameTaken(ctx context.Context, scope *uuid.UUID, username domain.Username) (bool, error) {
+	if s.userScope == UserScopeOrganization {
+		return s.userRepo.ExistsByUsernameInOrganization(ctx, scope, username)
+	}
+
+	return s.userRepo.ExistsByUsername(ctx, username)
+}
+
+func (s *service) emailTaken(ctx context.Context, scope *uuid.UUID, email domain.Email) (bool, error) {
+	if s.userScope == UserScopeOrganization {
+		return s.userRepo.ExistsByEmailInOrganization(ctx, scope, email)
+	}
+
+	return s.userRepo.ExistsByEmail(ctx, email)
+}
+
+func (s *service) auditOrganizationSwitch(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *RefreshRequest,
+	outcome domain.AuditOutcome,
+	metadata map[string]any,
+) {
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionOrgSwitched, outcome).
+		WithActorClaims(actor).
+		WithTarget(actor.UserID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(metadata))
+}
+
+func organizationString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func errOrganizationsDisabled() error {
+	return apperror.NotImplemented(apperror.ErrCodeOrganizationsDisabled, apperror.MsgOrganizationsDisabled, nil)
+}
+
+func errOrganizationMembershipRequired() error {
+	return apperror.Forbidden(
+		apperror.ErrCodeOrganizationMembershipRequired,
+		apperror.MsgOrganizationMemberOnly,
+		nil,
+	)
+}
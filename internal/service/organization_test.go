@@ -0,0 +1,411 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+func mustOrganization(t *testing.T, repo *mockOrganizationRepo, slug string) *domain.Organization {
+	t.Helper()
+
+	organization, err := domain.NewOrganization(slug, "Acme")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), organization))
+
+	return organization
+}
+
+func mustMember(t *testing.T, repo *mockOrganizationRepo, organizationID, userID uuid.UUID, role string) {
+	t.Helper()
+
+	r, err := domain.NewRole(role)
+	require.NoError(t, err)
+
+	member, err := domain.NewOrganizationMember(organizationID, userID, r)
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveMember(context.Background(), member))
+}
+
+func TestServiceSwitchOrganization(t *testing.T) {
+	ctx := context.Background()
+
+	switchReq := func(slug string) *service.SwitchOrganizationRequest {
+		return &service.SwitchOrganizationRequest{
+			Organization: slug,
+			RefreshToken: "token",
+			UserAgent:    "ua",
+			ClientIP:     "1.2.3.4",
+		}
+	}
+
+	t.Run("scopes tokens to the organisation role", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+		organizations := &mockOrganizationRepo{}
+		acme := mustOrganization(t, organizations, "acme")
+		mustMember(t, organizations, acme.ID, user.ID, domain.RoleAdmin)
+
+		sessions := &mockSessionRepo{getByToken: mustSession(t, user.ID, 24*time.Hour, false)}
+		access := &mockAccessTokenManager{generateToken: "scoped"}
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:         &mockUserRepo{getByIDUser: user},
+			SessionRepo:      sessions,
+			Access:           access,
+			AuditLogger:      auditLog,
+			Organizations:    organizations,
+			PermissionClaims: service.PermissionClaimsPermissions,
+		})
+		require.NoError(t, err)
+
+		res, err := svc.SwitchOrganization(ctx, &domain.AccessClaims{UserID: user.ID}, switchReq("ACME"))
+		require.NoError(t, err)
+		assert.Equal(t, "scoped", res.AccessToken)
+
+		claims := access.lastClaims
+		assert.Equal(t, acme.ID, claims.OrganizationID)
+		assert.Equal(t, domain.RoleAdmin, claims.Role.String())
+		assert.Contains(t, claims.Permissions, domain.PermUserRead)
+		assert.NotContains(t, claims.Permissions, domain.PermUserBan)
+
+		require.NotNil(t, sessions.savedSession.OrganizationID)
+		assert.Equal(t, acme.ID, *sessions.savedSession.OrganizationID)
+		assert.Equal(t, domain.AuditActionOrgSwitched, auditLog.last().Action)
+		assert.Equal(t, acme.ID.String(), auditLog.last().Metadata["to"])
+	})
+	t.Run("empty organisation leaves it", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+		organizationID := uuid.New()
+		session := mustSession(t, user.ID, 24*time.Hour, false)
+		session.OrganizationID = &organizationID
+		sessions := &mockSessionRepo{getByToken: session}
+		access := &mockAccessTokenManager{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      &mockUserRepo{getByIDUser: user},
+			SessionRepo:   sessions,
+			Access:        access,
+			Organizations: &mockOrganizationRepo{},
+		})
+		require.NoError(t, err)
+
+		actor := &domain.AccessClaims{UserID: user.ID, OrganizationID: organizationID}
+		_, err = svc.SwitchOrganization(ctx, actor, switchReq(""))
+		require.NoError(t, err)
+		assert.Nil(t, sessions.savedSession.OrganizationID)
+		assert.False(t, access.lastClaims.InOrganization())
+		assert.Equal(t, user.Role, access.lastClaims.Role)
+	})
+	t.Run("not a member", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+		organizations := &mockOrganizationRepo{}
+		mustOrganization(t, organizations, "acme")
+
+		svc, err := newTestServiceWith(testDeps{
+			SessionRepo:   &mockSessionRepo{getByToken: mustSession(t, user.ID, 24*time.Hour, false)},
+			Organizations: organizations,
+		})
+		require.NoError(t, err)
+
+		_, err = svc.SwitchOrganization(ctx, &domain.AccessClaims{UserID: user.ID}, switchReq("acme"))
+		assertAppErrorCode(t, err, apperror.ErrCodeOrganizationMembershipRequired)
+	})
+	t.Run("unknown organisation", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{Organizations: &mockOrganizationRepo{}})
+		require.NoError(t, err)
+
+		_, err = svc.SwitchOrganization(ctx, mustActor(t, domain.RoleUser), switchReq("acme"))
+		assertAppErrorCode(t, err, apperror.ErrCodeOrganizationNotFound)
+	})
+	t.Run("session of another user", func(t *testing.T) {
+		t.Parallel()
+
+		actor := mustActor(t, domain.RoleUser)
+
+		svc, err := newTestServiceWith(testDeps{
+			SessionRepo:   &mockSessionRepo{getByToken: mustSession(t, uuid.New(), 24*time.Hour, false)},
+			Organizations: &mockOrganizationRepo{},
+		})
+		require.NoError(t, err)
+
+		_, err = svc.SwitchOrganization(ctx, actor, switchReq(""))
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidToken)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.SwitchOrganization(ctx, mustActor(t, domain.RoleUser), switchReq("acme"))
+		assertAppErrorCode(t, err, apperror.ErrCodeOrganizationsDisabled)
+	})
+}
+
+func TestServiceRefreshOrganizationSession(t *testing.T) {
+	ctx := context.Background()
+
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	organizations := &mockOrganizationRepo{}
+	acme := mustOrganization(t, organizations, "acme")
+
+	session := mustSession(t, user.ID, 24*time.Hour, false)
+	session.OrganizationID = &acme.ID
+
+	svc, err := newTestServiceWith(testDeps{
+		UserRepo:      &mockUserRepo{getByIDUser: user},
+		SessionRepo:   &mockSessionRepo{getByToken: session},
+		Organizations: organizations,
+	})
+	require.NoError(t, err)
+
+	// Removed members can no longer refresh sessions scoped to the organisation.
+	_, err = svc.Refresh(ctx, validRefreshReq)
+	assertAppErrorCode(t, err, apperror.ErrCodeOrganizationMembershipRequired)
+}
+
+func TestServiceListOrganizations(t *testing.T) {
+	ctx := context.Background()
+
+	actor := mustActor(t, domain.RoleUser)
+	organizations := &mockOrganizationRepo{}
+	acme := mustOrganization(t, organizations, "acme")
+	globex := mustOrganization(t, organizations, "globex")
+	mustOrganization(t, organizations, "initech")
+	mustMember(t, organizations, acme.ID, actor.UserID, domain.RoleUser)
+	mustMember(t, organizations, globex.ID, actor.UserID, domain.RoleAdmin)
+	actor.OrganizationID = globex.ID
+
+	svc, err := newTestServiceWith(testDeps{Organizations: organizations})
+	require.NoError(t, err)
+
+	got, err := svc.ListOrganizations(ctx, actor)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "acme", got[0].Slug)
+	assert.False(t, got[0].Active)
+	assert.Equal(t, "globex", got[1].Slug)
+	assert.Equal(t, domain.RoleAdmin, got[1].Role)
+	assert.True(t, got[1].Active)
+
+	_, err = svc.ListOrganizations(ctx, nil)
+	assertAppErrorCode(t, err, apperror.ErrCodeUnauthorized)
+}
+
+func TestServiceCreateOrganization(t *testing.T) {
+	ctx := context.Background()
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+
+	t.Run("success and conflict", func(t *testing.T) {
+		t.Parallel()
+
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{Organizations: &mockOrganizationRepo{}, AuditLogger: auditLog})
+		require.NoError(t, err)
+
+		req := &service.CreateOrganizationRequest{Slug: "Acme", Name: "Acme Corp"}
+		got, err := svc.CreateOrganization(ctx, superadmin, req)
+		require.NoError(t, err)
+		assert.Equal(t, "acme", got.Slug)
+		assert.Equal(t, "Acme Corp", got.Name)
+		assert.Equal(t, domain.AuditActionOrgCreated, auditLog.last().Action)
+
+		_, err = svc.CreateOrganization(ctx, superadmin, req)
+		assertAppErrorCode(t, err, apperror.ErrCodeOrganizationAlreadyExists)
+	})
+	t.Run("invalid slug", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{Organizations: &mockOrganizationRepo{}})
+		require.NoError(t, err)
+
+		_, err = svc.CreateOrganization(ctx, superadmin, &service.CreateOrganizationRequest{Slug: "a_b", Name: "Acme"})
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidParam)
+	})
+	t.Run("admin forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{Organizations: &mockOrganizationRepo{}})
+		require.NoError(t, err)
+
+		req := &service.CreateOrganizationRequest{Slug: "acme", Name: "Acme"}
+		_, err = svc.CreateOrganization(ctx, mustActor(t, domain.RoleAdmin), req)
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+	})
+}
+
+func TestServiceOrganizationMembers(t *testing.T) {
+	ctx := context.Background()
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+
+	t.Run("set and remove", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "bob", "bob@example.com", "$hash")
+		organizations := &mockOrganizationRepo{}
+		acme := mustOrganization(t, organizations, "acme")
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      &mockUserRepo{getByIDUser: user},
+			Organizations: organizations,
+			AuditLogger:   auditLog,
+		})
+		require.NoError(t, err)
+
+		req := &service.OrganizationMemberRequest{Organization: "acme", UserID: user.ID, Role: domain.RoleAdmin}
+		require.NoError(t, svc.SetOrganizationMember(ctx, superadmin, req))
+
+		member, err := organizations.GetMember(ctx, acme.ID, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, member)
+		assert.Equal(t, domain.RoleAdmin, member.Role.String())
+		assert.Equal(t, domain.AuditActionMemberSet, auditLog.last().Action)
+
+		require.NoError(t, svc.RemoveOrganizationMember(ctx, superadmin, req))
+		assert.Equal(t, domain.AuditActionMemberRemoved, auditLog.last().Action)
+
+		err = svc.RemoveOrganizationMember(ctx, superadmin, req)
+		assertAppErrorCode(t, err, apperror.ErrCodeOrganizationMemberNotFound)
+	})
+	t.Run("unknown user", func(t *testing.T) {
+		t.Parallel()
+
+		organizations := &mockOrganizationRepo{}
+		mustOrganization(t, organizations, "acme")
+
+		svc, err := newTestServiceWith(testDeps{Organizations: organizations})
+		require.NoError(t, err)
+
+		req := &service.OrganizationMemberRequest{Organization: "acme", UserID: uuid.New(), Role: domain.RoleUser}
+		err = svc.SetOrganizationMember(ctx, superadmin, req)
+		assertAppErrorCode(t, err, apperror.ErrCodeUserNotFound)
+	})
+	t.Run("organisation admin cannot manage members", func(t *testing.T) {
+		t.Parallel()
+
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{Organizations: &mockOrganizationRepo{}, AuditLogger: auditLog})
+		require.NoError(t, err)
+
+		actor := mustActor(t, domain.RoleSuperAdmin)
+		actor.OrganizationID = uuid.New()
+
+		req := &service.OrganizationMemberRequest{Organization: "acme", UserID: uuid.New(), Role: domain.RoleUser}
+		err = svc.SetOrganizationMember(ctx, actor, req)
+		assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+		assert.Equal(t, domain.AuditOutcomeDenied, auditLog.last().Outcome)
+	})
+}
+
+func TestServiceOrganizationAccounts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("register joins the home organisation", func(t *testing.T) {
+		t.Parallel()
+
+		users := &mockUserRepo{}
+		organizations := &mockOrganizationRepo{}
+		acme := mustOrganization(t, organizations, "acme")
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      users,
+			Organizations: organizations,
+			UserScope:     service.UserScopeOrganization,
+		})
+		require.NoError(t, err)
+
+		req := *validRegisterReq
+		req.Organization = "acme"
+
+		res, err := svc.Register(ctx, &req)
+		require.NoError(t, err)
+		require.NotNil(t, users.lookupScope)
+		assert.Equal(t, acme.ID, *users.lookupScope)
+		assert.Equal(t, &acme.ID, users.savedUser.OrganizationID)
+
+		member, err := organizations.GetMember(ctx, acme.ID, res.UserID)
+		require.NoError(t, err)
+		require.NotNil(t, member)
+		assert.Equal(t, domain.RoleUser, member.Role.String())
+	})
+	t.Run("login in unknown organisation", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      &mockUserRepo{getByUsernameUser: user},
+			Hasher:        &mockPasswordHasher{compareOk: true},
+			Organizations: &mockOrganizationRepo{},
+			UserScope:     service.UserScopeOrganization,
+		})
+		require.NoError(t, err)
+
+		req := *validLoginReq
+		req.Organization = "acme"
+
+		_, err = svc.Login(ctx, &req)
+		assertAppErrorCode(t, err, apperror.ErrCodeInvalidCredentials)
+	})
+	t.Run("login starts in the home organisation", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+		organizations := &mockOrganizationRepo{}
+		acme := mustOrganization(t, organizations, "acme")
+		mustMember(t, organizations, acme.ID, user.ID, domain.RoleUser)
+		user.OrganizationID = &acme.ID
+
+		users := &mockUserRepo{getByUsernameUser: user}
+		sessions := &mockSessionRepo{}
+		access := &mockAccessTokenManager{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:      users,
+			SessionRepo:   sessions,
+			Hasher:        &mockPasswordHasher{compareOk: true},
+			Access:        access,
+			Organizations: organizations,
+			UserScope:     service.UserScopeOrganization,
+		})
+		require.NoError(t, err)
+
+		req := *validLoginReq
+		req.Organization = "acme"
+
+		_, err = svc.Login(ctx, &req)
+		require.NoError(t, err)
+		assert.Equal(t, &acme.ID, users.lookupScope)
+		assert.Equal(t, &acme.ID, sessions.savedSession.OrganizationID)
+		assert.Equal(t, acme.ID, access.lastClaims.OrganizationID)
+	})
+	t.Run("organisation scope requires the repository", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestServiceWith(testDeps{UserScope: service.UserScopeOrganization})
+		require.Error(t, err)
+
+		_, err = newTestServiceWith(testDeps{Organizations: &mockOrganizationRepo{}, UserScope: "tenant"})
+		require.Error(t, err)
+	})
+}
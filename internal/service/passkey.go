@@ -0,0 +1,446 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+const defaultPasskeyChallengeTTL = 5 * time.Minute
+
+type PasskeyRegistrationOptions struct {
+	Challenge          string
+	RPID               string
+	RPName             string
+	UserHandle         []byte
+	UserName           string
+	DisplayName        string
+	Algorithms         []int
+	ExcludeCredentials [][]byte
+	ExpiresAt          time.Time
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	Name        string
+	Attestation *domain.PasskeyAttestation
+	UserAgent   string
+	ClientIP    string
+}
+
+type PasskeyResponse struct {
+	ID         uuid.UUID
+	Name       string
+	Transports []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+type PasskeyLoginOptions struct {
+	Challenge string
+	RPID      string
+	ExpiresAt time.Time
+}
+
+type PasskeyLoginRequest struct {
+	Assertion *domain.PasskeyAssertion
+	UserAgent string
+	ClientIP  string
+}
+
+func (s *service) BeginPasskeyRegistration(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+) (*PasskeyRegistrationOptions, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireRecentAuth(actor); err != nil {
+		return nil, err
+	}
+
+	if s.passkeyVerifier == nil {
+		return nil, errPasskeysDisabled()
+	}
+
+	user, err := s.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil {
+		return nil, apperror.NotFound(apperror.ErrCodeUserNotFound, apperror.MsgUserNotFound, nil)
+	}
+
+	credentials, err := s.passkeyRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListPasskeys, err)
+	}
+
+	raw, challenge, err := s.issuePasskeyChallenge(ctx, domain.PasskeyCeremonyRegistration, &user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := make([][]byte, len(credentials))
+	for i, c := range credentials {
+		exclude[i] = c.CredentialID
+	}
+
+	rpID, rpName := s.passkeyVerifier.RelyingParty()
+
+	return &PasskeyRegistrationOptions{
+		Challenge:          raw,
+		RPID:               rpID,
+		RPName:             rpName,
+		UserHandle:         user.ID[:],
+		UserName:           user.Email.String(),
+		DisplayName:        user.FirstName + " " + user.LastName,
+		Algorithms:         s.passkeyVerifier.Algorithms(),
+		ExcludeCredentials: exclude,
+		ExpiresAt:          challenge.ExpiresAt,
+	}, nil
+}
+
+func (s *service) FinishPasskeyRegistration(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *FinishPasskeyRegistrationRequest,
+) (*PasskeyResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if err := s.requireRecentAuth(actor); err != nil {
+		return nil, err
+	}
+
+	if s.passkeyVerifier == nil {
+		return nil, errPasskeysDisabled()
+	}
+
+	if req == nil || req.Attestation == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgPasskeyRequestRequired, nil)
+	}
+
+	challenge, err := s.consumePasskeyChallenge(
+		ctx,
+		req.Attestation.ClientDataJSON,
+		domain.PasskeyCeremonyRegistration,
+		actor.UserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := s.passkeyVerifier.VerifyRegistration(challenge, req.Attestation)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodePasskeyInvalid, apperror.MsgPasskeyInvalid, err)
+	}
+
+	credential, err := domain.NewPasskeyCredential(actor.UserID, verified, req.Name)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	if err = s.passkeyRepo.Save(ctx, credential); err != nil {
+		if errors.Is(err, domain.ErrPasskeyAlreadyRegistered) {
+			return nil, apperror.Conflict(apperror.ErrCodePasskeyAlreadyExists, apperror.MsgPasskeyAlreadyExists, err)
+		}
+
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSavePasskey, err)
+	}
+
+	s.auditPasskey(ctx, domain.AuditActionPasskeyAdded, actor.UserID, credential, req.UserAgent, req.ClientIP)
+
+	return toPasskeyResponse(credential), nil
+}
+
+func (s *service) ListPasskeys(ctx context.Context, actor *domain.AccessClaims) ([]*PasskeyResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if s.passkeyVerifier == nil {
+		return nil, errPasskeysDisabled()
+	}
+
+	credentials, err := s.passkeyRepo.ListByUserID(ctx, actor.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListPasskeys, err)
+	}
+
+	out := make([]*PasskeyResponse, len(credentials))
+	for i, c := range credentials {
+		out[i] = toPasskeyResponse(c)
+	}
+
+	return out, nil
+}
+
+func (s *service) DeletePasskey(ctx context.Context, actor *domain.AccessClaims, id uuid.UUID) error {
+	if err := s.authenticate(actor); err != nil {
+		return err
+	}
+
+	if err := s.requireRecentAuth(actor); err != nil {
+		return err
+	}
+
+	if s.passkeyVerifier == nil {
+		return errPasskeysDisabled()
+	}
+
+	deleted, err := s.passkeyRepo.Delete(ctx, actor.UserID, id)
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgDeletePasskey, err)
+	}
+
+	if !deleted {
+		return apperror.NotFound(apperror.ErrCodePasskeyNotFound, apperror.MsgPasskeyNotFound, nil)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionPasskeyRemoved, domain.AuditOutcomeSuccess).
+		WithActor(actor.UserID).
+		WithTarget(actor.UserID).
+		WithMetadata(map[string]any{"passkey_id": id.String()}))
+
+	return nil
+}
+
+func (s *service) BeginPasskeyLogin(ctx context.Context) (*PasskeyLoginOptions, error) {
+	if s.passkeyVerifier == nil {
+		return nil, errPasskeysDisabled()
+	}
+
+	raw, challenge, err := s.issuePasskeyChallenge(ctx, domain.PasskeyCeremonyLogin, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rpID, _ := s.passkeyVerifier.RelyingParty()
+
+	return &PasskeyLoginOptions{Challenge: raw, RPID: rpID, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// FinishPasskeyLogin signs the user in with an assertion for the options from
+// BeginPasskeyLogin. A signature counter that did not advance denies the login, as the
+// credential may have been cloned.
+//
+// Unlike the other sign-in methods it does not issue an MFA challenge: the relying party
+// requires user verification, so the assertion already combines possession of the
+// authenticator with its PIN or biometric.
+func (s *service) FinishPasskeyLogin(ctx context.Context, req *PasskeyLoginRequest) (*LoginResponse, error) {
+	if s.passkeyVerifier == nil {
+		return nil, errPasskeysDisabled()
+	}
+
+	if req == nil || req.Assertion == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgPasskeyRequestRequired, nil)
+	}
+
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	loginReq := &LoginRequest{UserAgent: req.UserAgent, ClientIP: clientIP.String()}
+
+	if !s.clientIPAllowed(clientIP) {
+		s.auditLogin(ctx, loginReq, nil, domain.AuditOutcomeDenied, passkeyMetadata("reason", "ip_denied"))
+
+		return nil, errIPNotAllowed()
+	}
+
+	challenge, err := s.consumePasskeyChallenge(ctx, req.Assertion.ClientDataJSON, domain.PasskeyCeremonyLogin, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.passkeyRepo.GetByCredentialID(ctx, req.Assertion.CredentialID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetPasskey, err)
+	}
+
+	if credential == nil ||
+		(len(req.Assertion.UserHandle) > 0 && !bytes.Equal(req.Assertion.UserHandle, credential.UserID[:])) {
+		s.auditLogin(ctx, loginReq, nil, domain.AuditOutcomeFailure, passkeyMetadata("reason", "unknown_passkey"))
+
+		return nil, errPasskeyInvalid(nil)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, credential.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil {
+		return nil, errPasskeyInvalid(nil)
+	}
+
+	loginReq.Login = user.Email.String()
+
+	if err = s.usePasskey(ctx, challenge, credential, req.Assertion); err != nil {
+		reason := "invalid_assertion"
+		if errors.Is(err, domain.ErrPasskeySignCount) {
+			reason = "sign_count"
+		}
+
+		if errors.Is(err, domain.ErrPasskeyInvalid) || errors.Is(err, domain.ErrPasskeySignCount) {
+			s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeFailure, passkeyMetadata("reason", reason))
+
+			return nil, errPasskeyInvalid(err)
+		}
+
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUsePasskey, err)
+	}
+
+	if user.IsBanned() || !user.CanLogin() {
+		s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeDenied, passkeyMetadata("reason", "account_blocked"))
+
+		return nil, apperror.Forbidden(apperror.ErrCodeUserBlocked, apperror.MsgAccountAccessRevoked, nil)
+	}
+
+	resp, err := s.createSession(ctx, user, loginReq)
+	if err != nil {
+		return nil, err
+	}
+
+	info, isNew := s.trackDevice(ctx, user, loginReq)
+
+	s.auditLogin(ctx, loginReq, user, domain.AuditOutcomeSuccess, passkeyMetadata(
+		"passkey_id", credential.ID.String(),
+		"device", info.Family,
+		"new_device", isNew,
+	))
+
+	return resp, nil
+}
+
+func (s *service) usePasskey(
+	ctx context.Context,
+	challenge string,
+	credential *domain.PasskeyCredential,
+	assertion *domain.PasskeyAssertion,
+) error {
+	signCount, err := s.passkeyVerifier.VerifyAssertion(challenge, credential.PublicKey, assertion)
+	if err != nil {
+		return err
+	}
+
+	if err = credential.Use(signCount); err != nil {
+		return err
+	}
+
+	return s.passkeyRepo.Use(ctx, credential)
+}
+
+func (s *service) issuePasskeyChallenge(
+	ctx context.Context,
+	ceremony domain.PasskeyCeremony,
+	userID *uuid.UUID,
+) (string, *domain.PasskeyChallenge, error) {
+	raw, hash, err := s.generateOpaque()
+	if err != nil {
+		return "", nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGeneratePasskey, err)
+	}
+
+	challenge, err := domain.NewPasskeyChallenge(hash, ceremony, userID, s.passkeyChallengeTTL)
+	if err != nil {
+		return "", nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGeneratePasskey, err)
+	}
+
+	if err = s.passkeyChallengeRepo.Save(ctx, challenge); err != nil {
+		return "", nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSavePasskeyChallenge, err)
+	}
+
+	return raw, challenge, nil
+}
+
+// consumePasskeyChallenge spends the challenge the client data answers. It is gone even when
+// verification fails later. userID is the user a registration challenge must belong to.
+func (s *service) consumePasskeyChallenge(
+	ctx context.Context,
+	clientDataJSON []byte,
+	ceremony domain.PasskeyCeremony,
+	userID uuid.UUID,
+) (string, error) {
+	raw, err := s.passkeyVerifier.ClientChallenge(clientDataJSON)
+	if err != nil {
+		return "", errPasskeyInvalid(err)
+	}
+
+	hash, err := s.opaqueTokenManager.Hash(raw)
+	if err != nil {
+		return "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgConsumePasskey, err)
+	}
+
+	challenge, err := s.passkeyChallengeRepo.Consume(ctx, hash)
+	if err != nil {
+		return "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgConsumePasskey, err)
+	}
+
+	if challenge == nil || challenge.IsExpired() || challenge.Ceremony != ceremony {
+		return "", errPasskeyChallengeInvalid()
+	}
+
+	if ceremony == domain.PasskeyCeremonyRegistration && (challenge.UserID == nil || *challenge.UserID != userID) {
+		return "", errPasskeyChallengeInvalid()
+	}
+
+	return raw, nil
+}
+
+func (s *service) auditPasskey(
+	ctx context.Context,
+	action domain.AuditAction,
+	userID uuid.UUID,
+	credential *domain.PasskeyCredential,
+	userAgent, clientIP string,
+) {
+	s.audit(ctx, domain.NewAuditEvent(action, domain.AuditOutcomeSuccess).
+		WithActor(userID).
+		WithTarget(userID).
+		WithClient(userAgent, clientIP).
+		WithMetadata(map[string]any{"passkey_id": credential.ID.String(), "name": credential.Name}))
+}
+
+func toPasskeyResponse(credential *domain.PasskeyCredential) *PasskeyResponse {
+	return &PasskeyResponse{
+		ID:         credential.ID,
+		Name:       credential.Name,
+		Transports: credential.Transports,
+		CreatedAt:  credential.CreatedAt,
+		LastUsedAt: credential.LastUsedAt,
+	}
+}
+
+func passkeyMetadata(kv ...any) map[string]any {
+	metadata := map[string]any{"method": "passkey"}
+
+	for i := 0; i+1 < len(kv); i += 2 {
+		if key, ok := kv[i].(string); ok {
+			metadata[key] = kv[i+1]
+		}
+	}
+
+	return metadata
+}
+
+func errPasskeysDisabled() error {
+	return apperror.NotImplemented(apperror.ErrCodePasskeysDisabled, apperror.MsgPasskeysDisabled, nil)
+}
+
+func errPasskeyChallengeInvalid() error {
+	return apperror.BadRequest(apperror.ErrCodePasskeyChallengeInvalid, apperror.MsgPasskeyChallengeInvalid, nil)
+}
+
+func errPasskeyInvalid(cause error) error {
+	return apperror.Unauthorized(apperror.ErrCodePasskeyInvalid, apperror.MsgPasskeyInvalid, cause)
+}
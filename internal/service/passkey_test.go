@@ -0,0 +1,457 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+	"go-auth/internal/webauthn"
+	"go-auth/internal/webauthn/webauthntest"
+)
+
+const (
+	testRPID          = "example.com"
+	testPasskeyOrigin = "https://app.example.com"
+)
+
+func newTestRelyingParty(t *testing.T) *webauthn.RelyingParty {
+	t.Helper()
+
+	rp, err := webauthn.New(webauthn.Config{RPID: testRPID, RPName: "Example", Origins: []string{testPasskeyOrigin}})
+	require.NoError(t, err)
+
+	return rp
+}
+
+func newTestAuthenticator(t *testing.T) *webauthntest.Authenticator {
+	t.Helper()
+
+	a, err := webauthntest.NewAuthenticator()
+	require.NoError(t, err)
+
+	return a
+}
+
+// mustPasskey registers the authenticator's credential to userID.
+func mustPasskey(t *testing.T, repo *mockPasskeyRepo, userID uuid.UUID, a *webauthntest.Authenticator) uuid.UUID {
+	t.Helper()
+
+	credential, err := domain.NewPasskeyCredential(userID, &domain.VerifiedPasskey{
+		CredentialID: a.CredentialID,
+		PublicKey:    a.PublicKey(),
+		SignCount:    a.SignCount,
+	}, "Laptop")
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(context.Background(), credential))
+
+	a.UserHandle = userID[:]
+
+	return credential.ID
+}
+
+func TestServicePasskeyRegistration(t *testing.T) {
+	ctx := context.Background()
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	actor := &domain.AccessClaims{UserID: user.ID, AuthTime: time.Now()}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.BeginPasskeyRegistration(ctx, actor)
+		assertAppErrorCode(t, err, apperror.ErrCodePasskeysDisabled)
+	})
+	t.Run("requires a user", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{
+			PasskeyVerifier: newTestRelyingParty(t),
+			Passkeys:        &mockPasskeyRepo{},
+			Challenges:      &mockPasskeyChallengeRepo{},
+		})
+		require.NoError(t, err)
+
+		_, err = svc.BeginPasskeyRegistration(ctx, nil)
+		assertAppErrorCode(t, err, apperror.ErrCodeUnauthorized)
+	})
+	t.Run("refuses stale, delegated and impersonated actors", func(t *testing.T) {
+		t.Parallel()
+
+		challenges := &mockPasskeyChallengeRepo{}
+		passkeys := &mockPasskeyRepo{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:        &mockUserRepo{getByIDUser: user},
+			PasskeyVerifier: newTestRelyingParty(t),
+			Passkeys:        passkeys,
+			Challenges:      challenges,
+		})
+		require.NoError(t, err)
+
+		stale := *actor
+		stale.AuthTime = time.Time{}
+
+		delegated := *actor
+		delegated.ClientID = "client-1"
+
+		impersonated := *actor
+		impersonated.ImpersonatorID = uuid.New()
+
+		refused := []struct {
+			actor    *domain.AccessClaims
+			wantCode apperror.Code
+		}{
+			{&stale, apperror.ErrCodeReauthenticationRequired},
+			{&delegated, apperror.ErrCodeReauthenticationRequired},
+			{&impersonated, apperror.ErrCodeImpersonationRestricted},
+		}
+
+		for _, r := range refused {
+			_, err = svc.BeginPasskeyRegistration(ctx, r.actor)
+			assertAppErrorCode(t, err, r.wantCode)
+
+			_, err = svc.FinishPasskeyRegistration(ctx, r.actor, &service.FinishPasskeyRegistrationRequest{
+				Name:        "laptop",
+				Attestation: &domain.PasskeyAttestation{},
+			})
+			assertAppErrorCode(t, err, r.wantCode)
+		}
+
+		assert.Nil(t, challenges.saved)
+		assert.Empty(t, passkeys.credentials)
+	})
+	t.Run("registers a credential", func(t *testing.T) {
+		t.Parallel()
+
+		existing := newTestAuthenticator(t)
+		passkeys := &mockPasskeyRepo{}
+		mustPasskey(t, passkeys, user.ID, existing)
+
+		challenges := &mockPasskeyChallengeRepo{}
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:        &mockUserRepo{getByIDUser: user},
+			AuditLogger:     auditLog,
+			PasskeyVerifier: newTestRelyingParty(t),
+			Passkeys:        passkeys,
+			Challenges:      challenges,
+		})
+		require.NoError(t, err)
+
+		opts, err := svc.BeginPasskeyRegistration(ctx, actor)
+		require.NoError(t, err)
+		assert.Equal(t, "refresh-token", opts.Challenge)
+		assert.Equal(t, testRPID, opts.RPID)
+		assert.Equal(t, user.ID[:], opts.UserHandle)
+		assert.Equal(t, "alice@example.com", opts.UserName)
+		assert.Equal(t, [][]byte{existing.CredentialID}, opts.ExcludeCredentials)
+		assert.Contains(t, opts.Algorithms, webauthn.AlgES256)
+
+		require.NotNil(t, challenges.saved)
+		assert.Equal(t, domain.PasskeyCeremonyRegistration, challenges.saved.Ceremony)
+		assert.Equal(t, &user.ID, challenges.saved.UserID)
+
+		a := newTestAuthenticator(t)
+		att, err := a.Create(opts.RPID, testPasskeyOrigin, opts.Challenge, opts.UserHandle)
+		require.NoError(t, err)
+
+		res, err := svc.FinishPasskeyRegistration(ctx, actor, &service.FinishPasskeyRegistrationRequest{
+			Name:        " Phone ",
+			Attestation: att,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Phone", res.Name)
+		assert.Equal(t, []string{"internal"}, res.Transports)
+
+		stored := passkeys.stored(res.ID)
+		require.NotNil(t, stored)
+		assert.Equal(t, user.ID, stored.UserID)
+		assert.Equal(t, a.PublicKey(), stored.PublicKey)
+		assert.Equal(t, domain.AuditActionPasskeyAdded, auditLog.last().Action)
+
+		_, err = svc.FinishPasskeyRegistration(ctx, actor, &service.FinishPasskeyRegistrationRequest{
+			Name:        "Phone",
+			Attestation: att,
+		})
+		assertAppErrorCode(t, err, apperror.ErrCodePasskeyChallengeInvalid)
+	})
+
+	tests := []struct {
+		name string
+		// owner is the user the challenge was issued to; it defaults to the actor.
+		owner     uuid.UUID
+		ceremony  domain.PasskeyCeremony
+		origin    string
+		passkey   string
+		duplicate bool
+		wantCode  apperror.Code
+	}{
+		{name: "challenge of another user", owner: uuid.New(), wantCode: apperror.ErrCodePasskeyChallengeInvalid},
+		{name: "login challenge", ceremony: domain.PasskeyCeremonyLogin, wantCode: apperror.ErrCodePasskeyChallengeInvalid},
+		{name: "wrong origin", origin: "https://evil.example.net", wantCode: apperror.ErrCodePasskeyInvalid},
+		{name: "missing name", passkey: " ", wantCode: apperror.ErrCodeInvalidParam},
+		{name: "already registered", duplicate: true, wantCode: apperror.ErrCodePasskeyAlreadyExists},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			owner, ceremony, origin, name := user.ID, domain.PasskeyCeremonyRegistration, testPasskeyOrigin, "Phone"
+			if tt.owner != uuid.Nil {
+				owner = tt.owner
+			}
+
+			if tt.ceremony != "" {
+				ceremony = tt.ceremony
+			}
+
+			if tt.origin != "" {
+				origin = tt.origin
+			}
+
+			if tt.passkey != "" {
+				name = tt.passkey
+			}
+
+			var ownerID *uuid.UUID
+			if ceremony == domain.PasskeyCeremonyRegistration {
+				ownerID = &owner
+			}
+
+			challenge, err := domain.NewPasskeyChallenge("hashed-challenge", ceremony, ownerID, time.Minute)
+			require.NoError(t, err)
+
+			challenges := &mockPasskeyChallengeRepo{}
+			require.NoError(t, challenges.Save(ctx, challenge))
+
+			a := newTestAuthenticator(t)
+			passkeys := &mockPasskeyRepo{}
+
+			if tt.duplicate {
+				mustPasskey(t, passkeys, uuid.New(), a)
+			}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:        &mockUserRepo{getByIDUser: user},
+				PasskeyVerifier: newTestRelyingParty(t),
+				Passkeys:        passkeys,
+				Challenges:      challenges,
+			})
+			require.NoError(t, err)
+
+			att, err := a.Create(testRPID, origin, "challenge", user.ID[:])
+			require.NoError(t, err)
+
+			_, err = svc.FinishPasskeyRegistration(ctx, actor, &service.FinishPasskeyRegistrationRequest{
+				Name:        name,
+				Attestation: att,
+			})
+			assertAppErrorCode(t, err, tt.wantCode)
+		})
+	}
+}
+
+func TestServiceFinishPasskeyLogin(t *testing.T) {
+	ctx := context.Background()
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	blocked := mustVerifiedUser(t, "bob", "bob@example.com", "$hash")
+	require.NoError(t, blocked.Ban())
+
+	mfaUser := mustEmailOTPUser(t)
+
+	tests := []struct {
+		name string
+		user *domain.User
+		// configure runs after the credential is registered and before the assertion.
+		configure func(*webauthntest.Authenticator)
+		// noCounter registers an authenticator that always reports a zero counter.
+		noCounter  bool
+		unknown    bool
+		ceremony   domain.PasskeyCeremony
+		wantCode   apperror.Code
+		wantReason string
+		wantCount  uint32
+	}{
+		{name: "valid", user: user, wantCount: 8},
+		// A user-verified assertion stands in for the second factor.
+		{name: "MFA user is not challenged", user: mfaUser, wantCount: 8},
+		{name: "authenticator without counter", user: user, noCounter: true, wantCount: 0},
+		{
+			name:       "counter did not advance",
+			user:       user,
+			configure:  func(a *webauthntest.Authenticator) { a.SignCount = 3 },
+			wantCode:   apperror.ErrCodePasskeyInvalid,
+			wantReason: "sign_count",
+			wantCount:  7,
+		},
+		{
+			name:       "counter stopped",
+			user:       user,
+			configure:  func(a *webauthntest.Authenticator) { a.SignCount, a.NoCounter = 0, true },
+			wantCode:   apperror.ErrCodePasskeyInvalid,
+			wantReason: "sign_count",
+			wantCount:  7,
+		},
+		{
+			name:       "wrong user handle",
+			user:       user,
+			configure:  func(a *webauthntest.Authenticator) { a.UserHandle = []byte("someone-else") },
+			wantCode:   apperror.ErrCodePasskeyInvalid,
+			wantReason: "unknown_passkey",
+			wantCount:  7,
+		},
+		{
+			name:       "unknown credential",
+			user:       user,
+			unknown:    true,
+			wantCode:   apperror.ErrCodePasskeyInvalid,
+			wantReason: "unknown_passkey",
+			wantCount:  7,
+		},
+		{
+			name:      "registration challenge",
+			user:      user,
+			ceremony:  domain.PasskeyCeremonyRegistration,
+			wantCode:  apperror.ErrCodePasskeyChallengeInvalid,
+			wantCount: 7,
+		},
+		{
+			name:       "blocked user",
+			user:       blocked,
+			wantCode:   apperror.ErrCodeUserBlocked,
+			wantReason: "account_blocked",
+			wantCount:  8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := newTestAuthenticator(t)
+			a.SignCount = 7
+
+			if tt.noCounter {
+				a.SignCount, a.NoCounter = 0, true
+			}
+
+			passkeys := &mockPasskeyRepo{}
+			id := mustPasskey(t, passkeys, tt.user.ID, a)
+
+			if tt.configure != nil {
+				tt.configure(a)
+			}
+
+			if tt.unknown {
+				a.CredentialID = []byte("unknown-credential")
+			}
+
+			challenges := &mockPasskeyChallengeRepo{}
+			sessions := &mockSessionRepo{}
+			auditLog := &mockAuditLogger{}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:        &mockUserRepo{getByIDUser: tt.user},
+				SessionRepo:     sessions,
+				AuditLogger:     auditLog,
+				PasskeyVerifier: newTestRelyingParty(t),
+				Passkeys:        passkeys,
+				Challenges:      challenges,
+			})
+			require.NoError(t, err)
+
+			opts, err := svc.BeginPasskeyLogin(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, testRPID, opts.RPID)
+
+			if tt.ceremony != "" {
+				challenges.saved.Ceremony = tt.ceremony
+				challenges.saved.UserID = &tt.user.ID
+			}
+
+			assertion, err := a.Get(opts.RPID, testPasskeyOrigin, opts.Challenge)
+			require.NoError(t, err)
+
+			res, err := svc.FinishPasskeyLogin(ctx, &service.PasskeyLoginRequest{
+				Assertion: assertion,
+				UserAgent: "ua",
+				ClientIP:  "198.51.100.10",
+			})
+
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+				assert.Nil(t, sessions.savedSession)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.user.ID, res.UserID)
+				require.NotNil(t, sessions.savedSession)
+				assert.Equal(t, tt.user.ID, sessions.savedSession.UserID)
+			}
+
+			assert.Equal(t, tt.wantCount, passkeys.stored(id).SignCount)
+
+			if tt.wantReason != "" {
+				assert.Equal(t, tt.wantReason, auditLog.last().Metadata["reason"])
+			}
+
+			if tt.wantCode == "" {
+				assert.Equal(t, domain.AuditOutcomeSuccess, auditLog.last().Outcome)
+				assert.Equal(t, "passkey", auditLog.last().Metadata["method"])
+			}
+		})
+	}
+}
+
+func TestServiceDeletePasskey(t *testing.T) {
+	ctx := context.Background()
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+
+	passkeys := &mockPasskeyRepo{}
+	id := mustPasskey(t, passkeys, user.ID, newTestAuthenticator(t))
+
+	svc, err := newTestServiceWith(testDeps{
+		PasskeyVerifier: newTestRelyingParty(t),
+		Passkeys:        passkeys,
+		Challenges:      &mockPasskeyChallengeRepo{},
+	})
+	require.NoError(t, err)
+
+	err = svc.DeletePasskey(ctx, &domain.AccessClaims{UserID: uuid.New(), AuthTime: time.Now()}, id)
+	assertAppErrorCode(t, err, apperror.ErrCodePasskeyNotFound)
+
+	actor := &domain.AccessClaims{UserID: user.ID, AuthTime: time.Now()}
+
+	err = svc.DeletePasskey(ctx, &domain.AccessClaims{UserID: user.ID}, id)
+	assertAppErrorCode(t, err, apperror.ErrCodeReauthenticationRequired)
+
+	delegated := *actor
+	delegated.APIKeyID = uuid.New()
+
+	err = svc.DeletePasskey(ctx, &delegated, id)
+	assertAppErrorCode(t, err, apperror.ErrCodeReauthenticationRequired)
+
+	impersonated := *actor
+	impersonated.ImpersonatorID = uuid.New()
+
+	err = svc.DeletePasskey(ctx, &impersonated, id)
+	assertAppErrorCode(t, err, apperror.ErrCodeImpersonationRestricted)
+
+	list, err := svc.ListPasskeys(ctx, actor)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, id, list[0].ID)
+
+	require.NoError(t, svc.DeletePasskey(ctx, actor, id))
+	assert.Nil(t, passkeys.stored(id))
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+const defaultStepUpWindow = 5 * time.Minute
+
+type ReauthenticateRequest struct {
+	Password  string
+	MFAToken  string
+	Code      string
+	UserAgent string
+	ClientIP  string
+}
+
+type ReauthenticateResponse struct {
+	AccessToken string
+	ExpiresAt   time.Time
+	MFA         *MFAChallenge
+}
+
+func (s *service) Reauthenticate(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *ReauthenticateRequest,
+) (*ReauthenticateResponse, error) {
+	if err := s.authenticate(actor); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgReauthRequestRequired, nil)
+	}
+
+	// A third-party application or script must not be able to turn its delegated credential
+	// into a token that passes the step-up guard.
+	if actor.IsDelegated() {
+		return nil, apperror.Forbidden(apperror.ErrCodePermissionDenied, apperror.MsgReauthNotAllowed, nil)
+	}
+
+	if actor.IsImpersonated() {
+		return nil, errImpersonationRestricted()
+	}
+
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := *req
+	normalized.ClientIP = clientIP.String()
+	req = &normalized
+
+	if !s.clientIPAllowed(clientIP) {
+		s.auditReauth(ctx, actor, req, domain.AuditOutcomeDenied, map[string]any{"reason": "ip_denied"})
+
+		return nil, errIPNotAllowed()
+	}
+
+	user, err := s.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil {
+		return nil, apperror.NotFound(apperror.ErrCodeUserNotFound, apperror.MsgUserNotFound, nil)
+	}
+
+	if user.IsBanned() || !user.CanLogin() {
+		s.auditReauth(ctx, actor, req, domain.AuditOutcomeDenied, map[string]any{"reason": "account_blocked"})
+
+		return nil, apperror.Forbidden(apperror.ErrCodeUserBlocked, apperror.MsgAccountAccessRevoked, nil)
+	}
+
+	switch {
+	case req.MFAToken != "":
+		if err = s.reauthenticateMFA(ctx, actor, user, req); err != nil {
+			return nil, err
+		}
+	case req.Password != "":
+		if !s.passwordHasher.Compare(req.Password, user.Password) {
+			metadata := map[string]any{"method": "password", "reason": "invalid_password"}
+			s.auditReauth(ctx, actor, req, domain.AuditOutcomeFailure, metadata)
+
+			return nil, apperror.Unauthorized(apperror.ErrCodeInvalidCredentials, apperror.MsgInvalidCredentials, nil)
+		}
+
+		if user.RequiresMFA() {
+			if !s.mfaFactorEnabled(user.MFAFactor) {
+				return nil, errMFAFactorDisabled()
+			}
+
+			challenge, challengeErr := s.issueMFAChallenge(ctx, user, req.UserAgent, req.ClientIP)
+			if challengeErr != nil {
+				return nil, challengeErr
+			}
+
+			return &ReauthenticateResponse{MFA: challenge}, nil
+		}
+	default:
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgReauthProofRequired, nil)
+	}
+
+	return s.elevate(ctx, actor, user, req)
+}
+
+func (s *service) reauthenticateMFA(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	user *domain.User,
+	req *ReauthenticateRequest,
+) error {
+	if strings.TrimSpace(req.Code) == "" {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgMFACodeRequired, nil)
+	}
+
+	if !s.emailOTP {
+		return errMFAFactorDisabled()
+	}
+
+	token, err := s.pendingMFAChallenge(ctx, req.MFAToken)
+	if err != nil {
+		return err
+	}
+
+	if token == nil || token.UserID != user.ID || user.MFAFactor.ChallengeTokenType() != token.Type {
+		metadata := map[string]any{"method": "mfa", "reason": "invalid_challenge"}
+		s.auditReauth(ctx, actor, req, domain.AuditOutcomeFailure, metadata)
+
+		return errMFAChallengeInvalid()
+	}
+
+	if reason, verifyErr := s.verifyMFACode(ctx, token, req.MFAToken, req.Code); verifyErr != nil {
+		if reason != "" {
+			s.auditReauth(ctx, actor, req, domain.AuditOutcomeFailure, map[string]any{"method": "mfa", "reason": reason})
+		}
+
+		return verifyErr
+	}
+
+	return nil
+}
+
+func (s *service) elevate(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	user *domain.User,
+	req *ReauthenticateRequest,
+) (*ReauthenticateResponse, error) {
+	now := time.Now().UTC()
+
+	expiresAt := now.Add(s.stepUpWindow)
+	if limit := now.Add(s.accessTokenTTL); limit.Before(expiresAt) {
+		expiresAt = limit
+	}
+
+	claims := s.userAccessClaims(user)
+	claims.AuthTime = now
+	claims.ExpiresAt = expiresAt
+
+	if actor.InOrganization() {
+		scoped, err := s.organizationAccessClaims(ctx, claims, actor.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+
+		claims = scoped
+	}
+
+	accessToken, err := s.accessTokenManager.Generate(claims)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	method := "password"
+	if req.MFAToken != "" {
+		method = "mfa"
+	}
+
+	s.auditReauth(ctx, actor, req, domain.AuditOutcomeSuccess, map[string]any{"method": method})
+
+	return &ReauthenticateResponse{AccessToken: accessToken, ExpiresAt: expiresAt}, nil
+}
+
+// requireRecentAuth guards sensitive operations: the caller must have logged in or called
+// Reauthenticate within the step-up window, and neither be impersonated nor use a token
+// issued to an OAuth client or an API key. It guards every operation that adds or removes a
+// way into the account: changing the MFA factor, creating API keys, adding and removing
+// passkeys, linking federated identities and granting OAuth consent.
+func (s *service) requireRecentAuth(actor *domain.AccessClaims) error {
+	if actor.IsImpersonated() {
+		return errImpersonationRestricted()
+	}
+
+	if actor.IsDelegated() || !actor.AuthenticatedWithin(s.stepUpWindow, time.Now()) {
+		return apperror.Forbidden(apperror.ErrCodeReauthenticationRequired, apperror.MsgReauthenticationRequired, nil)
+	}
+
+	return nil
+}
+
+func (s *service) auditReauth(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *ReauthenticateRequest,
+	outcome domain.AuditOutcome,
+	metadata map[string]any,
+) {
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionReauthenticate, outcome).
+		WithActor(actor.UserID).
+		WithTarget(actor.UserID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(metadata))
+}
@@ -0,0 +1,193 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+func TestServiceReauthenticate(t *testing.T) {
+	ctx := context.Background()
+	stale := time.Now().Add(-time.Hour)
+
+	t.Run("password", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+		access := &mockAccessTokenManager{generateToken: "elevated"}
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:    &mockUserRepo{getByIDUser: user},
+			Hasher:      &mockPasswordHasher{compareOk: true},
+			Access:      access,
+			AuditLogger: auditLog,
+		})
+		require.NoError(t, err)
+
+		actor := &domain.AccessClaims{UserID: user.ID, AuthTime: stale}
+		err = svc.SetMFAFactor(ctx, actor, &service.SetMFAFactorRequest{})
+		assertAppErrorCode(t, err, apperror.ErrCodeReauthenticationRequired)
+
+		res, err := svc.Reauthenticate(ctx, actor, &service.ReauthenticateRequest{
+			Password: "secret",
+			ClientIP: "198.51.100.10",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "elevated", res.AccessToken)
+		assert.Nil(t, res.MFA)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), res.ExpiresAt, time.Second)
+
+		claims := access.lastClaims
+		assert.Equal(t, user.ID, claims.UserID)
+		assert.Equal(t, res.ExpiresAt, claims.ExpiresAt)
+		assert.WithinDuration(t, time.Now(), claims.AuthTime, time.Second)
+		assert.Equal(t, domain.AuditActionReauthenticate, auditLog.last().Action)
+		assert.Equal(t, "password", auditLog.last().Metadata["method"])
+
+		require.NoError(t, svc.SetMFAFactor(ctx, &claims, &service.SetMFAFactorRequest{}))
+	})
+	t.Run("password then email OTP", func(t *testing.T) {
+		t.Parallel()
+
+		user := mustEmailOTPUser(t)
+		tokens := &mockTokenRepo{}
+		mailer := newMockMailer()
+		access := &mockAccessTokenManager{}
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo: &mockUserRepo{getByIDUser: user},
+			Hasher:   &mockPasswordHasher{compareOk: true},
+			Access:   access,
+			Tokens:   tokens,
+			Mailer:   mailer,
+			EmailOTP: true,
+		})
+		require.NoError(t, err)
+
+		actor := &domain.AccessClaims{UserID: user.ID}
+
+		res, err := svc.Reauthenticate(ctx, actor, &service.ReauthenticateRequest{
+			Password: "secret",
+			ClientIP: "198.51.100.10",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, res.MFA)
+		assert.Empty(t, res.AccessToken)
+		assert.Equal(t, domain.TokenTypeEmailOTP, tokens.saved.Type)
+
+		code := receiveOTP(t, mailer)
+
+		res, err = svc.Reauthenticate(ctx, actor, &service.ReauthenticateRequest{
+			MFAToken: res.MFA.Token,
+			Code:     code,
+			ClientIP: "198.51.100.10",
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.AccessToken)
+		assert.False(t, access.lastClaims.AuthTime.IsZero())
+	})
+	t.Run("challenge of another user", func(t *testing.T) {
+		t.Parallel()
+
+		owner := mustEmailOTPUser(t)
+		other := mustEmailOTPUser(t)
+		mailer := newMockMailer()
+
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo: &mockUserRepo{getByUsernameUser: owner, getByIDUser: other},
+			Hasher:   &mockPasswordHasher{compareOk: true},
+			Tokens:   &mockTokenRepo{},
+			Mailer:   mailer,
+			EmailOTP: true,
+		})
+		require.NoError(t, err)
+
+		login, err := svc.Login(ctx, &service.LoginRequest{Login: "alice", Password: "secret", ClientIP: "198.51.100.10"})
+		require.NoError(t, err)
+
+		_, err = svc.Reauthenticate(ctx, &domain.AccessClaims{UserID: other.ID}, &service.ReauthenticateRequest{
+			MFAToken: login.MFA.Token,
+			Code:     receiveOTP(t, mailer),
+			ClientIP: "198.51.100.10",
+		})
+		assertAppErrorCode(t, err, apperror.ErrCodeMFAChallengeInvalid)
+	})
+	t.Run("unauthenticated", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{})
+		require.NoError(t, err)
+
+		_, err = svc.Reauthenticate(ctx, nil, &service.ReauthenticateRequest{Password: "secret"})
+		assertAppErrorCode(t, err, apperror.ErrCodeUnauthorized)
+	})
+
+	tests := []struct {
+		name     string
+		clientID string
+		req      *service.ReauthenticateRequest
+		wantCode apperror.Code
+	}{
+		{
+			name:     "OAuth client token",
+			clientID: "app",
+			req:      &service.ReauthenticateRequest{Password: "secret", ClientIP: "198.51.100.10"},
+			wantCode: apperror.ErrCodePermissionDenied,
+		},
+		{
+			name:     "wrong password",
+			req:      &service.ReauthenticateRequest{Password: "wrong", ClientIP: "198.51.100.10"},
+			wantCode: apperror.ErrCodeInvalidCredentials,
+		},
+		{
+			name:     "no proof",
+			req:      &service.ReauthenticateRequest{ClientIP: "198.51.100.10"},
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo: &mockUserRepo{getByIDUser: user},
+				Hasher:   &mockPasswordHasher{compareOk: tt.req.Password == "secret"},
+			})
+			require.NoError(t, err)
+
+			actor := &domain.AccessClaims{UserID: user.ID, ClientID: tt.clientID}
+
+			_, err = svc.Reauthenticate(ctx, actor, tt.req)
+			assertAppErrorCode(t, err, tt.wantCode)
+		})
+	}
+}
+
+func TestServiceLoginSetsAuthTime(t *testing.T) {
+	t.Parallel()
+
+	user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+	access := &mockAccessTokenManager{}
+
+	svc, err := newTestServiceWith(testDeps{
+		UserRepo: &mockUserRepo{getByUsernameUser: user},
+		Hasher:   &mockPasswordHasher{compareOk: true},
+		Access:   access,
+	})
+	require.NoError(t, err)
+
+	_, err = svc.Login(context.Background(), validLoginReq)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), access.lastClaims.AuthTime, time.Second)
+}
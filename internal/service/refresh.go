@@ -2,13 +2,21 @@ package service
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"time"
 
+	"github.com/google/uuid"
+
 	"go-auth/internal/apperror"
 	"go-auth/internal/domain"
 )
 
 func (s *service) Refresh(ctx context.Context, req *RefreshRequest) (*RefreshResponse, error) {
+	return s.refresh(ctx, req, "")
+}
+
+func (s *service) refresh(ctx context.Context, req *RefreshRequest, clientID string) (*RefreshResponse, error) {
 	if req == nil {
 		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgRefreshRequestRequired, nil)
 	}
@@ -17,6 +25,23 @@ func (s *service) Refresh(ctx context.Context, req *RefreshRequest) (*RefreshRes
 		return nil, apperror.BadRequest(apperror.ErrCodeTokenRequired, apperror.MsgRefreshTokenRequired, nil)
 	}
 
+	clientIP, err := parseClientIP(req.ClientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := *req
+	normalized.ClientIP = clientIP.String()
+	req = &normalized
+
+	if !s.clientIPAllowed(clientIP) {
+		s.audit(ctx, domain.NewAuditEvent(domain.AuditActionRefresh, domain.AuditOutcomeDenied).
+			WithClient(req.UserAgent, req.ClientIP).
+			WithMetadata(map[string]any{"reason": "ip_denied"}))
+
+		return nil, errIPNotAllowed()
+	}
+
 	refreshTokenHash, err := s.opaqueTokenManager.Hash(req.RefreshToken)
 	if err != nil {
 		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
@@ -27,12 +52,40 @@ func (s *service) Refresh(ctx context.Context, req *RefreshRequest) (*RefreshRes
 		return nil, err
 	}
 
-	newSession, newRefreshToken, err := s.rotateSession(ctx, session, req.UserAgent, req.ClientIP)
+	if session.ClientID != clientID {
+		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgSessionNotActive, nil)
+	}
+
+	newSession, newRefreshToken, err := s.nextSession(ctx, session, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.buildRefresh(ctx, newSession, newRefreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.buildRefresh(ctx, newSession, newRefreshToken)
+	metadata := map[string]any{"session_id": newSession.ID.String(), "previous_session_id": session.ID.String()}
+	if clientID != "" {
+		metadata["client_id"] = clientID
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionRefresh, domain.AuditOutcomeSuccess).
+		WithActor(session.UserID).
+		WithTarget(session.UserID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(metadata))
+
+	s.publish(ctx, domain.SessionRefreshed{
+		UserID:            session.UserID,
+		SessionID:         newSession.ID,
+		PreviousSessionID: session.ID,
+		ClientID:          clientID,
+		At:                time.Now().UTC(),
+	})
+
+	return resp, nil
 }
 
 func (s *service) getSessionForRefresh(ctx context.Context, refreshTokenHash string) (*domain.Session, error) {
@@ -45,19 +98,113 @@ func (s *service) getSessionForRefresh(ctx context.Context, refreshTokenHash str
 		return nil, apperror.NotFound(apperror.ErrCodeSessionNotFound, apperror.MsgSessionNotFound, nil)
 	}
 
+	return session, nil
+}
+
+// nextSession rotates session, or returns the successor it was already rotated to
+// when the same refresh token is presented again within the grace period.
+func (s *service) nextSession(
+	ctx context.Context,
+	session *domain.Session,
+	req *RefreshRequest,
+) (*domain.Session, string, error) {
+	if session.RotatedWithin(s.refreshGrace) {
+		return s.graceSuccessor(ctx, session, req)
+	}
+
+	if err := s.checkRefreshable(session); err != nil {
+		return nil, "", err
+	}
+
+	if err := s.checkSessionBinding(ctx, session, req); err != nil {
+		return nil, "", err
+	}
+
+	newSession, newRefreshToken, err := s.rotateSession(ctx, session, req, session.OrganizationID)
+	if !errors.Is(err, domain.ErrSessionRevoked) {
+		return newSession, newRefreshToken, err
+	}
+
+	// A concurrent refresh, possibly on another replica, rotated the session first.
+	current, err := s.sessionRepo.GetByID(ctx, session.ID)
+	if err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetSession, err)
+	}
+
+	if current == nil || !current.RotatedWithin(s.refreshGrace) {
+		return nil, "", apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgSessionNotActive, nil)
+	}
+
+	return s.graceSuccessor(ctx, current, req)
+}
+
+func (s *service) checkRefreshable(session *domain.Session) error {
 	if !session.IsActive() {
-		return nil, apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgSessionNotActive, nil)
+		return apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgSessionNotActive, nil)
 	}
 
-	return session, nil
+	switch err := session.CheckLimits(s.sessionLimits); {
+	case errors.Is(err, domain.ErrSessionLifetimeExceeded):
+		return apperror.Unauthorized(apperror.ErrCodeSessionExpired, apperror.MsgSessionLifetimeExceeded, err)
+	case errors.Is(err, domain.ErrSessionIdle):
+		return apperror.Unauthorized(apperror.ErrCodeSessionExpired, apperror.MsgSessionIdle, err)
+	}
+
+	return nil
+}
+
+// graceSuccessor reissues the successor of a session rotated within the grace period.
+// The successor token is re-derived from the presented one, so it is never stored.
+func (s *service) graceSuccessor(
+	ctx context.Context,
+	session *domain.Session,
+	req *RefreshRequest,
+) (*domain.Session, string, error) {
+	successor, err := s.sessionRepo.GetByID(ctx, *session.ReplacedBy)
+	if err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetSession, err)
+	}
+
+	if successor == nil || !successor.IsActive() {
+		return nil, "", apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgSessionNotActive, nil)
+	}
+
+	successorToken, err := s.opaqueTokenManager.Derive(req.RefreshToken)
+	if err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateRefreshToken, err)
+	}
+
+	successorHash, err := s.opaqueTokenManager.Hash(successorToken)
+	if err != nil {
+		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgHashRefreshToken, err)
+	}
+
+	if successorHash != successor.Token {
+		return nil, "", apperror.Unauthorized(apperror.ErrCodeInvalidToken, apperror.MsgSessionNotActive, nil)
+	}
+
+	wasFlagged := successor.IsFlagged()
+
+	if err = s.checkSessionBinding(ctx, successor, req); err != nil {
+		return nil, "", err
+	}
+
+	if successor.IsFlagged() != wasFlagged {
+		if err = s.sessionRepo.Update(ctx, successor); err != nil {
+			return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUpdateSession, err)
+		}
+	}
+
+	return successor, successorToken, nil
 }
 
 func (s *service) rotateSession(
 	ctx context.Context,
 	session *domain.Session,
-	userAgent, clientIP string,
+	req *RefreshRequest,
+	organizationID *uuid.UUID,
 ) (*domain.Session, string, error) {
-	newRefreshToken, err := s.opaqueTokenManager.Generate()
+	newRefreshToken, err := s.opaqueTokenManager.Derive(req.RefreshToken)
 	if err != nil {
 		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateRefreshToken, err)
 	}
@@ -70,16 +217,18 @@ func (s *service) rotateSession(
 	now := time.Now().UTC()
 	newRefreshExpiresAt := now.Add(s.refreshTokenTTL)
 
-	newSession, err := session.Rotate(newRefreshTokenHash, newRefreshExpiresAt, userAgent, clientIP)
+	newSession, err := session.Rotate(newRefreshTokenHash, newRefreshExpiresAt, req.UserAgent, req.ClientIP, s.sessionLimits)
 	if err != nil {
 		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgRotateSession, err)
 	}
 
-	if err = s.sessionRepo.Update(ctx, session); err != nil {
-		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUpdateSession, err)
-	}
+	newSession.OrganizationID = organizationID
+
+	if err = s.sessionRepo.Rotate(ctx, session, newSession); err != nil {
+		if errors.Is(err, domain.ErrSessionRevoked) {
+			return nil, "", err
+		}
 
-	if err = s.sessionRepo.Save(ctx, newSession); err != nil {
 		return nil, "", apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveNewSession, err)
 	}
 
@@ -103,10 +252,12 @@ func (s *service) buildRefresh(ctx context.Context, newSes *domain.Session, newT
 	now := time.Now().UTC()
 	accessExpiresAt := now.Add(s.accessTokenTTL)
 
-	accessToken, err := s.accessTokenManager.Generate(domain.AccessClaims{
-		UserID: user.ID,
-		Role:   user.Role,
-	})
+	claims, err := s.sessionAccessClaims(ctx, user, newSes)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.accessTokenManager.Generate(claims)
 	if err != nil {
 		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateAccessToken, err)
 	}
@@ -116,5 +267,6 @@ func (s *service) buildRefresh(ctx context.Context, newSes *domain.Session, newT
 		RefreshToken:     newTkn,
 		AccessExpiresAt:  accessExpiresAt,
 		RefreshExpiresAt: newSes.ExpiresAt,
+		Scopes:           slices.Clone(newSes.Scopes),
 	}, nil
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
 	"go-auth/internal/service"
 )
 
@@ -150,3 +151,183 @@ func assertRefreshResult(t *testing.T, wantErr bool, wantCode apperror.Code, got
 	assert.False(t, got.AccessExpiresAt.IsZero())
 	assert.False(t, got.RefreshExpiresAt.IsZero())
 }
+
+func TestServiceRefreshSessionLimits(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		limits        domain.SessionLimits
+		authenticated time.Duration
+		lastRefresh   time.Duration
+		wantCode      apperror.Code
+		wantDeadline  bool
+	}{
+		{
+			name:          "no limits",
+			authenticated: 90 * 24 * time.Hour,
+			lastRefresh:   24 * time.Hour,
+		},
+		{
+			name:          "max lifetime exceeded",
+			limits:        domain.SessionLimits{MaxLifetime: 30 * 24 * time.Hour},
+			authenticated: 31 * 24 * time.Hour,
+			wantCode:      apperror.ErrCodeSessionExpired,
+		},
+		{
+			name:        "idle timeout exceeded",
+			limits:      domain.SessionLimits{IdleTimeout: time.Hour},
+			lastRefresh: 2 * time.Hour,
+			wantCode:    apperror.ErrCodeSessionExpired,
+		},
+		{
+			name:          "new session capped at max lifetime",
+			limits:        domain.SessionLimits{MaxLifetime: 24 * time.Hour, IdleTimeout: time.Hour},
+			authenticated: 23 * time.Hour,
+			lastRefresh:   30 * time.Minute,
+			wantDeadline:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+			session := mustSession(t, user.ID, time.Hour, false)
+			now := time.Now().UTC()
+			session.AuthenticatedAt = now.Add(-tt.authenticated)
+			session.CreatedAt = now.Add(-tt.lastRefresh)
+
+			sessionRepo := &mockSessionRepo{getByToken: session}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:      &mockUserRepo{getByIDUser: user},
+				SessionRepo:   sessionRepo,
+				SessionLimits: tt.limits,
+			})
+			require.NoError(t, err)
+
+			got, err := svc.Refresh(ctx, validRefreshReq)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+				assert.Nil(t, sessionRepo.savedSession)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, sessionRepo.savedSession)
+			assert.Equal(t, session.AuthenticatedAt, sessionRepo.savedSession.AuthenticatedAt)
+
+			if tt.wantDeadline {
+				assert.Equal(t, session.AuthenticatedAt.Add(tt.limits.MaxLifetime), got.RefreshExpiresAt)
+			}
+		})
+	}
+}
+
+func TestServiceRefreshGrace(t *testing.T) {
+	ctx := context.Background()
+
+	const grace = 10 * time.Second
+
+	tests := []struct {
+		name       string
+		rotatedAgo time.Duration
+		logout     bool
+		lostRace   bool
+		successor  func(s *domain.Session)
+		wantCode   apperror.Code
+	}{
+		{
+			name:       "within grace returns same successor",
+			rotatedAgo: 2 * time.Second,
+		},
+		{
+			name:     "concurrent rotation returns winner's successor",
+			lostRace: true,
+		},
+		{
+			name:       "outside grace",
+			rotatedAgo: time.Minute,
+			wantCode:   apperror.ErrCodeInvalidToken,
+		},
+		{
+			name:       "revoked by logout",
+			rotatedAgo: time.Second,
+			logout:     true,
+			wantCode:   apperror.ErrCodeInvalidToken,
+		},
+		{
+			name:       "successor revoked",
+			rotatedAgo: time.Second,
+			successor:  func(s *domain.Session) { require.NoError(t, s.Revoke()) },
+			wantCode:   apperror.ErrCodeInvalidToken,
+		},
+		{
+			name:       "successor not derived from token",
+			rotatedAgo: time.Second,
+			successor:  func(s *domain.Session) { s.Token = "hashed-other" },
+			wantCode:   apperror.ErrCodeInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := mustVerifiedUser(t, "alice", "alice@example.com", "$hash")
+			session := mustSession(t, user.ID, 24*time.Hour, false)
+
+			successor, err := session.Rotate(
+				"hashed-derived-"+validRefreshReq.RefreshToken,
+				time.Now().UTC().Add(24*time.Hour),
+				"", "",
+				domain.SessionLimits{},
+			)
+			require.NoError(t, err)
+
+			rotatedAt := time.Now().UTC().Add(-tt.rotatedAgo)
+			session.RevokedAt = &rotatedAt
+
+			if tt.logout {
+				session.ReplacedBy = nil
+			}
+
+			if tt.successor != nil {
+				tt.successor(successor)
+			}
+
+			sessionRepo := &mockSessionRepo{
+				getByToken: session,
+				byID:       map[uuid.UUID]*domain.Session{session.ID: session, successor.ID: successor},
+			}
+
+			if tt.lostRace {
+				sessionRepo.getByToken = mustSession(t, user.ID, 24*time.Hour, false)
+				sessionRepo.getByToken.ID = session.ID
+				sessionRepo.rotateErr = domain.ErrSessionRevoked
+			}
+
+			svc, err := newTestServiceWith(testDeps{
+				UserRepo:     &mockUserRepo{getByIDUser: user},
+				SessionRepo:  sessionRepo,
+				RefreshGrace: grace,
+			})
+			require.NoError(t, err)
+
+			got, err := svc.Refresh(ctx, validRefreshReq)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "derived-"+validRefreshReq.RefreshToken, got.RefreshToken)
+			assert.Equal(t, successor.ExpiresAt, got.RefreshExpiresAt)
+			assert.Nil(t, sessionRepo.rotatedSession)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"go-auth/internal/apperror"
 	"go-auth/internal/domain"
 )
@@ -13,7 +15,12 @@ func (s *service) Register(ctx context.Context, req *RegisterRequest) (*Register
 		return nil, err
 	}
 
-	if checkErr := s.checkConflicts(ctx, username, email); checkErr != nil {
+	organizationID, invitation, err := s.registrationOrganization(ctx, req, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkErr := s.checkConflicts(ctx, organizationID, username, email); checkErr != nil {
 		return nil, checkErr
 	}
 
@@ -31,15 +38,77 @@ func (s *service) Register(ctx context.Context, req *RegisterRequest) (*Register
 		)
 	}
 
-	if saveErr := s.userRepo.Save(ctx, user); saveErr != nil {
-		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, saveErr)
+	user.OrganizationID = organizationID
+	role := user.Role
+
+	if invitation != nil {
+		if err = user.Verify(); err != nil {
+			return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+		}
+
+		role = invitation.Role
 	}
 
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if saveErr := s.userRepo.Save(ctx, user); saveErr != nil {
+			return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, saveErr)
+		}
+
+		if invitation != nil {
+			if acceptErr := s.acceptInvitation(ctx, invitation, user.ID); acceptErr != nil {
+				return acceptErr
+			}
+		}
+
+		if organizationID != nil {
+			if joinErr := s.joinOrganization(ctx, *organizationID, user.ID, role); joinErr != nil {
+				return joinErr
+			}
+		}
+
+		return s.publishUserEvents(ctx, user, userCreatedEvents(user)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation != nil {
+		s.audit(ctx, invitationAcceptedEvent(invitation))
+	}
+
+	s.publish(ctx, userRegisteredEvent(user, ""))
+
 	return &RegisterResponse{
 		UserID: user.ID,
 	}, nil
 }
 
+func (s *service) registrationOrganization(
+	ctx context.Context,
+	req *RegisterRequest,
+	email domain.Email,
+) (*uuid.UUID, *domain.Invitation, error) {
+	if req.Invitation != "" {
+		invitation, err := s.pendingInvitation(ctx, req.Invitation, email)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &invitation.OrganizationID, invitation, nil
+	}
+
+	if req.Organization == "" {
+		return nil, nil, nil
+	}
+
+	organization, err := s.getOrganization(ctx, req.Organization)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &organization.ID, nil, nil
+}
+
 func (s *service) validateRequest(req *RegisterRequest) (domain.Username, domain.Email, error) {
 	if req == nil {
 		return domain.Username{}, domain.Email{}, apperror.BadRequest(
@@ -70,8 +139,26 @@ func (s *service) validateRequest(req *RegisterRequest) (domain.Username, domain
 	return username, email, nil
 }
 
-func (s *service) checkConflicts(ctx context.Context, username domain.Username, email domain.Email) error {
-	exists, err := s.userRepo.ExistsByUsername(ctx, username)
+func (s *service) joinOrganization(ctx context.Context, organizationID, userID uuid.UUID, role domain.Role) error {
+	member, err := domain.NewOrganizationMember(organizationID, userID, role)
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveMember, err)
+	}
+
+	if err = s.organizationRepo.SaveMember(ctx, member); err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveMember, err)
+	}
+
+	return nil
+}
+
+func (s *service) checkConflicts(
+	ctx context.Context,
+	scope *uuid.UUID,
+	username domain.Username,
+	email domain.Email,
+) error {
+	exists, err := s.usernameTaken(ctx, scope, username)
 	if err != nil {
 		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
 	}
@@ -80,7 +167,7 @@ func (s *service) checkConflicts(ctx context.Context, username domain.Username,
 		return apperror.Conflict(apperror.ErrCodeUsernameAlreadyUsed, apperror.MsgUsernameAlreadyInUse, nil)
 	}
 
-	exists, err = s.userRepo.ExistsByEmail(ctx, email)
+	exists, err = s.emailTaken(ctx, scope, email)
 	if err != nil {
 		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
 	}
@@ -91,3 +178,13 @@ func (s *service) checkConflicts(ctx context.Context, username domain.Username,
 
 	return nil
 }
+
+func userRegisteredEvent(user *domain.User, provider string) domain.UserRegistered {
+	return domain.UserRegistered{
+		UserID:         user.ID,
+		OrganizationID: user.OrganizationID,
+		Verified:       user.IsVerified(),
+		Provider:       provider,
+		At:             user.CreatedAt,
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+type RevokeRequest struct {
+	ClientID      string
+	ClientSecret  string
+	Token         string
+	TokenTypeHint string
+	UserAgent     string
+	ClientIP      string
+}
+
+// Revoke ends the session of a refresh token issued to the calling client. Unknown,
+// already revoked and other clients' tokens are ignored, so the result reveals nothing
+// about them. Access tokens cannot be revoked and fail with ErrCodeUnsupportedTokenType.
+func (s *service) Revoke(ctx context.Context, req *RevokeRequest) error {
+	if req == nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgRevokeRequired, nil)
+	}
+
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	if req.Token == "" {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgTokenParamRequired, nil)
+	}
+
+	session, err := s.sessionByRefreshToken(ctx, req.Token)
+	if err != nil {
+		return err
+	}
+
+	if session == nil {
+		if s.introspectAccessToken(req.Token).Active {
+			return apperror.BadRequest(apperror.ErrCodeUnsupportedTokenType, apperror.MsgUnsupportedTokenType, nil)
+		}
+
+		return nil
+	}
+
+	if session.ClientID != client.ID || session.IsRevoked() {
+		return nil
+	}
+
+	if err := session.Revoke(); err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgRevokeSession, err)
+	}
+
+	if err := s.sessionRepo.Update(ctx, session); err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUpdateSession, err)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionOAuthRevoke, domain.AuditOutcomeSuccess).
+		WithTarget(session.UserID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(map[string]any{"client_id": client.ID, "session_id": session.ID.String()}))
+
+	s.publish(ctx, sessionRevokedEvent(session, domain.SessionRevokedByClient))
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+func TestServiceRevoke(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	sessionFor := func(clientID string, revoked bool) func(*testing.T) *domain.Session {
+		return func(t *testing.T) *domain.Session {
+			t.Helper()
+
+			session := mustSession(t, userID, time.Hour, revoked)
+			session.ClientID = clientID
+
+			return session
+		}
+	}
+
+	revoke := func(token string) *service.RevokeRequest {
+		return &service.RevokeRequest{ClientID: "app", ClientSecret: testClientSecret, Token: token}
+	}
+
+	tests := []struct {
+		name        string
+		req         *service.RevokeRequest
+		access      *domain.AccessClaims
+		session     func(*testing.T) *domain.Session
+		wantCode    apperror.Code
+		wantRevoked bool
+	}{
+		{name: "nil request", wantCode: apperror.ErrCodeInvalidParam},
+		{
+			name:     "wrong secret",
+			req:      &service.RevokeRequest{ClientID: "app", ClientSecret: "wrong", Token: "refresh-token"},
+			wantCode: apperror.ErrCodeInvalidClient,
+		},
+		{name: "missing token", req: revoke(""), wantCode: apperror.ErrCodeInvalidParam},
+		{
+			name:        "own refresh token",
+			req:         revoke("refresh-token"),
+			session:     sessionFor("app", false),
+			wantRevoked: true,
+		},
+		{name: "other client's refresh token", req: revoke("refresh-token"), session: sessionFor("other", false)},
+		{name: "first-party refresh token", req: revoke("refresh-token"), session: sessionFor("", false)},
+		{name: "already revoked", req: revoke("refresh-token"), session: sessionFor("app", true)},
+		{name: "unknown token", req: revoke("garbage")},
+		{
+			name:     "access token",
+			req:      revoke("jwt"),
+			access:   &domain.AccessClaims{UserID: userID, ClientID: "app"},
+			wantCode: apperror.ErrCodeUnsupportedTokenType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sessions := &mockSessionRepo{}
+			if tt.session != nil {
+				sessions.getByToken = tt.session(t)
+			}
+
+			auditLog := &mockAuditLogger{}
+			svc, err := newTestServiceWith(testDeps{
+				SessionRepo: sessions,
+				AuditLogger: auditLog,
+				OAuthClients: &mockOAuthClientRepo{clients: map[string]*domain.OAuthClient{
+					"app": mustOAuthClient(t, "app", false),
+				}},
+				Access: &mockAccessTokenManager{validateClaims: tt.access},
+			})
+			require.NoError(t, err)
+
+			err = svc.Revoke(ctx, tt.req)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if !tt.wantRevoked {
+				assert.Nil(t, sessions.updatedSession)
+				assert.Empty(t, auditLog.actions())
+
+				return
+			}
+
+			require.NotNil(t, sessions.updatedSession)
+			assert.True(t, sessions.updatedSession.IsRevoked())
+			assert.Equal(t, []domain.AuditAction{domain.AuditActionOAuthRevoke}, auditLog.actions())
+		})
+	}
+}
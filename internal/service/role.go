@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+type CreateRoleRequest struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+type UpdateRoleRequest struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+type CreatePermissionRequest struct {
+	Name        string
+	Description string
+}
+
+type RoleResponse struct {
+	Name        string
+	Description string
+	Permissions []string
+	BuiltIn     bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type PermissionResponse struct {
+	Name        string
+	Description string
+	CreatedAt   time.Time
+}
+
+func (s *service) CreateRole(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *CreateRoleRequest,
+) (*RoleResponse, error) {
+	if err := s.authorize(actor, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgRoleRequestRequired, nil)
+	}
+
+	perms, err := s.resolvePermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := domain.NewRoleDefinition(req.Name, req.Description, perms)
+	if err != nil {
+		if errors.Is(err, domain.ErrRoleBuiltIn) {
+			return nil, apperror.Conflict(apperror.ErrCodeRoleAlreadyExists, apperror.MsgRoleAlreadyExists, err)
+		}
+
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	existing, err := s.roleRepo.GetByName(ctx, role.Name)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetRole, err)
+	}
+
+	if existing != nil {
+		return nil, apperror.Conflict(apperror.ErrCodeRoleAlreadyExists, apperror.MsgRoleAlreadyExists, nil)
+	}
+
+	if err = s.roleRepo.Save(ctx, role); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveRole, err)
+	}
+
+	s.putRolePermissions(role)
+
+	return toRoleResponse(role), nil
+}
+
+func (s *service) GetRole(ctx context.Context, actor *domain.AccessClaims, name string) (*RoleResponse, error) {
+	if err := s.authorize(actor, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+
+	role, err := s.getRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoleResponse(role), nil
+}
+
+func (s *service) ListRoles(ctx context.Context, actor *domain.AccessClaims) ([]*RoleResponse, error) {
+	if err := s.authorize(actor, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.roleRepo.List(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListRoles, err)
+	}
+
+	out := make([]*RoleResponse, len(roles))
+	for i, role := range roles {
+		out[i] = toRoleResponse(role)
+	}
+
+	return out, nil
+}
+
+func (s *service) UpdateRole(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *UpdateRoleRequest,
+) (*RoleResponse, error) {
+	if err := s.authorize(actor, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgRoleRequestRequired, nil)
+	}
+
+	role, err := s.getRole(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	perms, err := s.resolvePermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = role.Update(req.Description, perms); err != nil {
+		return nil, apperror.Forbidden(apperror.ErrCodeRoleBuiltIn, apperror.MsgRoleBuiltIn, err)
+	}
+
+	if err = s.roleRepo.Update(ctx, role); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUpdateRole, err)
+	}
+
+	s.putRolePermissions(role)
+
+	return toRoleResponse(role), nil
+}
+
+func (s *service) DeleteRole(ctx context.Context, actor *domain.AccessClaims, name string) error {
+	if err := s.authorize(actor, domain.PermRoleManage); err != nil {
+		return err
+	}
+
+	role, err := s.getRole(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if role.BuiltIn {
+		return apperror.Forbidden(apperror.ErrCodeRoleBuiltIn, apperror.MsgRoleBuiltIn, nil)
+	}
+
+	assigned, err := s.roleRepo.IsAssigned(ctx, role.Name)
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetRole, err)
+	}
+
+	if assigned {
+		return apperror.Conflict(apperror.ErrCodeRoleInUse, apperror.MsgRoleInUse, nil)
+	}
+
+	if err = s.roleRepo.Delete(ctx, role.Name); err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgDeleteRole, err)
+	}
+
+	if s.permRefresher != nil {
+		s.permRefresher.RemoveRole(role.Name)
+	}
+
+	return nil
+}
+
+func (s *service) CreatePermission(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *CreatePermissionRequest,
+) (*PermissionResponse, error) {
+	if err := s.authorize(actor, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgPermissionRequired, nil)
+	}
+
+	perm, err := domain.NewPermissionDefinition(req.Name, req.Description)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	existing, err := s.permissionRepo.GetByName(ctx, perm.Name)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetPermission, err)
+	}
+
+	if existing != nil {
+		return nil, apperror.Conflict(apperror.ErrCodePermissionAlreadyExists, apperror.MsgPermissionAlreadyExists, nil)
+	}
+
+	if err = s.permissionRepo.Save(ctx, perm); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSavePermission, err)
+	}
+
+	return toPermissionResponse(perm), nil
+}
+
+func (s *service) ListPermissions(ctx context.Context, actor *domain.AccessClaims) ([]*PermissionResponse, error) {
+	if err := s.authorize(actor, domain.PermRoleManage); err != nil {
+		return nil, err
+	}
+
+	perms, err := s.permissionRepo.List(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListPermissions, err)
+	}
+
+	out := make([]*PermissionResponse, len(perms))
+	for i, perm := range perms {
+		out[i] = toPermissionResponse(perm)
+	}
+
+	return out, nil
+}
+
+func (s *service) getRole(ctx context.Context, name string) (*domain.RoleDefinition, error) {
+	role, err := s.roleRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetRole, err)
+	}
+
+	if role == nil {
+		return nil, apperror.NotFound(apperror.ErrCodeRoleNotFound, apperror.MsgRoleNotFound, nil)
+	}
+
+	return role, nil
+}
+
+func (s *service) resolvePermissions(ctx context.Context, raw []string) ([]domain.Permission, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	known, err := s.permissionRepo.List(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListPermissions, err)
+	}
+
+	knownSet := make(map[domain.Permission]struct{}, len(known))
+	for _, perm := range known {
+		knownSet[perm.Name] = struct{}{}
+	}
+
+	perms := make([]domain.Permission, 0, len(raw))
+
+	for _, r := range raw {
+		perm, err := domain.NewPermission(r)
+		if err != nil {
+			return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+		}
+
+		if _, ok := knownSet[perm]; !ok {
+			return nil, apperror.NotFound(apperror.ErrCodePermissionNotFound, apperror.MsgPermissionNotFound, nil)
+		}
+
+		perms = append(perms, perm)
+	}
+
+	return perms, nil
+}
+
+func (s *service) putRolePermissions(role *domain.RoleDefinition) {
+	if s.permRefresher != nil {
+		s.permRefresher.PutRole(role)
+	}
+}
+
+func toRoleResponse(role *domain.RoleDefinition) *RoleResponse {
+	perms := make([]string, len(role.Permissions))
+	for i, perm := range role.Permissions {
+		perms[i] = perm.String()
+	}
+
+	return &RoleResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: perms,
+		BuiltIn:     role.BuiltIn,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+func toPermissionResponse(perm *domain.PermissionDefinition) *PermissionResponse {
+	return &PermissionResponse{
+		Name:        perm.Name.String(),
+		Description: perm.Description,
+		CreatedAt:   perm.CreatedAt,
+	}
+}
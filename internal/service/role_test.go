@@ -0,0 +1,306 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/rbac"
+	"go-auth/internal/service"
+	"go-auth/pkg/logger"
+	_ "go-auth/pkg/logger/adapter/nop"
+)
+
+func knownPermissions() *mockPermissionRepo {
+	return &mockPermissionRepo{perms: []*domain.PermissionDefinition{
+		{Name: domain.PermUserRead},
+		{Name: domain.PermUserWrite},
+	}}
+}
+
+func TestServiceCreateRole(t *testing.T) {
+	ctx := context.Background()
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+	admin := mustActor(t, domain.RoleAdmin)
+	validReq := &service.CreateRoleRequest{
+		Name:        "support",
+		Description: "Support staff",
+		Permissions: []string{"user:read"},
+	}
+
+	tests := []struct {
+		name     string
+		actor    *domain.AccessClaims
+		req      *service.CreateRoleRequest
+		roleRepo *mockRoleRepo
+		wantCode apperror.Code
+	}{
+		{
+			name:     "no actor",
+			actor:    nil,
+			req:      validReq,
+			wantCode: apperror.ErrCodeUnauthorized,
+		},
+		{
+			name:     "actor lacks role:manage",
+			actor:    admin,
+			req:      validReq,
+			wantCode: apperror.ErrCodePermissionDenied,
+		},
+		{
+			name:     "nil request",
+			actor:    superadmin,
+			req:      nil,
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:     "invalid name",
+			actor:    superadmin,
+			req:      &service.CreateRoleRequest{Name: "Bad Name!"},
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:     "built-in name",
+			actor:    superadmin,
+			req:      &service.CreateRoleRequest{Name: domain.RoleAdmin},
+			wantCode: apperror.ErrCodeRoleAlreadyExists,
+		},
+		{
+			name:     "unknown permission",
+			actor:    superadmin,
+			req:      &service.CreateRoleRequest{Name: "support", Permissions: []string{"billing:read"}},
+			wantCode: apperror.ErrCodePermissionNotFound,
+		},
+		{
+			name:  "already exists",
+			actor: superadmin,
+			req:   validReq,
+			roleRepo: &mockRoleRepo{roles: map[string]*domain.RoleDefinition{
+				"support": {Name: "support"},
+			}},
+			wantCode: apperror.ErrCodeRoleAlreadyExists,
+		},
+		{
+			name:     "save error",
+			actor:    superadmin,
+			req:      validReq,
+			roleRepo: &mockRoleRepo{saveErr: errors.New("db error")},
+			wantCode: apperror.ErrCodeInternalServer,
+		},
+		{
+			name:     "success",
+			actor:    superadmin,
+			req:      validReq,
+			roleRepo: &mockRoleRepo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, err := newTestServiceWith(testDeps{RoleRepo: tt.roleRepo, PermissionRepo: knownPermissions()})
+			require.NoError(t, err)
+
+			got, err := svc.CreateRole(ctx, tt.actor, tt.req)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "support", got.Name)
+			assert.Equal(t, []string{"user:read"}, got.Permissions)
+			assert.False(t, got.BuiltIn)
+			assert.Equal(t, "support", tt.roleRepo.savedRole.Name)
+		})
+	}
+}
+
+func TestServiceUpdateRole(t *testing.T) {
+	ctx := context.Background()
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+
+	newRepo := func() *mockRoleRepo {
+		return &mockRoleRepo{roles: map[string]*domain.RoleDefinition{
+			"support":        {Name: "support", Permissions: []domain.Permission{domain.PermUserRead}},
+			domain.RoleAdmin: {Name: domain.RoleAdmin, BuiltIn: true},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		req      *service.UpdateRoleRequest
+		wantCode apperror.Code
+	}{
+		{
+			name:     "not found",
+			req:      &service.UpdateRoleRequest{Name: "missing"},
+			wantCode: apperror.ErrCodeRoleNotFound,
+		},
+		{
+			name:     "built-in role",
+			req:      &service.UpdateRoleRequest{Name: domain.RoleAdmin},
+			wantCode: apperror.ErrCodeRoleBuiltIn,
+		},
+		{
+			name: "success",
+			req: &service.UpdateRoleRequest{
+				Name:        "support",
+				Permissions: []string{"user:write", "user:read"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepo()
+			svc, err := newTestServiceWith(testDeps{RoleRepo: repo, PermissionRepo: knownPermissions()})
+			require.NoError(t, err)
+
+			got, err := svc.UpdateRole(ctx, superadmin, tt.req)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"user:read", "user:write"}, got.Permissions)
+		})
+	}
+}
+
+// Not parallel: it swaps the global permission resolver.
+func TestServiceCreateRoleAssignableImmediately(t *testing.T) {
+	ctx := context.Background()
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+
+	log, err := logger.New(logger.WithDriver(logger.DriverNop))
+	require.NoError(t, err)
+
+	resolver, err := rbac.NewResolver(&mockRoleRepo{}, log, 0)
+	require.NoError(t, err)
+
+	domain.SetPermissionResolver(resolver)
+	t.Cleanup(func() { domain.SetPermissionResolver(nil) })
+
+	roles := &mockRoleRepo{}
+	users := &mockUserRepo{getByIDUser: mustUserWithRole(t, domain.RoleUser)}
+	svc, err := newTestServiceWith(testDeps{
+		UserRepo:       users,
+		RoleRepo:       roles,
+		PermissionRepo: knownPermissions(),
+		PermRefresher:  resolver,
+	})
+	require.NoError(t, err)
+
+	_, err = svc.CreateRole(ctx, superadmin, &service.CreateRoleRequest{
+		Name:        "support",
+		Permissions: []string{"user:read"},
+	})
+	require.NoError(t, err)
+
+	err = svc.ChangeUserRole(ctx, superadmin, &service.ChangeUserRoleRequest{
+		UserID: users.getByIDUser.ID,
+		Role:   "support",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, users.updatedUser)
+	assert.Equal(t, "support", users.updatedUser.Role.String())
+
+	roles.roles = map[string]*domain.RoleDefinition{"support": roles.savedRole}
+	require.NoError(t, svc.DeleteRole(ctx, superadmin, "support"))
+	assert.False(t, resolver.RoleExists("support"))
+}
+
+func TestServiceDeleteRole(t *testing.T) {
+	ctx := context.Background()
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+
+	tests := []struct {
+		name     string
+		role     string
+		assigned bool
+		wantCode apperror.Code
+	}{
+		{name: "not found", role: "missing", wantCode: apperror.ErrCodeRoleNotFound},
+		{name: "built-in role", role: domain.RoleUser, wantCode: apperror.ErrCodeRoleBuiltIn},
+		{name: "assigned to users", role: "support", assigned: true, wantCode: apperror.ErrCodeRoleInUse},
+		{name: "success", role: "support"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockRoleRepo{
+				assigned: tt.assigned,
+				roles: map[string]*domain.RoleDefinition{
+					"support":       {Name: "support"},
+					domain.RoleUser: {Name: domain.RoleUser, BuiltIn: true},
+				},
+			}
+			svc, err := newTestServiceWith(testDeps{RoleRepo: repo})
+			require.NoError(t, err)
+
+			err = svc.DeleteRole(ctx, superadmin, tt.role)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+				assert.Empty(t, repo.deletedRole)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.role, repo.deletedRole)
+		})
+	}
+}
+
+func TestServiceCreatePermission(t *testing.T) {
+	ctx := context.Background()
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+
+	tests := []struct {
+		name     string
+		req      *service.CreatePermissionRequest
+		wantCode apperror.Code
+	}{
+		{name: "nil request", req: nil, wantCode: apperror.ErrCodeInvalidParam},
+		{name: "invalid name", req: &service.CreatePermissionRequest{Name: "billing"}, wantCode: apperror.ErrCodeInvalidParam},
+		{
+			name:     "already exists",
+			req:      &service.CreatePermissionRequest{Name: "user:read"},
+			wantCode: apperror.ErrCodePermissionAlreadyExists,
+		},
+		{name: "success", req: &service.CreatePermissionRequest{Name: "Billing:Read", Description: "Read invoices"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, err := newTestServiceWith(testDeps{PermissionRepo: knownPermissions()})
+			require.NoError(t, err)
+
+			got, err := svc.CreatePermission(ctx, superadmin, tt.req)
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "billing:read", got.Name)
+		})
+	}
+}
@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,14 +19,121 @@ type Service interface {
 	Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error)
 	Logout(ctx context.Context, refreshToken string) error
 	Refresh(ctx context.Context, req *RefreshRequest) (*RefreshResponse, error)
+
+	ValidateAuthorization(ctx context.Context, req *AuthorizeRequest) error
+	Authorize(ctx context.Context, actor *domain.AccessClaims, req *AuthorizeRequest) (*AuthorizeResponse, error)
+	Token(ctx context.Context, req *TokenRequest) (*TokenResponse, error)
+	Introspect(ctx context.Context, req *IntrospectRequest) (*IntrospectResponse, error)
+	Revoke(ctx context.Context, req *RevokeRequest) error
+	UserInfo(ctx context.Context, actor *domain.AccessClaims) (*UserInfoResponse, error)
+	CreateOAuthClient(
+		ctx context.Context,
+		actor *domain.AccessClaims,
+		req *CreateOAuthClientRequest,
+	) (*OAuthClientResponse, error)
+	RotateOAuthClientSecret(ctx context.Context, actor *domain.AccessClaims, clientID string) (*OAuthClientResponse, error)
+	DisableOAuthClient(ctx context.Context, actor *domain.AccessClaims, clientID string) error
+
+	CreateRole(ctx context.Context, actor *domain.AccessClaims, req *CreateRoleRequest) (*RoleResponse, error)
+	GetRole(ctx context.Context, actor *domain.AccessClaims, name string) (*RoleResponse, error)
+	ListRoles(ctx context.Context, actor *domain.AccessClaims) ([]*RoleResponse, error)
+	UpdateRole(ctx context.Context, actor *domain.AccessClaims, req *UpdateRoleRequest) (*RoleResponse, error)
+	DeleteRole(ctx context.Context, actor *domain.AccessClaims, name string) error
+	CreatePermission(
+		ctx context.Context,
+		actor *domain.AccessClaims,
+		req *CreatePermissionRequest,
+	) (*PermissionResponse, error)
+	ListPermissions(ctx context.Context, actor *domain.AccessClaims) ([]*PermissionResponse, error)
+
+	BanUser(ctx context.Context, actor *domain.AccessClaims, req *UserActionRequest) error
+	UnbanUser(ctx context.Context, actor *domain.AccessClaims, req *UserActionRequest) error
+	ChangeUserRole(ctx context.Context, actor *domain.AccessClaims, req *ChangeUserRoleRequest) error
+	Impersonate(ctx context.Context, actor *domain.AccessClaims, req *ImpersonateRequest) (*ImpersonateResponse, error)
+	ListAuditEvents(
+		ctx context.Context,
+		actor *domain.AccessClaims,
+		req *ListAuditEventsRequest,
+	) ([]*AuditEventResponse, error)
+
+	ListLoginHistory(ctx context.Context, actor *domain.AccessClaims, limit int) ([]*LoginAttemptResponse, error)
+	ListKnownDevices(ctx context.Context, actor *domain.AccessClaims) ([]*KnownDeviceResponse, error)
+
+	StartFederatedLogin(ctx context.Context, provider string) (*FederatedStartResponse, error)
+	CompleteFederatedLogin(ctx context.Context, req *FederatedCallbackRequest) (*LoginResponse, error)
+	StartFederatedLink(ctx context.Context, actor *domain.AccessClaims, provider string) (*FederatedStartResponse, error)
+	CompleteFederatedLink(
+		ctx context.Context,
+		actor *domain.AccessClaims,
+		req *FederatedCallbackRequest,
+	) (*IdentityResponse, error)
+
+	RequestMagicLink(ctx context.Context, req *MagicLinkRequest) (*MagicLinkResponse, error)
+	ConsumeMagicLink(ctx context.Context, req *ConsumeMagicLinkRequest) (*LoginResponse, error)
+
+	BeginPasskeyRegistration(ctx context.Context, actor *domain.AccessClaims) (*PasskeyRegistrationOptions, error)
+	FinishPasskeyRegistration(
+		ctx context.Context,
+		actor *domain.AccessClaims,
+		req *FinishPasskeyRegistrationRequest,
+	) (*PasskeyResponse, error)
+	ListPasskeys(ctx context.Context, actor *domain.AccessClaims) ([]*PasskeyResponse, error)
+	DeletePasskey(ctx context.Context, actor *domain.AccessClaims, id uuid.UUID) error
+	BeginPasskeyLogin(ctx context.Context) (*PasskeyLoginOptions, error)
+	FinishPasskeyLogin(ctx context.Context, req *PasskeyLoginRequest) (*LoginResponse, error)
+
+	SetMFAFactor(ctx context.Context, actor *domain.AccessClaims, req *SetMFAFactorRequest) error
+	VerifyMFA(ctx context.Context, req *VerifyMFARequest) (*LoginResponse, error)
+	Reauthenticate(
+		ctx context.Context,
+		actor *domain.AccessClaims,
+		req *ReauthenticateRequest,
+	) (*ReauthenticateResponse, error)
+
+	ListOrganizations(ctx context.Context, actor *domain.AccessClaims) ([]*OrganizationMembershipResponse, error)
+	SwitchOrganization(
+		ctx context.Context,
+		actor *domain.AccessClaims,
+		req *SwitchOrganizationRequest,
+	) (*RefreshResponse, error)
+	CreateOrganization(
+		ctx context.Context,
+		actor *domain.AccessClaims,
+		req *CreateOrganizationRequest,
+	) (*OrganizationResponse, error)
+	SetOrganizationMember(ctx context.Context, actor *domain.AccessClaims, req *OrganizationMemberRequest) error
+	RemoveOrganizationMember(ctx context.Context, actor *domain.AccessClaims, req *OrganizationMemberRequest) error
+
+	InviteToOrganization(ctx context.Context, actor *domain.AccessClaims, req *InviteRequest) (*InvitationResponse, error)
+	ListInvitations(ctx context.Context, actor *domain.AccessClaims, organization string) ([]*InvitationResponse, error)
+	RevokeInvitation(ctx context.Context, actor *domain.AccessClaims, req *InvitationActionRequest) error
+	AcceptInvitation(
+		ctx context.Context,
+		actor *domain.AccessClaims,
+		req *AcceptInvitationRequest,
+	) (*OrganizationMembershipResponse, error)
+
+	CreateAPIKey(ctx context.Context, actor *domain.AccessClaims, req *CreateAPIKeyRequest) (*APIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, actor *domain.AccessClaims) ([]*APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, actor *domain.AccessClaims, id uuid.UUID) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.AccessClaims, error)
+
+	CreateWebhook(ctx context.Context, actor *domain.AccessClaims, req *CreateWebhookRequest) (*WebhookResponse, error)
+	ListWebhooks(ctx context.Context, actor *domain.AccessClaims) ([]*WebhookResponse, error)
+	DisableWebhook(ctx context.Context, actor *domain.AccessClaims, id uuid.UUID) error
 }
 
 type RegisterRequest struct {
-	Username  string
-	Email     string
-	Password  string
-	FirstName string
-	LastName  string
+	Username     string
+	Email        string
+	Password     string
+	FirstName    string
+	LastName     string
+	Organization string
+	// Invitation is the secret of an organisation invitation sent to Email. The account is
+	// registered in the inviting organisation, which it joins with the invited role, and its
+	// email counts as verified. Organization is ignored with it.
+	Invitation string
 }
 
 type RegisterResponse struct {
@@ -30,10 +141,11 @@ type RegisterResponse struct {
 }
 
 type LoginRequest struct {
-	Login     string
-	Password  string
-	UserAgent string
-	ClientIP  string
+	Login        string
+	Password     string
+	UserAgent    string
+	ClientIP     string
+	Organization string
 }
 
 type LoginResponse struct {
@@ -42,6 +154,7 @@ type LoginResponse struct {
 	RefreshToken     string
 	AccessExpiresAt  time.Time
 	RefreshExpiresAt time.Time
+	MFA              *MFAChallenge
 }
 
 type RefreshRequest struct {
@@ -55,26 +168,156 @@ type RefreshResponse struct {
 	RefreshToken     string
 	AccessExpiresAt  time.Time
 	RefreshExpiresAt time.Time
+	Scopes           []string
+}
+
+type PermissionClaims string
+
+const (
+	PermissionClaimsNone        PermissionClaims = "none"
+	PermissionClaimsPermissions PermissionClaims = "permissions"
+	PermissionClaimsScope       PermissionClaims = "scope"
+)
+
+type SessionBinding string
+
+const (
+	SessionBindingStrict SessionBinding = "strict"
+	SessionBindingWarn   SessionBinding = "warn"
+	SessionBindingOff    SessionBinding = "off"
+)
+
+type UserScope string
+
+const (
+	UserScopeGlobal       UserScope = "global"
+	UserScopeOrganization UserScope = "organization"
+)
+
+type PermissionRefresher interface {
+	PutRole(role *domain.RoleDefinition)
+	RemoveRole(name string)
+}
+
+type ClientIPPolicy interface {
+	Allowed(addr netip.Addr) bool
 }
 
 type Config struct {
-	UserRepo           domain.UserRepository
-	SessionRepo        domain.SessionRepository
-	PasswordHasher     domain.PasswordHasher
-	OpaqueTokenManager domain.OpaqueTokenManager
-	AccessTokenManager domain.AccessTokenManager
-	AccessTokenTTL     time.Duration
-	RefreshTokenTTL    time.Duration
+	UserRepo             domain.UserRepository
+	SessionRepo          domain.SessionRepository
+	RoleRepo             domain.RoleRepository
+	PermissionRepo       domain.PermissionRepository
+	AuditRepo            domain.AuditRepository
+	AuditLogger          domain.AuditLogger
+	DeviceRepo           domain.KnownDeviceRepository
+	OAuthClientRepo      domain.OAuthClientRepository
+	AuthCodeRepo         domain.AuthorizationCodeRepository
+	Mailer               domain.Mailer
+	ClientIPPolicy       ClientIPPolicy
+	PermRefresher        PermissionRefresher
+	PasswordHasher       domain.PasswordHasher
+	OpaqueTokenManager   domain.OpaqueTokenManager
+	AccessTokenManager   domain.AccessTokenManager
+	IDTokenManager       domain.IDTokenManager
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	PermissionClaims     PermissionClaims
+	TokenAudience        []string
+	SessionBinding       SessionBinding
+	SessionMaxLifetime   time.Duration
+	SessionIdleTimeout   time.Duration
+	RefreshGracePeriod   time.Duration
+	AuthorizationCodeTTL time.Duration
+	IdentityProviders    []domain.IdentityProvider
+	UserIdentityRepo     domain.UserIdentityRepository
+	LoginStateRepo       domain.FederatedLoginStateRepository
+	FederatedStateTTL    time.Duration
+	MagicLinkURL         string
+	TokenRepo            domain.TokenRepository
+	MagicLinkTTL         time.Duration
+	MagicLinkBinding     bool
+	PasskeyVerifier      domain.PasskeyVerifier
+	PasskeyRepo          domain.PasskeyCredentialRepository
+	PasskeyChallengeRepo domain.PasskeyChallengeRepository
+	PasskeyChallengeTTL  time.Duration
+	EmailOTP             bool
+	EmailOTPTTL          time.Duration
+	EmailOTPMaxAttempts  int
+	StepUpWindow         time.Duration
+	ImpersonationTTL     time.Duration
+	// ClientAdmin lets client_credentials tokens use deployment-wide permissions granted in
+	// their scopes; by default only users can.
+	ClientAdmin      bool
+	OrganizationRepo domain.OrganizationRepository
+	UserScope        UserScope
+	InvitationURL    string
+	InvitationRepo   domain.InvitationRepository
+	InvitationTTL    time.Duration
+	APIKeys          bool
+	APIKeyRepo       domain.APIKeyRepository
+	APIKeyMaxTTL     time.Duration
+	Webhooks         bool
+	WebhookRepo      domain.WebhookRepository
+	Transactor       domain.Transactor
+	Events           domain.EventPublisher
 }
 
 type service struct {
-	userRepo           domain.UserRepository
-	sessionRepo        domain.SessionRepository
-	passwordHasher     domain.PasswordHasher
-	opaqueTokenManager domain.OpaqueTokenManager
-	accessTokenManager domain.AccessTokenManager
-	accessTokenTTL     time.Duration
-	refreshTokenTTL    time.Duration
+	userRepo             domain.UserRepository
+	sessionRepo          domain.SessionRepository
+	roleRepo             domain.RoleRepository
+	permissionRepo       domain.PermissionRepository
+	auditRepo            domain.AuditRepository
+	auditLogger          domain.AuditLogger
+	deviceRepo           domain.KnownDeviceRepository
+	oauthClientRepo      domain.OAuthClientRepository
+	authCodeRepo         domain.AuthorizationCodeRepository
+	mailer               domain.Mailer
+	clientIPPolicy       ClientIPPolicy
+	permRefresher        PermissionRefresher
+	passwordHasher       domain.PasswordHasher
+	opaqueTokenManager   domain.OpaqueTokenManager
+	accessTokenManager   domain.AccessTokenManager
+	idTokenManager       domain.IDTokenManager
+	accessTokenTTL       time.Duration
+	refreshTokenTTL      time.Duration
+	permissionClaims     PermissionClaims
+	tokenAudience        []string
+	sessionBinding       SessionBinding
+	sessionLimits        domain.SessionLimits
+	refreshGrace         time.Duration
+	authCodeTTL          time.Duration
+	identityProviders    map[string]domain.IdentityProvider
+	userIdentityRepo     domain.UserIdentityRepository
+	loginStateRepo       domain.FederatedLoginStateRepository
+	federatedStateTTL    time.Duration
+	tokenRepo            domain.TokenRepository
+	magicLinkURL         *url.URL
+	magicLinkTTL         time.Duration
+	magicLinkBinding     bool
+	passkeyVerifier      domain.PasskeyVerifier
+	passkeyRepo          domain.PasskeyCredentialRepository
+	passkeyChallengeRepo domain.PasskeyChallengeRepository
+	passkeyChallengeTTL  time.Duration
+	emailOTP             bool
+	emailOTPTTL          time.Duration
+	emailOTPMaxAttempts  int
+	stepUpWindow         time.Duration
+	impersonationTTL     time.Duration
+	clientAdmin          bool
+	organizationRepo     domain.OrganizationRepository
+	userScope            UserScope
+	invitationURL        *url.URL
+	invitationRepo       domain.InvitationRepository
+	invitationTTL        time.Duration
+	apiKeys              bool
+	apiKeyRepo           domain.APIKeyRepository
+	apiKeyMaxTTL         time.Duration
+	webhooks             bool
+	webhookRepo          domain.WebhookRepository
+	transactor           domain.Transactor
+	events               domain.EventPublisher
 }
 
 func NewService(cfg *Config) (Service, error) {
@@ -90,6 +333,159 @@ func NewService(cfg *Config) (Service, error) {
 		return nil, errors.New("refresh token TTL must be positive")
 	}
 
+	if cfg.SessionMaxLifetime < 0 {
+		return nil, errors.New("session max lifetime must not be negative")
+	}
+
+	if cfg.SessionIdleTimeout < 0 {
+		return nil, errors.New("session idle timeout must not be negative")
+	}
+
+	if cfg.RefreshGracePeriod < 0 {
+		return nil, errors.New("refresh grace period must not be negative")
+	}
+
+	if cfg.AuthorizationCodeTTL < 0 {
+		return nil, errors.New("authorization code TTL must not be negative")
+	}
+
+	authCodeTTL := cfg.AuthorizationCodeTTL
+	if authCodeTTL == 0 {
+		authCodeTTL = defaultAuthorizationCodeTTL
+	}
+
+	if cfg.FederatedStateTTL < 0 {
+		return nil, errors.New("federated state TTL must not be negative")
+	}
+
+	federatedStateTTL := cfg.FederatedStateTTL
+	if federatedStateTTL == 0 {
+		federatedStateTTL = defaultFederatedStateTTL
+	}
+
+	identityProviders, err := indexIdentityProviders(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	magicLinkURL, err := parseMagicLinkURL(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MagicLinkTTL < 0 {
+		return nil, errors.New("magic link TTL must not be negative")
+	}
+
+	magicLinkTTL := cfg.MagicLinkTTL
+	if magicLinkTTL == 0 {
+		magicLinkTTL = defaultMagicLinkTTL
+	}
+
+	if err = validatePasskeys(cfg); err != nil {
+		return nil, err
+	}
+
+	passkeyChallengeTTL := cfg.PasskeyChallengeTTL
+	if passkeyChallengeTTL == 0 {
+		passkeyChallengeTTL = defaultPasskeyChallengeTTL
+	}
+
+	if err = validateEmailOTP(cfg); err != nil {
+		return nil, err
+	}
+
+	emailOTPTTL := cfg.EmailOTPTTL
+	if emailOTPTTL == 0 {
+		emailOTPTTL = defaultEmailOTPTTL
+	}
+
+	emailOTPMaxAttempts := cfg.EmailOTPMaxAttempts
+	if emailOTPMaxAttempts == 0 {
+		emailOTPMaxAttempts = defaultEmailOTPMaxAttempts
+	}
+
+	if cfg.StepUpWindow < 0 {
+		return nil, errors.New("step-up window must not be negative")
+	}
+
+	stepUpWindow := cfg.StepUpWindow
+	if stepUpWindow == 0 {
+		stepUpWindow = defaultStepUpWindow
+	}
+
+	if cfg.ImpersonationTTL < 0 {
+		return nil, errors.New("impersonation TTL must not be negative")
+	}
+
+	impersonationTTL := cfg.ImpersonationTTL
+	if impersonationTTL == 0 {
+		impersonationTTL = defaultImpersonationTTL
+	}
+
+	permissionClaims := cfg.PermissionClaims
+	if permissionClaims == "" {
+		permissionClaims = PermissionClaimsNone
+	}
+
+	switch permissionClaims {
+	case PermissionClaimsNone, PermissionClaimsPermissions, PermissionClaimsScope:
+	default:
+		return nil, fmt.Errorf("unknown permission claims mode %q", permissionClaims)
+	}
+
+	userScope, err := validateOrganizations(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	invitationURL, err := parseInvitationURL(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.InvitationTTL < 0 {
+		return nil, errors.New("invitation TTL must not be negative")
+	}
+
+	invitationTTL := cfg.InvitationTTL
+	if invitationTTL == 0 {
+		invitationTTL = defaultInvitationTTL
+	}
+
+	if err = validateAPIKeys(cfg); err != nil {
+		return nil, err
+	}
+
+	apiKeyMaxTTL := cfg.APIKeyMaxTTL
+	if apiKeyMaxTTL == 0 {
+		apiKeyMaxTTL = defaultAPIKeyMaxTTL
+	}
+
+	if cfg.Webhooks && cfg.WebhookRepo == nil {
+		return nil, errors.New("webhook repository is required with webhooks")
+	}
+
+	if cfg.Webhooks && cfg.Transactor == nil {
+		return nil, errors.New("transactor is required with webhooks")
+	}
+
+	transactor := cfg.Transactor
+	if transactor == nil {
+		transactor = nopTransactor{}
+	}
+
+	sessionBinding := cfg.SessionBinding
+	if sessionBinding == "" {
+		sessionBinding = SessionBindingOff
+	}
+
+	switch sessionBinding {
+	case SessionBindingStrict, SessionBindingWarn, SessionBindingOff:
+	default:
+		return nil, fmt.Errorf("unknown session binding policy %q", sessionBinding)
+	}
+
 	if cfg.UserRepo == nil {
 		return nil, errors.New("user repository is required")
 	}
@@ -98,6 +494,40 @@ func NewService(cfg *Config) (Service, error) {
 		return nil, errors.New("session repository is required")
 	}
 
+	if cfg.RoleRepo == nil {
+		return nil, errors.New("role repository is required")
+	}
+
+	if cfg.PermissionRepo == nil {
+		return nil, errors.New("permission repository is required")
+	}
+
+	if cfg.AuditRepo == nil {
+		return nil, errors.New("audit repository is required")
+	}
+
+	if cfg.DeviceRepo == nil {
+		return nil, errors.New("device repository is required")
+	}
+
+	if cfg.OAuthClientRepo == nil {
+		return nil, errors.New("oauth client repository is required")
+	}
+
+	if cfg.AuthCodeRepo == nil {
+		return nil, errors.New("authorization code repository is required")
+	}
+
+	auditLogger := cfg.AuditLogger
+	if auditLogger == nil {
+		auditLogger = nopAuditLogger{}
+	}
+
+	events := cfg.Events
+	if events == nil {
+		events = nopEventPublisher{}
+	}
+
 	if cfg.PasswordHasher == nil {
 		return nil, errors.New("password hasher is required")
 	}
@@ -113,10 +543,212 @@ func NewService(cfg *Config) (Service, error) {
 	return &service{
 		userRepo:           cfg.UserRepo,
 		sessionRepo:        cfg.SessionRepo,
+		roleRepo:           cfg.RoleRepo,
+		permissionRepo:     cfg.PermissionRepo,
+		auditRepo:          cfg.AuditRepo,
+		auditLogger:        auditLogger,
+		deviceRepo:         cfg.DeviceRepo,
+		oauthClientRepo:    cfg.OAuthClientRepo,
+		authCodeRepo:       cfg.AuthCodeRepo,
+		mailer:             cfg.Mailer,
+		clientIPPolicy:     cfg.ClientIPPolicy,
+		permRefresher:      cfg.PermRefresher,
 		passwordHasher:     cfg.PasswordHasher,
 		opaqueTokenManager: cfg.OpaqueTokenManager,
 		accessTokenManager: cfg.AccessTokenManager,
+		idTokenManager:     cfg.IDTokenManager,
 		accessTokenTTL:     cfg.AccessTokenTTL,
 		refreshTokenTTL:    cfg.RefreshTokenTTL,
+		permissionClaims:   permissionClaims,
+		tokenAudience:      slices.Clone(cfg.TokenAudience),
+		sessionBinding:     sessionBinding,
+		sessionLimits: domain.SessionLimits{
+			MaxLifetime: cfg.SessionMaxLifetime,
+			IdleTimeout: cfg.SessionIdleTimeout,
+		},
+		refreshGrace:         cfg.RefreshGracePeriod,
+		authCodeTTL:          authCodeTTL,
+		identityProviders:    identityProviders,
+		userIdentityRepo:     cfg.UserIdentityRepo,
+		loginStateRepo:       cfg.LoginStateRepo,
+		federatedStateTTL:    federatedStateTTL,
+		tokenRepo:            cfg.TokenRepo,
+		magicLinkURL:         magicLinkURL,
+		magicLinkTTL:         magicLinkTTL,
+		magicLinkBinding:     cfg.MagicLinkBinding,
+		passkeyVerifier:      cfg.PasskeyVerifier,
+		passkeyRepo:          cfg.PasskeyRepo,
+		passkeyChallengeRepo: cfg.PasskeyChallengeRepo,
+		passkeyChallengeTTL:  passkeyChallengeTTL,
+		emailOTP:             cfg.EmailOTP,
+		emailOTPTTL:          emailOTPTTL,
+		emailOTPMaxAttempts:  emailOTPMaxAttempts,
+		stepUpWindow:         stepUpWindow,
+		impersonationTTL:     impersonationTTL,
+		clientAdmin:          cfg.ClientAdmin,
+		organizationRepo:     cfg.OrganizationRepo,
+		userScope:            userScope,
+		invitationURL:        invitationURL,
+		invitationRepo:       cfg.InvitationRepo,
+		invitationTTL:        invitationTTL,
+		apiKeys:              cfg.APIKeys,
+		apiKeyRepo:           cfg.APIKeyRepo,
+		apiKeyMaxTTL:         apiKeyMaxTTL,
+		webhooks:             cfg.Webhooks,
+		webhookRepo:          cfg.WebhookRepo,
+		transactor:           transactor,
+		events:               events,
 	}, nil
 }
+
+func indexIdentityProviders(cfg *Config) (map[string]domain.IdentityProvider, error) {
+	if len(cfg.IdentityProviders) == 0 {
+		return nil, nil
+	}
+
+	if cfg.UserIdentityRepo == nil {
+		return nil, errors.New("user identity repository is required with identity providers")
+	}
+
+	if cfg.LoginStateRepo == nil {
+		return nil, errors.New("federated login state repository is required with identity providers")
+	}
+
+	providers := make(map[string]domain.IdentityProvider, len(cfg.IdentityProviders))
+
+	for _, provider := range cfg.IdentityProviders {
+		name := provider.Name()
+		if err := domain.ValidateProviderName(name); err != nil {
+			return nil, fmt.Errorf("identity provider %q: %w", name, err)
+		}
+
+		if _, ok := providers[name]; ok {
+			return nil, fmt.Errorf("duplicate identity provider %q", name)
+		}
+
+		providers[name] = provider
+	}
+
+	return providers, nil
+}
+
+func parseMagicLinkURL(cfg *Config) (*url.URL, error) {
+	if cfg.MagicLinkURL == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(cfg.MagicLinkURL)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("magic link URL %q must be an absolute URL", cfg.MagicLinkURL)
+	}
+
+	if cfg.TokenRepo == nil {
+		return nil, errors.New("token repository is required with magic links")
+	}
+
+	if cfg.Mailer == nil {
+		return nil, errors.New("mailer is required with magic links")
+	}
+
+	return u, nil
+}
+
+func validatePasskeys(cfg *Config) error {
+	if cfg.PasskeyChallengeTTL < 0 {
+		return errors.New("passkey challenge TTL must not be negative")
+	}
+
+	if cfg.PasskeyVerifier == nil {
+		return nil
+	}
+
+	if cfg.PasskeyRepo == nil {
+		return errors.New("passkey repository is required with passkeys")
+	}
+
+	if cfg.PasskeyChallengeRepo == nil {
+		return errors.New("passkey challenge repository is required with passkeys")
+	}
+
+	return nil
+}
+
+func validateEmailOTP(cfg *Config) error {
+	if cfg.EmailOTPTTL < 0 {
+		return errors.New("email OTP TTL must not be negative")
+	}
+
+	if cfg.EmailOTPMaxAttempts < 0 {
+		return errors.New("email OTP max attempts must not be negative")
+	}
+
+	if !cfg.EmailOTP {
+		return nil
+	}
+
+	if cfg.TokenRepo == nil {
+		return errors.New("token repository is required with email OTP")
+	}
+
+	if cfg.Mailer == nil {
+		return errors.New("mailer is required with email OTP")
+	}
+
+	return nil
+}
+
+func validateOrganizations(cfg *Config) (UserScope, error) {
+	userScope := cfg.UserScope
+	if userScope == "" {
+		userScope = UserScopeGlobal
+	}
+
+	switch userScope {
+	case UserScopeGlobal:
+	case UserScopeOrganization:
+		if cfg.OrganizationRepo == nil {
+			return "", errors.New("organization repository is required with organization user scope")
+		}
+	default:
+		return "", fmt.Errorf("unknown user scope %q", userScope)
+	}
+
+	return userScope, nil
+}
+
+func parseInvitationURL(cfg *Config) (*url.URL, error) {
+	if cfg.InvitationURL == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(cfg.InvitationURL)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("invitation URL %q must be an absolute URL", cfg.InvitationURL)
+	}
+
+	if cfg.OrganizationRepo == nil {
+		return nil, errors.New("organization repository is required with invitations")
+	}
+
+	if cfg.InvitationRepo == nil {
+		return nil, errors.New("invitation repository is required with invitations")
+	}
+
+	if cfg.Mailer == nil {
+		return nil, errors.New("mailer is required with invitations")
+	}
+
+	return u, nil
+}
+
+func validateAPIKeys(cfg *Config) error {
+	if cfg.APIKeyMaxTTL < 0 {
+		return errors.New("API key max TTL must not be negative")
+	}
+
+	if cfg.APIKeys && cfg.APIKeyRepo == nil {
+		return errors.New("API key repository is required with API keys")
+	}
+
+	return nil
+}
@@ -1,8 +1,11 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -32,7 +35,10 @@ type mockUserRepo struct {
 	existsByUsernameErr error
 	existsByEmail       bool
 	existsByEmailErr    error
+	updateErr           error
 	savedUser           *domain.User
+	updatedUser         *domain.User
+	lookupScope         *uuid.UUID
 }
 
 func (m *mockUserRepo) Save(ctx context.Context, user *domain.User) error {
@@ -52,45 +58,1184 @@ func (m *mockUserRepo) GetByUsername(ctx context.Context, u domain.Username) (*d
 func (m *mockUserRepo) GetByEmail(ctx context.Context, e domain.Email) (*domain.User, error) {
 	return m.getByEmailUser, m.getByEmailErr
 }
-func (m *mockUserRepo) Update(ctx context.Context, user *domain.User) error { return nil }
-func (m *mockUserRepo) Delete(ctx context.Context, id uuid.UUID) error      { return nil }
+
+func (m *mockUserRepo) Update(ctx context.Context, user *domain.User) error {
+	m.updatedUser = user
+
+	return m.updateErr
+}
+
+func (m *mockUserRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+
 func (m *mockUserRepo) ExistsByUsername(ctx context.Context, u domain.Username) (bool, error) {
 	return m.existsByUsername, m.existsByUsernameErr
 }
 
-func (m *mockUserRepo) ExistsByEmail(ctx context.Context, e domain.Email) (bool, error) {
-	return m.existsByEmail, m.existsByEmailErr
+func (m *mockUserRepo) ExistsByEmail(ctx context.Context, e domain.Email) (bool, error) {
+	return m.existsByEmail, m.existsByEmailErr
+}
+
+func (m *mockUserRepo) GetByUsernameInOrganization(
+	ctx context.Context,
+	organizationID *uuid.UUID,
+	u domain.Username,
+) (*domain.User, error) {
+	m.lookupScope = organizationID
+
+	return m.getByUsernameUser, m.getByUsernameErr
+}
+
+func (m *mockUserRepo) GetByEmailInOrganization(
+	ctx context.Context,
+	organizationID *uuid.UUID,
+	e domain.Email,
+) (*domain.User, error) {
+	m.lookupScope = organizationID
+
+	return m.getByEmailUser, m.getByEmailErr
+}
+
+func (m *mockUserRepo) ExistsByUsernameInOrganization(
+	ctx context.Context,
+	organizationID *uuid.UUID,
+	u domain.Username,
+) (bool, error) {
+	m.lookupScope = organizationID
+
+	return m.existsByUsername, m.existsByUsernameErr
+}
+
+func (m *mockUserRepo) ExistsByEmailInOrganization(
+	ctx context.Context,
+	organizationID *uuid.UUID,
+	e domain.Email,
+) (bool, error) {
+	m.lookupScope = organizationID
+
+	return m.existsByEmail, m.existsByEmailErr
+}
+
+type mockSessionRepo struct {
+	saveErr        error
+	getByToken     *domain.Session
+	getByTokenErr  error
+	updateErr      error
+	savedSession   *domain.Session
+	updatedSession *domain.Session
+	rotateErr      error
+	rotatedSession *domain.Session
+	byID           map[uuid.UUID]*domain.Session
+}
+
+func (m *mockSessionRepo) Save(ctx context.Context, session *domain.Session) error {
+	m.savedSession = session
+
+	return m.saveErr
+}
+
+func (m *mockSessionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	return m.byID[id], nil
+}
+
+func (m *mockSessionRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Session, error) {
+	return nil, nil
+}
+
+func (m *mockSessionRepo) GetByToken(ctx context.Context, token string) (*domain.Session, error) {
+	if m.getByTokenErr != nil {
+		return nil, m.getByTokenErr
+	}
+
+	return m.getByToken, nil
+}
+
+func (m *mockSessionRepo) Update(ctx context.Context, session *domain.Session) error {
+	m.updatedSession = session
+
+	return m.updateErr
+}
+func (m *mockSessionRepo) Rotate(ctx context.Context, session, successor *domain.Session) error {
+	if m.rotateErr != nil {
+		return m.rotateErr
+	}
+
+	m.rotatedSession = session
+	m.savedSession = successor
+
+	return nil
+}
+
+func (m *mockSessionRepo) Delete(ctx context.Context, id uuid.UUID) error             { return nil }
+func (m *mockSessionRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error { return nil }
+
+type mockRoleRepo struct {
+	roles       map[string]*domain.RoleDefinition
+	getErr      error
+	listErr     error
+	saveErr     error
+	updateErr   error
+	deleteErr   error
+	assigned    bool
+	savedRole   *domain.RoleDefinition
+	deletedRole string
+}
+
+func (m *mockRoleRepo) Save(ctx context.Context, role *domain.RoleDefinition) error {
+	m.savedRole = role
+
+	return m.saveErr
+}
+
+func (m *mockRoleRepo) GetByName(ctx context.Context, name string) (*domain.RoleDefinition, error) {
+	return m.roles[name], m.getErr
+}
+
+func (m *mockRoleRepo) List(ctx context.Context) ([]*domain.RoleDefinition, error) {
+	out := make([]*domain.RoleDefinition, 0, len(m.roles))
+	for _, role := range m.roles {
+		out = append(out, role)
+	}
+
+	return out, m.listErr
+}
+
+func (m *mockRoleRepo) Update(ctx context.Context, role *domain.RoleDefinition) error {
+	m.savedRole = role
+
+	return m.updateErr
+}
+
+func (m *mockRoleRepo) Delete(ctx context.Context, name string) error {
+	m.deletedRole = name
+
+	return m.deleteErr
+}
+
+func (m *mockRoleRepo) IsAssigned(ctx context.Context, name string) (bool, error) {
+	return m.assigned, nil
+}
+
+type mockPermissionRepo struct {
+	perms   []*domain.PermissionDefinition
+	listErr error
+	saveErr error
+}
+
+func (m *mockPermissionRepo) Save(ctx context.Context, perm *domain.PermissionDefinition) error {
+	return m.saveErr
+}
+
+func (m *mockPermissionRepo) GetByName(
+	ctx context.Context,
+	name domain.Permission,
+) (*domain.PermissionDefinition, error) {
+	for _, perm := range m.perms {
+		if perm.Name == name {
+			return perm, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *mockPermissionRepo) List(ctx context.Context) ([]*domain.PermissionDefinition, error) {
+	return m.perms, m.listErr
+}
+
+func (m *mockPermissionRepo) Delete(ctx context.Context, name domain.Permission) error { return nil }
+
+type mockAuditRepo struct {
+	events  []*domain.AuditEvent
+	listErr error
+	filter  domain.AuditFilter
+}
+
+func (m *mockAuditRepo) Save(ctx context.Context, event *domain.AuditEvent) error { return nil }
+
+func (m *mockAuditRepo) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	m.filter = filter
+
+	return m.events, m.listErr
+}
+
+type mockAuditLogger struct {
+	mu     sync.Mutex
+	events []*domain.AuditEvent
+}
+
+func (m *mockAuditLogger) Log(ctx context.Context, event *domain.AuditEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, event)
+
+	return nil
+}
+
+func (m *mockAuditLogger) actions() []domain.AuditAction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]domain.AuditAction, len(m.events))
+	for i, event := range m.events {
+		out[i] = event.Action
+	}
+
+	return out
+}
+
+func (m *mockAuditLogger) last() *domain.AuditEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.events) == 0 {
+		return nil
+	}
+
+	return m.events[len(m.events)-1]
+}
+
+type mockDeviceRepo struct {
+	mu        sync.Mutex
+	devices   map[string]*domain.KnownDevice
+	upsertErr error
+	countErr  error
+}
+
+func (m *mockDeviceRepo) Upsert(ctx context.Context, device *domain.KnownDevice) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.upsertErr != nil {
+		return false, m.upsertErr
+	}
+
+	if m.devices == nil {
+		m.devices = map[string]*domain.KnownDevice{}
+	}
+
+	if existing, ok := m.devices[device.Fingerprint]; ok {
+		existing.LastSeenAt = device.LastSeenAt
+
+		return false, nil
+	}
+
+	m.devices[device.Fingerprint] = device
+
+	return true, nil
+}
+
+func (m *mockDeviceRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.KnownDevice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]*domain.KnownDevice, 0, len(m.devices))
+	for _, device := range m.devices {
+		out = append(out, device)
+	}
+
+	return out, nil
+}
+
+func (m *mockDeviceRepo) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return int64(len(m.devices)), m.countErr
+}
+
+type mockOAuthClientRepo struct {
+	clients       map[string]*domain.OAuthClient
+	saveErr       error
+	savedClient   *domain.OAuthClient
+	updatedClient *domain.OAuthClient
+}
+
+func (m *mockOAuthClientRepo) Save(ctx context.Context, client *domain.OAuthClient) error {
+	m.savedClient = client
+
+	return m.saveErr
+}
+
+func (m *mockOAuthClientRepo) GetByID(ctx context.Context, id string) (*domain.OAuthClient, error) {
+	return m.clients[id], nil
+}
+
+func (m *mockOAuthClientRepo) Update(ctx context.Context, client *domain.OAuthClient) error {
+	m.updatedClient = client
+
+	return nil
+}
+
+type mockAuthCodeRepo struct {
+	mu        sync.Mutex
+	codes     map[string]*domain.AuthorizationCode
+	savedCode *domain.AuthorizationCode
+	useErr    error
+}
+
+func (m *mockAuthCodeRepo) Save(ctx context.Context, code *domain.AuthorizationCode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.codes == nil {
+		m.codes = map[string]*domain.AuthorizationCode{}
+	}
+
+	m.codes[code.CodeHash] = code
+	m.savedCode = code
+
+	return nil
+}
+
+func (m *mockAuthCodeRepo) GetByHash(ctx context.Context, codeHash string) (*domain.AuthorizationCode, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	code, ok := m.codes[codeHash]
+	if !ok {
+		return nil, nil
+	}
+
+	// Hand out a copy, as a database would, so Use on it does not leak into the store.
+	clone := *code
+
+	return &clone, nil
+}
+
+func (m *mockAuthCodeRepo) Use(ctx context.Context, code *domain.AuthorizationCode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.useErr != nil {
+		return m.useErr
+	}
+
+	stored, ok := m.codes[code.CodeHash]
+	if !ok || stored.IsUsed() {
+		return domain.ErrTokenUsed
+	}
+
+	stored.UsedAt = code.UsedAt
+
+	return nil
+}
+
+type mockTokenRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*domain.Token
+	saved  *domain.Token
+	useErr error
+}
+
+func (m *mockTokenRepo) Save(ctx context.Context, token *domain.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tokens == nil {
+		m.tokens = map[string]*domain.Token{}
+	}
+
+	m.tokens[token.Token] = token
+	m.saved = token
+
+	return nil
+}
+
+func (m *mockTokenRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Token, error) {
+	return nil, nil
+}
+
+func (m *mockTokenRepo) GetByToken(ctx context.Context, tokenHash string) (*domain.Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+
+	clone := *token
+
+	return &clone, nil
+}
+
+func (m *mockTokenRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Token, error) {
+	return nil, nil
+}
+
+func (m *mockTokenRepo) Update(ctx context.Context, token *domain.Token) error { return nil }
+
+func (m *mockTokenRepo) Use(ctx context.Context, token *domain.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.useErr != nil {
+		return m.useErr
+	}
+
+	stored, ok := m.tokens[token.Token]
+	if !ok || stored.IsUsed() {
+		return domain.ErrTokenUsed
+	}
+
+	stored.UsedAt = token.UsedAt
+
+	return nil
+}
+
+func (m *mockTokenRepo) Attempt(ctx context.Context, token *domain.Token, maxAttempts int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.tokens[token.Token]
+	if !ok || stored.IsUsed() || stored.Attempts >= maxAttempts {
+		return domain.ErrTokenUsed
+	}
+
+	stored.Attempts++
+	token.Attempts = stored.Attempts
+
+	return nil
+}
+
+func (m *mockTokenRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+
+func (m *mockTokenRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error { return nil }
+
+type mockUserIdentityRepo struct {
+	mu         sync.Mutex
+	identities []*domain.UserIdentity
+	saved      []*domain.UserIdentity
+}
+
+func (m *mockUserIdentityRepo) Save(ctx context.Context, identity *domain.UserIdentity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return domain.ErrIdentityAlreadyLinked
+		}
+	}
+
+	m.identities = append(m.identities, identity)
+	m.saved = append(m.saved, identity)
+
+	return nil
+}
+
+func (m *mockUserIdentityRepo) GetByProviderSubject(
+	ctx context.Context,
+	provider, subject string,
+) (*domain.UserIdentity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, identity := range m.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+
+	return nil, nil
+}
+
+type mockLoginStateRepo struct {
+	mu     sync.Mutex
+	states map[string]*domain.FederatedLoginState
+	saved  *domain.FederatedLoginState
+}
+
+func (m *mockLoginStateRepo) Save(ctx context.Context, state *domain.FederatedLoginState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.states == nil {
+		m.states = map[string]*domain.FederatedLoginState{}
+	}
+
+	m.states[state.StateHash] = state
+	m.saved = state
+
+	return nil
+}
+
+func (m *mockLoginStateRepo) Consume(ctx context.Context, stateHash string) (*domain.FederatedLoginState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.states[stateHash]
+	delete(m.states, stateHash)
+
+	return state, nil
+}
+
+type mockPasskeyRepo struct {
+	mu          sync.Mutex
+	credentials []*domain.PasskeyCredential
+	useErr      error
+}
+
+func (m *mockPasskeyRepo) Save(ctx context.Context, credential *domain.PasskeyCredential) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.credentials {
+		if bytes.Equal(existing.CredentialID, credential.CredentialID) {
+			return domain.ErrPasskeyAlreadyRegistered
+		}
+	}
+
+	clone := *credential
+	m.credentials = append(m.credentials, &clone)
+
+	return nil
+}
+
+func (m *mockPasskeyRepo) GetByCredentialID(
+	ctx context.Context,
+	credentialID []byte,
+) (*domain.PasskeyCredential, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.credentials {
+		if bytes.Equal(c.CredentialID, credentialID) {
+			clone := *c
+
+			return &clone, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *mockPasskeyRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.PasskeyCredential, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []*domain.PasskeyCredential
+
+	for _, c := range m.credentials {
+		if c.UserID == userID {
+			clone := *c
+			out = append(out, &clone)
+		}
+	}
+
+	return out, nil
+}
+
+func (m *mockPasskeyRepo) Use(ctx context.Context, credential *domain.PasskeyCredential) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.useErr != nil {
+		return m.useErr
+	}
+
+	for _, c := range m.credentials {
+		if c.ID != credential.ID {
+			continue
+		}
+
+		if c.SignCount >= credential.SignCount && (c.SignCount != 0 || credential.SignCount != 0) {
+			return domain.ErrPasskeySignCount
+		}
+
+		c.SignCount = credential.SignCount
+		c.LastUsedAt = credential.LastUsedAt
+
+		return nil
+	}
+
+	return domain.ErrPasskeySignCount
+}
+
+func (m *mockPasskeyRepo) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, c := range m.credentials {
+		if c.ID == id && c.UserID == userID {
+			m.credentials = append(m.credentials[:i], m.credentials[i+1:]...)
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (m *mockPasskeyRepo) stored(id uuid.UUID) *domain.PasskeyCredential {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range m.credentials {
+		if c.ID == id {
+			clone := *c
+
+			return &clone
+		}
+	}
+
+	return nil
+}
+
+type mockPasskeyChallengeRepo struct {
+	mu         sync.Mutex
+	challenges map[string]*domain.PasskeyChallenge
+	saved      *domain.PasskeyChallenge
+}
+
+func (m *mockPasskeyChallengeRepo) Save(ctx context.Context, challenge *domain.PasskeyChallenge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.challenges == nil {
+		m.challenges = map[string]*domain.PasskeyChallenge{}
+	}
+
+	m.challenges[challenge.ChallengeHash] = challenge
+	m.saved = challenge
+
+	return nil
+}
+
+func (m *mockPasskeyChallengeRepo) Consume(
+	ctx context.Context,
+	challengeHash string,
+) (*domain.PasskeyChallenge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	challenge := m.challenges[challengeHash]
+	delete(m.challenges, challengeHash)
+
+	return challenge, nil
+}
+
+type mockOrganizationRepo struct {
+	mu            sync.Mutex
+	organizations []*domain.Organization
+	members       []*domain.OrganizationMember
+	saveMemberErr error
+}
+
+func (m *mockOrganizationRepo) snapshot() func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	members := slices.Clone(m.members)
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.members = members
+	}
+}
+
+func (m *mockOrganizationRepo) Save(ctx context.Context, organization *domain.Organization) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.organizations {
+		if existing.Slug == organization.Slug {
+			return domain.ErrOrganizationSlugTaken
+		}
+	}
+
+	m.organizations = append(m.organizations, organization)
+
+	return nil
+}
+
+func (m *mockOrganizationRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, organization := range m.organizations {
+		if organization.ID == id {
+			return organization, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *mockOrganizationRepo) GetBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, organization := range m.organizations {
+		if organization.Slug == slug {
+			return organization, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *mockOrganizationRepo) SaveMember(ctx context.Context, member *domain.OrganizationMember) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.saveMemberErr != nil {
+		return m.saveMemberErr
+	}
+
+	for i, existing := range m.members {
+		if existing.OrganizationID == member.OrganizationID && existing.UserID == member.UserID {
+			m.members[i] = member
+
+			return nil
+		}
+	}
+
+	m.members = append(m.members, member)
+
+	return nil
+}
+
+func (m *mockOrganizationRepo) GetMember(
+	ctx context.Context,
+	organizationID, userID uuid.UUID,
+) (*domain.OrganizationMember, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, member := range m.members {
+		if member.OrganizationID == organizationID && member.UserID == userID {
+			return member, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *mockOrganizationRepo) ListMemberships(
+	ctx context.Context,
+	userID uuid.UUID,
+) ([]*domain.OrganizationMembership, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []*domain.OrganizationMembership
+
+	for _, member := range m.members {
+		if member.UserID != userID {
+			continue
+		}
+
+		for _, organization := range m.organizations {
+			if organization.ID == member.OrganizationID {
+				out = append(out, &domain.OrganizationMembership{
+					Organization: organization,
+					Role:         member.Role,
+					JoinedAt:     member.CreatedAt,
+				})
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func (m *mockOrganizationRepo) RemoveMember(ctx context.Context, organizationID, userID uuid.UUID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, member := range m.members {
+		if member.OrganizationID == organizationID && member.UserID == userID {
+			m.members = append(m.members[:i], m.members[i+1:]...)
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type mockInvitationRepo struct {
+	mu          sync.Mutex
+	invitations []*domain.Invitation
+	acceptErr   error
+}
+
+func (m *mockInvitationRepo) snapshot() func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	invitations := make([]*domain.Invitation, len(m.invitations))
+	for i, invitation := range m.invitations {
+		copied := *invitation
+		invitations[i] = &copied
+	}
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.invitations = invitations
+	}
+}
+
+func (m *mockInvitationRepo) Save(ctx context.Context, invitation *domain.Invitation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.invitations = append(m.invitations, invitation)
+
+	return nil
+}
+
+func (m *mockInvitationRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, invitation := range m.invitations {
+		if invitation.ID == id {
+			copied := *invitation
+
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *mockInvitationRepo) GetByToken(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, invitation := range m.invitations {
+		if invitation.Token == tokenHash {
+			copied := *invitation
+
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *mockInvitationRepo) ListByOrganization(
+	ctx context.Context,
+	organizationID uuid.UUID,
+) ([]*domain.Invitation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []*domain.Invitation
+
+	for i := len(m.invitations) - 1; i >= 0; i-- {
+		if m.invitations[i].OrganizationID == organizationID {
+			out = append(out, m.invitations[i])
+		}
+	}
+
+	return out, nil
+}
+
+func (m *mockInvitationRepo) Accept(ctx context.Context, invitation *domain.Invitation) error {
+	if m.acceptErr != nil {
+		return m.acceptErr
+	}
+
+	return m.update(invitation, func(stored *domain.Invitation) {
+		stored.AcceptedAt = invitation.AcceptedAt
+		stored.AcceptedBy = invitation.AcceptedBy
+	})
+}
+
+func (m *mockInvitationRepo) Revoke(ctx context.Context, invitation *domain.Invitation) error {
+	return m.update(invitation, func(stored *domain.Invitation) {
+		stored.RevokedAt = invitation.RevokedAt
+	})
+}
+
+// update applies set to the stored copy of invitation if it is still pending, mirroring the
+// conditional UPDATE of the real repository.
+func (m *mockInvitationRepo) update(invitation *domain.Invitation, set func(*domain.Invitation)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, stored := range m.invitations {
+		if stored.ID == invitation.ID {
+			if stored.Status() != domain.InvitationStatusPending {
+				return domain.ErrInvitationNotPending
+			}
+
+			set(stored)
+
+			return nil
+		}
+	}
+
+	return domain.ErrInvitationNotPending
+}
+
+type mockAPIKeyRepo struct {
+	mu   sync.Mutex
+	keys []*domain.APIKey
+}
+
+func (m *mockAPIKeyRepo) Save(ctx context.Context, key *domain.APIKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.keys = append(m.keys, key)
+
+	return nil
+}
+
+func (m *mockAPIKeyRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range m.keys {
+		if key.ID == id {
+			copied := *key
+
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (m *mockAPIKeyRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out []*domain.APIKey
+
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		if m.keys[i].UserID == userID {
+			out = append(out, m.keys[i])
+		}
+	}
+
+	return out, nil
+}
+
+func (m *mockAPIKeyRepo) Use(ctx context.Context, key *domain.APIKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, stored := range m.keys {
+		if stored.ID == key.ID {
+			stored.LastUsedAt = key.LastUsedAt
+		}
+	}
+
+	return nil
+}
+
+func (m *mockAPIKeyRepo) Revoke(ctx context.Context, userID, id uuid.UUID, revokedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, stored := range m.keys {
+		if stored.ID == id && stored.UserID == userID && stored.RevokedAt == nil {
+			stored.RevokedAt = &revokedAt
+
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
-type mockSessionRepo struct {
-	saveErr       error
-	getByToken    *domain.Session
-	getByTokenErr error
-	updateErr     error
+type mockWebhookRepo struct {
+	mu            sync.Mutex
+	subscriptions []*domain.WebhookSubscription
+	events        []*domain.WebhookEvent
+	enqueueErr    error
 }
 
-func (m *mockSessionRepo) Save(ctx context.Context, session *domain.Session) error { return m.saveErr }
-func (m *mockSessionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
-	return nil, nil
+func (m *mockWebhookRepo) SaveSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subscriptions = append(m.subscriptions, subscription)
+
+	return nil
 }
 
-func (m *mockSessionRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Session, error) {
+func (m *mockWebhookRepo) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.subscriptions), nil
+}
+
+func (m *mockWebhookRepo) DisableSubscription(ctx context.Context, id uuid.UUID, disabledAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, stored := range m.subscriptions {
+		if stored.ID == id {
+			stored.DisabledAt = &disabledAt
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (m *mockWebhookRepo) Enqueue(ctx context.Context, event *domain.WebhookEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.enqueueErr != nil {
+		return m.enqueueErr
+	}
+
+	m.events = append(m.events, event)
+
+	return nil
+}
+
+func (m *mockWebhookRepo) ClaimDeliveries(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]*domain.WebhookDelivery, error) {
 	return nil, nil
 }
 
-func (m *mockSessionRepo) GetByToken(ctx context.Context, token string) (*domain.Session, error) {
-	if m.getByTokenErr != nil {
-		return nil, m.getByTokenErr
+func (m *mockWebhookRepo) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return nil
+}
+
+func (m *mockWebhookRepo) eventTypes() []domain.WebhookEventType {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	types := make([]domain.WebhookEventType, len(m.events))
+	for i, event := range m.events {
+		types[i] = event.Type
 	}
 
-	return m.getByToken, nil
+	return types
 }
 
-func (m *mockSessionRepo) Update(ctx context.Context, session *domain.Session) error {
-	return m.updateErr
+type mockEventPublisher struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func (m *mockEventPublisher) Publish(ctx context.Context, event domain.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, event)
+}
+
+func (m *mockEventPublisher) last() domain.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.events) == 0 {
+		return nil
+	}
+
+	return m.events[len(m.events)-1]
+}
+
+// mockTransactor counts the transactions run through it and returns the error of fn.
+// mockTransactor stands in for a database transaction: when fn fails, the repositories in
+// rollback are restored to their state before the call.
+type mockTransactor struct {
+	mu       sync.Mutex
+	calls    int
+	rollback []snapshotter
+}
+
+type snapshotter interface {
+	snapshot() (restore func())
+}
+
+func (m *mockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+
+	restores := make([]func(), len(m.rollback))
+	for i, repo := range m.rollback {
+		restores[i] = repo.snapshot()
+	}
+
+	if err := fn(ctx); err != nil {
+		for _, restore := range restores {
+			restore()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+type mockIdentityProvider struct {
+	name         string
+	authURLErr   error
+	identity     *domain.ExternalIdentity
+	exchangeErr  error
+	gotNonce     string
+	gotVerifier  string
+	gotChallenge domain.PKCEChallenge
+}
+
+func (m *mockIdentityProvider) Name() string { return m.name }
+
+func (m *mockIdentityProvider) AuthCodeURL(
+	ctx context.Context,
+	state, nonce string,
+	pkce domain.PKCEChallenge,
+) (string, error) {
+	if m.authURLErr != nil {
+		return "", m.authURLErr
+	}
+
+	m.gotChallenge = pkce
+
+	return "https://idp.example.com/authorize?state=" + state, nil
+}
+
+func (m *mockIdentityProvider) Exchange(
+	ctx context.Context,
+	code, codeVerifier, nonce string,
+) (*domain.ExternalIdentity, error) {
+	m.gotNonce = nonce
+	m.gotVerifier = codeVerifier
+
+	if m.exchangeErr != nil {
+		return nil, m.exchangeErr
+	}
+
+	identity := *m.identity
+
+	return &identity, nil
+}
+
+type mockMailer struct {
+	sent chan *domain.EmailMessage
+}
+
+func newMockMailer() *mockMailer {
+	return &mockMailer{sent: make(chan *domain.EmailMessage, 8)}
+}
+
+func (m *mockMailer) Send(ctx context.Context, msg *domain.EmailMessage) error {
+	m.sent <- msg
+
+	return nil
 }
-func (m *mockSessionRepo) Delete(ctx context.Context, id uuid.UUID) error             { return nil }
-func (m *mockSessionRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error { return nil }
 
 type mockPasswordHasher struct {
 	hashErr   error
@@ -113,6 +1258,7 @@ type mockOpaqueTokenManager struct {
 	generateErr   error
 	hashResult    string
 	hashErr       error
+	deriveErr     error
 }
 
 func (m *mockOpaqueTokenManager) Generate() (string, error) {
@@ -139,12 +1285,47 @@ func (m *mockOpaqueTokenManager) Hash(token string) (string, error) {
 	return "hashed-" + token, nil
 }
 
+func (m *mockOpaqueTokenManager) Derive(token string) (string, error) {
+	if m.deriveErr != nil {
+		return "", m.deriveErr
+	}
+
+	return "derived-" + token, nil
+}
+
+type mockIDTokenManager struct {
+	lastClaims *domain.IDClaims
+}
+
+func (m *mockIDTokenManager) Generate(claims domain.IDClaims) (string, error) {
+	m.lastClaims = &claims
+
+	return "id-token", nil
+}
+
+func (m *mockIDTokenManager) PublicKeys() []domain.JSONWebKey {
+	return nil
+}
+
+func (m *mockIDTokenManager) Algorithm() string {
+	return "RS256"
+}
+
+func (m *mockIDTokenManager) TTL() time.Duration {
+	return time.Hour
+}
+
 type mockAccessTokenManager struct {
-	generateToken string
-	generateErr   error
+	generateToken  string
+	generateErr    error
+	lastClaims     domain.AccessClaims
+	validateClaims *domain.AccessClaims
+	validateErr    error
 }
 
 func (m *mockAccessTokenManager) Generate(claims domain.AccessClaims) (string, error) {
+	m.lastClaims = claims
+
 	if m.generateErr != nil {
 		return "", m.generateErr
 	}
@@ -156,35 +1337,55 @@ func (m *mockAccessTokenManager) Generate(claims domain.AccessClaims) (string, e
 	return "access-token", nil
 }
 
-func (m *mockAccessTokenManager) Validate(token string) (*domain.AccessClaims, error) {
-	return nil, nil
-}
-
-func newTestService(
-	ur domain.UserRepository,
-	sr domain.SessionRepository,
-	ph domain.PasswordHasher,
-	ot domain.OpaqueTokenManager,
-	at domain.AccessTokenManager,
-) (service.Service, error) {
-	return service.NewService(&service.Config{
-		UserRepo:           ur,
-		SessionRepo:        sr,
-		PasswordHasher:     ph,
-		OpaqueTokenManager: ot,
-		AccessTokenManager: at,
-		AccessTokenTTL:     testAccessTTL,
-		RefreshTokenTTL:    testRefreshTTL,
-	})
+func (m *mockAccessTokenManager) Validate(token string, audience ...string) (*domain.AccessClaims, error) {
+	return m.validateClaims, m.validateErr
 }
 
 // testDeps holds optional test doubles; nil fields are replaced with fresh no-op mocks.
 type testDeps struct {
-	UserRepo    *mockUserRepo
-	SessionRepo *mockSessionRepo
-	Hasher      *mockPasswordHasher
-	Opaque      *mockOpaqueTokenManager
-	Access      *mockAccessTokenManager
+	UserRepo       *mockUserRepo
+	SessionRepo    *mockSessionRepo
+	RoleRepo       *mockRoleRepo
+	PermissionRepo *mockPermissionRepo
+	AuditRepo      *mockAuditRepo
+	AuditLogger    *mockAuditLogger
+	DeviceRepo     *mockDeviceRepo
+	OAuthClients   *mockOAuthClientRepo
+	AuthCodes      *mockAuthCodeRepo
+	Mailer         *mockMailer
+	Hasher         *mockPasswordHasher
+	Opaque         *mockOpaqueTokenManager
+	Access         *mockAccessTokenManager
+	IDTokens       *mockIDTokenManager
+	Identities     *mockUserIdentityRepo
+	LoginStates    *mockLoginStateRepo
+	Tokens         *mockTokenRepo
+	Passkeys       *mockPasskeyRepo
+	Challenges     *mockPasskeyChallengeRepo
+	Organizations  *mockOrganizationRepo
+	Invitations    *mockInvitationRepo
+	APIKeys        *mockAPIKeyRepo
+	Webhooks       *mockWebhookRepo
+	Transactor     *mockTransactor
+	Events         *mockEventPublisher
+
+	PermissionClaims service.PermissionClaims
+	TokenAudience    []string
+	ClientIPPolicy   service.ClientIPPolicy
+	SessionBinding   service.SessionBinding
+	SessionLimits    domain.SessionLimits
+	RefreshGrace     time.Duration
+	AuthCodeTTL      time.Duration
+
+	IdentityProviders []domain.IdentityProvider
+	MagicLinkURL      string
+	MagicLinkBinding  bool
+	PasskeyVerifier   domain.PasskeyVerifier
+	EmailOTP          bool
+	UserScope         service.UserScope
+	InvitationURL     string
+	ClientAdmin       bool
+	PermRefresher     service.PermissionRefresher
 }
 
 // newTestServiceWith builds a service from d; any nil dep is filled with a default no-op mock.
@@ -197,6 +1398,34 @@ func newTestServiceWith(d testDeps) (service.Service, error) {
 		d.SessionRepo = &mockSessionRepo{}
 	}
 
+	if d.RoleRepo == nil {
+		d.RoleRepo = &mockRoleRepo{}
+	}
+
+	if d.PermissionRepo == nil {
+		d.PermissionRepo = &mockPermissionRepo{}
+	}
+
+	if d.AuditRepo == nil {
+		d.AuditRepo = &mockAuditRepo{}
+	}
+
+	if d.AuditLogger == nil {
+		d.AuditLogger = &mockAuditLogger{}
+	}
+
+	if d.DeviceRepo == nil {
+		d.DeviceRepo = &mockDeviceRepo{}
+	}
+
+	if d.OAuthClients == nil {
+		d.OAuthClients = &mockOAuthClientRepo{}
+	}
+
+	if d.AuthCodes == nil {
+		d.AuthCodes = &mockAuthCodeRepo{}
+	}
+
 	if d.Hasher == nil {
 		d.Hasher = &mockPasswordHasher{}
 	}
@@ -209,7 +1438,97 @@ func newTestServiceWith(d testDeps) (service.Service, error) {
 		d.Access = &mockAccessTokenManager{}
 	}
 
-	return newTestService(d.UserRepo, d.SessionRepo, d.Hasher, d.Opaque, d.Access)
+	cfg := &service.Config{
+		UserRepo:             d.UserRepo,
+		SessionRepo:          d.SessionRepo,
+		RoleRepo:             d.RoleRepo,
+		PermissionRepo:       d.PermissionRepo,
+		AuditRepo:            d.AuditRepo,
+		AuditLogger:          d.AuditLogger,
+		DeviceRepo:           d.DeviceRepo,
+		OAuthClientRepo:      d.OAuthClients,
+		AuthCodeRepo:         d.AuthCodes,
+		PasswordHasher:       d.Hasher,
+		OpaqueTokenManager:   d.Opaque,
+		AccessTokenManager:   d.Access,
+		AccessTokenTTL:       testAccessTTL,
+		RefreshTokenTTL:      testRefreshTTL,
+		PermissionClaims:     d.PermissionClaims,
+		TokenAudience:        d.TokenAudience,
+		ClientIPPolicy:       d.ClientIPPolicy,
+		SessionBinding:       d.SessionBinding,
+		SessionMaxLifetime:   d.SessionLimits.MaxLifetime,
+		SessionIdleTimeout:   d.SessionLimits.IdleTimeout,
+		RefreshGracePeriod:   d.RefreshGrace,
+		AuthorizationCodeTTL: d.AuthCodeTTL,
+		IdentityProviders:    d.IdentityProviders,
+		MagicLinkURL:         d.MagicLinkURL,
+		MagicLinkBinding:     d.MagicLinkBinding,
+		PasskeyVerifier:      d.PasskeyVerifier,
+		EmailOTP:             d.EmailOTP,
+		UserScope:            d.UserScope,
+		InvitationURL:        d.InvitationURL,
+		ClientAdmin:          d.ClientAdmin,
+		PermRefresher:        d.PermRefresher,
+	}
+
+	// A nil *mockMailer must stay a nil interface so the service skips notifications.
+	if d.Mailer != nil {
+		cfg.Mailer = d.Mailer
+	}
+
+	if d.IDTokens != nil {
+		cfg.IDTokenManager = d.IDTokens
+	}
+
+	if d.Identities != nil {
+		cfg.UserIdentityRepo = d.Identities
+	}
+
+	if d.LoginStates != nil {
+		cfg.LoginStateRepo = d.LoginStates
+	}
+
+	if d.Tokens != nil {
+		cfg.TokenRepo = d.Tokens
+	}
+
+	if d.Passkeys != nil {
+		cfg.PasskeyRepo = d.Passkeys
+	}
+
+	if d.Challenges != nil {
+		cfg.PasskeyChallengeRepo = d.Challenges
+	}
+
+	if d.Organizations != nil {
+		cfg.OrganizationRepo = d.Organizations
+	}
+
+	if d.Invitations != nil {
+		cfg.InvitationRepo = d.Invitations
+	}
+
+	if d.APIKeys != nil {
+		cfg.APIKeys = true
+		cfg.APIKeyRepo = d.APIKeys
+	}
+
+	if d.Webhooks != nil {
+		cfg.Webhooks = true
+		cfg.WebhookRepo = d.Webhooks
+		cfg.Transactor = &mockTransactor{}
+	}
+
+	if d.Transactor != nil {
+		cfg.Transactor = d.Transactor
+	}
+
+	if d.Events != nil {
+		cfg.Events = d.Events
+	}
+
+	return service.NewService(cfg)
 }
 
 func mustVerifiedUser(t *testing.T, username, email, passHash string) *domain.User {
@@ -256,6 +1575,25 @@ func mustSession(t *testing.T, userID uuid.UUID, expiresIn time.Duration, revoke
 	return s
 }
 
+func mustActor(t *testing.T, role string) *domain.AccessClaims {
+	t.Helper()
+
+	r, err := domain.NewRole(role)
+	require.NoError(t, err)
+
+	return &domain.AccessClaims{UserID: uuid.New(), Role: r}
+}
+
+// mustRecentActor returns an actor that passes the step-up guard.
+func mustRecentActor(t *testing.T, role string) *domain.AccessClaims {
+	t.Helper()
+
+	actor := mustActor(t, role)
+	actor.AuthTime = time.Now()
+
+	return actor
+}
+
 func assertAppErrorCode(t *testing.T, err error, code apperror.Code) {
 	t.Helper()
 	require.Error(t, err)
@@ -294,4 +1632,149 @@ func TestNewService(t *testing.T) {
 		})
 		require.Error(t, err)
 	})
+	t.Run("missing role repo", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := service.NewService(&service.Config{
+			UserRepo:           &mockUserRepo{},
+			SessionRepo:        &mockSessionRepo{},
+			PermissionRepo:     &mockPermissionRepo{},
+			PasswordHasher:     &mockPasswordHasher{},
+			OpaqueTokenManager: &mockOpaqueTokenManager{},
+			AccessTokenManager: &mockAccessTokenManager{},
+			AccessTokenTTL:     testAccessTTL,
+			RefreshTokenTTL:    testRefreshTTL,
+		})
+		require.Error(t, err)
+	})
+	t.Run("missing audit repo", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := service.NewService(&service.Config{
+			UserRepo:           &mockUserRepo{},
+			SessionRepo:        &mockSessionRepo{},
+			RoleRepo:           &mockRoleRepo{},
+			PermissionRepo:     &mockPermissionRepo{},
+			PasswordHasher:     &mockPasswordHasher{},
+			OpaqueTokenManager: &mockOpaqueTokenManager{},
+			AccessTokenManager: &mockAccessTokenManager{},
+			AccessTokenTTL:     testAccessTTL,
+			RefreshTokenTTL:    testRefreshTTL,
+		})
+		require.Error(t, err)
+	})
+	t.Run("missing device repo", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := service.NewService(&service.Config{
+			UserRepo:           &mockUserRepo{},
+			SessionRepo:        &mockSessionRepo{},
+			RoleRepo:           &mockRoleRepo{},
+			PermissionRepo:     &mockPermissionRepo{},
+			AuditRepo:          &mockAuditRepo{},
+			PasswordHasher:     &mockPasswordHasher{},
+			OpaqueTokenManager: &mockOpaqueTokenManager{},
+			AccessTokenManager: &mockAccessTokenManager{},
+			AccessTokenTTL:     testAccessTTL,
+			RefreshTokenTTL:    testRefreshTTL,
+		})
+		require.Error(t, err)
+	})
+	t.Run("missing oauth client repo", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := service.NewService(&service.Config{
+			UserRepo:           &mockUserRepo{},
+			SessionRepo:        &mockSessionRepo{},
+			RoleRepo:           &mockRoleRepo{},
+			PermissionRepo:     &mockPermissionRepo{},
+			AuditRepo:          &mockAuditRepo{},
+			DeviceRepo:         &mockDeviceRepo{},
+			PasswordHasher:     &mockPasswordHasher{},
+			OpaqueTokenManager: &mockOpaqueTokenManager{},
+			AccessTokenManager: &mockAccessTokenManager{},
+			AccessTokenTTL:     testAccessTTL,
+			RefreshTokenTTL:    testRefreshTTL,
+		})
+		require.Error(t, err)
+	})
+	t.Run("unknown permission claims mode", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestServiceWith(testDeps{PermissionClaims: "roles"})
+		require.Error(t, err)
+	})
+	t.Run("unknown session binding policy", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestServiceWith(testDeps{SessionBinding: "lenient"})
+		require.Error(t, err)
+	})
+	t.Run("negative session limits", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestServiceWith(testDeps{SessionLimits: domain.SessionLimits{MaxLifetime: -time.Hour}})
+		require.Error(t, err)
+
+		_, err = newTestServiceWith(testDeps{SessionLimits: domain.SessionLimits{IdleTimeout: -time.Hour}})
+		require.Error(t, err)
+	})
+	t.Run("negative authorization code TTL", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestServiceWith(testDeps{AuthCodeTTL: -time.Minute})
+		require.Error(t, err)
+	})
+	t.Run("identity providers without repositories", func(t *testing.T) {
+		t.Parallel()
+
+		providers := []domain.IdentityProvider{&mockIdentityProvider{name: "google"}}
+
+		_, err := newTestServiceWith(testDeps{IdentityProviders: providers})
+		require.Error(t, err)
+
+		_, err = newTestServiceWith(testDeps{
+			IdentityProviders: append(providers, &mockIdentityProvider{name: "google"}),
+			Identities:        &mockUserIdentityRepo{},
+			LoginStates:       &mockLoginStateRepo{},
+		})
+		require.Error(t, err)
+	})
+	t.Run("magic links without dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestServiceWith(testDeps{MagicLinkURL: "https://app.example.com/login/link"})
+		require.Error(t, err)
+
+		_, err = newTestServiceWith(testDeps{
+			MagicLinkURL: "https://app.example.com/login/link",
+			Tokens:       &mockTokenRepo{},
+		})
+		require.Error(t, err)
+
+		_, err = newTestServiceWith(testDeps{
+			MagicLinkURL: "/login/link",
+			Tokens:       &mockTokenRepo{},
+			Mailer:       newMockMailer(),
+		})
+		require.Error(t, err)
+	})
+	t.Run("passkeys without dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestServiceWith(testDeps{PasskeyVerifier: newTestRelyingParty(t)})
+		require.Error(t, err)
+
+		_, err = newTestServiceWith(testDeps{PasskeyVerifier: newTestRelyingParty(t), Passkeys: &mockPasskeyRepo{}})
+		require.Error(t, err)
+	})
+	t.Run("email OTP without dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTestServiceWith(testDeps{EmailOTP: true, Mailer: newMockMailer()})
+		require.Error(t, err)
+
+		_, err = newTestServiceWith(testDeps{EmailOTP: true, Tokens: &mockTokenRepo{}})
+		require.Error(t, err)
+	})
 }
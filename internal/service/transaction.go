@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go-auth/internal/apperror"
+)
+
+type nopTransactor struct{}
+
+func (nopTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (s *service) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := s.transactor.WithinTransaction(ctx, fn)
+
+	var appErr *apperror.Error
+	if err != nil && !errors.As(err, &appErr) {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	return err
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+type UserActionRequest struct {
+	UserID    uuid.UUID
+	UserAgent string
+	ClientIP  string
+}
+
+type ChangeUserRoleRequest struct {
+	UserID    uuid.UUID
+	Role      string
+	UserAgent string
+	ClientIP  string
+}
+
+func (s *service) BanUser(ctx context.Context, actor *domain.AccessClaims, req *UserActionRequest) error {
+	return s.setUserBanned(ctx, actor, req, true)
+}
+
+func (s *service) UnbanUser(ctx context.Context, actor *domain.AccessClaims, req *UserActionRequest) error {
+	return s.setUserBanned(ctx, actor, req, false)
+}
+
+func (s *service) setUserBanned(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *UserActionRequest,
+	banned bool,
+) error {
+	action := domain.AuditActionUserUnban
+	if banned {
+		action = domain.AuditActionUserBanned
+	}
+
+	if req == nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgUserActionRequired, nil)
+	}
+
+	if err := s.authorize(actor, domain.PermUserBan); err != nil {
+		s.auditDenied(ctx, action, actor, req.UserID, req.UserAgent, req.ClientIP)
+
+		return err
+	}
+
+	user, err := s.getManagedUser(ctx, actor, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if banned {
+		err = user.Ban()
+	} else {
+		err = user.Unban()
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrUserBanned):
+		return apperror.Conflict(apperror.ErrCodeUserAlreadyBanned, apperror.MsgUserAlreadyBanned, err)
+	case errors.Is(err, domain.ErrUserNotBanned):
+		return apperror.Conflict(apperror.ErrCodeUserNotBanned, apperror.MsgUserNotBanned, err)
+	case err != nil:
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgOperationFailed, err)
+	}
+
+	event := domain.WebhookEventUserUnbanned
+	if banned {
+		event = domain.WebhookEventUserBanned
+	}
+
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		if updateErr := s.userRepo.Update(ctx, user); updateErr != nil {
+			return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUpdateUser, updateErr)
+		}
+
+		return s.publishUserEvents(ctx, user, event)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(action, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithTarget(user.ID).
+		WithClient(req.UserAgent, req.ClientIP))
+
+	now := time.Now().UTC()
+	if banned {
+		s.publish(ctx, domain.UserBanned{UserID: user.ID, ActorID: actor.UserID, At: now})
+	} else {
+		s.publish(ctx, domain.UserUnbanned{UserID: user.ID, ActorID: actor.UserID, At: now})
+	}
+
+	return nil
+}
+
+func (s *service) ChangeUserRole(ctx context.Context, actor *domain.AccessClaims, req *ChangeUserRoleRequest) error {
+	if req == nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgUserActionRequired, nil)
+	}
+
+	if err := s.authorize(actor, domain.PermRoleManage); err != nil {
+		s.auditDenied(ctx, domain.AuditActionRoleChanged, actor, req.UserID, req.UserAgent, req.ClientIP)
+
+		return err
+	}
+
+	role, err := domain.NewRole(req.Role)
+	if err != nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	user, err := s.getManagedUser(ctx, actor, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	previous := user.Role
+
+	if err = user.UpdateRole(role); err != nil {
+		return apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	if err = s.userRepo.Update(ctx, user); err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgUpdateUser, err)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionRoleChanged, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithTarget(user.ID).
+		WithClient(req.UserAgent, req.ClientIP).
+		WithMetadata(map[string]any{"from": previous.String(), "to": role.String()}))
+
+	return nil
+}
+
+// getManagedUser loads the target of an admin action. Admins may not act on
+// their own account or on superadmins.
+func (s *service) getManagedUser(ctx context.Context, actor *domain.AccessClaims, id uuid.UUID) (*domain.User, error) {
+	if id == actor.UserID {
+		return nil, apperror.Forbidden(apperror.ErrCodeTargetProtected, apperror.MsgCannotManageSelf, nil)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGetUser, err)
+	}
+
+	if user == nil {
+		return nil, apperror.NotFound(apperror.ErrCodeUserNotFound, apperror.MsgUserNotFound, nil)
+	}
+
+	if user.Role.String() == domain.RoleSuperAdmin {
+		return nil, apperror.Forbidden(apperror.ErrCodeTargetProtected, apperror.MsgTargetProtected, nil)
+	}
+
+	return user, nil
+}
+
+func (s *service) auditDenied(
+	ctx context.Context,
+	action domain.AuditAction,
+	actor *domain.AccessClaims,
+	target uuid.UUID,
+	userAgent, clientIP string,
+) {
+	s.audit(ctx, domain.NewAuditEvent(action, domain.AuditOutcomeDenied).
+		WithActorClaims(actor).
+		WithTarget(target).
+		WithClient(userAgent, clientIP))
+}
@@ -0,0 +1,272 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+func mustUserWithRole(t *testing.T, role string) *domain.User {
+	t.Helper()
+
+	user := mustVerifiedUser(t, "target", "target@example.com", "$hash")
+	r, err := domain.NewRole(role)
+	require.NoError(t, err)
+	user.Role = r
+
+	return user
+}
+
+func TestServiceBanUser(t *testing.T) {
+	ctx := context.Background()
+	admin := mustActor(t, domain.RoleAdmin)
+	user := mustActor(t, domain.RoleUser)
+
+	banned := mustUserWithRole(t, domain.RoleUser)
+	require.NoError(t, banned.Ban())
+
+	tests := []struct {
+		name        string
+		actor       *domain.AccessClaims
+		req         func(target *domain.User) *service.UserActionRequest
+		target      *domain.User
+		missing     bool
+		userRepo    *mockUserRepo
+		wantCode    apperror.Code
+		wantOutcome domain.AuditOutcome
+	}{
+		{
+			name:     "nil request",
+			actor:    admin,
+			req:      func(*domain.User) *service.UserActionRequest { return nil },
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:        "actor lacks user:ban",
+			actor:       user,
+			target:      mustUserWithRole(t, domain.RoleUser),
+			wantCode:    apperror.ErrCodePermissionDenied,
+			wantOutcome: domain.AuditOutcomeDenied,
+		},
+		{
+			name:  "self",
+			actor: admin,
+			req: func(*domain.User) *service.UserActionRequest {
+				return &service.UserActionRequest{UserID: admin.UserID}
+			},
+			wantCode: apperror.ErrCodeTargetProtected,
+		},
+		{
+			name:     "target not found",
+			actor:    admin,
+			target:   mustUserWithRole(t, domain.RoleUser),
+			missing:  true,
+			wantCode: apperror.ErrCodeUserNotFound,
+		},
+		{
+			name:     "superadmin target",
+			actor:    admin,
+			target:   mustUserWithRole(t, domain.RoleSuperAdmin),
+			wantCode: apperror.ErrCodeTargetProtected,
+		},
+		{
+			name:     "already banned",
+			actor:    admin,
+			target:   banned,
+			wantCode: apperror.ErrCodeUserAlreadyBanned,
+		},
+		{
+			name:     "update error",
+			actor:    admin,
+			target:   mustUserWithRole(t, domain.RoleUser),
+			userRepo: &mockUserRepo{updateErr: errors.New("db error")},
+			wantCode: apperror.ErrCodeInternalServer,
+		},
+		{
+			name:        "success",
+			actor:       admin,
+			target:      mustUserWithRole(t, domain.RoleUser),
+			wantOutcome: domain.AuditOutcomeSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := tt.userRepo
+			if repo == nil {
+				repo = &mockUserRepo{}
+			}
+
+			if !tt.missing {
+				repo.getByIDUser = tt.target
+			}
+
+			req := &service.UserActionRequest{UserAgent: "ua", ClientIP: "1.2.3.4"}
+			if tt.target != nil {
+				req.UserID = tt.target.ID
+			}
+
+			if tt.req != nil {
+				req = tt.req(tt.target)
+			}
+
+			auditLog := &mockAuditLogger{}
+			svc, err := newTestServiceWith(testDeps{UserRepo: repo, AuditLogger: auditLog})
+			require.NoError(t, err)
+
+			err = svc.BanUser(ctx, tt.actor, req)
+
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, repo.updatedUser)
+				assert.True(t, repo.updatedUser.IsBanned())
+			}
+
+			if tt.wantOutcome == "" {
+				assert.Nil(t, auditLog.last())
+
+				return
+			}
+
+			event := auditLog.last()
+			require.NotNil(t, event)
+			assert.Equal(t, domain.AuditActionUserBanned, event.Action)
+			assert.Equal(t, tt.wantOutcome, event.Outcome)
+			require.NotNil(t, event.ActorID)
+			assert.Equal(t, tt.actor.UserID, *event.ActorID)
+			require.NotNil(t, event.TargetID)
+			assert.Equal(t, req.UserID, *event.TargetID)
+		})
+	}
+}
+
+func TestServiceUnbanUser(t *testing.T) {
+	ctx := context.Background()
+	admin := mustActor(t, domain.RoleAdmin)
+
+	t.Run("not banned", func(t *testing.T) {
+		t.Parallel()
+
+		target := mustUserWithRole(t, domain.RoleUser)
+		svc, err := newTestServiceWith(testDeps{UserRepo: &mockUserRepo{getByIDUser: target}})
+		require.NoError(t, err)
+
+		err = svc.UnbanUser(ctx, admin, &service.UserActionRequest{UserID: target.ID})
+		assertAppErrorCode(t, err, apperror.ErrCodeUserNotBanned)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		target := mustUserWithRole(t, domain.RoleUser)
+		require.NoError(t, target.Ban())
+
+		auditLog := &mockAuditLogger{}
+		svc, err := newTestServiceWith(testDeps{
+			UserRepo:    &mockUserRepo{getByIDUser: target},
+			AuditLogger: auditLog,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.UnbanUser(ctx, admin, &service.UserActionRequest{UserID: target.ID}))
+		assert.False(t, target.IsBanned())
+		assert.Equal(t, []domain.AuditAction{domain.AuditActionUserUnban}, auditLog.actions())
+	})
+}
+
+func TestServiceChangeUserRole(t *testing.T) {
+	ctx := context.Background()
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+	admin := mustActor(t, domain.RoleAdmin)
+
+	tests := []struct {
+		name     string
+		actor    *domain.AccessClaims
+		role     string
+		target   *domain.User
+		wantCode apperror.Code
+	}{
+		{
+			name:     "actor lacks role:manage",
+			actor:    admin,
+			role:     domain.RoleAdmin,
+			target:   mustUserWithRole(t, domain.RoleUser),
+			wantCode: apperror.ErrCodePermissionDenied,
+		},
+		{
+			name:     "unknown role",
+			actor:    superadmin,
+			role:     "ghost",
+			target:   mustUserWithRole(t, domain.RoleUser),
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:     "superadmin target",
+			actor:    superadmin,
+			role:     domain.RoleUser,
+			target:   mustUserWithRole(t, domain.RoleSuperAdmin),
+			wantCode: apperror.ErrCodeTargetProtected,
+		},
+		{
+			name:   "success",
+			actor:  superadmin,
+			role:   domain.RoleAdmin,
+			target: mustUserWithRole(t, domain.RoleUser),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := &mockUserRepo{getByIDUser: tt.target}
+			auditLog := &mockAuditLogger{}
+			svc, err := newTestServiceWith(testDeps{UserRepo: repo, AuditLogger: auditLog})
+			require.NoError(t, err)
+
+			err = svc.ChangeUserRole(ctx, tt.actor, &service.ChangeUserRoleRequest{
+				UserID: tt.target.ID,
+				Role:   tt.role,
+			})
+
+			if tt.wantCode != "" {
+				assertAppErrorCode(t, err, tt.wantCode)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, repo.updatedUser)
+			assert.Equal(t, tt.role, repo.updatedUser.Role.String())
+
+			event := auditLog.last()
+			require.NotNil(t, event)
+			assert.Equal(t, domain.AuditActionRoleChanged, event.Action)
+			assert.Equal(t, map[string]any{"from": domain.RoleUser, "to": tt.role}, event.Metadata)
+		})
+	}
+}
+
+func TestServiceChangeUserRoleSelf(t *testing.T) {
+	superadmin := mustActor(t, domain.RoleSuperAdmin)
+
+	svc, err := newTestServiceWith(testDeps{})
+	require.NoError(t, err)
+
+	err = svc.ChangeUserRole(context.Background(), superadmin, &service.ChangeUserRoleRequest{
+		UserID: superadmin.UserID,
+		Role:   domain.RoleUser,
+	})
+	assertAppErrorCode(t, err, apperror.ErrCodeTargetProtected)
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+)
+
+type CreateWebhookRequest struct {
+	URL    string
+	Events []string
+}
+
+type WebhookResponse struct {
+	ID         uuid.UUID
+	URL        string
+	Secret     string
+	Events     []string
+	DisabledAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (s *service) CreateWebhook(
+	ctx context.Context,
+	actor *domain.AccessClaims,
+	req *CreateWebhookRequest,
+) (*WebhookResponse, error) {
+	if err := s.authorize(actor, domain.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	if !s.webhooks {
+		return nil, errWebhooksDisabled()
+	}
+
+	if req == nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, apperror.MsgWebhookRequired, nil)
+	}
+
+	secret, err := s.opaqueTokenManager.Generate()
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgGenerateWebhookSecret, err)
+	}
+
+	subscription, err := domain.NewWebhookSubscription(req.URL, secret, req.Events)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.ErrCodeInvalidParam, err.Error(), err)
+	}
+
+	if err = s.webhookRepo.SaveSubscription(ctx, subscription); err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgSaveWebhook, err)
+	}
+
+	res := toWebhookResponse(subscription)
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionWebhookCreated, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithMetadata(map[string]any{
+			"webhook_id": subscription.ID.String(),
+			"url":        subscription.URL,
+			"events":     res.Events,
+		}))
+
+	res.Secret = secret
+
+	return res, nil
+}
+
+func (s *service) ListWebhooks(ctx context.Context, actor *domain.AccessClaims) ([]*WebhookResponse, error) {
+	if err := s.authorize(actor, domain.PermWebhookManage); err != nil {
+		return nil, err
+	}
+
+	if !s.webhooks {
+		return nil, errWebhooksDisabled()
+	}
+
+	subscriptions, err := s.webhookRepo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgListWebhooks, err)
+	}
+
+	out := make([]*WebhookResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		out[i] = toWebhookResponse(subscription)
+	}
+
+	return out, nil
+}
+
+func (s *service) DisableWebhook(ctx context.Context, actor *domain.AccessClaims, id uuid.UUID) error {
+	if err := s.authorize(actor, domain.PermWebhookManage); err != nil {
+		return err
+	}
+
+	if !s.webhooks {
+		return errWebhooksDisabled()
+	}
+
+	disabled, err := s.webhookRepo.DisableSubscription(ctx, id, time.Now().UTC())
+	if err != nil {
+		return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgDisableWebhook, err)
+	}
+
+	if !disabled {
+		return apperror.NotFound(apperror.ErrCodeWebhookNotFound, apperror.MsgWebhookNotFound, nil)
+	}
+
+	s.audit(ctx, domain.NewAuditEvent(domain.AuditActionWebhookDisable, domain.AuditOutcomeSuccess).
+		WithActorClaims(actor).
+		WithMetadata(map[string]any{"webhook_id": id.String()}))
+
+	return nil
+}
+
+// publishUserEvents queues webhook events of the given types about user. Callers run it in
+// the transaction of the change the events report, so that neither is stored without the other.
+func (s *service) publishUserEvents(ctx context.Context, user *domain.User, types ...domain.WebhookEventType) error {
+	if !s.webhooks {
+		return nil
+	}
+
+	for _, t := range types {
+		if err := s.webhookRepo.Enqueue(ctx, domain.NewWebhookEvent(t, userEventData(user))); err != nil {
+			return apperror.InternalServerError(apperror.ErrCodeInternalServer, apperror.MsgEnqueueWebhook, err)
+		}
+	}
+
+	return nil
+}
+
+func userCreatedEvents(user *domain.User) []domain.WebhookEventType {
+	if user.IsVerified() {
+		return []domain.WebhookEventType{domain.WebhookEventUserRegistered, domain.WebhookEventUserVerified}
+	}
+
+	return []domain.WebhookEventType{domain.WebhookEventUserRegistered}
+}
+
+func userEventData(user *domain.User) map[string]any {
+	data := map[string]any{
+		"user_id":  user.ID.String(),
+		"username": user.Username.String(),
+		"email":    user.Email.String(),
+		"status":   user.Status.String(),
+	}
+
+	if user.OrganizationID != nil {
+		data["organization_id"] = user.OrganizationID.String()
+	}
+
+	return data
+}
+
+func toWebhookResponse(subscription *domain.WebhookSubscription) *WebhookResponse {
+	events := make([]string, len(subscription.Events))
+	for i, t := range subscription.Events {
+		events[i] = t.String()
+	}
+
+	return &WebhookResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		Events:     events,
+		DisabledAt: subscription.DisabledAt,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+func errWebhooksDisabled() error {
+	return apperror.NotImplemented(apperror.ErrCodeWebhooksDisabled, apperror.MsgWebhooksDisabled, nil)
+}
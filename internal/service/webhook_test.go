@@ -0,0 +1,208 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/apperror"
+	"go-auth/internal/domain"
+	"go-auth/internal/service"
+)
+
+var validWebhookReq = &service.CreateWebhookRequest{
+	URL:    "https://hooks.example.com/auth",
+	Events: []string{"user.registered", "user.banned"},
+}
+
+func TestServiceCreateWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the secret once", func(t *testing.T) {
+		t.Parallel()
+
+		webhooks := &mockWebhookRepo{}
+		auditLog := &mockAuditLogger{}
+
+		svc, err := newTestServiceWith(testDeps{
+			Webhooks:    webhooks,
+			AuditLogger: auditLog,
+			Opaque:      &mockOpaqueTokenManager{generateToken: "webhook-secret"},
+		})
+		require.NoError(t, err)
+
+		res, err := svc.CreateWebhook(ctx, mustActor(t, domain.RoleSuperAdmin), validWebhookReq)
+		require.NoError(t, err)
+		assert.Equal(t, "webhook-secret", res.Secret)
+		assert.Equal(t, []string{"user.banned", "user.registered"}, res.Events)
+
+		require.Len(t, webhooks.subscriptions, 1)
+		assert.Equal(t, "webhook-secret", webhooks.subscriptions[0].Secret)
+
+		listed, err := svc.ListWebhooks(ctx, mustActor(t, domain.RoleSuperAdmin))
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.Equal(t, res.ID, listed[0].ID)
+		assert.Empty(t, listed[0].Secret)
+
+		event := auditLog.last()
+		require.NotNil(t, event)
+		assert.Equal(t, domain.AuditActionWebhookCreated, event.Action)
+		assert.NotContains(t, event.Metadata, "secret")
+	})
+
+	tests := []struct {
+		name     string
+		role     string
+		disabled bool
+		req      *service.CreateWebhookRequest
+		wantCode apperror.Code
+	}{
+		{
+			name:     "actor lacks webhook:manage",
+			role:     domain.RoleAdmin,
+			req:      validWebhookReq,
+			wantCode: apperror.ErrCodePermissionDenied,
+		},
+		{
+			name:     "webhooks disabled",
+			role:     domain.RoleSuperAdmin,
+			disabled: true,
+			req:      validWebhookReq,
+			wantCode: apperror.ErrCodeWebhooksDisabled,
+		},
+		{
+			name:     "nil request",
+			role:     domain.RoleSuperAdmin,
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:     "plain http",
+			role:     domain.RoleSuperAdmin,
+			req:      &service.CreateWebhookRequest{URL: "http://hooks.example.com", Events: []string{"user.banned"}},
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+		{
+			name:     "unknown event",
+			role:     domain.RoleSuperAdmin,
+			req:      &service.CreateWebhookRequest{URL: validWebhookReq.URL, Events: []string{"user.deleted"}},
+			wantCode: apperror.ErrCodeInvalidParam,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			deps := testDeps{Webhooks: &mockWebhookRepo{}}
+			if tt.disabled {
+				deps = testDeps{}
+			}
+
+			svc, err := newTestServiceWith(deps)
+			require.NoError(t, err)
+
+			_, err = svc.CreateWebhook(ctx, mustActor(t, tt.role), tt.req)
+			assertAppErrorCode(t, err, tt.wantCode)
+		})
+	}
+}
+
+func TestServiceDisableWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	webhooks := &mockWebhookRepo{}
+	auditLog := &mockAuditLogger{}
+
+	svc, err := newTestServiceWith(testDeps{Webhooks: webhooks, AuditLogger: auditLog})
+	require.NoError(t, err)
+
+	actor := mustActor(t, domain.RoleSuperAdmin)
+
+	res, err := svc.CreateWebhook(ctx, actor, validWebhookReq)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DisableWebhook(ctx, actor, res.ID))
+	assert.True(t, webhooks.subscriptions[0].IsDisabled())
+
+	event := auditLog.last()
+	require.NotNil(t, event)
+	assert.Equal(t, domain.AuditActionWebhookDisable, event.Action)
+
+	err = svc.DisableWebhook(ctx, actor, uuid.New())
+	assertAppErrorCode(t, err, apperror.ErrCodeWebhookNotFound)
+
+	err = svc.DisableWebhook(ctx, mustActor(t, domain.RoleAdmin), res.ID)
+	assertAppErrorCode(t, err, apperror.ErrCodePermissionDenied)
+}
+
+func TestServicePublishesUserEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("register", func(t *testing.T) {
+		t.Parallel()
+
+		users := &mockUserRepo{}
+		webhooks := &mockWebhookRepo{}
+		transactor := &mockTransactor{}
+
+		svc, err := newTestServiceWith(testDeps{UserRepo: users, Webhooks: webhooks, Transactor: transactor})
+		require.NoError(t, err)
+
+		res, err := svc.Register(ctx, validRegisterReq)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, transactor.calls)
+		assert.Equal(t, []domain.WebhookEventType{domain.WebhookEventUserRegistered}, webhooks.eventTypes())
+		assert.Equal(t, res.UserID.String(), webhooks.events[0].Data["user_id"])
+		assert.Equal(t, "alice@example.com", webhooks.events[0].Data["email"])
+	})
+
+	t.Run("register fails when the event cannot be queued", func(t *testing.T) {
+		t.Parallel()
+
+		webhooks := &mockWebhookRepo{enqueueErr: errors.New("outbox unavailable")}
+
+		svc, err := newTestServiceWith(testDeps{UserRepo: &mockUserRepo{}, Webhooks: webhooks})
+		require.NoError(t, err)
+
+		_, err = svc.Register(ctx, validRegisterReq)
+		assertAppErrorCode(t, err, apperror.ErrCodeInternalServer)
+	})
+
+	t.Run("ban and unban", func(t *testing.T) {
+		t.Parallel()
+
+		target := mustUserWithRole(t, domain.RoleUser)
+		users := &mockUserRepo{getByIDUser: target}
+		webhooks := &mockWebhookRepo{}
+
+		svc, err := newTestServiceWith(testDeps{UserRepo: users, Webhooks: webhooks})
+		require.NoError(t, err)
+
+		admin := mustActor(t, domain.RoleAdmin)
+		req := &service.UserActionRequest{UserID: target.ID}
+
+		require.NoError(t, svc.BanUser(ctx, admin, req))
+		require.NoError(t, svc.UnbanUser(ctx, admin, req))
+
+		assert.Equal(t, []domain.WebhookEventType{
+			domain.WebhookEventUserBanned,
+			domain.WebhookEventUserUnbanned,
+		}, webhooks.eventTypes())
+	})
+
+	t.Run("nothing is queued when webhooks are disabled", func(t *testing.T) {
+		t.Parallel()
+
+		svc, err := newTestServiceWith(testDeps{UserRepo: &mockUserRepo{}})
+		require.NoError(t, err)
+
+		_, err = svc.Register(ctx, validRegisterReq)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,58 @@
+package webauthn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     []byte
+		want     any
+		wantRest []byte
+		wantErr  bool
+	}{
+		{name: "small uint", data: []byte{0x17}, want: int64(23)},
+		{name: "uint16", data: []byte{0x19, 0x01, 0x00}, want: int64(256)},
+		{name: "negative", data: []byte{0x38, 0x63}, want: int64(-100)},
+		{name: "bytes", data: []byte{0x42, 0x01, 0x02, 0xff}, want: []byte{0x01, 0x02}, wantRest: []byte{0xff}},
+		{name: "text", data: []byte{0x63, 'f', 'm', 't'}, want: "fmt"},
+		{name: "array", data: []byte{0x82, 0x01, 0xf5}, want: []any{int64(1), true}},
+		{name: "map", data: []byte{0xa2, 0x01, 0x02, 0x20, 0xf6}, want: map[any]any{int64(1): int64(2), int64(-1): nil}},
+		{name: "empty", data: nil, wantErr: true},
+		{name: "truncated bytes", data: []byte{0x45, 0x01}, wantErr: true},
+		{name: "huge array", data: []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, wantErr: true},
+		{name: "duplicate key", data: []byte{0xa2, 0x01, 0x02, 0x01, 0x03}, wantErr: true},
+		{name: "array key", data: []byte{0xa1, 0x80, 0x01}, wantErr: true},
+		{name: "indefinite length", data: []byte{0x5f, 0x41, 0x01, 0xff}, wantErr: true},
+		{name: "tag", data: []byte{0xc1, 0x01}, wantErr: true},
+		{name: "float", data: []byte{0xf9, 0x3c, 0x00}, wantErr: true},
+		{
+			name:    "too deep",
+			data:    []byte{0x81, 0x81, 0x81, 0x81, 0x81, 0x81, 0x81, 0x81, 0x81, 0x81, 0x01},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, rest, err := decodeCBOR(tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, len(tt.wantRest), len(rest))
+		})
+	}
+}
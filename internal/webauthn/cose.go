@@ -0,0 +1,133 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go-auth/internal/domain"
+)
+
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+const (
+	coseKeyType   = 1
+	coseAlgorithm = 3
+
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+
+	coseCurve = -1 // EC2 and OKP
+	coseX     = -2 // EC2 and OKP
+	coseY     = -3 // EC2
+	coseRSAN  = -1
+	coseRSAE  = -2
+)
+
+const minRSABits = 2048
+
+type coseKey struct {
+	alg int
+	key crypto.PublicKey
+}
+
+func parseCOSEKey(data []byte) (*coseKey, error) {
+	item, rest, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) != 0 {
+		return nil, errors.New("cose: trailing data after key")
+	}
+
+	m, ok := item.(map[any]any)
+	if !ok {
+		return nil, errors.New("cose: key is not a map")
+	}
+
+	kty, _ := m[int64(coseKeyType)].(int64)
+	alg, _ := m[int64(coseAlgorithm)].(int64)
+
+	switch {
+	case alg == AlgES256 && kty == coseKeyTypeEC2:
+		key, err := parseEC2Key(m)
+		if err != nil {
+			return nil, err
+		}
+
+		return &coseKey{alg: AlgES256, key: key}, nil
+	case alg == AlgEdDSA && kty == coseKeyTypeOKP:
+		crv, _ := m[int64(coseCurve)].(int64)
+		x, _ := m[int64(coseX)].([]byte)
+
+		if crv != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("cose: invalid Ed25519 key")
+		}
+
+		return &coseKey{alg: AlgEdDSA, key: ed25519.PublicKey(x)}, nil
+	case alg == AlgRS256 && kty == coseKeyTypeRSA:
+		n, _ := m[int64(coseRSAN)].([]byte)
+		e, _ := m[int64(coseRSAE)].([]byte)
+
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if key.N.BitLen() < minRSABits || len(e) == 0 || len(e) > 4 || key.E < 3 || key.E%2 == 0 {
+			return nil, errors.New("cose: invalid RSA key")
+		}
+
+		return &coseKey{alg: AlgRS256, key: key}, nil
+	default:
+		return nil, fmt.Errorf("%w: key type %d with algorithm %d", domain.ErrPasskeyAlgorithmNotAllowed, kty, alg)
+	}
+}
+
+func parseEC2Key(m map[any]any) (*ecdsa.PublicKey, error) {
+	crv, _ := m[int64(coseCurve)].(int64)
+	x, _ := m[int64(coseX)].([]byte)
+	y, _ := m[int64(coseY)].([]byte)
+
+	if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("cose: invalid P-256 key")
+	}
+
+	// Parsing the uncompressed point checks that it is on the curve.
+	point := append([]byte{0x04}, append(x, y...)...)
+
+	key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+	if err != nil {
+		return nil, fmt.Errorf("cose: invalid P-256 key: %w", err)
+	}
+
+	return key, nil
+}
+
+func (k *coseKey) verify(message, sig []byte) bool {
+	switch k.alg {
+	case AlgES256:
+		digest := sha256.Sum256(message)
+
+		return ecdsa.VerifyASN1(k.key.(*ecdsa.PublicKey), digest[:], sig)
+	case AlgEdDSA:
+		return ed25519.Verify(k.key.(ed25519.PublicKey), message, sig)
+	case AlgRS256:
+		digest := sha256.Sum256(message)
+
+		return rsa.VerifyPKCS1v15(k.key.(*rsa.PublicKey), crypto.SHA256, digest[:], sig) == nil
+	default:
+		return false
+	}
+}
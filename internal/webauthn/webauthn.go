@@ -0,0 +1,294 @@
+// Package webauthn implements the relying party side of WebAuthn Level 2 registration and
+// authentication ceremonies for passkeys. Only the "none" attestation format is accepted:
+// credentials are trusted because the signed-in user registered them, not because of
+// their authenticator's make.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"go-auth/internal/domain"
+)
+
+var _ domain.PasskeyVerifier = (*RelyingParty)(nil)
+
+const (
+	flagUserPresent            = 0x01
+	flagUserVerified           = 0x04
+	flagAttestedCredentialData = 0x40
+	flagExtensionData          = 0x80
+)
+
+const (
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+
+	minAuthenticatorData = 37
+	aaguidLength         = 16
+	maxCredentialID      = 1023
+)
+
+var algorithms = []int{AlgES256, AlgEdDSA, AlgRS256}
+
+type Config struct {
+	RPID    string
+	RPName  string
+	Origins []string
+}
+
+type RelyingParty struct {
+	id      string
+	name    string
+	idHash  [sha256.Size]byte
+	origins []string
+}
+
+func New(cfg Config) (*RelyingParty, error) {
+	id := strings.ToLower(strings.TrimSpace(cfg.RPID))
+	if id == "" || strings.ContainsAny(id, ":/") {
+		return nil, fmt.Errorf("webauthn: relying party ID %q must be a bare domain", cfg.RPID)
+	}
+
+	if len(cfg.Origins) == 0 {
+		return nil, errors.New("webauthn: at least one origin is required")
+	}
+
+	origins := make([]string, 0, len(cfg.Origins))
+
+	for _, origin := range cfg.Origins {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" || u.Path != "" || u.RawQuery != "" {
+			return nil, fmt.Errorf("webauthn: origin %q is invalid", origin)
+		}
+
+		host := u.Hostname()
+		if u.Scheme != "https" && (u.Scheme != "http" || host != "localhost") {
+			return nil, fmt.Errorf("webauthn: origin %q must use https", origin)
+		}
+
+		if host != id && !strings.HasSuffix(host, "."+id) {
+			return nil, fmt.Errorf("webauthn: origin %q is not within relying party ID %q", origin, id)
+		}
+
+		origins = append(origins, u.Scheme+"://"+u.Host)
+	}
+
+	name := cfg.RPName
+	if name == "" {
+		name = id
+	}
+
+	return &RelyingParty{
+		id:      id,
+		name:    name,
+		idHash:  sha256.Sum256([]byte(id)),
+		origins: origins,
+	}, nil
+}
+
+func (rp *RelyingParty) RelyingParty() (string, string) {
+	return rp.id, rp.name
+}
+
+func (rp *RelyingParty) Algorithms() []int {
+	return slices.Clone(algorithms)
+}
+
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+func (rp *RelyingParty) ClientChallenge(clientDataJSON []byte) (string, error) {
+	var cd clientData
+	if err := json.Unmarshal(clientDataJSON, &cd); err != nil {
+		return "", fmt.Errorf("%w: client data: %w", domain.ErrPasskeyInvalid, err)
+	}
+
+	if cd.Challenge == "" {
+		return "", fmt.Errorf("%w: client data has no challenge", domain.ErrPasskeyInvalid)
+	}
+
+	return cd.Challenge, nil
+}
+
+func (rp *RelyingParty) VerifyRegistration(
+	challenge string,
+	attestation *domain.PasskeyAttestation,
+) (*domain.VerifiedPasskey, error) {
+	if err := rp.verifyClientData(attestation.ClientDataJSON, ceremonyCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	item, rest, err := decodeCBOR(attestation.AttestationObject)
+	if err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("%w: attestation object is malformed", domain.ErrPasskeyInvalid)
+	}
+
+	obj, _ := item.(map[any]any)
+	format, _ := obj["fmt"].(string)
+	statement, _ := obj["attStmt"].(map[any]any)
+	rawAuthData, _ := obj["authData"].([]byte)
+
+	if format != "none" || statement == nil || len(statement) != 0 {
+		return nil, fmt.Errorf("%w: attestation format %q is not supported", domain.ErrPasskeyInvalid, format)
+	}
+
+	authData, err := rp.parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+
+	if authData.flags&flagAttestedCredentialData == 0 {
+		return nil, fmt.Errorf("%w: no attested credential data", domain.ErrPasskeyInvalid)
+	}
+
+	if _, err = parseCOSEKey(authData.publicKey); err != nil {
+		return nil, fmt.Errorf("%w: credential public key: %w", domain.ErrPasskeyInvalid, err)
+	}
+
+	return &domain.VerifiedPasskey{
+		CredentialID: bytes.Clone(authData.credentialID),
+		PublicKey:    bytes.Clone(authData.publicKey),
+		SignCount:    authData.signCount,
+		AAGUID:       bytes.Clone(authData.aaguid),
+		Transports:   slices.Clone(attestation.Transports),
+	}, nil
+}
+
+func (rp *RelyingParty) VerifyAssertion(
+	challenge string,
+	publicKey []byte,
+	assertion *domain.PasskeyAssertion,
+) (uint32, error) {
+	if err := rp.verifyClientData(assertion.ClientDataJSON, ceremonyGet, challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := rp.parseAuthenticatorData(assertion.AuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := parseCOSEKey(publicKey)
+	if err != nil {
+		return 0, fmt.Errorf("%w: stored public key: %w", domain.ErrPasskeyInvalid, err)
+	}
+
+	clientDataHash := sha256.Sum256(assertion.ClientDataJSON)
+	signed := append(bytes.Clone(assertion.AuthenticatorData), clientDataHash[:]...)
+
+	if !key.verify(signed, assertion.Signature) {
+		return 0, fmt.Errorf("%w: signature does not verify", domain.ErrPasskeyInvalid)
+	}
+
+	return authData.signCount, nil
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, ceremony, challenge string) error {
+	var cd clientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return fmt.Errorf("%w: client data: %w", domain.ErrPasskeyInvalid, err)
+	}
+
+	if cd.Type != ceremony {
+		return fmt.Errorf("%w: client data type %q", domain.ErrPasskeyInvalid, cd.Type)
+	}
+
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(cd.Challenge), []byte(challenge)) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", domain.ErrPasskeyInvalid)
+	}
+
+	if !slices.Contains(rp.origins, cd.Origin) {
+		return fmt.Errorf("%w: origin %q is not allowed", domain.ErrPasskeyInvalid, cd.Origin)
+	}
+
+	if cd.CrossOrigin {
+		return fmt.Errorf("%w: cross-origin ceremonies are not allowed", domain.ErrPasskeyInvalid)
+	}
+
+	return nil
+}
+
+type authenticatorData struct {
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+// parseAuthenticatorData decodes authenticator data (WebAuthn section 6.1) and checks the
+// RP ID hash and that the user was both present and verified: a passkey is the only
+// factor, so the authenticator must have checked a PIN or biometric.
+func (rp *RelyingParty) parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < minAuthenticatorData {
+		return nil, fmt.Errorf("%w: authenticator data is too short", domain.ErrPasskeyInvalid)
+	}
+
+	if subtle.ConstantTimeCompare(data[:sha256.Size], rp.idHash[:]) != 1 {
+		return nil, fmt.Errorf("%w: relying party ID mismatch", domain.ErrPasskeyInvalid)
+	}
+
+	ad := &authenticatorData{
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	if ad.flags&flagUserPresent == 0 || ad.flags&flagUserVerified == 0 {
+		return nil, fmt.Errorf("%w: user was not verified", domain.ErrPasskeyInvalid)
+	}
+
+	rest := data[minAuthenticatorData:]
+
+	if ad.flags&flagAttestedCredentialData != 0 {
+		if len(rest) < aaguidLength+2 {
+			return nil, fmt.Errorf("%w: attested credential data is truncated", domain.ErrPasskeyInvalid)
+		}
+
+		ad.aaguid = rest[:aaguidLength]
+		idLength := int(binary.BigEndian.Uint16(rest[aaguidLength:]))
+		rest = rest[aaguidLength+2:]
+
+		if idLength == 0 || idLength > maxCredentialID || idLength > len(rest) {
+			return nil, fmt.Errorf("%w: credential ID length is invalid", domain.ErrPasskeyInvalid)
+		}
+
+		ad.credentialID = rest[:idLength]
+		rest = rest[idLength:]
+
+		_, afterKey, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: credential public key: %w", domain.ErrPasskeyInvalid, err)
+		}
+
+		ad.publicKey = rest[:len(rest)-len(afterKey)]
+		rest = afterKey
+	}
+
+	if ad.flags&flagExtensionData != 0 {
+		_, afterExtensions, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: extensions: %w", domain.ErrPasskeyInvalid, err)
+		}
+
+		rest = afterExtensions
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing authenticator data", domain.ErrPasskeyInvalid)
+	}
+
+	return ad, nil
+}
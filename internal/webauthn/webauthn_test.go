@@ -0,0 +1,257 @@
+package webauthn_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/webauthn"
+	"go-auth/internal/webauthn/webauthntest"
+)
+
+const (
+	testRPID      = "example.com"
+	testOrigin    = "https://app.example.com"
+	testChallenge = "Y2hhbGxlbmdlLWNoYWxsZW5nZS1jaGFsbGVuZ2U"
+)
+
+func newTestRelyingParty(t *testing.T) *webauthn.RelyingParty {
+	t.Helper()
+
+	rp, err := webauthn.New(webauthn.Config{RPID: testRPID, RPName: "Example", Origins: []string{testOrigin}})
+	require.NoError(t, err)
+
+	return rp
+}
+
+func newTestAuthenticator(t *testing.T) *webauthntest.Authenticator {
+	t.Helper()
+
+	a, err := webauthntest.NewAuthenticator()
+	require.NoError(t, err)
+
+	return a
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     webauthn.Config
+		wantErr bool
+	}{
+		{name: "valid", cfg: webauthn.Config{RPID: "example.com", Origins: []string{"https://example.com"}}},
+		{name: "subdomain origin", cfg: webauthn.Config{RPID: "example.com", Origins: []string{"https://id.example.com"}}},
+		{name: "localhost", cfg: webauthn.Config{RPID: "localhost", Origins: []string{"http://localhost:3000"}}},
+		{name: "no rp id", cfg: webauthn.Config{Origins: []string{"https://example.com"}}, wantErr: true},
+		{
+			name:    "rp id with scheme",
+			cfg:     webauthn.Config{RPID: "https://example.com", Origins: []string{"https://example.com"}},
+			wantErr: true,
+		},
+		{name: "no origins", cfg: webauthn.Config{RPID: "example.com"}, wantErr: true},
+		{
+			name:    "http origin",
+			cfg:     webauthn.Config{RPID: "example.com", Origins: []string{"http://example.com"}},
+			wantErr: true,
+		},
+		{
+			name:    "origin outside rp id",
+			cfg:     webauthn.Config{RPID: "example.com", Origins: []string{"https://notexample.com"}},
+			wantErr: true,
+		},
+		{
+			name:    "origin with path",
+			cfg:     webauthn.Config{RPID: "example.com", Origins: []string{"https://example.com/login"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := webauthn.New(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRelyingPartyVerifyRegistration(t *testing.T) {
+	t.Parallel()
+
+	rp := newTestRelyingParty(t)
+
+	tests := []struct {
+		name      string
+		rpID      string
+		origin    string
+		challenge string
+		configure func(*webauthntest.Authenticator)
+		mutate    func(*testing.T, *webauthntest.Authenticator, *domain.PasskeyAttestation)
+		wantErr   error
+	}{
+		{name: "valid"},
+		{name: "wrong challenge", challenge: "other", wantErr: domain.ErrPasskeyInvalid},
+		{name: "wrong origin", origin: "https://evil.example.net", wantErr: domain.ErrPasskeyInvalid},
+		{name: "wrong rp id", rpID: "evil.example.net", wantErr: domain.ErrPasskeyInvalid},
+		{
+			name:      "user not verified",
+			configure: func(a *webauthntest.Authenticator) { a.Flags = webauthntest.FlagUserPresent },
+			wantErr:   domain.ErrPasskeyInvalid,
+		},
+		{
+			name: "assertion client data",
+			mutate: func(t *testing.T, a *webauthntest.Authenticator, att *domain.PasskeyAttestation) {
+				t.Helper()
+
+				assertion, err := a.Get(testRPID, testOrigin, testChallenge)
+				require.NoError(t, err)
+
+				att.ClientDataJSON = assertion.ClientDataJSON
+			},
+			wantErr: domain.ErrPasskeyInvalid,
+		},
+		{
+			name: "truncated attestation object",
+			mutate: func(_ *testing.T, _ *webauthntest.Authenticator, att *domain.PasskeyAttestation) {
+				att.AttestationObject = att.AttestationObject[:len(att.AttestationObject)-1]
+			},
+			wantErr: domain.ErrPasskeyInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rpID, origin, challenge := testRPID, testOrigin, testChallenge
+			if tt.rpID != "" {
+				rpID = tt.rpID
+			}
+
+			if tt.origin != "" {
+				origin = tt.origin
+			}
+
+			if tt.challenge != "" {
+				challenge = tt.challenge
+			}
+
+			a := newTestAuthenticator(t)
+			if tt.configure != nil {
+				tt.configure(a)
+			}
+
+			att, err := a.Create(rpID, origin, challenge, []byte("user-handle"))
+			require.NoError(t, err)
+
+			if tt.mutate != nil {
+				tt.mutate(t, a, att)
+			}
+
+			got, err := rp.VerifyRegistration(testChallenge, att)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, a.CredentialID, got.CredentialID)
+			assert.Equal(t, a.PublicKey(), got.PublicKey)
+			assert.Equal(t, []string{"internal"}, got.Transports)
+			assert.Len(t, got.AAGUID, 16)
+		})
+	}
+}
+
+func TestRelyingPartyVerifyAssertion(t *testing.T) {
+	t.Parallel()
+
+	rp := newTestRelyingParty(t)
+
+	tests := []struct {
+		name   string
+		origin string
+		mutate func(*domain.PasskeyAssertion)
+		// otherKey verifies against another credential's public key.
+		otherKey bool
+		wantErr  error
+	}{
+		{name: "valid"},
+		{name: "wrong origin", origin: "https://example.com.evil.net", wantErr: domain.ErrPasskeyInvalid},
+		{
+			name:    "tampered signature",
+			mutate:  func(a *domain.PasskeyAssertion) { a.Signature[len(a.Signature)-1] ^= 0xff },
+			wantErr: domain.ErrPasskeyInvalid,
+		},
+		{
+			name:    "tampered authenticator data",
+			mutate:  func(a *domain.PasskeyAssertion) { a.AuthenticatorData[36]++ },
+			wantErr: domain.ErrPasskeyInvalid,
+		},
+		{name: "other credential key", otherKey: true, wantErr: domain.ErrPasskeyInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			origin := testOrigin
+			if tt.origin != "" {
+				origin = tt.origin
+			}
+
+			a := newTestAuthenticator(t)
+			a.SignCount = 41
+
+			assertion, err := a.Get(testRPID, origin, testChallenge)
+			require.NoError(t, err)
+
+			if tt.mutate != nil {
+				tt.mutate(assertion)
+			}
+
+			key := a.PublicKey()
+			if tt.otherKey {
+				key = newTestAuthenticator(t).PublicKey()
+			}
+
+			count, err := rp.VerifyAssertion(testChallenge, key, assertion)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, uint32(42), count)
+		})
+	}
+}
+
+func TestRelyingPartyClientChallenge(t *testing.T) {
+	t.Parallel()
+
+	rp := newTestRelyingParty(t)
+
+	att, err := newTestAuthenticator(t).Create(testRPID, testOrigin, testChallenge, nil)
+	require.NoError(t, err)
+
+	challenge, err := rp.ClientChallenge(att.ClientDataJSON)
+	require.NoError(t, err)
+	assert.Equal(t, testChallenge, challenge)
+
+	_, err = rp.ClientChallenge([]byte(`{"type":"webauthn.get"}`))
+	require.ErrorIs(t, err, domain.ErrPasskeyInvalid)
+}
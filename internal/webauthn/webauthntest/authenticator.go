@@ -0,0 +1,134 @@
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"go-auth/internal/domain"
+)
+
+const (
+	FlagUserPresent            = 0x01
+	FlagUserVerified           = 0x04
+	FlagAttestedCredentialData = 0x40
+)
+
+const algES256 = -7
+
+type Authenticator struct {
+	Key          *ecdsa.PrivateKey
+	CredentialID []byte
+	AAGUID       []byte
+	UserHandle   []byte
+	SignCount    uint32
+	NoCounter    bool
+	Flags        byte
+}
+
+func NewAuthenticator() (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 16)
+	if _, err = rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &Authenticator{
+		Key:          key,
+		CredentialID: id,
+		AAGUID:       make([]byte, 16),
+		Flags:        FlagUserPresent | FlagUserVerified,
+	}, nil
+}
+
+func (a *Authenticator) Create(
+	rpID, origin, challenge string,
+	userHandle []byte,
+) (*domain.PasskeyAttestation, error) {
+	clientDataJSON, err := clientData("webauthn.create", challenge, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	a.UserHandle = userHandle
+
+	authData := a.authenticatorData(rpID, a.Flags|FlagAttestedCredentialData, a.SignCount)
+	authData = binary.BigEndian.AppendUint16(append(authData, a.AAGUID...), uint16(len(a.CredentialID)))
+	authData = append(append(authData, a.CredentialID...), a.PublicKey()...)
+
+	return &domain.PasskeyAttestation{
+		ClientDataJSON: clientDataJSON,
+		AttestationObject: encode(pairs{
+			{"fmt", "none"},
+			{"attStmt", pairs{}},
+			{"authData", authData},
+		}),
+		Transports: []string{"internal"},
+	}, nil
+}
+
+func (a *Authenticator) Get(rpID, origin, challenge string) (*domain.PasskeyAssertion, error) {
+	clientDataJSON, err := clientData("webauthn.get", challenge, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.NoCounter {
+		a.SignCount++
+	}
+
+	authData := a.authenticatorData(rpID, a.Flags, a.SignCount)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+
+	sig, err := ecdsa.SignASN1(rand.Reader, a.Key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PasskeyAssertion{
+		CredentialID:      a.CredentialID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authData,
+		Signature:         sig,
+		UserHandle:        a.UserHandle,
+	}, nil
+}
+
+func (a *Authenticator) PublicKey() []byte {
+	point, err := a.Key.PublicKey.Bytes()
+	if err != nil {
+		panic(fmt.Sprintf("webauthntest: encode public key: %v", err))
+	}
+
+	return encode(pairs{
+		{1, 2},            // kty: EC2
+		{3, algES256},     // alg
+		{-1, 1},           // crv: P-256
+		{-2, point[1:33]}, // x
+		{-3, point[33:]},  // y
+	})
+}
+
+func (a *Authenticator) authenticatorData(rpID string, flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	return binary.BigEndian.AppendUint32(append(rpIDHash[:], flags), signCount)
+}
+
+func clientData(ceremony, challenge, origin string) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      origin,
+		"crossOrigin": false,
+	})
+}
@@ -0,0 +1,51 @@
+package webauthntest
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+type pairs []struct {
+	key   any
+	value any
+}
+
+func encode(v any) []byte {
+	switch v := v.(type) {
+	case int:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+
+		return head(0, uint64(v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case pairs:
+		out := head(5, uint64(len(v)))
+		for _, p := range v {
+			out = append(out, encode(p.key)...)
+			out = append(out, encode(p.value)...)
+		}
+
+		return out
+	default:
+		panic(fmt.Sprintf("webauthntest: cannot encode %T", v))
+	}
+}
+
+func head(major byte, n uint64) []byte {
+	major <<= 5
+
+	switch {
+	case n < 24:
+		return []byte{major | byte(n)}
+	case n <= 0xff:
+		return []byte{major | 24, byte(n)}
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major | 25}, uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32([]byte{major | 26}, uint32(n))
+	}
+}
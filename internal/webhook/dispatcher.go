@@ -0,0 +1,235 @@
+package webhook
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"go-auth/internal/domain"
+	"go-auth/pkg/logger"
+)
+
+const (
+	DefaultPollInterval  = 5 * time.Second
+	DefaultBatchSize     = 100
+	DefaultMaxAttempts   = 10
+	DefaultTimeout       = 10 * time.Second
+	DefaultRetryDelay    = 30 * time.Second
+	DefaultMaxRetryDelay = 6 * time.Hour
+)
+
+const maxResponseBody = 64 << 10
+
+type Config struct {
+	Repo domain.WebhookRepository
+	Log  logger.Logger
+	// Client sends the deliveries; the default does not follow redirects.
+	Client        *http.Client
+	PollInterval  time.Duration
+	BatchSize     int
+	MaxAttempts   int
+	Timeout       time.Duration
+	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+}
+
+// Dispatcher sends the deliveries queued in the webhook outbox from a background goroutine
+// started with Run. Claims hide deliveries from other instances for twice the timeout; a
+// delivery whose outcome is not recorded by then, because its instance stopped, is sent again.
+type Dispatcher struct {
+	repo          domain.WebhookRepository
+	log           logger.Logger
+	client        *http.Client
+	pollInterval  time.Duration
+	batchSize     int
+	maxAttempts   int
+	timeout       time.Duration
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
+	done          chan struct{}
+}
+
+func NewDispatcher(cfg Config) (*Dispatcher, error) {
+	if cfg.Repo == nil {
+		return nil, errors.New("webhook repository is required")
+	}
+
+	if cfg.Log == nil {
+		return nil, errors.New("logger is required")
+	}
+
+	if cfg.PollInterval < 0 || cfg.BatchSize < 0 || cfg.MaxAttempts < 0 || cfg.Timeout < 0 ||
+		cfg.RetryDelay < 0 || cfg.MaxRetryDelay < 0 {
+		return nil, errors.New("webhook dispatcher settings must not be negative")
+	}
+
+	d := &Dispatcher{
+		repo:          cfg.Repo,
+		log:           cfg.Log,
+		client:        cfg.Client,
+		pollInterval:  cmp.Or(cfg.PollInterval, DefaultPollInterval),
+		batchSize:     cmp.Or(cfg.BatchSize, DefaultBatchSize),
+		maxAttempts:   cmp.Or(cfg.MaxAttempts, DefaultMaxAttempts),
+		timeout:       cmp.Or(cfg.Timeout, DefaultTimeout),
+		retryDelay:    cmp.Or(cfg.RetryDelay, DefaultRetryDelay),
+		maxRetryDelay: cmp.Or(cfg.MaxRetryDelay, DefaultMaxRetryDelay),
+		done:          make(chan struct{}),
+	}
+
+	if d.client == nil {
+		d.client = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	}
+
+	return d, nil
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	sendCtx := context.WithoutCancel(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for ctx.Err() == nil {
+			n, err := d.DispatchBatch(sendCtx)
+			if err != nil {
+				d.log.ErrorCtx(ctx, "Dispatch webhook deliveries failed", "error", err)
+			}
+
+			if err != nil || n < d.batchSize {
+				break
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) Done() <-chan struct{} {
+	return d.done
+}
+
+func (d *Dispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+
+	deliveries, err := d.repo.ClaimDeliveries(ctx, now, now.Add(2*d.timeout), d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Go(func() { d.deliver(ctx, delivery) })
+	}
+
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	err := d.send(ctx, delivery)
+
+	now := time.Now().UTC()
+	if err == nil {
+		delivery.Succeed(now)
+	} else {
+		delivery.Fail(err.Error(), now.Add(d.backoff(delivery.Attempts)), d.maxAttempts)
+
+		if delivery.Status == domain.WebhookDeliveryDead {
+			d.log.WarnCtx(ctx, "Webhook delivery dead-lettered",
+				"id", delivery.ID,
+				"event_id", delivery.Event.ID,
+				"url", delivery.URL,
+				"attempts", delivery.Attempts,
+				"error", err,
+			)
+		}
+	}
+
+	if err = d.repo.UpdateDelivery(ctx, delivery); err != nil {
+		d.log.ErrorCtx(ctx, "Record webhook delivery failed", "id", delivery.ID, "error", err)
+	}
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.retryDelay
+	for range attempts {
+		delay *= 2
+		if delay >= d.maxRetryDelay {
+			return d.maxRetryDelay
+		}
+	}
+
+	return min(delay, d.maxRetryDelay)
+}
+
+type payload struct {
+	ID        uuid.UUID      `json:"id"`
+	Type      string         `json:"type"`
+	CreatedAt time.Time      `json:"created_at"`
+	Data      map[string]any `json:"data"`
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	event := delivery.Event
+
+	body, err := json.Marshal(payload{
+		ID:        event.ID,
+		Type:      event.Type.String(),
+		CreatedAt: event.CreatedAt,
+		Data:      event.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(sendCtx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	now := time.Now()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, event.ID.String())
+	req.Header.Set(EventHeader, event.Type.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, now, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("endpoint responded %s", resp.Status)
+	}
+
+	return nil
+}
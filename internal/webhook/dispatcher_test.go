@@ -0,0 +1,184 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-auth/internal/domain"
+	"go-auth/internal/webhook"
+	"go-auth/pkg/logger"
+	_ "go-auth/pkg/logger/adapter/nop"
+)
+
+type fakeRepo struct {
+	domain.WebhookRepository
+
+	mu      sync.Mutex
+	due     []*domain.WebhookDelivery
+	updated []*domain.WebhookDelivery
+}
+
+func (f *fakeRepo) ClaimDeliveries(
+	ctx context.Context,
+	now, leaseUntil time.Time,
+	limit int,
+) ([]*domain.WebhookDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := min(limit, len(f.due))
+	claimed := f.due[:n]
+	f.due = f.due[n:]
+
+	return claimed, nil
+}
+
+func (f *fakeRepo) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.updated = append(f.updated, delivery)
+
+	return nil
+}
+
+func newNopLogger(t *testing.T) logger.Logger {
+	t.Helper()
+
+	log, err := logger.New(logger.WithDriver(logger.DriverNop))
+	require.NoError(t, err)
+
+	return log
+}
+
+func newDelivery(url string, attempts int) *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: uuid.New(),
+		URL:            url,
+		Secret:         "secret",
+		Event: domain.NewWebhookEvent(domain.WebhookEventUserBanned, map[string]any{
+			"user_id": uuid.NewString(),
+		}),
+		Status:   domain.WebhookDeliveryPending,
+		Attempts: attempts,
+	}
+}
+
+func TestNewDispatcher(t *testing.T) {
+	_, err := webhook.NewDispatcher(webhook.Config{Log: newNopLogger(t)})
+	require.Error(t, err)
+
+	_, err = webhook.NewDispatcher(webhook.Config{Repo: &fakeRepo{}})
+	require.Error(t, err)
+
+	_, err = webhook.NewDispatcher(webhook.Config{Repo: &fakeRepo{}, Log: newNopLogger(t), MaxAttempts: -1})
+	require.Error(t, err)
+}
+
+func TestDispatcherDispatchBatch(t *testing.T) {
+	t.Run("delivers a signed payload", func(t *testing.T) {
+		var got *http.Request
+		var body []byte
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		delivery := newDelivery(srv.URL, 0)
+		repo := &fakeRepo{due: []*domain.WebhookDelivery{delivery}}
+
+		d, err := webhook.NewDispatcher(webhook.Config{Repo: repo, Log: newNopLogger(t)})
+		require.NoError(t, err)
+
+		n, err := d.DispatchBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		require.NotNil(t, got)
+		assert.Equal(t, delivery.Event.ID.String(), got.Header.Get(webhook.IDHeader))
+		assert.Equal(t, "user.banned", got.Header.Get(webhook.EventHeader))
+		assert.Contains(t, string(body), `"type":"user.banned"`)
+		require.NoError(t, webhook.Verify("secret", got.Header.Get(webhook.SignatureHeader),
+			got.Header.Get(webhook.TimestampHeader), body, time.Minute, time.Now()))
+
+		require.Len(t, repo.updated, 1)
+		assert.Equal(t, domain.WebhookDeliveryDelivered, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.NotNil(t, delivery.DeliveredAt)
+	})
+
+	t.Run("failed attempts back off and are dead-lettered", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		retried := newDelivery(srv.URL, 2)
+		repo := &fakeRepo{due: []*domain.WebhookDelivery{retried}}
+
+		d, err := webhook.NewDispatcher(webhook.Config{
+			Repo:          repo,
+			Log:           newNopLogger(t),
+			MaxAttempts:   4,
+			RetryDelay:    time.Minute,
+			MaxRetryDelay: time.Hour,
+		})
+		require.NoError(t, err)
+
+		start := time.Now()
+
+		_, err = d.DispatchBatch(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.WebhookDeliveryPending, retried.Status)
+		assert.Equal(t, 3, retried.Attempts)
+		assert.Contains(t, retried.LastError, "500")
+		assert.WithinDuration(t, start.Add(4*time.Minute), retried.NextAttemptAt, 5*time.Second)
+
+		last := newDelivery(srv.URL, 3)
+		repo.due = []*domain.WebhookDelivery{last}
+
+		_, err = d.DispatchBatch(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.WebhookDeliveryDead, last.Status)
+		assert.Equal(t, 4, last.Attempts)
+		assert.Len(t, repo.updated, 2)
+	})
+
+	t.Run("redirects are not followed", func(t *testing.T) {
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("redirect was followed")
+		}))
+		defer target.Close()
+
+		srv := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+		defer srv.Close()
+
+		delivery := newDelivery(srv.URL, 0)
+
+		d, err := webhook.NewDispatcher(webhook.Config{
+			Repo: &fakeRepo{due: []*domain.WebhookDelivery{delivery}},
+			Log:  newNopLogger(t),
+		})
+		require.NoError(t, err)
+
+		_, err = d.DispatchBatch(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, domain.WebhookDeliveryPending, delivery.Status)
+		assert.Contains(t, delivery.LastError, "307")
+	})
+}
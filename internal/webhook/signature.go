@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery. Receivers should drop deliveries whose IDHeader they
+// have already processed: a delivery is retried when its acknowledgement is lost.
+const (
+	IDHeader        = "Webhook-Id"
+	EventHeader     = "Webhook-Event"
+	TimestampHeader = "Webhook-Timestamp"
+	SignatureHeader = "Webhook-Signature"
+)
+
+const signaturePrefix = "v1="
+
+var (
+	ErrSignatureInvalid = errors.New("webhook signature is invalid")
+	ErrTimestampInvalid = errors.New("webhook timestamp is invalid or outside the tolerance")
+)
+
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a delivery received at now. Deliveries
+// signed more than tolerance away from now are rejected, which limits replays.
+func Verify(secret, signature, timestamp string, body []byte, tolerance time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrTimestampInvalid
+	}
+
+	signedAt := time.Unix(unix, 0)
+	if now.Sub(signedAt).Abs() > tolerance {
+		return ErrTimestampInvalid
+	}
+
+	if !strings.HasPrefix(signature, signaturePrefix) ||
+		!hmac.Equal([]byte(signature), []byte(Sign(secret, signedAt, body))) {
+		return ErrSignatureInvalid
+	}
+
+	return nil
+}
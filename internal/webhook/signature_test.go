@@ -0,0 +1,86 @@
+package webhook_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-auth/internal/webhook"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	body := []byte(`{"type":"user.banned"}`)
+	signature := webhook.Sign("secret", now, body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		body      []byte
+		wantErr   error
+	}{
+		{
+			name:      "valid",
+			secret:    "secret",
+			signature: signature,
+			timestamp: timestamp,
+			body:      body,
+		},
+		{
+			name:      "wrong secret",
+			secret:    "other",
+			signature: signature,
+			timestamp: timestamp,
+			body:      body,
+			wantErr:   webhook.ErrSignatureInvalid,
+		},
+		{
+			name:      "tampered body",
+			secret:    "secret",
+			signature: signature,
+			timestamp: timestamp,
+			body:      []byte(`{"type":"user.unbanned"}`),
+			wantErr:   webhook.ErrSignatureInvalid,
+		},
+		{
+			name:      "missing version",
+			secret:    "secret",
+			signature: signature[len("v1="):],
+			timestamp: timestamp,
+			body:      body,
+			wantErr:   webhook.ErrSignatureInvalid,
+		},
+		{
+			name:      "stale timestamp",
+			secret:    "secret",
+			signature: webhook.Sign("secret", now.Add(-time.Hour), body),
+			timestamp: strconv.FormatInt(now.Add(-time.Hour).Unix(), 10),
+			body:      body,
+			wantErr:   webhook.ErrTimestampInvalid,
+		},
+		{
+			name:      "malformed timestamp",
+			secret:    "secret",
+			signature: signature,
+			timestamp: "yesterday",
+			body:      body,
+			wantErr:   webhook.ErrTimestampInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := webhook.Verify(tt.secret, tt.signature, tt.timestamp, tt.body, 5*time.Minute, now)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
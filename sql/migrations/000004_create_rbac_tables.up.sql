@@ -0,0 +1,53 @@
+CREATE TABLE IF NOT EXISTS roles (
+  name VARCHAR(20) PRIMARY KEY,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  built_in BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+  name VARCHAR(64) PRIMARY KEY,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role VARCHAR(20) NOT NULL,
+  permission VARCHAR(64) NOT NULL,
+
+  PRIMARY KEY (role, permission),
+  CONSTRAINT fk_role_permissions_role FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE,
+  CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission) REFERENCES permissions(name) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_role_permissions_permission ON role_permissions(permission);
+
+INSERT INTO roles (name, description, built_in) VALUES
+  ('user', 'Regular account', TRUE),
+  ('admin', 'Administrator', TRUE),
+  ('superadmin', 'Super administrator', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+  ('user:read', 'Read user accounts'),
+  ('user:write', 'Modify user accounts'),
+  ('user:ban', 'Ban and unban user accounts'),
+  ('user:delete', 'Delete user accounts'),
+  ('role:manage', 'Manage roles and permissions')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('user', 'user:read'),
+  ('admin', 'user:read'),
+  ('admin', 'user:write'),
+  ('admin', 'user:ban'),
+  ('superadmin', 'user:read'),
+  ('superadmin', 'user:write'),
+  ('superadmin', 'user:ban'),
+  ('superadmin', 'user:delete'),
+  ('superadmin', 'role:manage')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users
+  ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
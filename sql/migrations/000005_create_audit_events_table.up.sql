@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+  id UUID PRIMARY KEY,
+  actor_id UUID,
+  target_id UUID,
+  action VARCHAR(50) NOT NULL,
+  outcome VARCHAR(20) NOT NULL,
+  client_ip VARCHAR(45) NOT NULL DEFAULT '',
+  user_agent TEXT NOT NULL DEFAULT '',
+  metadata JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id, created_at DESC);
+CREATE INDEX idx_audit_events_target_id ON audit_events(target_id, created_at DESC);
+CREATE INDEX idx_audit_events_action ON audit_events(action, created_at DESC);
+CREATE INDEX idx_audit_events_created_at ON audit_events(created_at DESC);
+
+INSERT INTO permissions (name, description) VALUES
+  ('audit:read', 'Read the audit log')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('admin', 'audit:read'),
+  ('superadmin', 'audit:read')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS known_devices (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  fingerprint VARCHAR(64) NOT NULL,
+  ua_family VARCHAR(100) NOT NULL,
+  ip_prefix VARCHAR(50) NOT NULL,
+  first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT fk_known_devices_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_known_devices_user_fingerprint ON known_devices(user_id, fingerprint);
@@ -0,0 +1,6 @@
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS authenticated_at TIMESTAMPTZ;
+
+UPDATE sessions SET authenticated_at = created_at WHERE authenticated_at IS NULL;
+
+ALTER TABLE sessions ALTER COLUMN authenticated_at SET NOT NULL;
+ALTER TABLE sessions ALTER COLUMN authenticated_at SET DEFAULT now();
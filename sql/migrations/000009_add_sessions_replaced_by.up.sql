@@ -0,0 +1,2 @@
+ALTER TABLE sessions
+  ADD COLUMN IF NOT EXISTS replaced_by UUID REFERENCES sessions(id) ON DELETE SET NULL;
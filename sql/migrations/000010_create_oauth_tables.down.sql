@@ -0,0 +1,7 @@
+DELETE FROM permissions WHERE name = 'oauth_client:manage';
+DROP INDEX IF EXISTS idx_sessions_client_id;
+ALTER TABLE sessions
+  DROP COLUMN IF EXISTS scopes,
+  DROP COLUMN IF EXISTS client_id;
+DROP TABLE IF EXISTS oauth_authorization_codes;
+DROP TABLE IF EXISTS oauth_clients;
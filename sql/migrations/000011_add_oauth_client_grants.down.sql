@@ -0,0 +1,3 @@
+ALTER TABLE oauth_clients
+  DROP COLUMN IF EXISTS disabled_at,
+  DROP COLUMN IF EXISTS grant_types;
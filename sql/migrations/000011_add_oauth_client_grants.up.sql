@@ -0,0 +1,3 @@
+ALTER TABLE oauth_clients
+  ADD COLUMN IF NOT EXISTS grant_types TEXT[] NOT NULL DEFAULT '{authorization_code,refresh_token}',
+  ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS federated_login_states;
+DROP TABLE IF EXISTS user_identities;
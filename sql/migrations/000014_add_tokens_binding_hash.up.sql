@@ -0,0 +1,2 @@
+ALTER TABLE tokens
+  ADD COLUMN IF NOT EXISTS binding_hash TEXT;
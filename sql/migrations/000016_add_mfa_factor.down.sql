@@ -0,0 +1,6 @@
+ALTER TABLE tokens
+  DROP COLUMN IF EXISTS attempts,
+  DROP COLUMN IF EXISTS code_hash;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS mfa_factor;
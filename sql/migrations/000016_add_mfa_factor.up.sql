@@ -0,0 +1,6 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS mfa_factor TEXT;
+
+ALTER TABLE tokens
+  ADD COLUMN IF NOT EXISTS code_hash TEXT,
+  ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+INSERT INTO permissions (name, description) VALUES
+  ('user:impersonate', 'Act as another user for support')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('superadmin', 'user:impersonate')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,18 @@
+DELETE FROM permissions WHERE name = 'organization:manage';
+
+ALTER TABLE sessions
+  DROP COLUMN IF EXISTS organization_id;
+
+DROP INDEX IF EXISTS idx_users_organization_email;
+DROP INDEX IF EXISTS idx_users_organization_username;
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_username;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS organization_id;
+
+CREATE UNIQUE INDEX idx_users_email ON users(email);
+CREATE UNIQUE INDEX idx_users_username ON users(username);
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS organizations (
+  id UUID PRIMARY KEY,
+  slug VARCHAR(64) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_organizations_slug ON organizations(slug);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+  organization_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  role VARCHAR(20) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  PRIMARY KEY (organization_id, user_id),
+  CONSTRAINT fk_organization_members_organization FOREIGN KEY (organization_id)
+    REFERENCES organizations(id) ON DELETE CASCADE,
+  CONSTRAINT fk_organization_members_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT fk_organization_members_role FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE
+);
+
+CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+
+-- organization_id is the organisation an account was registered in; NULL for global accounts.
+-- Usernames and emails are unique within it, global accounts sharing the nil UUID.
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES organizations(id);
+
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_username;
+
+CREATE UNIQUE INDEX idx_users_organization_email
+  ON users((COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)), email);
+CREATE UNIQUE INDEX idx_users_organization_username
+  ON users((COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)), username);
+CREATE INDEX idx_users_email ON users(email);
+CREATE INDEX idx_users_username ON users(username);
+
+-- organization_id is the session's active organisation; it is carried across rotations.
+ALTER TABLE sessions
+  ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL;
+
+INSERT INTO permissions (name, description) VALUES
+  ('organization:manage', 'Manage organisations and their members')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('superadmin', 'organization:manage')
+ON CONFLICT DO NOTHING;
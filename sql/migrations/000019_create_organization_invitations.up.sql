@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS organization_invitations (
+  id UUID PRIMARY KEY,
+  organization_id UUID NOT NULL,
+  inviter_id UUID NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  role VARCHAR(20) NOT NULL,
+  token TEXT NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  accepted_at TIMESTAMPTZ,
+  accepted_by UUID,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT fk_organization_invitations_organization FOREIGN KEY (organization_id)
+    REFERENCES organizations(id) ON DELETE CASCADE,
+  CONSTRAINT fk_organization_invitations_role FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_organization_invitations_token ON organization_invitations(token);
+CREATE INDEX idx_organization_invitations_organization_id ON organization_invitations(organization_id);
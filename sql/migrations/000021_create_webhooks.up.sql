@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+  id UUID PRIMARY KEY,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  events TEXT[] NOT NULL,
+  disabled_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- webhook_events is the outbox: events are written in the transaction of the change they
+-- report, with one delivery per subscription, and sent by the delivery worker afterwards.
+CREATE TABLE IF NOT EXISTS webhook_events (
+  id UUID PRIMARY KEY,
+  type VARCHAR(50) NOT NULL,
+  data JSONB NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id UUID PRIMARY KEY,
+  subscription_id UUID NOT NULL,
+  event_id UUID NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL,
+  last_error TEXT NOT NULL DEFAULT '',
+  delivered_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  CONSTRAINT fk_webhook_deliveries_subscription FOREIGN KEY (subscription_id)
+    REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+  CONSTRAINT fk_webhook_deliveries_event FOREIGN KEY (event_id)
+    REFERENCES webhook_events(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_event_id ON webhook_deliveries(event_id);
+
+INSERT INTO permissions (name, description) VALUES
+  ('webhook:manage', 'Manage webhook subscriptions')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('superadmin', 'webhook:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,36 @@
+-- name: CreateAPIKey :exec
+INSERT INTO api_keys (
+  id,
+  user_id,
+  name,
+  secret_hash,
+  scopes,
+  expires_at,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: GetAPIKeyByID :one
+SELECT *
+FROM api_keys
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListAPIKeysByUserID :many
+SELECT *
+FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: UseAPIKey :exec
+UPDATE api_keys
+SET last_used_at = $2
+WHERE id = $1;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = $3
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL;
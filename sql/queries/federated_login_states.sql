@@ -0,0 +1,17 @@
+-- name: CreateFederatedLoginState :exec
+INSERT INTO federated_login_states (
+  state_hash,
+  provider,
+  nonce,
+  code_verifier,
+  user_id,
+  expires_at,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: ConsumeFederatedLoginState :one
+DELETE FROM federated_login_states
+WHERE state_hash = $1
+RETURNING *;
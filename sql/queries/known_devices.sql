@@ -0,0 +1,26 @@
+-- name: UpsertKnownDevice :one
+INSERT INTO known_devices (
+  id,
+  user_id,
+  fingerprint,
+  ua_family,
+  ip_prefix,
+  first_seen_at,
+  last_seen_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (user_id, fingerprint) DO UPDATE
+SET last_seen_at = EXCLUDED.last_seen_at
+RETURNING (xmax = 0)::boolean AS inserted;
+
+-- name: ListKnownDevicesByUserID :many
+SELECT *
+FROM known_devices
+WHERE user_id = $1
+ORDER BY last_seen_at DESC;
+
+-- name: CountKnownDevicesByUserID :one
+SELECT COUNT(*)
+FROM known_devices
+WHERE user_id = $1;
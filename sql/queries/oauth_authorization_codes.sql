@@ -0,0 +1,29 @@
+-- name: CreateOAuthAuthorizationCode :one
+INSERT INTO oauth_authorization_codes (
+  code_hash,
+  client_id,
+  user_id,
+  redirect_uri,
+  scopes,
+  code_challenge,
+  code_challenge_method,
+  nonce,
+  expires_at,
+  used_at,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+RETURNING *;
+
+-- name: GetOAuthAuthorizationCodeByHash :one
+SELECT *
+FROM oauth_authorization_codes
+WHERE code_hash = $1
+LIMIT 1;
+
+-- name: MarkOAuthAuthorizationCodeUsed :execrows
+UPDATE oauth_authorization_codes
+SET used_at = $2
+WHERE code_hash = $1
+  AND used_at IS NULL;
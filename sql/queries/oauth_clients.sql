@@ -0,0 +1,34 @@
+-- name: CreateOAuthClient :one
+INSERT INTO oauth_clients (
+  id,
+  name,
+  secret_hash,
+  redirect_uris,
+  scopes,
+  grant_types,
+  disabled_at,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetOAuthClientByID :one
+SELECT *
+FROM oauth_clients
+WHERE id = $1
+LIMIT 1;
+
+-- name: UpdateOAuthClient :one
+UPDATE oauth_clients
+SET
+  name = $2,
+  secret_hash = $3,
+  redirect_uris = $4,
+  scopes = $5,
+  grant_types = $6,
+  disabled_at = $7,
+  updated_at = $8
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,48 @@
+-- name: CreateOrganizationInvitation :exec
+INSERT INTO organization_invitations (
+  id,
+  organization_id,
+  inviter_id,
+  email,
+  role,
+  token,
+  expires_at,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: GetOrganizationInvitationByID :one
+SELECT *
+FROM organization_invitations
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetOrganizationInvitationByToken :one
+SELECT *
+FROM organization_invitations
+WHERE token = $1
+LIMIT 1;
+
+-- name: ListOrganizationInvitations :many
+SELECT *
+FROM organization_invitations
+WHERE organization_id = $1
+ORDER BY created_at DESC;
+
+-- name: AcceptOrganizationInvitation :execrows
+UPDATE organization_invitations
+SET
+  accepted_at = $2,
+  accepted_by = $3
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > $2;
+
+-- name: RevokeOrganizationInvitation :execrows
+UPDATE organization_invitations
+SET revoked_at = $2
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL;
@@ -0,0 +1,64 @@
+-- name: CreateOrganization :execrows
+INSERT INTO organizations (
+  id,
+  slug,
+  name,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (slug) DO NOTHING;
+
+-- name: GetOrganizationByID :one
+SELECT *
+FROM organizations
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetOrganizationBySlug :one
+SELECT *
+FROM organizations
+WHERE slug = $1
+LIMIT 1;
+
+-- name: UpsertOrganizationMember :exec
+INSERT INTO organization_members (
+  organization_id,
+  user_id,
+  role,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (organization_id, user_id) DO UPDATE
+SET
+  role = EXCLUDED.role,
+  updated_at = EXCLUDED.updated_at;
+
+-- name: GetOrganizationMember :one
+SELECT *
+FROM organization_members
+WHERE organization_id = $1
+  AND user_id = $2
+LIMIT 1;
+
+-- name: ListOrganizationMembershipsByUserID :many
+SELECT
+  o.id,
+  o.slug,
+  o.name,
+  o.created_at,
+  o.updated_at,
+  m.role,
+  m.created_at AS joined_at
+FROM organization_members m
+JOIN organizations o ON o.id = m.organization_id
+WHERE m.user_id = $1
+ORDER BY o.slug;
+
+-- name: DeleteOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE organization_id = $1
+  AND user_id = $2;
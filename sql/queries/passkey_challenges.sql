@@ -0,0 +1,15 @@
+-- name: CreatePasskeyChallenge :exec
+INSERT INTO passkey_challenges (
+  challenge_hash,
+  ceremony,
+  user_id,
+  expires_at,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: ConsumePasskeyChallenge :one
+DELETE FROM passkey_challenges
+WHERE challenge_hash = $1
+RETURNING *;
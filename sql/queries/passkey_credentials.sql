@@ -0,0 +1,41 @@
+-- name: CreatePasskeyCredential :execrows
+INSERT INTO passkey_credentials (
+  id,
+  user_id,
+  credential_id,
+  public_key,
+  sign_count,
+  aaguid,
+  transports,
+  name,
+  created_at,
+  last_used_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (credential_id) DO NOTHING;
+
+-- name: GetPasskeyCredentialByCredentialID :one
+SELECT *
+FROM passkey_credentials
+WHERE credential_id = $1
+LIMIT 1;
+
+-- name: ListPasskeyCredentialsByUserID :many
+SELECT *
+FROM passkey_credentials
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: UsePasskeyCredential :execrows
+UPDATE passkey_credentials
+SET
+  sign_count = @sign_count,
+  last_used_at = @last_used_at
+WHERE id = @id
+  AND (sign_count < @sign_count OR (sign_count = 0 AND @sign_count = 0));
+
+-- name: DeletePasskeyCredential :execrows
+DELETE FROM passkey_credentials
+WHERE id = $1
+  AND user_id = $2;
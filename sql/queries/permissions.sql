@@ -0,0 +1,24 @@
+-- name: CreatePermission :one
+INSERT INTO permissions (
+  name,
+  description,
+  created_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetPermissionByName :one
+SELECT *
+FROM permissions
+WHERE name = $1
+LIMIT 1;
+
+-- name: ListPermissions :many
+SELECT *
+FROM permissions
+ORDER BY name;
+
+-- name: DeletePermission :exec
+DELETE FROM permissions
+WHERE name = $1;
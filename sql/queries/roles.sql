@@ -0,0 +1,61 @@
+-- name: CreateRole :one
+INSERT INTO roles (
+  name,
+  description,
+  built_in,
+  created_at,
+  updated_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetRoleByName :one
+SELECT *
+FROM roles
+WHERE name = $1
+LIMIT 1;
+
+-- name: ListRoles :many
+SELECT *
+FROM roles
+ORDER BY name;
+
+-- name: UpdateRole :one
+UPDATE roles
+SET
+  description = $2,
+  updated_at = $3
+WHERE name = $1
+RETURNING *;
+
+-- name: DeleteRole :exec
+DELETE FROM roles
+WHERE name = $1;
+
+-- name: ExistsRoleAssignment :one
+SELECT
+  EXISTS(SELECT 1 FROM users WHERE role = $1)
+  OR EXISTS(SELECT 1 FROM organization_members WHERE role = $1)
+  OR EXISTS(SELECT 1 FROM organization_invitations WHERE role = $1) AS assigned;
+
+-- name: ListRolePermissions :many
+SELECT *
+FROM role_permissions
+ORDER BY role, permission;
+
+-- name: GetPermissionsByRole :many
+SELECT permission
+FROM role_permissions
+WHERE role = $1
+ORDER BY permission;
+
+-- name: AddRolePermissions :exec
+INSERT INTO role_permissions (role, permission)
+SELECT @role::text, unnest(@permissions::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteRolePermissionsExcept :exec
+DELETE FROM role_permissions
+WHERE role = @role::text
+  AND NOT (permission = ANY(@permissions::text[]));
@@ -8,9 +8,15 @@ INSERT INTO sessions (
   expires_at,
   revoked_at,
   created_at,
-  updated_at
+  updated_at,
+  flagged_at,
+  authenticated_at,
+  replaced_by,
+  client_id,
+  scopes,
+  organization_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 )
 RETURNING *;
 
@@ -40,10 +46,56 @@ SET
   client_ip = $4,
   expires_at = $5,
   revoked_at = $6,
-  updated_at = $7
+  updated_at = $7,
+  flagged_at = $8,
+  replaced_by = $9
 WHERE id = $1
 RETURNING *;
 
+-- name: RotateSession :one
+WITH rotated AS (
+  UPDATE sessions
+  SET
+    revoked_at = sqlc.arg(revoked_at),
+    updated_at = sqlc.arg(revoked_at),
+    flagged_at = sqlc.narg(flagged_at),
+    replaced_by = sqlc.arg(successor_id)
+  WHERE sessions.id = sqlc.arg(id)
+    AND sessions.revoked_at IS NULL
+  RETURNING sessions.id, sessions.client_id, sessions.scopes
+)
+INSERT INTO sessions (
+  id,
+  user_id,
+  token,
+  user_agent,
+  client_ip,
+  expires_at,
+  created_at,
+  updated_at,
+  flagged_at,
+  authenticated_at,
+  client_id,
+  scopes,
+  organization_id
+)
+SELECT
+  sqlc.arg(successor_id),
+  sqlc.arg(user_id),
+  sqlc.arg(token),
+  sqlc.arg(user_agent),
+  sqlc.arg(client_ip),
+  sqlc.arg(expires_at),
+  sqlc.arg(created_at),
+  sqlc.arg(created_at),
+  sqlc.narg(flagged_at),
+  sqlc.arg(authenticated_at),
+  rotated.client_id,
+  rotated.scopes,
+  sqlc.narg(organization_id)
+FROM rotated
+RETURNING *;
+
 -- name: DeleteSession :exec
 DELETE FROM sessions
 WHERE id = $1;
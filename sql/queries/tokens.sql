@@ -6,9 +6,11 @@ INSERT INTO tokens (
   type,
   expires_at,
   used_at,
-  created_at
+  created_at,
+  binding_hash,
+  code_hash
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
@@ -18,6 +20,12 @@ FROM tokens
 WHERE id = $1
 LIMIT 1;
 
+-- name: GetTokenByHash :one
+SELECT *
+FROM tokens
+WHERE token = $1
+LIMIT 1;
+
 -- name: GetTokensByUserID :many
 SELECT *
 FROM tokens
@@ -34,6 +42,20 @@ SET
 WHERE id = $1
 RETURNING *;
 
+-- name: MarkTokenUsed :execrows
+UPDATE tokens
+SET used_at = $2
+WHERE id = $1
+  AND used_at IS NULL;
+
+-- name: IncrementTokenAttempts :one
+UPDATE tokens
+SET attempts = attempts + 1
+WHERE id = @id
+  AND used_at IS NULL
+  AND attempts < @max_attempts
+RETURNING attempts;
+
 -- name: DeleteToken :exec
 DELETE FROM tokens
 WHERE id = $1;
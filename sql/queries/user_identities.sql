@@ -0,0 +1,19 @@
+-- name: CreateUserIdentity :execrows
+INSERT INTO user_identities (
+  id,
+  user_id,
+  provider,
+  subject,
+  email,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (provider, subject) DO NOTHING;
+
+-- name: GetUserIdentityByProviderSubject :one
+SELECT *
+FROM user_identities
+WHERE provider = $1
+  AND subject = $2
+LIMIT 1;
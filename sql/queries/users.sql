@@ -10,9 +10,11 @@ INSERT INTO users (
   status,
   verified_at,
   created_at,
-  updated_at
+  updated_at,
+  mfa_factor,
+  organization_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
 RETURNING *;
 
@@ -34,6 +36,22 @@ FROM users
 WHERE email = $1
 LIMIT 1;
 
+-- name: GetUserByOrganizationAndUsername :one
+SELECT *
+FROM users
+WHERE COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)
+    = COALESCE(sqlc.narg(organization_id)::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+  AND username = sqlc.arg(username)
+LIMIT 1;
+
+-- name: GetUserByOrganizationAndEmail :one
+SELECT *
+FROM users
+WHERE COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)
+    = COALESCE(sqlc.narg(organization_id)::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+  AND email = sqlc.arg(email)
+LIMIT 1;
+
 -- name: UpdateUser :one
 UPDATE users
 SET
@@ -45,7 +63,8 @@ SET
   role = $7,
   status = $8,
   verified_at = $9,
-  updated_at = $10
+  updated_at = $10,
+  mfa_factor = $11
 WHERE id = $1
 RETURNING *;
 
@@ -58,3 +77,19 @@ SELECT EXISTS(SELECT 1 FROM users WHERE username = $1);
 
 -- name: ExistsByEmail :one
 SELECT EXISTS(SELECT 1 FROM users WHERE email = $1);
+
+-- name: ExistsByOrganizationAndUsername :one
+SELECT EXISTS(
+  SELECT 1 FROM users
+  WHERE COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)
+      = COALESCE(sqlc.narg(organization_id)::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+    AND username = sqlc.arg(username)
+);
+
+-- name: ExistsByOrganizationAndEmail :one
+SELECT EXISTS(
+  SELECT 1 FROM users
+  WHERE COALESCE(organization_id, '00000000-0000-0000-0000-000000000000'::uuid)
+      = COALESCE(sqlc.narg(organization_id)::uuid, '00000000-0000-0000-0000-000000000000'::uuid)
+    AND email = sqlc.arg(email)
+);
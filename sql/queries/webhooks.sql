@@ -0,0 +1,91 @@
+-- name: CreateWebhookSubscription :exec
+INSERT INTO webhook_subscriptions (
+  id,
+  url,
+  secret,
+  events,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: ListWebhookSubscriptions :many
+SELECT *
+FROM webhook_subscriptions
+ORDER BY created_at DESC;
+
+-- name: DisableWebhookSubscription :execrows
+UPDATE webhook_subscriptions
+SET disabled_at = $2
+WHERE id = $1
+  AND disabled_at IS NULL;
+
+-- name: CreateWebhookEvent :exec
+INSERT INTO webhook_events (
+  id,
+  type,
+  data,
+  created_at
+) VALUES (
+  $1, $2, $3, $4
+);
+
+-- name: CreateWebhookDeliveries :exec
+INSERT INTO webhook_deliveries (
+  id,
+  subscription_id,
+  event_id,
+  next_attempt_at,
+  created_at
+)
+SELECT gen_random_uuid(), s.id, sqlc.arg(event_id), sqlc.arg(created_at), sqlc.arg(created_at)
+FROM webhook_subscriptions s
+WHERE s.disabled_at IS NULL
+  AND sqlc.arg(type)::TEXT = ANY(s.events);
+
+-- name: ClaimWebhookDeliveries :many
+WITH due AS (
+  SELECT d.id
+  FROM webhook_deliveries d
+  JOIN webhook_subscriptions s ON s.id = d.subscription_id
+  WHERE d.status = 'pending'
+    AND d.next_attempt_at <= sqlc.arg(now)
+    AND s.disabled_at IS NULL
+  ORDER BY d.next_attempt_at
+  LIMIT sqlc.arg(max_deliveries)
+  FOR UPDATE OF d SKIP LOCKED
+), claimed AS (
+  UPDATE webhook_deliveries d
+  SET next_attempt_at = sqlc.arg(lease_until)
+  FROM due
+  WHERE d.id = due.id
+  RETURNING d.*
+)
+SELECT
+  claimed.id,
+  claimed.subscription_id,
+  claimed.event_id,
+  claimed.status,
+  claimed.attempts,
+  claimed.next_attempt_at,
+  claimed.last_error,
+  claimed.delivered_at,
+  claimed.created_at,
+  s.url,
+  s.secret,
+  e.type AS event_type,
+  e.data AS event_data,
+  e.created_at AS event_created_at
+FROM claimed
+JOIN webhook_subscriptions s ON s.id = claimed.subscription_id
+JOIN webhook_events e ON e.id = claimed.event_id
+ORDER BY claimed.next_attempt_at;
+
+-- name: UpdateWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = $2,
+    attempts = $3,
+    next_attempt_at = $4,
+    last_error = $5,
+    delivered_at = $6
+WHERE id = $1;